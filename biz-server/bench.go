@@ -0,0 +1,541 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+// benchConfig 压测参数
+type benchConfig struct {
+	ConnectNodes []string      `json:"connect_nodes"` // 客户端按顺序轮流连接
+	PushManager  string        `json:"push_manager"`
+	Clients      int           `json:"clients"`
+	Rooms        int           `json:"rooms"`
+	JoinRate     int           `json:"join_rate"`    // 每秒建立的连接数
+	Publishers   int           `json:"publishers"`   // 并发发布者数
+	PublishRate  int           `json:"publish_rate"` // 全部发布者每秒发布的消息数
+	Payload      int           `json:"payload"`      // 消息体填充字节数
+	Warmup       time.Duration `json:"warmup"`       // 全部客户端加入房间后、开始发布前的等待时间
+	Duration     time.Duration `json:"duration"`     // 发布时长
+	Drain        time.Duration `json:"drain"`        // 停止发布后等待在途消息的时间
+	ReportPath   string        `json:"-"`            // JSON 报告输出路径
+	MinDelivery  float64       `json:"min_delivery"` // 送达率低于该值时以非 0 退出
+	MaxP99       time.Duration `json:"max_p99"`      // P99 延迟超过该值时以非 0 退出
+}
+
+// benchMessage 压测消息体：接收方用 sent_at 计算端到端延迟，run 区分不同的压测
+type benchMessage struct {
+	Run    string `json:"run"`
+	Seq    int64  `json:"seq"`
+	SentAt int64  `json:"sent_at"` // Unix 纳秒
+	Pad    string `json:"pad,omitempty"`
+}
+
+// benchNode 一个 Connect-Node 的连接和接收统计
+type benchNode struct {
+	addr      string
+	clients   atomic.Int64
+	failed    atomic.Int64
+	received  atomic.Int64
+	lastRecvd atomic.Int64 // 最后一次收到压测消息的时间（Unix 纳秒）
+}
+
+// benchRun 一次压测的运行状态
+type benchRun struct {
+	cfg   benchConfig
+	runID string
+	nodes []*benchNode
+
+	// 每个房间已确认加入（收到加入房间响应）的客户端数，发布时据此计算应送达数
+	roomMembers []atomic.Int64
+	joinFailed  atomic.Int64
+
+	published     atomic.Int64
+	publishFailed atomic.Int64
+	expected      atomic.Int64
+	received      atomic.Int64
+	stray         atomic.Int64 // 收到的非本次压测的消息（其他推送、在线状态变化等）
+
+	mu            sync.Mutex
+	latencies     []time.Duration // 端到端推送延迟
+	joinLatencies []time.Duration // 从发起连接到收到加入房间响应
+}
+
+// benchReport 压测报告（-bench-report 输出为 JSON）
+type benchReport struct {
+	Config      benchConfig       `json:"config"`
+	StartedAt   time.Time         `json:"started_at"`
+	Connections benchConnStats    `json:"connections"`
+	Publish     benchPublishStats `json:"publish"`
+	Latency     latencyStats      `json:"latency"`
+	Nodes       []benchNodeReport `json:"nodes"`
+	Passed      bool              `json:"passed"`
+	Failures    []string          `json:"failures,omitempty"`
+}
+
+type benchConnStats struct {
+	Attempted  int          `json:"attempted"`
+	Connected  int64        `json:"connected"`
+	Failed     int64        `json:"failed"`
+	Joined     int64        `json:"joined"`
+	JoinFailed int64        `json:"join_failed"`
+	JoinTime   latencyStats `json:"join_time"` // 从发送加入房间请求到收到响应
+}
+
+type benchPublishStats struct {
+	Published     int64   `json:"published"`
+	Failed        int64   `json:"failed"`
+	Rate          float64 `json:"rate"` // 实际发布速率（条/秒）
+	Expected      int64   `json:"expected"`
+	Received      int64   `json:"received"`
+	DeliveryRatio float64 `json:"delivery_ratio"`
+	Stray         int64   `json:"stray"`
+}
+
+type benchNodeReport struct {
+	Address    string  `json:"address"`
+	Clients    int64   `json:"clients"`
+	Failed     int64   `json:"failed"`
+	Received   int64   `json:"received"`
+	Throughput float64 `json:"throughput"` // 下行推送条/秒
+}
+
+// latencyStats 延迟分布（毫秒）
+type latencyStats struct {
+	Count int64   `json:"count"`
+	Mean  float64 `json:"mean_ms"`
+	P50   float64 `json:"p50_ms"`
+	P90   float64 `json:"p90_ms"`
+	P99   float64 `json:"p99_ms"`
+	P999  float64 `json:"p999_ms"`
+	Max   float64 `json:"max_ms"`
+}
+
+// runBench 压测：按 join-rate 建立 N 个客户端并分散加入房间，M 个发布者通过 Push-Manager 向房间发布带时间戳的消息，
+// 统计端到端延迟分位数、送达率和各 Connect-Node 的下行吞吐，输出报告。返回是否达到 -bench-min-delivery / -bench-max-p99
+func runBench(cfg benchConfig, sigChan chan os.Signal) bool {
+	if cfg.Clients <= 0 || cfg.Rooms <= 0 || cfg.JoinRate <= 0 || len(cfg.ConnectNodes) == 0 {
+		log.Fatalf("❌ -bench-clients、-bench-rooms、-bench-join-rate 必须大于 0，且至少指定一个 Connect-Node")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-sigChan:
+			log.Printf("👋 收到退出信号，提前结束压测并输出报告...")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	run := &benchRun{
+		cfg:         cfg,
+		runID:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		roomMembers: make([]atomic.Int64, cfg.Rooms),
+	}
+	for _, addr := range cfg.ConnectNodes {
+		run.nodes = append(run.nodes, &benchNode{addr: addr})
+	}
+	startedAt := time.Now()
+
+	log.Printf("🚀 压测开始: run=%s, clients=%d, rooms=%d, join-rate=%d/s, nodes=%v",
+		run.runID, cfg.Clients, cfg.Rooms, cfg.JoinRate, cfg.ConnectNodes)
+
+	clients := run.connectClients(ctx)
+	defer func() {
+		log.Printf("👋 关闭 %d 个客户端...", len(clients))
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+
+	sleepContext(ctx, cfg.Warmup)
+
+	log.Printf("📢 开始发布: publishers=%d, rate=%d/s, duration=%s, payload=%dB",
+		cfg.Publishers, cfg.PublishRate, cfg.Duration, cfg.Payload)
+	publishStart := time.Now()
+	run.publish(ctx)
+	publishElapsed := time.Since(publishStart)
+
+	log.Printf("⏳ 等待在途消息 %s...", cfg.Drain)
+	sleepContext(ctx, cfg.Drain)
+
+	report := run.report(startedAt, publishStart, publishElapsed)
+	printBenchReport(report)
+	if cfg.ReportPath != "" {
+		if err := writeBenchReport(cfg.ReportPath, report); err != nil {
+			log.Printf("❌ 写入报告失败: %v", err)
+		} else {
+			log.Printf("📝 报告已写入: %s", cfg.ReportPath)
+		}
+	}
+	return report.Passed
+}
+
+// connectClients 按 join-rate 建立客户端并加入房间（客户端 i 连接 nodes[i%len]，加入 room i%rooms）
+func (r *benchRun) connectClients(ctx context.Context) []*GettyWebSocketClient {
+	var (
+		mu      sync.Mutex
+		clients = make([]*GettyWebSocketClient, 0, r.cfg.Clients)
+		wg      sync.WaitGroup
+	)
+	ticker := time.NewTicker(time.Second / time.Duration(r.cfg.JoinRate))
+	defer ticker.Stop()
+
+	for i := 0; i < r.cfg.Clients; i++ {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return clients
+		case <-ticker.C:
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if client := r.connectClient(i); client != nil {
+				mu.Lock()
+				clients = append(clients, client)
+				mu.Unlock()
+			}
+		}(i)
+
+		if (i+1)%1000 == 0 {
+			log.Printf("🔌 已发起 %d/%d 个连接", i+1, r.cfg.Clients)
+		}
+	}
+	wg.Wait()
+
+	joined := int64(0)
+	for i := range r.roomMembers {
+		joined += r.roomMembers[i].Load()
+	}
+	log.Printf("✅ 连接完成: connected=%d, joined=%d, failed=%d", len(clients), joined, r.cfg.Clients-len(clients))
+	return clients
+}
+
+// connectClient 建立一个客户端并发送加入房间请求，加入房间响应由 onMessage 统计
+func (r *benchRun) connectClient(i int) *GettyWebSocketClient {
+	node := r.nodes[i%len(r.nodes)]
+	room := i % r.cfg.Rooms
+	roomID := r.roomID(room)
+	userID := fmt.Sprintf("bench-%s-%d", r.runID, i)
+
+	var joined atomic.Bool
+	var joinSentAt atomic.Int64
+	client, err := NewGettyWebSocketClientWithHandler(node.addr, userID, userID, roomID, func(p *protocol.Proto) {
+		// 加入房间响应与推送消息同为 op 2，按响应体区分
+		if isReply, rejected, _ := protocol.JoinRoomReply(p); isReply && !joined.Load() {
+			joined.Store(true)
+			if rejected {
+				r.joinFailed.Add(1)
+				return
+			}
+			r.roomMembers[room].Add(1)
+			r.recordJoin(time.Since(time.Unix(0, joinSentAt.Load())))
+			return
+		}
+		r.onMessage(node, p)
+	})
+	if err != nil {
+		node.failed.Add(1)
+		return nil
+	}
+	node.clients.Add(1)
+	joinSentAt.Store(time.Now().UnixNano())
+	if err := client.JoinRoom(); err != nil {
+		r.joinFailed.Add(1)
+	}
+	return client
+}
+
+// onMessage 统计收到的压测消息
+func (r *benchRun) onMessage(node *benchNode, p *protocol.Proto) {
+	var msg benchMessage
+	if p.Op != protocol.OpSendMsg || json.Unmarshal(p.Body, &msg) != nil || msg.Run != r.runID {
+		r.stray.Add(1)
+		return
+	}
+	now := time.Now()
+	node.received.Add(1)
+	node.lastRecvd.Store(now.UnixNano())
+	r.received.Add(1)
+
+	r.mu.Lock()
+	r.latencies = append(r.latencies, now.Sub(time.Unix(0, msg.SentAt)))
+	r.mu.Unlock()
+}
+
+func (r *benchRun) recordJoin(d time.Duration) {
+	r.mu.Lock()
+	r.joinLatencies = append(r.joinLatencies, d)
+	r.mu.Unlock()
+}
+
+// roomID 压测房间 ID
+func (r *benchRun) roomID(room int) string {
+	return fmt.Sprintf("bench-%s-room-%d", r.runID, room)
+}
+
+// publish 发布者轮流向各房间发布消息，直到 duration 结束或收到退出信号
+func (r *benchRun) publish(ctx context.Context) {
+	if r.cfg.Publishers <= 0 || r.cfg.PublishRate <= 0 {
+		return
+	}
+	pushClient, err := NewPushManagerClient(r.cfg.PushManager)
+	if err != nil {
+		log.Printf("❌ 连接 Push-Manager 失败: %v", err)
+		return
+	}
+	defer pushClient.Close()
+
+	// 发布时长结束后不再发起新的发布，在途请求仍使用 ctx（避免把已发出的消息计为失败）
+	publishCtx, cancel := context.WithTimeout(ctx, r.cfg.Duration)
+	defer cancel()
+
+	pad := strings.Repeat("x", r.cfg.Payload)
+	var seq atomic.Int64
+	var wg sync.WaitGroup
+	// 每个发布者的发布间隔使全部发布者合计达到 publish-rate
+	interval := time.Second * time.Duration(r.cfg.Publishers) / time.Duration(r.cfg.PublishRate)
+	if interval <= 0 {
+		interval = time.Microsecond
+	}
+	for i := 0; i < r.cfg.Publishers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-publishCtx.Done():
+					return
+				case <-ticker.C:
+				}
+				n := seq.Add(1)
+				r.publishOne(ctx, pushClient, int(n%int64(r.cfg.Rooms)), n, pad)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// publishOne 发布一条消息，应送达数为发布时房间内已加入的客户端数
+func (r *benchRun) publishOne(ctx context.Context, pushClient *PushManagerClient, room int, seq int64, pad string) {
+	body, _ := json.Marshal(benchMessage{Run: r.runID, Seq: seq, SentAt: time.Now().UnixNano(), Pad: pad})
+	members := r.roomMembers[room].Load()
+
+	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := pushClient.client.BroadcastToRoom(reqCtx, &broadcast.BroadCastRoomReq{
+		RoomId: r.roomID(room),
+		Proto: &protocol.Proto{
+			Ver:    1,
+			Op:     protocol.OpSendMsg,
+			Seq:    int32(seq),
+			Roomid: r.roomID(room),
+			Body:   body,
+		},
+	})
+	if err != nil || resp.Code != "0" {
+		if r.publishFailed.Add(1) <= 10 {
+			if err == nil {
+				err = fmt.Errorf("%s: %s", resp.Msg, resp.Desc)
+			}
+			log.Printf("❌ 发布失败: room=%s, err=%v", r.roomID(room), err)
+		}
+		return
+	}
+	r.published.Add(1)
+	r.expected.Add(members)
+}
+
+// report 汇总压测结果
+func (r *benchRun) report(startedAt, publishStart time.Time, publishElapsed time.Duration) *benchReport {
+	r.mu.Lock()
+	latencies := append([]time.Duration(nil), r.latencies...)
+	joinLatencies := append([]time.Duration(nil), r.joinLatencies...)
+	r.mu.Unlock()
+
+	report := &benchReport{
+		Config:    r.cfg,
+		StartedAt: startedAt,
+		Latency:   newLatencyStats(latencies),
+		Passed:    true,
+	}
+
+	var joined int64
+	for i := range r.roomMembers {
+		joined += r.roomMembers[i].Load()
+	}
+	report.Connections = benchConnStats{
+		Attempted:  r.cfg.Clients,
+		Joined:     joined,
+		JoinFailed: r.joinFailed.Load(),
+		JoinTime:   newLatencyStats(joinLatencies),
+	}
+
+	for _, node := range r.nodes {
+		nr := benchNodeReport{
+			Address:  node.addr,
+			Clients:  node.clients.Load(),
+			Failed:   node.failed.Load(),
+			Received: node.received.Load(),
+		}
+		// 吞吐按从开始发布到该节点最后一次收到消息计算
+		if last := node.lastRecvd.Load(); last > 0 {
+			if elapsed := time.Unix(0, last).Sub(publishStart); elapsed > 0 {
+				nr.Throughput = float64(nr.Received) / elapsed.Seconds()
+			}
+		}
+		report.Connections.Connected += nr.Clients
+		report.Connections.Failed += nr.Failed
+		report.Nodes = append(report.Nodes, nr)
+	}
+
+	report.Publish = benchPublishStats{
+		Published: r.published.Load(),
+		Failed:    r.publishFailed.Load(),
+		Expected:  r.expected.Load(),
+		Received:  r.received.Load(),
+		Stray:     r.stray.Load(),
+	}
+	if publishElapsed > 0 {
+		report.Publish.Rate = float64(report.Publish.Published) / publishElapsed.Seconds()
+	}
+	if report.Publish.Expected > 0 {
+		report.Publish.DeliveryRatio = float64(report.Publish.Received) / float64(report.Publish.Expected)
+	}
+
+	if r.cfg.MinDelivery > 0 && report.Publish.DeliveryRatio < r.cfg.MinDelivery {
+		report.Failures = append(report.Failures, fmt.Sprintf("送达率 %.4f 低于 %.4f", report.Publish.DeliveryRatio, r.cfg.MinDelivery))
+	}
+	if r.cfg.MaxP99 > 0 && report.Latency.P99 > durationMillis(r.cfg.MaxP99) {
+		report.Failures = append(report.Failures, fmt.Sprintf("P99 延迟 %.2fms 超过 %s", report.Latency.P99, r.cfg.MaxP99))
+	}
+	report.Passed = len(report.Failures) == 0
+	return report
+}
+
+// newLatencyStats 计算延迟分布
+func newLatencyStats(samples []time.Duration) latencyStats {
+	if len(samples) == 0 {
+		return latencyStats{}
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	var sum time.Duration
+	for _, d := range samples {
+		sum += d
+	}
+	return latencyStats{
+		Count: int64(len(samples)),
+		Mean:  durationMillis(sum / time.Duration(len(samples))),
+		P50:   durationMillis(percentile(samples, 0.50)),
+		P90:   durationMillis(percentile(samples, 0.90)),
+		P99:   durationMillis(percentile(samples, 0.99)),
+		P999:  durationMillis(percentile(samples, 0.999)),
+		Max:   durationMillis(samples[len(samples)-1]),
+	}
+}
+
+// percentile 已排序样本的分位数（nearest-rank：第 ⌈n·q⌉ 个样本，减去误差容忍浮点乘法的舍入）
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	idx := int(math.Ceil(float64(len(sorted))*q-1e-9)) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// printBenchReport 输出压测报告
+func printBenchReport(report *benchReport) {
+	c, p, l := report.Connections, report.Publish, report.Latency
+	log.Printf("")
+	log.Printf("====================================")
+	log.Printf("   压测报告")
+	log.Printf("====================================")
+	log.Printf("连接: attempted=%d, connected=%d, failed=%d, joined=%d, join_failed=%d",
+		c.Attempted, c.Connected, c.Failed, c.Joined, c.JoinFailed)
+	log.Printf("加入耗时(ms): p50=%.2f, p90=%.2f, p99=%.2f, max=%.2f", c.JoinTime.P50, c.JoinTime.P90, c.JoinTime.P99, c.JoinTime.Max)
+	log.Printf("发布: published=%d, failed=%d, rate=%.1f/s", p.Published, p.Failed, p.Rate)
+	log.Printf("送达: expected=%d, received=%d, ratio=%.4f, stray=%d", p.Expected, p.Received, p.DeliveryRatio, p.Stray)
+	log.Printf("端到端延迟(ms): mean=%.2f, p50=%.2f, p90=%.2f, p99=%.2f, p99.9=%.2f, max=%.2f",
+		l.Mean, l.P50, l.P90, l.P99, l.P999, l.Max)
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tCLIENTS\tFAILED\tRECEIVED\tMSG/S")
+	for _, n := range report.Nodes {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1f\n", n.Address, n.Clients, n.Failed, n.Received, n.Throughput)
+	}
+	w.Flush()
+	log.Printf("各节点下行:\n%s", b.String())
+
+	if report.Passed {
+		log.Printf("✅ 压测通过")
+	} else {
+		for _, f := range report.Failures {
+			log.Printf("❌ %s", f)
+		}
+	}
+}
+
+// writeBenchReport 以 JSON 写入报告
+func writeBenchReport(path string, report *benchReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// sleepContext 等待 d，ctx 取消时提前返回 false
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	samples := make([]time.Duration, 100)
+	for i := range samples {
+		samples[i] = time.Duration(i+1) * time.Millisecond
+	}
+	tests := []struct {
+		sorted []time.Duration
+		q      float64
+		want   time.Duration
+	}{
+		{samples, 0.50, 50 * time.Millisecond},
+		{samples, 0.90, 90 * time.Millisecond},
+		{samples, 0.99, 99 * time.Millisecond},
+		{samples, 0.999, 100 * time.Millisecond},
+		{samples, 1, 100 * time.Millisecond},
+		{samples, 0, time.Millisecond},
+		{samples[:10], 0.51, 6 * time.Millisecond},
+		{samples[:10], 0.50, 5 * time.Millisecond},
+		{samples[:1], 0.99, time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := percentile(tt.sorted, tt.q); got != tt.want {
+			t.Errorf("percentile(%d samples, %v) = %v, want %v", len(tt.sorted), tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestNewLatencyStats(t *testing.T) {
+	if got := newLatencyStats(nil); got != (latencyStats{}) {
+		t.Errorf("newLatencyStats(nil) = %+v, want zero", got)
+	}
+	got := newLatencyStats([]time.Duration{4 * time.Millisecond, time.Millisecond, 3 * time.Millisecond, 2 * time.Millisecond})
+	want := latencyStats{Count: 4, Mean: 2.5, P50: 2, P90: 4, P99: 4, P999: 4, Max: 4}
+	if got != want {
+		t.Errorf("newLatencyStats() = %+v, want %+v", got, want)
+	}
+}
@@ -26,33 +26,55 @@ import (
 	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
 )
 
+// gettyConnectTimeout 等待 WebSocket 连接建立的超时
+const gettyConnectTimeout = 5 * time.Second
+
 // GettyWebSocketClient Getty WebSocket 客户端
 type GettyWebSocketClient struct {
+	client     getty.Client
 	session    getty.Session
 	userID     string
 	userName   string
 	roomID     string
 	done       chan struct{}
 	closeOnce  sync.Once
+	ready      chan struct{}
+	readyOnce  sync.Once
 	mu         sync.RWMutex
+
+	// handler 不为空时由 handler 处理收到的全部消息，并且不输出连接和消息日志（压测时使用）
+	handler func(*protocol.Proto)
 }
 
 // NewGettyWebSocketClient 创建 Getty WebSocket 客户端
 func NewGettyWebSocketClient(addr, userID, userName, roomID string) (*GettyWebSocketClient, error) {
+	return newGettyWebSocketClient(addr, userID, userName, roomID, nil)
+}
+
+// NewGettyWebSocketClientWithHandler 创建由 handler 处理收到消息的 Getty WebSocket 客户端
+func NewGettyWebSocketClientWithHandler(addr, userID, userName, roomID string, handler func(*protocol.Proto)) (*GettyWebSocketClient, error) {
+	return newGettyWebSocketClient(addr, userID, userName, roomID, handler)
+}
+
+func newGettyWebSocketClient(addr, userID, userName, roomID string, handler func(*protocol.Proto)) (*GettyWebSocketClient, error) {
 	// Getty 要求地址格式为 ws://host:port/path
 	if len(addr) > 0 && addr[:5] != "ws://" && addr[:6] != "wss://" {
 		addr = "ws://" + addr + "/connect"
 	}
 
-	log.Printf("🔌 连接到 Connect-Node (Getty): %s", addr)
-	log.Printf("   用户: %s (%s)", userName, userID)
-	log.Printf("   房间: %s", roomID)
-
 	client := &GettyWebSocketClient{
 		userID:   userID,
 		userName: userName,
 		roomID:   roomID,
 		done:     make(chan struct{}),
+		ready:    make(chan struct{}),
+		handler:  handler,
+	}
+
+	if !client.quiet() {
+		log.Printf("🔌 连接到 Connect-Node (Getty): %s", addr)
+		log.Printf("   用户: %s (%s)", userName, userID)
+		log.Printf("   房间: %s", roomID)
 	}
 
 	// 创建 Getty WebSocket 客户端
@@ -60,10 +82,13 @@ func NewGettyWebSocketClient(addr, userID, userName, roomID string) (*GettyWebSo
 		getty.WithServerAddress(addr),
 		getty.WithConnectionNumber(1),
 	)
+	client.client = wsClient
 
 	// 设置会话回调
 	wsClient.RunEventLoop(func(session getty.Session) error {
-		log.Printf("✅ Getty Session 创建: %s", session.Stat())
+		if !client.quiet() {
+			log.Printf("✅ Getty Session 创建: %s", session.Stat())
+		}
 
 		// 配置 session
 		session.SetName("pubsub-client")
@@ -89,20 +114,33 @@ func NewGettyWebSocketClient(addr, userID, userName, roomID string) (*GettyWebSo
 		return nil
 	})
 
-	// 等待连接建立
-	time.Sleep(2 * time.Second)
-
-	if client.session == nil {
+	// 等待连接建立（OnOpen）
+	select {
+	case <-client.ready:
+	case <-time.After(gettyConnectTimeout):
+		wsClient.Close()
 		return nil, fmt.Errorf("连接失败: session 未创建")
 	}
 
-	log.Printf("✅ Getty WebSocket 连接成功")
+	if !client.quiet() {
+		log.Printf("✅ Getty WebSocket 连接成功")
+	}
 	return client, nil
 }
 
+// quiet 是否关闭连接和消息日志
+func (c *GettyWebSocketClient) quiet() bool {
+	return c.handler != nil
+}
+
 // OnOpen Getty 会话打开回调
 func (c *GettyWebSocketClient) OnOpen(session getty.Session) error {
-	log.Printf("✅ [Getty] Session 打开: %s", session.Stat())
+	if !c.quiet() {
+		log.Printf("✅ [Getty] Session 打开: %s", session.Stat())
+	}
+	c.readyOnce.Do(func() {
+		close(c.ready)
+	})
 	return nil
 }
 
@@ -113,7 +151,9 @@ func (c *GettyWebSocketClient) OnError(session getty.Session, err error) {
 
 // OnClose Getty 会话关闭回调
 func (c *GettyWebSocketClient) OnClose(session getty.Session) {
-	log.Printf("👋 [Getty] Session 关闭: %s", session.Stat())
+	if !c.quiet() {
+		log.Printf("👋 [Getty] Session 关闭: %s", session.Stat())
+	}
 	// 使用 sync.Once 确保 channel 只关闭一次
 	c.closeOnce.Do(func() {
 		close(c.done)
@@ -128,6 +168,11 @@ func (c *GettyWebSocketClient) OnMessage(session getty.Session, pkg interface{})
 		return
 	}
 
+	if c.handler != nil {
+		c.handler(protoMsg)
+		return
+	}
+
 	log.Printf("📥 [Client] 收到消息: op=%d, seq=%d, roomId=%s, userId=%s, bodyLen=%d",
 		protoMsg.Op, protoMsg.Seq, protoMsg.Roomid, protoMsg.Userid, len(protoMsg.Body))
 
@@ -143,7 +188,9 @@ func (c *GettyWebSocketClient) OnCron(session getty.Session) {
 
 // JoinRoom 加入房间
 func (c *GettyWebSocketClient) JoinRoom() error {
-	log.Printf("🚪 加入房间: %s", c.roomID)
+	if !c.quiet() {
+		log.Printf("🚪 加入房间: %s", c.roomID)
+	}
 
 	c.mu.RLock()
 	session := c.session
@@ -169,7 +216,9 @@ func (c *GettyWebSocketClient) JoinRoom() error {
 		return fmt.Errorf("发送失败: %w", err)
 	}
 
-	log.Printf("✅ 加入房间请求已发送")
+	if !c.quiet() {
+		log.Printf("✅ 加入房间请求已发送")
+	}
 	return nil
 }
 
@@ -232,9 +281,14 @@ func (c *GettyWebSocketClient) handleMessage(msg *protocol.Proto) {
 	}
 }
 
-// Close 关闭连接
+// Close 关闭连接（同时停止 Getty 客户端的自动重连）
 func (c *GettyWebSocketClient) Close() error {
-	log.Printf("👋 关闭 Getty WebSocket 连接")
+	if !c.quiet() {
+		log.Printf("👋 关闭 Getty WebSocket 连接")
+	}
+
+	// 先停止客户端，避免 session 关闭后触发重连
+	c.client.Close()
 
 	c.mu.RLock()
 	session := c.session
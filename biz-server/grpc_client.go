@@ -95,6 +95,31 @@ func (c *PushManagerClient) PushToUser(userID, message string) error {
 	return nil
 }
 
+// RequestClient 向用户的客户端发起请求并等待响应（deviceID 为空时最先响应的会话生效）
+func (c *PushManagerClient) RequestClient(userID, deviceID string, body []byte, timeout time.Duration) ([]byte, error) {
+	log.Printf("📨 向客户端发起请求: userId=%s, deviceId=%s", userID, deviceID)
+
+	// 留出 Push-Manager 路由和返回的时间
+	ctx, cancel := context.WithTimeout(context.Background(), timeout+5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.RequestClient(ctx, &broadcast.RequestClientReq{
+		UserId:    userID,
+		DeviceId:  deviceID,
+		Body:      body,
+		TimeoutMs: timeout.Milliseconds(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	if resp.Code != "0" {
+		return nil, fmt.Errorf("请求失败: msg=%s, desc=%s", resp.Msg, resp.Desc)
+	}
+
+	log.Printf("✅ 收到客户端响应: requestId=%s, connId=%s", resp.RequestId, resp.ConnId)
+	return resp.Body, nil
+}
+
 // GetRoomStats 获取房间统计信息（暂未实现）
 func (c *PushManagerClient) GetRoomStats() error {
 	log.Printf("📊 获取系统统计")
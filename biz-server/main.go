@@ -21,17 +21,34 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
 )
 
 func main() {
 	// 命令行参数
-	mode := flag.String("mode", "both", "运行模式: ws (WebSocket客户端), grpc (gRPC客户端), both (两者都运行)")
-	connectNodeAddr := flag.String("connect-node", "localhost:8083", "Connect-Node 地址 (host:port)")
+	mode := flag.String("mode", "both", "运行模式: ws (WebSocket客户端), grpc (gRPC客户端), both (两者都运行), webhook (本地 webhook 接收方), bench (压测)")
+	connectNodeAddr := flag.String("connect-node", "localhost:8083", "Connect-Node 地址 (host:port)，bench 模式可以用逗号分隔多个节点")
 	pushManagerAddr := flag.String("push-manager", "localhost:50053", "Push-Manager gRPC 地址")
 	userID := flag.String("user-id", "user-001", "用户 ID")
 	userName := flag.String("user-name", "测试用户", "用户名称")
 	roomID := flag.String("room-id", "room-001", "房间 ID")
 	message := flag.String("message", "Hello from Biz-Server!", "要广播的消息")
+	webhookAddr := flag.String("webhook-addr", "localhost:8090", "webhook 模式的监听地址")
+	webhookSecret := flag.String("webhook-secret", "", "webhook 签名密钥（与 Controller 配置一致）")
+	webhookFail := flag.Int("webhook-fail", 0, "webhook 模式下前 N 个请求返回 500（测试重试）")
+	benchClients := flag.Int("bench-clients", 100, "bench 模式的客户端数")
+	benchRooms := flag.Int("bench-rooms", 10, "bench 模式的房间数（客户端平均分配）")
+	benchJoinRate := flag.Int("bench-join-rate", 50, "bench 模式每秒建立的连接数")
+	benchPublishers := flag.Int("bench-publishers", 4, "bench 模式的并发发布者数")
+	benchPublishRate := flag.Int("bench-publish-rate", 100, "bench 模式全部发布者每秒发布的消息数")
+	benchPayload := flag.Int("bench-payload", 128, "bench 模式消息体填充字节数")
+	benchWarmup := flag.Duration("bench-warmup", 2*time.Second, "bench 模式全部客户端加入后、开始发布前的等待时间")
+	benchDuration := flag.Duration("bench-duration", 30*time.Second, "bench 模式的发布时长")
+	benchDrain := flag.Duration("bench-drain", 5*time.Second, "bench 模式停止发布后等待在途消息的时间")
+	benchReport := flag.String("bench-report", "", "bench 模式 JSON 报告的输出路径")
+	benchMinDelivery := flag.Float64("bench-min-delivery", 0, "bench 模式送达率低于该值时以非 0 退出（如 0.999）")
+	benchMaxP99 := flag.Duration("bench-max-p99", 0, "bench 模式 P99 延迟超过该值时以非 0 退出（如 200ms）")
 	flag.Parse()
 
 	log.Printf("====================================")
@@ -52,8 +69,30 @@ func main() {
 		runGRPCClient(*pushManagerAddr, *roomID, *userID, *message)
 	case "both":
 		runBothClients(*connectNodeAddr, *pushManagerAddr, *userID, *userName, *roomID, *message, sigChan)
+	case "webhook":
+		runWebhookReceiver(*webhookAddr, *webhookSecret, *webhookFail, sigChan)
+	case "bench":
+		passed := runBench(benchConfig{
+			ConnectNodes: pkg.SplitList(*connectNodeAddr),
+			PushManager:  *pushManagerAddr,
+			Clients:      *benchClients,
+			Rooms:        *benchRooms,
+			JoinRate:     *benchJoinRate,
+			Publishers:   *benchPublishers,
+			PublishRate:  *benchPublishRate,
+			Payload:      *benchPayload,
+			Warmup:       *benchWarmup,
+			Duration:     *benchDuration,
+			Drain:        *benchDrain,
+			ReportPath:   *benchReport,
+			MinDelivery:  *benchMinDelivery,
+			MaxP99:       *benchMaxP99,
+		}, sigChan)
+		if !passed {
+			os.Exit(1)
+		}
 	default:
-		log.Fatalf("❌ 未知模式: %s (支持: ws, grpc, both)", *mode)
+		log.Fatalf("❌ 未知模式: %s (支持: ws, grpc, both, webhook, bench)", *mode)
 	}
 }
 
@@ -0,0 +1,89 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+)
+
+// webhookTolerance 签名时间戳允许的偏差
+const webhookTolerance = 5 * time.Minute
+
+// runWebhookReceiver 本地 webhook 接收方（Controller webhook 的测试替身）：校验签名并打印事件，
+// failFirst > 0 时前 failFirst 个请求返回 500，用于观察 Controller 的重试和失败记录
+func runWebhookReceiver(addr, secret string, failFirst int, sigChan chan os.Signal) {
+	var received atomic.Int64
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if secret != "" {
+			if err := pkg.VerifyWebhook(secret, r.Header.Get(pkg.WebhookSignatureHeader), body, webhookTolerance); err != nil {
+				log.Printf("🚫 签名校验失败: delivery=%s, err=%v", r.Header.Get(pkg.WebhookDeliveryHeader), err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+
+		n := received.Add(1)
+		if n <= int64(failFirst) {
+			log.Printf("💥 模拟失败 (%d/%d): delivery=%s", n, failFirst, r.Header.Get(pkg.WebhookDeliveryHeader))
+			http.Error(w, "simulated failure", http.StatusInternalServerError)
+			return
+		}
+
+		var event map[string]interface{}
+		if err := json.Unmarshal(body, &event); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("🪝 收到事件: type=%s, delivery=%s, body=%s",
+			r.Header.Get(pkg.WebhookEventHeader), r.Header.Get(pkg.WebhookDeliveryHeader), body)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("❌ webhook 接收方启动失败: %v", err)
+		}
+	}()
+
+	log.Printf("✅ webhook 接收方运行中: http://%s/webhook", addr)
+	if secret == "" {
+		log.Printf("⚠️  未设置 -webhook-secret，不校验签名")
+	}
+	log.Printf("📝 按 Ctrl+C 退出")
+
+	<-sigChan
+	log.Printf("👋 收到退出信号，已接收 %d 个请求", received.Load())
+	server.Close()
+}
@@ -0,0 +1,218 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/admin"
+)
+
+const (
+	// adminDefaultSessions / adminMaxSessions ListSessions 每个节点返回的会话数
+	adminDefaultSessions = 100
+	adminMaxSessions     = 1000
+	// adminDefaultTopRooms GetDistribution 默认输出的房间数
+	adminDefaultTopRooms = 20
+)
+
+// nodeAdminServer 本节点的运维接口（由 Controller 的 AdminService 调用）
+type nodeAdminServer struct {
+	admin.UnimplementedNodeAdminServer
+
+	server *ConnectNodeServer
+}
+
+func newNodeAdminServer(server *ConnectNodeServer) *nodeAdminServer {
+	return &nodeAdminServer{server: server}
+}
+
+// GetNodeStatus 节点实时状态
+func (a *nodeAdminServer) GetNodeStatus(ctx context.Context, req *admin.GetNodeStatusRequest) (*admin.NodeStatus, error) {
+	s := a.server
+	status := &admin.NodeStatus{
+		NodeId:    s.nodeID,
+		Draining:  s.draining.Load(),
+		Buckets:   int32(len(s.buckets)),
+		StartedAt: s.startedAt.Unix(),
+	}
+	for _, bucket := range s.buckets {
+		status.Rooms += int32(bucket.RoomCount())
+		for _, ch := range bucket.Sessions() {
+			status.Sessions++
+			status.QueueDepth += int64(ch.QueueDepth())
+		}
+	}
+	return status, nil
+}
+
+// ListSessions 列出本节点的会话（按连接时间排序）
+func (a *nodeAdminServer) ListSessions(ctx context.Context, req *admin.ListSessionsRequest) (*admin.ListSessionsResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = adminDefaultSessions
+	}
+	if limit > adminMaxSessions {
+		limit = adminMaxSessions
+	}
+
+	var sessions []*admin.Session
+	for _, bucket := range a.server.buckets {
+		var chs []*Channel
+		if req.UserId != "" {
+			chs = bucket.UserChannels(req.UserId)
+		} else {
+			chs = bucket.Sessions()
+		}
+		for _, ch := range chs {
+			if !sessionMatches(ch, req) {
+				continue
+			}
+			sessions = append(sessions, a.sessionToProto(ch))
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].ConnectedAt < sessions[j].ConnectedAt
+	})
+	resp := &admin.ListSessionsResponse{Sessions: sessions}
+	if len(sessions) > limit {
+		resp.Sessions = sessions[:limit]
+		resp.Truncated = true
+	}
+	return resp, nil
+}
+
+// DisconnectSession 强制断开用户的会话（客户端按正常断线处理，可重连）
+func (a *nodeAdminServer) DisconnectSession(ctx context.Context, req *admin.DisconnectSessionRequest) (*admin.DisconnectSessionResponse, error) {
+	if req.UserId == "" {
+		return &admin.DisconnectSessionResponse{Success: false, Message: "user_id 不能为空"}, nil
+	}
+
+	var disconnected int32
+	for _, ch := range a.server.Bucket(req.UserId).UserChannels(req.UserId) {
+		if req.DeviceId != "" && ch.DeviceID != req.DeviceId {
+			continue
+		}
+		if req.ConnId != "" && ch.ConnID != req.ConnId {
+			continue
+		}
+		log.Printf("🔌 [ConnectNodeServer] 运维断开会话: user=%s, device=%s, conn=%s, reason=%s", ch.UserID, ch.DeviceID, ch.ConnID, req.Reason)
+		ch.Close()
+		disconnected++
+	}
+	return &admin.DisconnectSessionResponse{Success: true, Message: "OK", Disconnected: disconnected}, nil
+}
+
+// GetDistribution 各 bucket 的会话/用户/房间数，以及在线人数最多的房间
+func (a *nodeAdminServer) GetDistribution(ctx context.Context, req *admin.GetDistributionRequest) (*admin.NodeDistribution, error) {
+	top := int(req.TopRooms)
+	if top <= 0 {
+		top = adminDefaultTopRooms
+	}
+
+	dist := &admin.NodeDistribution{NodeId: a.server.nodeID}
+	rooms := make(map[string]*admin.RoomLoad)
+	for i, bucket := range a.server.buckets {
+		counts := bucket.RoomsCount()
+		dist.Buckets = append(dist.Buckets, &admin.BucketStats{
+			Index:    int32(i),
+			Sessions: int32(bucket.ChannelCount()),
+			Users:    int32(bucket.UserCount()),
+			Rooms:    int32(bucket.RoomCount()),
+		})
+		// 同一房间的成员按用户分布在多个 bucket 中
+		for roomID, online := range counts {
+			load, ok := rooms[roomID]
+			if !ok {
+				load = &admin.RoomLoad{RoomId: roomID}
+				rooms[roomID] = load
+			}
+			load.Online += online
+			load.Buckets++
+		}
+	}
+
+	dist.TotalRooms = int32(len(rooms))
+	dist.TopRooms = topRooms(rooms, top)
+	return dist, nil
+}
+
+// SetDrain 开启/关闭 drain 模式
+func (a *nodeAdminServer) SetDrain(ctx context.Context, req *admin.SetDrainRequest) (*admin.SetDrainResponse, error) {
+	s := a.server
+	if s.draining.Swap(req.Drain) != req.Drain {
+		if req.Drain {
+			log.Printf("🚧 [ConnectNodeServer] 进入 drain 模式，不再接受新连接: node=%s", s.nodeID)
+		} else {
+			log.Printf("✅ [ConnectNodeServer] 退出 drain 模式: node=%s", s.nodeID)
+		}
+	}
+
+	var sessions int32
+	for _, bucket := range s.buckets {
+		sessions += int32(bucket.ChannelCount())
+	}
+	return &admin.SetDrainResponse{Success: true, Message: "OK", Draining: req.Drain, Sessions: sessions}, nil
+}
+
+// sessionMatches 会话是否满足过滤条件
+func sessionMatches(ch *Channel, req *admin.ListSessionsRequest) bool {
+	if req.AppId != "" && ch.AppID != req.AppId {
+		return false
+	}
+	if req.RoomId != "" && (ch.Room == nil || ch.Room.ID != req.RoomId) {
+		return false
+	}
+	return true
+}
+
+func (a *nodeAdminServer) sessionToProto(ch *Channel) *admin.Session {
+	session := &admin.Session{
+		NodeId:      a.server.nodeID,
+		AppId:       ch.AppID,
+		UserId:      ch.UserID,
+		DeviceId:    ch.DeviceID,
+		DeviceType:  ch.DeviceType,
+		ConnId:      ch.ConnID,
+		Ip:          ch.IP,
+		ConnectedAt: ch.ConnectedAt.Unix(),
+		QueueDepth:  int32(ch.QueueDepth()),
+	}
+	if room := ch.Room; room != nil {
+		session.RoomId = room.ID
+	}
+	return session
+}
+
+// topRooms 在线人数最多的 n 个房间
+func topRooms(rooms map[string]*admin.RoomLoad, n int) []*admin.RoomLoad {
+	loads := make([]*admin.RoomLoad, 0, len(rooms))
+	for _, load := range rooms {
+		loads = append(loads, load)
+	}
+	sort.Slice(loads, func(i, j int) bool {
+		if loads[i].Online != loads[j].Online {
+			return loads[i].Online > loads[j].Online
+		}
+		return loads[i].RoomId < loads[j].RoomId
+	})
+	if len(loads) > n {
+		loads = loads[:n]
+	}
+	return loads
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/config"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/push"
@@ -11,26 +12,36 @@ import (
 
 type Bucket struct {
 	c     *config.BucketConfig
-	cLock sync.RWMutex        // protect the channels for chs
-	chs   map[string]*Channel // map sub key to a channel
+	cLock sync.RWMutex                   // protect the channels for chs
+	chs   map[string]*Channel            // map sub key to a channel
+	users map[string]map[string]*Channel // userId -> session key -> channel（多设备）
 	// room
-	rooms       map[string]*Room // bucket room channels
-	routines    []chan *push.BroadcastRoomReq
+	rooms       map[string]*Room                     // bucket room channels
+	routines    []*pkg.Lanes[*push.BroadcastRoomReq] // 房间广播按优先级分道
 	routinesNum uint64
 
 	ipCnts map[string]int32
+
+	// 主题订阅前缀树
+	topics *TopicTrie
+
+	// 房间广播排队期间过期时回调（记录指标）
+	onExpired func(stage, kind string)
 }
 
-func NewBucket(c *config.BucketConfig) (b *Bucket) {
+func NewBucket(c *config.BucketConfig, onExpired func(stage, kind string)) (b *Bucket) {
 
 	b = new(Bucket)
 	b.chs = make(map[string]*Channel, c.Channel)
+	b.users = make(map[string]map[string]*Channel)
 	b.ipCnts = make(map[string]int32)
 	b.c = c
+	b.onExpired = onExpired
+	b.topics = NewTopicTrie()
 	b.rooms = make(map[string]*Room, c.Room)
-	b.routines = make([]chan *push.BroadcastRoomReq, c.RoutineAmount)
+	b.routines = make([]*pkg.Lanes[*push.BroadcastRoomReq], c.RoutineAmount)
 	for i := uint64(0); i < c.RoutineAmount; i++ {
-		c := make(chan *push.BroadcastRoomReq, c.RoutineSize)
+		c := pkg.NewLanes[*push.BroadcastRoomReq](c.RoutineSize, pkg.DefaultStarvationLimit)
 		b.routines[i] = c
 		go b.roomprc(c) // 启动房间广播处理 goroutine
 	}
@@ -112,11 +123,20 @@ func (b *Bucket) Put(roomId string, channel *Channel) (err error) {
 
 	b.cLock.Lock()
 
+	// 同一设备重连时替换旧会话，其他设备的会话不受影响
 	if oldChannel := b.chs[channel.Key]; oldChannel != nil {
 		oldChannel.Close()
 	}
 
 	b.chs[channel.Key] = channel
+	if channel.UserID != "" {
+		sessions, ok := b.users[channel.UserID]
+		if !ok {
+			sessions = make(map[string]*Channel)
+			b.users[channel.UserID] = sessions
+		}
+		sessions[channel.Key] = channel
+	}
 
 	if roomId != "" {
 		if room, ok = b.rooms[roomId]; !ok {
@@ -137,13 +157,19 @@ func (b *Bucket) Put(roomId string, channel *Channel) (err error) {
 }
 
 func (b *Bucket) Del(dch *Channel) {
-	room := dch.Room
-
 	b.cLock.Lock()
+	// 在锁内读取房间，与 JoinRoom 互斥
+	room := dch.Room
 
 	if ch, ok := b.chs[dch.Key]; ok {
 		if ch == dch {
 			delete(b.chs, ch.Key)
+			if sessions, ok := b.users[ch.UserID]; ok {
+				delete(sessions, ch.Key)
+				if len(sessions) == 0 {
+					delete(b.users, ch.UserID)
+				}
+			}
 		}
 
 		if b.ipCnts[ch.IP] > 1 {
@@ -155,6 +181,8 @@ func (b *Bucket) Del(dch *Channel) {
 
 	b.cLock.Unlock()
 
+	b.UnsubscribeTopics(dch, dch.Topics())
+
 	if room != nil && room.Del(dch) {
 		// if room channel is empty , then drop room
 		b.DelRoom(room)
@@ -162,6 +190,49 @@ func (b *Bucket) Del(dch *Channel) {
 
 }
 
+// JoinRoom Controller 接受加入房间后将会话放入房间：鉴权时只注册会话，被房间策略拒绝的会话不在房间内，收不到房间广播。
+// 会话已被移除（加入期间连接关闭或被同设备的新会话替换）时返回 ErrNotInRoom
+func (b *Bucket) JoinRoom(roomID string, ch *Channel) error {
+	b.cLock.Lock()
+	defer b.cLock.Unlock()
+
+	if b.chs[ch.Key] != ch {
+		return pkg.ErrNotInRoom
+	}
+	if ch.Room != nil {
+		return nil
+	}
+	room, ok := b.rooms[roomID]
+	if !ok {
+		room = NewRoom(roomID)
+		b.rooms[roomID] = room
+	}
+	if err := room.Put(ch); err != nil {
+		return err
+	}
+	ch.Room = room
+	return nil
+}
+
+// Sessions 本 bucket 中的全部会话
+func (b *Bucket) Sessions() []*Channel {
+	b.cLock.RLock()
+	defer b.cLock.RUnlock()
+
+	chs := make([]*Channel, 0, len(b.chs))
+	for _, ch := range b.chs {
+		chs = append(chs, ch)
+	}
+	return chs
+}
+
+// UserCount 本 bucket 中的在线用户数
+func (b *Bucket) UserCount() int {
+	b.cLock.RLock()
+	defer b.cLock.RUnlock()
+	return len(b.users)
+}
+
 func (b *Bucket) Channel(key string) (ch *Channel) {
 	b.cLock.RLock()
 	ch = b.chs[key]
@@ -169,8 +240,23 @@ func (b *Bucket) Channel(key string) (ch *Channel) {
 	return
 }
 
-func (b *Bucket) Broadcast(p *protocol.Proto, op int32) {
-	log.Printf("📢 [Bucket] Broadcast 被调用: op=%d, roomId=%s, 总channels=%d", op, p.Roomid, len(b.chs))
+// UserChannels 用户在本 bucket 中的全部会话
+func (b *Bucket) UserChannels(userID string) []*Channel {
+	b.cLock.RLock()
+	defer b.cLock.RUnlock()
+
+	sessions := b.users[userID]
+	chs := make([]*Channel, 0, len(sessions))
+	for _, ch := range sessions {
+		chs = append(chs, ch)
+	}
+	return chs
+}
+
+// Broadcast 推送给本 bucket 中属于租户 appID 的连接（消息指定 roomId 时只推送给该房间）
+func (b *Bucket) Broadcast(appID string, p *protocol.Proto, op int32, priority protocol.Priority, expireAt int64) {
+	log.Printf("📢 [Bucket] Broadcast 被调用: app=%s, op=%d, roomId=%s, 总channels=%d", appID, op, p.Roomid, len(b.chs))
+	roomID := pkg.ScopeID(appID, p.Roomid)
 	
 	var ch *Channel
 	matchedCount := 0
@@ -179,6 +265,9 @@ func (b *Bucket) Broadcast(p *protocol.Proto, op int32) {
 	
 	b.cLock.RLock()
 	for _, ch = range b.chs {
+		if ch.AppID != appID {
+			continue
+		}
 		if !ch.NeedPush(op) {
 			skippedByOp++
 			continue
@@ -186,12 +275,12 @@ func (b *Bucket) Broadcast(p *protocol.Proto, op int32) {
 		
 		// 只有当 channel 的 room 与消息的 roomId 匹配时才推送
 		// 如果消息没有指定 roomId（空字符串），则广播给所有客户端
-		if p.Roomid != "" && ch.Room != nil && ch.Room.ID != p.Roomid {
+		if roomID != "" && (ch.Room == nil || ch.Room.ID != roomID) {
 			skippedByRoom++
 			continue
 		}
 		
-		if err := ch.Push(p); err != nil {
+		if err := ch.Push(p, priority, expireAt); err != nil {
 			log.Printf("⚠️  [Bucket] Push 失败: err=%v", err)
 		} else {
 			matchedCount++
@@ -210,9 +299,9 @@ func (b *Bucket) Room(roomId string) (room *Room) {
 }
 
 func (b *Bucket) DelRoom(room *Room) {
-	b.cLock.RLock()
+	b.cLock.Lock()
 	delete(b.rooms, room.ID)
-	b.cLock.RUnlock()
+	b.cLock.Unlock()
 	room.Close()
 }
 
@@ -221,10 +310,41 @@ func (b *Bucket) BroadcastRoom(arg *push.BroadcastRoomReq) {
 	num := atomic.AddUint64(&b.routinesNum, 1) % b.c.RoutineAmount
 	log.Printf("🔔 [Bucket] 消息放入 routine %d", num)
 
-	b.routines[num] <- arg
+	b.routines[num].Push(arg.Priority, arg)
 	log.Printf("🔔 [Bucket] 消息已放入 channel")
 }
 
+// SubscribeTopics 订阅主题模式（模式需已校验）
+func (b *Bucket) SubscribeTopics(ch *Channel, patterns []string) error {
+	added, err := ch.AddTopics(patterns)
+	if err != nil {
+		return err
+	}
+	for _, pattern := range added {
+		b.topics.Subscribe(pattern, ch)
+	}
+	return nil
+}
+
+// UnsubscribeTopics 取消订阅主题模式
+func (b *Bucket) UnsubscribeTopics(ch *Channel, patterns []string) {
+	for _, pattern := range ch.RemoveTopics(patterns) {
+		b.topics.Unsubscribe(pattern, ch)
+	}
+}
+
+// BroadcastTopic 推送给本 bucket 中属于发布租户、订阅模式匹配主题的连接
+func (b *Bucket) BroadcastTopic(arg *push.BroadcastTopicReq) {
+	for _, ch := range b.topics.Match(arg.Topic) {
+		if ch.AppID != arg.AppID {
+			continue
+		}
+		if err := ch.Push(arg.Proto, arg.Priority, arg.ExpireAt); err != nil {
+			log.Printf("⚠️  [Bucket] 主题推送失败: topic=%s, key=%s, err=%v", arg.Topic, ch.Key, err)
+		}
+	}
+}
+
 func (b *Bucket) Rooms() (res map[string]struct{}) {
 
 	var (
@@ -263,14 +383,20 @@ func (b *Bucket) UpRoomsCount(roomCountMap map[string]int32) {
 
 }
 
-func (b *Bucket) roomprc(c chan *push.BroadcastRoomReq) {
+func (b *Bucket) roomprc(c *pkg.Lanes[*push.BroadcastRoomReq]) {
 	log.Printf("🚀 [Bucket] roomprc goroutine 已启动")
 	for {
-		arg := <-c
+		arg := c.Pop()
 		log.Printf("📨 [Bucket] roomprc 收到广播请求: roomID=%s", arg.RoomID)
+		if pkg.Expired(arg.ExpireAt) {
+			if b.onExpired != nil {
+				b.onExpired("bucket", "room")
+			}
+			continue
+		}
 		if room := b.Room(arg.RoomID); room != nil {
 			log.Printf("✅ [Bucket] 找到房间，推送消息: roomID=%s", arg.RoomID)
-			room.PushMsg(arg.Proto)
+			room.PushMsg(arg.Proto, arg.Priority, arg.ExpireAt)
 		} else {
 			log.Printf("❌ [Bucket] 房间不存在: roomID=%s", arg.RoomID)
 		}
@@ -4,32 +4,72 @@ import (
 	"github.com/livekit/psrpc/examples/pubsub/pkg"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
 	proto "github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Channel struct {
 	Room           *Room
 	ClientReqQueue Ring
-	signal         chan *protocol.Proto
+	signal         *pkg.Lanes[signalMsg]    // 下行消息按优先级分道，写协程先取高优先级
+	onExpired      func(stage, kind string) // 下行消息过期丢弃时回调（记录指标）
+	closed         atomic.Bool              // Close 之后不再接收下行消息
+	finishing      bool                     // 已取到结束信号，发完积压的消息后结束（只由写协程访问）
 
 	Next *Channel
 	Prev *Channel
 
 	Mid      int64
-	Key      string
+	Key      string // 会话 key: userId/deviceId（同一用户的多个设备互不影响）
 	IP       string
 	watchOps map[int32]struct{}
+	role     string              // 用户在房间中的角色（加入房间时写入，角色变更时刷新）
+	topics   map[string]struct{} // 已订阅的主题模式
 	mutex    sync.RWMutex
+
+	// 会话信息（多设备），UserID 为集群内 ID（租户前缀 + 用户 ID）
+	AppID      string
+	UserID     string
+	DeviceID   string
+	DeviceType string
+	ConnID     string
+
+	// 鉴权成功的时间
+	ConnectedAt time.Time
+}
+
+// SessionKey 用户某个设备会话的 key
+func SessionKey(userID, deviceID string) string {
+	return userID + "/" + deviceID
+}
+
+// SplitSessionKey 拆分会话 key，没有设备部分时 deviceID 为空（表示用户的全部会话）
+func SplitSessionKey(key string) (userID, deviceID string) {
+	if i := strings.IndexByte(key, '/'); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return key, ""
+}
+
+// signalMsg 下行队列中的消息，expireAt 为过期时间（Unix 毫秒，0 表示不过期）
+type signalMsg struct {
+	p        *protocol.Proto
+	expireAt int64
 }
 
-func NewChannel(cli, svr int) *Channel {
+func NewChannel(cli, svr int, onExpired func(stage, kind string)) *Channel {
 	c := new(Channel)
 
 	c.ClientReqQueue.Init(cli)
 
-	c.signal = make(chan *protocol.Proto, svr)
+	c.signal = pkg.NewLanes[signalMsg](svr, pkg.DefaultStarvationLimit)
+	c.onExpired = onExpired
 
 	c.watchOps = make(map[int32]struct{})
+	c.topics = make(map[string]struct{})
 	return c
 }
 
@@ -66,24 +106,115 @@ func (c *Channel) NeedPush(op int32) bool {
 
 }
 
-func (c *Channel) Push(p *protocol.Proto) (err error) {
-	select {
-	case c.signal <- p:
-	default:
+// AddTopics 记录订阅的主题模式，返回新增的模式；超过上限时不做修改
+func (c *Channel) AddTopics(patterns []string) ([]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var added []string
+	for _, pattern := range patterns {
+		if _, ok := c.topics[pattern]; !ok && !slices.Contains(added, pattern) {
+			added = append(added, pattern)
+		}
+	}
+	if len(c.topics)+len(added) > maxChannelTopics {
+		return nil, pkg.ErrTooManyTopics
+	}
+	for _, pattern := range added {
+		c.topics[pattern] = struct{}{}
+	}
+	return added, nil
+}
+
+// RemoveTopics 移除订阅的主题模式，返回实际移除的模式
+func (c *Channel) RemoveTopics(patterns []string) []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var removed []string
+	for _, pattern := range patterns {
+		if _, ok := c.topics[pattern]; ok {
+			delete(c.topics, pattern)
+			removed = append(removed, pattern)
+		}
+	}
+	return removed
+}
+
+// Topics 已订阅的主题模式
+func (c *Channel) Topics() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	topics := make([]string, 0, len(c.topics))
+	for pattern := range c.topics {
+		topics = append(topics, pattern)
+	}
+	return topics
+}
+
+// SetRole 缓存用户在房间中的角色
+func (c *Channel) SetRole(role string) {
+	c.mutex.Lock()
+	c.role = role
+	c.mutex.Unlock()
+}
+
+// Role 用户在房间中的角色
+func (c *Channel) Role() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.role
+}
+
+// Push 按优先级写入下行队列，对应优先级的队列满或 channel 已关闭时丢弃
+func (c *Channel) Push(p *protocol.Proto, priority protocol.Priority, expireAt int64) (err error) {
+	if c.closed.Load() || !c.signal.TryPush(priority, signalMsg{p: p, expireAt: expireAt}) {
 		err = pkg.ErrSignalFullMsgDropped
 	}
 
 	return
 }
 
+// Ready 取出下一条下行消息，排队期间过期的消息直接丢弃。
+// 结束信号走高优先级队列尽快唤醒写协程，但要等各优先级积压的消息都取完后才返回 ProtoFinish
 func (c *Channel) Ready() *protocol.Proto {
-	return <-c.signal
+	for {
+		msg, ok := c.signal.TryPop()
+		if !ok {
+			if c.finishing {
+				return proto.ProtoFinish
+			}
+			<-c.signal.Wait()
+			continue
+		}
+		switch {
+		case msg.p == proto.ProtoFinish:
+			c.finishing = true
+		case c.finishing && msg.p == proto.ProtoReady:
+			// 关闭后不再处理客户端请求
+		case !pkg.Expired(msg.expireAt):
+			return msg.p
+		case c.onExpired != nil:
+			c.onExpired("channel", "")
+		}
+	}
+}
+
+// QueueDepth 下行队列中待发送的消息数
+func (c *Channel) QueueDepth() int {
+	return c.signal.Len(protocol.Priority_PRIORITY_HIGH) +
+		c.signal.Len(protocol.Priority_PRIORITY_NORMAL) +
+		c.signal.Len(protocol.Priority_PRIORITY_LOW)
 }
 
+// Signal 控制信号走高优先级队列，不被批量消息阻塞
 func (c *Channel) Signal() {
-	c.signal <- proto.ProtoReady
+	c.signal.Push(protocol.Priority_PRIORITY_HIGH, signalMsg{p: proto.ProtoReady})
 }
 
+// Close 通知写协程发完已入队的消息后结束
 func (c *Channel) Close() {
-	c.signal <- proto.ProtoFinish
+	c.closed.Store(true)
+	c.signal.Push(protocol.Priority_PRIORITY_HIGH, signalMsg{p: proto.ProtoFinish})
 }
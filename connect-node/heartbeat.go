@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"log"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+)
+
+// cpuSampler 通过两次 getrusage 的差值计算进程 CPU 使用率
+type cpuSampler struct {
+	lastCPU  time.Duration
+	lastWall time.Time
+}
+
+// processCPUTime 进程累计 CPU 时间（用户态 + 内核态）
+func processCPUTime() time.Duration {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+}
+
+// Sample 返回距上次采样期间的 CPU 使用率（百分比，按核数归一化）
+func (c *cpuSampler) Sample() float32 {
+	now := time.Now()
+	cpu := processCPUTime()
+	defer func() {
+		c.lastCPU, c.lastWall = cpu, now
+	}()
+
+	if c.lastWall.IsZero() {
+		return 0
+	}
+	wall := now.Sub(c.lastWall)
+	if wall <= 0 {
+		return 0
+	}
+	return float32(float64(cpu-c.lastCPU) / float64(wall) / float64(runtime.NumCPU()) * 100)
+}
+
+// memoryUsageMB 进程从操作系统申请的内存（MB）
+func memoryUsageMB() float32 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return float32(ms.Sys) / (1024 * 1024)
+}
+
+// RunHeartbeat 定期向 Controller 上报节点心跳（连接数、房间数、CPU、内存）
+func (s *ConnectNodeServer) RunHeartbeat(ctx context.Context) {
+	interval := s.config.Node.HeartbeatInterval
+	sampler := &cpuSampler{}
+	sampler.Sample()
+
+	log.Printf("💓 [ConnectNodeServer] 心跳上报启动: node=%s, interval=%v", s.nodeID, interval)
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		if next := s.sendHeartbeat(ctx, sampler); next > 0 {
+			interval = next
+		}
+		timer.Reset(interval)
+	}
+}
+
+// sendHeartbeat 发送一次心跳，返回 Controller 期望的心跳间隔
+func (s *ConnectNodeServer) sendHeartbeat(ctx context.Context, sampler *cpuSampler) time.Duration {
+	var connections, rooms int
+	for _, bucket := range s.buckets {
+		connections += bucket.ChannelCount()
+		rooms += bucket.RoomCount()
+	}
+
+	req := &controller.NodeHeartbeatRequest{
+		NodeId:             s.nodeID,
+		Address:            s.nodeAddress,
+		Region:             s.config.Node.Region,
+		MaxConnections:     int32(s.config.Node.MaxConnections),
+		CurrentConnections: int32(connections),
+		RoomCount:          int32(rooms),
+		CpuUsage:           sampler.Sample(),
+		MemoryUsage:        memoryUsageMB(),
+	}
+
+	rpcCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := s.controllerClient.NodeHeartbeat(rpcCtx, req)
+	if err != nil {
+		log.Printf("⚠️  [ConnectNodeServer] 心跳上报失败: %v", err)
+		return 0
+	}
+	if !resp.Success {
+		log.Printf("⚠️  [ConnectNodeServer] 心跳被拒绝: %s", resp.Message)
+		return 0
+	}
+
+	s.metrics.SetNodeConnections(s.nodeID, int64(connections))
+	return time.Duration(resp.HeartbeatIntervalMs) * time.Millisecond
+}
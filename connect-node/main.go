@@ -17,6 +17,8 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/admin"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/push"
 	"log"
@@ -31,6 +33,7 @@ import (
 	"github.com/livekit/psrpc/examples/pubsub/pkg/etcd"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/metrics"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/tracing"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -73,19 +76,41 @@ func main() {
 	controllerClient := newLogicClient(cfg.config.RpcConfig, cfg.config.ETCD.Endpoints)
 	log.Printf("✅ Controller 客户端创建成功（通过 ETCD 服务发现）\n")
 
+	// 创建 Push-Manager 客户端（用于客户端上行发言）
+	pushClient := newPushServerClient(cfg.config.ETCD.Endpoints)
+
+	// 连接 Redis（订阅房间角色/策略变更）
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.config.Redis.Addr,
+		Password: cfg.config.Redis.Password,
+		DB:       cfg.config.Redis.DB,
+	})
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		log.Printf("⚠️  Redis 连接失败（角色变更不会实时刷新，租户用量不计量）: %v\n", err)
+		redisClient = nil
+	} else {
+		log.Printf("✅ Redis 连接成功\n")
+	}
+
 	// 创建 ConnectNode 服务器
 	connectNodeServer := NewConnectNodeServer(
 		cfg.nodeID,
 		cfg.nodeAddress,
 		cfg.config,
 		controllerClient,
+		pushClient,
+		redisClient,
 		metricsCollector,
 	)
+	go connectNodeServer.WatchRoomEvents(ctx)
+	go connectNodeServer.RunHeartbeat(ctx)
+	go connectNodeServer.RunUsageReport(ctx)
 
 	// 启动 gRPC 服务器（用于接收 Push-Manager 的推送）
 	grpcServer := grpc.NewServer()
 
 	push.RegisterCometServer(grpcServer, connectNodeServer)
+	admin.RegisterNodeAdminServer(grpcServer, newNodeAdminServer(connectNodeServer))
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.grpcPort))
 	if err != nil {
@@ -250,6 +275,30 @@ func newLogicClientNonBlocking(c *config.RpcConfig, etcdEndpoints []string) (con
 
 }
 
+// newPushServerClient 非阻塞创建 Push-Manager 客户端
+func newPushServerClient(etcdEndpoints []string) broadcast.PushServerClient {
+	resolverBuilder, err := etcd.GetETCDResolverBuilder(etcdEndpoints)
+	if err != nil {
+		log.Printf("❌ 获取 ETCD Resolver 失败: %v", err)
+		panic(err)
+	}
+
+	target := fmt.Sprintf("%s:///services/push-manager", resolverBuilder.Scheme())
+	log.Printf("🔗 通过 ETCD 连接 Push-Manager（非阻塞模式）: %s", target)
+
+	conn, err := grpc.Dial(target,
+		grpc.WithResolvers(resolverBuilder),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		log.Printf("❌ 创建 gRPC 连接失败: %v", err)
+		panic(err)
+	}
+
+	log.Printf("✅ Push-Manager 客户端已创建（将在后台建立连接）")
+	return broadcast.NewPushServerClient(conn)
+}
+
 // ConnectNodeConfig 配置
 type ConnectNodeConfig struct {
 	nodeID            string
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/push"
+)
+
+const (
+	// pushStreamCredits 建流时发放的 credit（Push-Manager 最多同时在途的批次数）
+	pushStreamCredits = 16
+)
+
+// PushStream Push-Manager 的长连接推送通道：逐批处理推送命令，每批回复确认并归还一个 credit
+func (s *ConnectNodeServer) PushStream(stream push.Comet_PushStreamServer) error {
+	log.Printf("🔗 [ConnectNodeServer] Push-Manager 推送流已建立")
+
+	if err := stream.Send(&push.PushBatchAck{Credits: pushStreamCredits}); err != nil {
+		return err
+	}
+
+	for {
+		batch, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			log.Printf("👋 [ConnectNodeServer] Push-Manager 推送流已关闭")
+			return nil
+		}
+		if err != nil {
+			log.Printf("⚠️  [ConnectNodeServer] 推送流接收失败: %v", err)
+			return err
+		}
+
+		ack := &push.PushBatchAck{BatchId: batch.BatchId, Credits: 1}
+		for i, cmd := range batch.Commands {
+			if err := s.execPushCommand(stream.Context(), cmd); err != nil {
+				ack.Failed = append(ack.Failed, int32(i))
+				if ack.Error == "" {
+					ack.Error = err.Error()
+				}
+			}
+		}
+
+		if err := stream.Send(ack); err != nil {
+			log.Printf("⚠️  [ConnectNodeServer] 推送流发送确认失败: %v", err)
+			return err
+		}
+	}
+}
+
+// execPushCommand 执行一条推送命令（与对应的一元 RPC 行为一致）
+func (s *ConnectNodeServer) execPushCommand(ctx context.Context, cmd *push.PushCommand) error {
+	var err error
+	switch c := cmd.Command.(type) {
+	case *push.PushCommand_PushMsg:
+		_, err = s.PushMsg(ctx, c.PushMsg)
+	case *push.PushCommand_Broadcast:
+		_, err = s.Broadcast(ctx, c.Broadcast)
+	case *push.PushCommand_BroadcastRoom:
+		_, err = s.BroadcastRoom(ctx, c.BroadcastRoom)
+	case *push.PushCommand_BroadcastTopic:
+		_, err = s.BroadcastTopic(ctx, c.BroadcastTopic)
+	default:
+		err = errors.New("empty push command")
+	}
+	return err
+}
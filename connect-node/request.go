@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	proto "github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/push"
+)
+
+const (
+	// defaultClientRequestTimeout 未指定超时时等待客户端响应的时间
+	defaultClientRequestTimeout = 10 * time.Second
+	// maxClientRequestTimeout 等待客户端响应的最长时间
+	maxClientRequestTimeout = time.Minute
+)
+
+// clientResponse 客户端对服务端请求的响应
+type clientResponse struct {
+	connID string
+	body   []byte
+}
+
+// clientRequests 等待客户端响应的服务端请求：key 为 connID/seq，seq 为下发请求时分配的关联序号
+type clientRequests struct {
+	seq     int32
+	mu      sync.Mutex
+	waiters map[string]chan clientResponse
+}
+
+func newClientRequests() *clientRequests {
+	return &clientRequests{waiters: make(map[string]chan clientResponse)}
+}
+
+func waiterKey(connID string, seq int32) string {
+	return fmt.Sprintf("%s/%d", connID, seq)
+}
+
+// nextSeq 分配关联序号（跳过 0，0 表示客户端未携带序号）
+func (r *clientRequests) nextSeq() int32 {
+	for {
+		if seq := atomic.AddInt32(&r.seq, 1); seq != 0 {
+			return seq
+		}
+	}
+}
+
+// add 登记等待者，同一请求发往多个会话时共用一个响应 channel
+func (r *clientRequests) add(connID string, seq int32, ch chan clientResponse) {
+	r.mu.Lock()
+	r.waiters[waiterKey(connID, seq)] = ch
+	r.mu.Unlock()
+}
+
+func (r *clientRequests) remove(connID string, seq int32) {
+	r.mu.Lock()
+	delete(r.waiters, waiterKey(connID, seq))
+	r.mu.Unlock()
+}
+
+// resolve 投递客户端响应，没有等待者（已超时或已有其他会话响应）时返回 false
+func (r *clientRequests) resolve(connID string, seq int32, body []byte) bool {
+	key := waiterKey(connID, seq)
+	r.mu.Lock()
+	ch, ok := r.waiters[key]
+	delete(r.waiters, key)
+	r.mu.Unlock()
+	if !ok {
+		return false
+	}
+	select {
+	case ch <- clientResponse{connID: connID, body: body}:
+		return true
+	default:
+		return false
+	}
+}
+
+// ClientRequest 向会话下发 OpServerRequest 并阻塞等待客户端以相同 seq 回复 OpServerResponse；
+// key 为 userId 时发给用户在本节点的全部会话，最先响应的生效。
+// 错误带 gRPC 状态码：会话不在线为 NotFound，客户端未在超时内响应为 DeadlineExceeded
+func (s *ConnectNodeServer) ClientRequest(ctx context.Context, req *push.ClientRequestReq) (*push.ClientRequestReply, error) {
+	if req.Key == "" {
+		return nil, status.Error(codes.InvalidArgument, pkg.ErrClientRequestArg.Error())
+	}
+	timeout := defaultClientRequestTimeout
+	if req.TimeoutMs > 0 {
+		timeout = min(time.Duration(req.TimeoutMs)*time.Millisecond, maxClientRequestTimeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	channels := s.sessionChannels(req.Key)
+	if len(channels) == 0 {
+		return nil, status.Error(codes.NotFound, pkg.ErrClientOffline.Error())
+	}
+
+	seq := s.requests.nextSeq()
+	respCh := make(chan clientResponse, 1)
+	sent := 0
+	for _, ch := range channels {
+		s.requests.add(ch.ConnID, seq, respCh)
+		defer s.requests.remove(ch.ConnID, seq)
+
+		p := &proto.Proto{
+			Ver:    1,
+			Op:     proto.OpServerRequest,
+			Seq:    seq,
+			Userid: pkg.UnscopeID(ch.AppID, ch.UserID),
+			Body:   req.Body,
+		}
+		if err := ch.Push(p, proto.Priority_PRIORITY_HIGH, deadline.UnixMilli()); err != nil {
+			log.Printf("⚠️  [ConnectNodeServer] 下发客户端请求失败: connId=%s, requestId=%s, err=%v", ch.ConnID, req.RequestID, err)
+			continue
+		}
+		sent++
+	}
+	if sent == 0 {
+		return nil, status.Error(codes.NotFound, pkg.ErrClientOffline.Error())
+	}
+	log.Printf("📨 [ConnectNodeServer] 已下发客户端请求: key=%s, requestId=%s, seq=%d, sessions=%d", req.Key, req.RequestID, seq, sent)
+
+	select {
+	case resp := <-respCh:
+		return &push.ClientRequestReply{RequestID: req.RequestID, ConnID: resp.connID, Body: resp.body}, nil
+	case <-ctx.Done():
+		log.Printf("⌛ [ConnectNodeServer] 客户端请求超时: key=%s, requestId=%s, seq=%d", req.Key, req.RequestID, seq)
+		return nil, status.Error(codes.DeadlineExceeded, pkg.ErrClientRequestTimeout.Error())
+	}
+}
+
+// handleServerResponse 客户端对 OpServerRequest 的响应（body 所在的读缓冲区会被复用，需拷贝）
+func (h *ProtoMessageHandler) handleServerResponse(p *proto.Proto) error {
+	body := append([]byte(nil), p.Body...)
+	if !h.server.requests.resolve(h.connID, p.Seq, body) {
+		log.Printf("⚠️  [ProtoHandler] 丢弃无等待者的客户端响应（已超时或已有其他会话响应）: connId=%s, seq=%d", h.connID, p.Seq)
+		return nil
+	}
+	log.Printf("✅ [ProtoHandler] 收到客户端响应: connId=%s, seq=%d, bodyLen=%d", h.connID, p.Seq, len(body))
+	return nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
 	"sync"
 )
@@ -13,6 +14,8 @@ type Room struct {
 	drop      bool
 	Online    int32 // dirty read is ok
 	AllOnline int32
+
+	policy *controller.RoomPolicy // 房间策略缓存（发言时校验）
 }
 
 // NewRoom new a room struct, store channel room info.
@@ -65,10 +68,14 @@ func (r *Room) Del(ch *Channel) bool {
 }
 
 // Push push msg to the room, if chan full discard it.
-func (r *Room) PushMsg(p *protocol.Proto) {
+// 只推送给订阅了该 op 的会话（与 Bucket.Broadcast 一致）
+func (r *Room) PushMsg(p *protocol.Proto, priority protocol.Priority, expireAt int64) {
 	r.rLock.RLock()
 	for ch := r.next; ch != nil; ch = ch.Next {
-		_ = ch.Push(p)
+		if !ch.NeedPush(p.Op) {
+			continue
+		}
+		_ = ch.Push(p, priority, expireAt)
 	}
 	r.rLock.RUnlock()
 }
@@ -82,6 +89,20 @@ func (r *Room) Close() {
 	r.rLock.RUnlock()
 }
 
+// SetPolicy cache the room policy.
+func (r *Room) SetPolicy(policy *controller.RoomPolicy) {
+	r.rLock.Lock()
+	r.policy = policy
+	r.rLock.Unlock()
+}
+
+// Policy the cached room policy, nil if unknown.
+func (r *Room) Policy() *controller.RoomPolicy {
+	r.rLock.RLock()
+	defer r.rLock.RUnlock()
+	return r.policy
+}
+
 // OnlineNum the room all online.
 func (r *Room) OnlineNum() int32 {
 	if r.AllOnline > 0 {
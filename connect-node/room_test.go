@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	getty "github.com/AlexStocks/getty/transport"
+	"google.golang.org/grpc"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg/config"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+// fakeJoinController 按用户决定是否接受加入房间
+type fakeJoinController struct {
+	controller.ControllerServiceClient
+	reject map[string]string // userId -> 拒绝原因
+}
+
+func (c *fakeJoinController) JoinRoom(ctx context.Context, in *controller.JoinRoomRequest, opts ...grpc.CallOption) (*controller.JoinRoomResponse, error) {
+	if reason, ok := c.reject[in.UserId]; ok {
+		return &controller.JoinRoomResponse{Success: false, Message: reason}, nil
+	}
+	return &controller.JoinRoomResponse{Success: true, Message: "OK", Role: "member"}, nil
+}
+
+// fakeSession 记录写出的响应
+type fakeSession struct {
+	getty.Session
+	written []*protocol.Proto
+}
+
+func (s *fakeSession) WritePkg(pkg any, timeout time.Duration) (int, int, error) {
+	s.written = append(s.written, pkg.(*protocol.Proto))
+	return 0, 0, nil
+}
+
+func newTestBucket() *Bucket {
+	return NewBucket(&config.BucketConfig{Channel: 16, Room: 16, RoutineAmount: 1, RoutineSize: 16}, nil)
+}
+
+// joinTestSession 模拟鉴权（注册会话）后发送加入房间请求
+func joinTestSession(t *testing.T, server *ConnectNodeServer, bucket *Bucket, userID string) (*ProtoMessageHandler, *fakeSession) {
+	t.Helper()
+	ch := NewChannel(8, 8, nil)
+	ch.UserID = userID
+	ch.Key = SessionKey(userID, "web")
+	bucket.Put("", ch)
+
+	h := &ProtoMessageHandler{server: server, roomId: "room-1", clientId: userID, bucket: bucket, auth: true, channel: ch}
+	session := &fakeSession{}
+	h.handleJoinRoom(session, &protocol.Proto{Op: protocol.OpJoinRoom, Seq: 1, Roomid: "room-1", Userid: userID})
+	if len(session.written) != 1 {
+		t.Fatalf("%s: join replies = %d, want 1", userID, len(session.written))
+	}
+	return h, session
+}
+
+func TestRejectedJoinGetsNoRoomBroadcast(t *testing.T) {
+	server := &ConnectNodeServer{
+		nodeID:           "node-1",
+		config:           &config.Config{Protocol: &config.Protocol{WatchOps: []config.OpRange{{Min: protocol.OpSendMsg, Max: protocol.OpSendMsg}}}},
+		controllerClient: &fakeJoinController{reject: map[string]string{"bob": "room is full"}},
+	}
+	bucket := newTestBucket()
+
+	alice, _ := joinTestSession(t, server, bucket, "alice")
+	bob, bobSession := joinTestSession(t, server, bucket, "bob")
+	if isReply, rejected, reason := protocol.JoinRoomReply(bobSession.written[0]); !isReply || !rejected || reason != "room is full" {
+		t.Fatalf("bob join reply = %q, want rejected", bobSession.written[0].Body)
+	}
+	// 被拒绝的会话自行订阅房间消息也不会收到房间广播
+	bob.channel.Watch(protocol.OpSendMsg)
+
+	if bob.channel.Room != nil || bob.joined {
+		t.Fatal("rejected session was placed in the room")
+	}
+	if alice.channel.Room == nil || !alice.joined {
+		t.Fatal("accepted session is not in the room")
+	}
+
+	msg := &protocol.Proto{Op: protocol.OpSendMsg, Roomid: "room-1", Body: []byte("hello")}
+	bucket.Room("room-1").PushMsg(msg, protocol.Priority_PRIORITY_NORMAL, 0)
+	bucket.Broadcast("", msg, protocol.OpSendMsg, protocol.Priority_PRIORITY_NORMAL, 0)
+	if n := bob.channel.QueueDepth(); n != 0 {
+		t.Errorf("rejected session queued %d room messages, want 0", n)
+	}
+	if n := alice.channel.QueueDepth(); n != 2 {
+		t.Errorf("accepted session queued %d room messages, want 2", n)
+	}
+}
+
+func TestBucketJoinRoomAfterRelease(t *testing.T) {
+	bucket := newTestBucket()
+	ch := NewChannel(8, 8, nil)
+	ch.UserID = "alice"
+	ch.Key = SessionKey("alice", "web")
+	bucket.Put("", ch)
+
+	// 加入房间期间连接关闭：会话已移除，不能再放入房间
+	bucket.Del(ch)
+	if err := bucket.JoinRoom("room-1", ch); err == nil {
+		t.Fatal("JoinRoom() of a released session succeeded")
+	}
+	if room := bucket.Room("room-1"); room != nil {
+		t.Errorf("room created for a released session: online=%d", room.Online)
+	}
+
+	// 重复加入同一房间不影响成员数
+	other := NewChannel(8, 8, nil)
+	other.Key = SessionKey("bob", "web")
+	bucket.Put("", other)
+	for i := 0; i < 2; i++ {
+		if err := bucket.JoinRoom("room-1", other); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if online := bucket.Room("room-1").Online; online != 1 {
+		t.Errorf("room online = %d after joining twice, want 1", online)
+	}
+}
+
+func TestRoomPushMsgRespectsWatch(t *testing.T) {
+	bucket := newTestBucket()
+	watcher, other := NewChannel(8, 8, nil), NewChannel(8, 8, nil)
+	watcher.Key, other.Key = SessionKey("alice", "web"), SessionKey("bob", "web")
+	for _, ch := range []*Channel{watcher, other} {
+		bucket.Put("", ch)
+		if err := bucket.JoinRoom("room-1", ch); err != nil {
+			t.Fatal(err)
+		}
+		ch.Watch(protocol.OpSendMsg)
+	}
+	watcher.Watch(protocol.OpPresence)
+	other.UnWatch(protocol.OpSendMsg)
+
+	room := bucket.Room("room-1")
+	room.PushMsg(&protocol.Proto{Op: protocol.OpSendMsg}, protocol.Priority_PRIORITY_NORMAL, 0)
+	room.PushMsg(&protocol.Proto{Op: protocol.OpPresence}, protocol.Priority_PRIORITY_NORMAL, 0)
+	if n := watcher.QueueDepth(); n != 2 {
+		t.Errorf("watching session queued %d messages, want 2", n)
+	}
+	// 取消订阅 op 2 且未订阅在线状态的会话收不到房间消息
+	if n := other.QueueDepth(); n != 0 {
+		t.Errorf("unwatched session queued %d messages, want 0", n)
+	}
+}
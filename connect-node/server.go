@@ -16,16 +16,22 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"strconv"
+	"sync/atomic"
 	getty "github.com/AlexStocks/getty/transport"
 	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/push"
+	"github.com/redis/go-redis/v9"
 	"github.com/zhenjl/cityhash"
 	"time"
 
 	"github.com/livekit/psrpc/examples/pubsub/pkg/config"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/metrics"
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
 )
 
 // ConnectNodeServer 连接节点服务器
@@ -40,6 +46,12 @@ type ConnectNodeServer struct {
 	// gRPC 客户端（用于调用 Controller）
 	controllerClient controller.ControllerServiceClient
 
+	// gRPC 客户端（用于客户端上行消息发布到 Push-Manager）
+	pushClient broadcast.PushServerClient
+
+	// Redis 客户端（订阅房间角色/策略变更，可为 nil）
+	redis *redis.Client
+
 	// Metrics
 	metrics *metrics.MetricsCollector
 
@@ -54,6 +66,26 @@ type ConnectNodeServer struct {
 
 	// 房间同步停止信号
 	stopRoomSync chan struct{}
+
+	// 连接 ID 生成（节点 ID + 启动时间 + 自增序号，保证节点重启后不重复）
+	bootID  string
+	connSeq uint64
+
+	// 最近处理过的推送消息 ID（丢弃 Push-Manager 重投造成的重复消息）
+	recentPushes *pkg.LRU
+
+	// 等待客户端响应的服务端请求
+	requests *clientRequests
+
+	// 租户查询（鉴权时校验 app_id）
+	tenants *pkg.TenantResolver
+
+	// 租户用量（连接数限制、投递计量）
+	usage *tenantUsage
+
+	// drain 模式：拒绝新连接，已有会话不受影响（AdminService 切换）
+	draining  atomic.Bool
+	startedAt time.Time
 }
 
 // NewConnectNodeServer 创建连接节点服务器
@@ -61,6 +93,8 @@ func NewConnectNodeServer(
 	nodeID, nodeAddress string,
 	cfg *config.Config,
 	controllerClient controller.ControllerServiceClient,
+	pushClient broadcast.PushServerClient,
+	redisClient *redis.Client,
 	metricsCollector *metrics.MetricsCollector,
 ) *ConnectNodeServer {
 	server := &ConnectNodeServer{
@@ -68,24 +102,65 @@ func NewConnectNodeServer(
 		nodeAddress:      nodeAddress,
 		config:           cfg,
 		controllerClient: controllerClient,
+		pushClient:       pushClient,
+		redis:            redisClient,
 		metrics:          metricsCollector,
 		buckets:          make([]*Bucket, cfg.Bucket.Size),
 		bucketIdx:        uint32(cfg.Bucket.Size),
 		round:            NewRound(cfg),
 		stopRoomSync:     make(chan struct{}),
+		bootID:           strconv.FormatInt(time.Now().UnixNano(), 36),
+		startedAt:        time.Now(),
+		recentPushes:     pkg.NewLRU(recentPushSize, recentPushTTL),
+		requests:         newClientRequests(),
+		tenants:          pkg.NewTenantResolver(controllerClient, pkg.TenantCacheSize, pkg.TenantCacheTTL),
 	}
-
 	for i := 0; i < cfg.Bucket.Size; i++ {
-		server.buckets[i] = NewBucket(cfg.Bucket)
+		server.buckets[i] = NewBucket(cfg.Bucket, server.recordExpired)
 	}
 
 	server.nodeID = cfg.Server.ID
 
+	var usageStore *redisstore.UsageStore
+	if redisClient != nil {
+		usageStore = redisstore.NewUsageStore(redisClient)
+	}
+	server.usage = newTenantUsage(server.nodeID, usageStore, metricsCollector)
+
 	go server.onlineproc()
 
 	return server
 }
 
+const (
+	// recentPushSize 推送去重记录容量
+	recentPushSize = 100000
+	// recentPushTTL 推送去重记录保留时间（覆盖出站队列的重试窗口）
+	recentPushTTL = 10 * time.Minute
+)
+
+// firstPush 记录推送消息 ID，已处理过时返回 false（未携带消息 ID 时不去重）
+func (s *ConnectNodeServer) firstPush(kind, msgID string) bool {
+	if msgID == "" {
+		return true
+	}
+	_, first := s.recentPushes.SetNX(kind+":"+msgID, nil)
+	if !first {
+		log.Printf("♻️  [ConnectNodeServer] 丢弃重复推送: kind=%s, msgID=%s", kind, msgID)
+	}
+	return first
+}
+
+// recordExpired 记录一条在 stage 环节因过期被丢弃的下行消息
+func (s *ConnectNodeServer) recordExpired(stage, kind string) {
+	s.metrics.RecordPushExpired(context.Background(), stage, kind)
+}
+
+// NextConnID 生成本节点唯一的连接 ID
+func (s *ConnectNodeServer) NextConnID() string {
+	return fmt.Sprintf("%s-%s-%d", s.nodeID, s.bootID, atomic.AddUint64(&s.connSeq, 1))
+}
+
 func (s *ConnectNodeServer) Buckets() []*Bucket {
 	return s.buckets
 }
@@ -121,6 +196,80 @@ func (s *ConnectNodeServer) onlineproc() {
 
 }
 
+// WatchRoomEvents 订阅 Controller 发布的房间事件，刷新本节点缓存的角色和策略
+func (s *ConnectNodeServer) WatchRoomEvents(ctx context.Context) {
+	if s.redis == nil {
+		log.Printf("⚠️  [ConnectNodeServer] 未配置 Redis，角色变更需重新加入房间后生效")
+		return
+	}
+	log.Printf("👂 [ConnectNodeServer] 开始订阅房间事件: %s", redisstore.RoomEventChannel)
+	redisstore.SubscribeRoomEvents(ctx, s.redis, s.handleRoomEvent)
+}
+
+// handleRoomEvent 处理单个房间事件
+func (s *ConnectNodeServer) handleRoomEvent(event *redisstore.RoomEvent) {
+	switch event.Type {
+	case redisstore.RoomEventRole:
+		for _, ch := range s.roomChannels(event.RoomID, event.UserID) {
+			ch.SetRole(event.Role)
+			log.Printf("🎭 [ConnectNodeServer] 刷新用户角色: room=%s, user=%s, device=%s, role=%s", event.RoomID, event.UserID, ch.DeviceID, event.Role)
+		}
+
+	case redisstore.RoomEventKick:
+		for _, ch := range s.roomChannels(event.RoomID, event.UserID) {
+			log.Printf("🦶 [ConnectNodeServer] 用户被踢出，关闭连接: room=%s, user=%s, device=%s", event.RoomID, event.UserID, ch.DeviceID)
+			ch.Close()
+		}
+
+	case redisstore.RoomEventEvict:
+		for _, ch := range s.Bucket(event.UserID).UserChannels(event.UserID) {
+			if ch.ConnID == event.ConnID {
+				log.Printf("📴 [ConnectNodeServer] 会话被挤下线: user=%s, device=%s, conn=%s, reason=%s", event.UserID, ch.DeviceID, ch.ConnID, event.Reason)
+				ch.Close()
+			}
+		}
+
+	case redisstore.RoomEventPolicy:
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resp, err := s.controllerClient.GetRoomInfo(ctx, &controller.GetRoomInfoRequest{RoomId: event.RoomID})
+		if err != nil || resp.RoomInfo == nil {
+			log.Printf("⚠️  [ConnectNodeServer] 刷新房间策略失败: room=%s, err=%v", event.RoomID, err)
+			return
+		}
+		for _, bucket := range s.buckets {
+			if room := bucket.Room(event.RoomID); room != nil {
+				room.SetPolicy(resp.RoomInfo.Policy)
+			}
+		}
+		log.Printf("⚙️  [ConnectNodeServer] 刷新房间策略: room=%s", event.RoomID)
+	}
+}
+
+// roomChannels 查找本节点上某用户在指定房间的全部会话
+func (s *ConnectNodeServer) roomChannels(roomID, userID string) []*Channel {
+	var chs []*Channel
+	for _, ch := range s.Bucket(userID).UserChannels(userID) {
+		if ch.Room != nil && ch.Room.ID == roomID {
+			chs = append(chs, ch)
+		}
+	}
+	return chs
+}
+
+// sessionChannels 按 key 查找会话：userId 表示用户的全部会话，userId/deviceId 表示指定设备
+func (s *ConnectNodeServer) sessionChannels(key string) []*Channel {
+	userID, deviceID := SplitSessionKey(key)
+	bucket := s.Bucket(userID)
+	if deviceID == "" {
+		return bucket.UserChannels(userID)
+	}
+	if ch := bucket.Channel(key); ch != nil {
+		return []*Channel{ch}
+	}
+	return nil
+}
+
 // ========== RPC 方法实现 ==========
 
 // 假设 userId 是全局 服务端颁发
@@ -128,24 +277,29 @@ func (s *ConnectNodeServer) PushMsg(ctx context.Context, req *push.PushMsgReq) (
 	if len(req.Keys) == 0 || req.Proto == nil {
 		return nil, pkg.ErrPushMsgArg
 	}
+	if pkg.Expired(req.ExpireAt) {
+		s.recordExpired("comet", "user")
+		return &push.PushMsgReply{}, nil
+	}
+	if !s.firstPush("user", req.MsgID) {
+		return &push.PushMsgReply{}, nil
+	}
 
+	// 单个会话下行队列已满时丢弃（与房间广播一致）并继续推送其他会话；不清除去重记录、不返回错误，
+	// 否则 Push-Manager 重试整条消息会向已送达的会话重复推送。丢失的消息由客户端按 Seq 缺口发现
+	dropped := 0
 	for _, key := range req.Keys {
-		bucket := s.Bucket(key)
-
-		if bucket == nil {
-			continue
-		}
-
-		if channel := bucket.Channel(key); channel != nil {
+		for _, channel := range s.sessionChannels(key) {
 			if !channel.NeedPush(req.ProtoOp) {
 				continue
 			}
-
-			if err = channel.Push(req.Proto); err != nil {
-				return
+			if channel.Push(req.Proto, req.Priority, req.ExpireAt) != nil {
+				dropped++
 			}
 		}
-
+	}
+	if dropped > 0 {
+		log.Printf("⚠️  [ConnectNodeServer] 会话下行队列已满，丢弃推送: msgID=%s, keys=%v, dropped=%d", req.MsgID, req.Keys, dropped)
 	}
 
 	return &push.PushMsgReply{}, nil
@@ -157,13 +311,25 @@ func (s *ConnectNodeServer) Broadcast(ctx context.Context, req *push.BroadcastRe
 	if req.Proto == nil {
 		return nil, pkg.ErrBroadCastArg
 	}
+	if pkg.Expired(req.ExpireAt) {
+		s.recordExpired("comet", "broadcast")
+		return &push.BroadcastReply{}, nil
+	}
+	if !s.firstPush("broadcast", req.MsgID) {
+		return &push.BroadcastReply{}, nil
+	}
 
 	go func() {
 		log.Printf("🚀 [ConnectNodeServer] 开始广播到 %d 个 buckets", len(s.Buckets()))
 		for i, bucket := range s.Buckets() {
+			// 限速广播耗时较长，中途过期时停止
+			if pkg.Expired(req.ExpireAt) {
+				s.recordExpired("bucket", "broadcast")
+				return
+			}
 			channelCount := bucket.ChannelCount()
 			log.Printf("📤 [ConnectNodeServer] 广播到 bucket[%d], channels=%d", i, channelCount)
-			bucket.Broadcast(req.GetProto(), req.ProtoOp)
+			bucket.Broadcast(req.AppID, req.GetProto(), req.ProtoOp, req.Priority, req.ExpireAt)
 			if req.Speed > 0 {
 				t := bucket.ChannelCount() / int(req.Speed)
 				time.Sleep(time.Duration(t) * time.Second)
@@ -180,6 +346,13 @@ func (s *ConnectNodeServer) BroadcastRoom(ctx context.Context, req *push.Broadca
 		log.Printf("❌ [ConnectNodeServer] 参数无效: roomID=%s, proto=%v", req.RoomID, req.Proto)
 		return nil, pkg.ErrBroadCastRoomArg
 	}
+	if pkg.Expired(req.ExpireAt) {
+		s.recordExpired("comet", "room")
+		return &push.BroadcastRoomReply{}, nil
+	}
+	if !s.firstPush("room", req.MsgID) {
+		return &push.BroadcastRoomReply{}, nil
+	}
 	log.Printf("🔄 [ConnectNodeServer] 分发到 %d 个 buckets", len(s.Buckets()))
 	for i, bucket := range s.Buckets() {
 		log.Printf("🔄 [ConnectNodeServer] 调用 bucket[%d].BroadcastRoom", i)
@@ -189,6 +362,23 @@ func (s *ConnectNodeServer) BroadcastRoom(ctx context.Context, req *push.Broadca
 	return &push.BroadcastRoomReply{}, nil
 }
 
+func (s *ConnectNodeServer) BroadcastTopic(ctx context.Context, req *push.BroadcastTopicReq) (*push.BroadcastTopicReply, error) {
+	if req.Proto == nil || pkg.ValidateTopic(req.Topic) != nil {
+		return nil, pkg.ErrTopicInvalid
+	}
+	if pkg.Expired(req.ExpireAt) {
+		s.recordExpired("comet", "topic")
+		return &push.BroadcastTopicReply{}, nil
+	}
+	if !s.firstPush("topic", req.MsgID) {
+		return &push.BroadcastTopicReply{}, nil
+	}
+	for _, bucket := range s.Buckets() {
+		bucket.BroadcastTopic(req)
+	}
+	return &push.BroadcastTopicReply{}, nil
+}
+
 func (s *ConnectNodeServer) Rooms(ctx context.Context, req *push.RoomsReq) (*push.RoomsReply, error) {
 	var (
 		roomIds = make(map[string]bool)
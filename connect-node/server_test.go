@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/push"
+)
+
+func TestPushMsgSkipsFullSessions(t *testing.T) {
+	bucket := newTestBucket()
+	s := &ConnectNodeServer{
+		buckets:      []*Bucket{bucket},
+		bucketIdx:    1,
+		recentPushes: pkg.NewLRU(16, recentPushTTL),
+	}
+	// alice 的下行队列容量为 1 且已占满，bob 正常
+	full, ok := NewChannel(8, 1, nil), NewChannel(8, 8, nil)
+	full.UserID, full.Key = "alice", SessionKey("alice", "web")
+	ok.UserID, ok.Key = "bob", SessionKey("bob", "web")
+	for _, ch := range []*Channel{full, ok} {
+		ch.Watch(protocol.OpSendMsg)
+		bucket.Put("", ch)
+	}
+	full.Push(&protocol.Proto{Op: protocol.OpSendMsg}, protocol.Priority_PRIORITY_NORMAL, 0)
+
+	req := &push.PushMsgReq{
+		Keys:    []string{"alice", "bob"},
+		Proto:   &protocol.Proto{Op: protocol.OpSendMsg, Body: []byte("hello")},
+		ProtoOp: protocol.OpSendMsg,
+		MsgID:   "msg-1",
+	}
+	if _, err := s.PushMsg(context.Background(), req); err != nil {
+		t.Fatalf("PushMsg() with a full session = %v, want nil", err)
+	}
+	if n := ok.QueueDepth(); n != 1 {
+		t.Fatalf("session after the full one queued %d messages, want 1", n)
+	}
+
+	// 去重记录保留：重投的消息不会再推送给已送达的会话
+	if _, err := s.PushMsg(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if n := ok.QueueDepth(); n != 1 {
+		t.Errorf("redelivered message queued again: depth=%d, want 1", n)
+	}
+}
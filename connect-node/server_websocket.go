@@ -3,12 +3,16 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	getty "github.com/AlexStocks/getty/transport"
 	gxnet "github.com/AlexStocks/goext/net"
 	gxsync "github.com/dubbogo/gost/sync"
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
 	gettypkg "github.com/livekit/psrpc/examples/pubsub/pkg/getty"
+	"github.com/livekit/psrpc/examples/pubsub/pkg/types"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
 	proto "github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
 	"log"
@@ -76,7 +80,7 @@ func InitWebsocket(server *ConnectNodeServer, addrs []string, accept int) (err e
 		//	session: session,
 		//}
 
-		channel := NewChannel(server.config.Protocol.CliProto, server.config.Protocol.SvrProto)
+		channel := NewChannel(server.config.Protocol.CliProto, server.config.Protocol.SvrProto, server.recordExpired)
 
 		protoMsgHandler := newProtoMessageHandler(server, channel, protoPkgHandler)
 
@@ -154,11 +158,46 @@ type ProtoMessageHandler struct {
 	server              *ConnectNodeServer
 	protoPackageHandler *gettypkg.ProtoPackageHandler
 
-	roomId   string
-	clientId string
+	roomId   string // 集群内房间 ID（租户前缀 + 房间 ID）
+	clientId string // 集群内用户 ID（租户前缀 + 用户 ID）
+	connID   string // 连接 ID（写入 Controller 的 user -> connections 索引）
 	bucket   *Bucket
 	auth     bool
 	channel  *Channel
+
+	// 是否已通过 Controller 加入房间（角色缓存在 channel，策略缓存在 room）
+	joined bool
+
+	// 连接关闭时只通知一次 Controller
+	offlineOnce sync.Once
+	// 连接关闭时只从 bucket 移除一次（OnError/OnClose 可能都会触发）
+	releaseOnce sync.Once
+}
+
+// joinRoomBody 鉴权/加入房间请求体（兼容纯文本用户名）
+type joinRoomBody struct {
+	// 凭证（二选一）：Web 签发的连接令牌或租户 API key，租户由凭证确定
+	Token  string `json:"token"`
+	APIKey string `json:"api_key"`
+	AppID  string `json:"app_id"` // 租户 ID（可选），与凭证所属租户不一致时拒绝
+
+	UserName   string `json:"user_name"`
+	Password   string `json:"password"`
+	DeviceID   string `json:"device_id"`   // 设备 ID，为空时使用连接 ID（每个连接一个会话）
+	DeviceType string `json:"device_type"` // 设备类型，如 ios / android / web / desktop
+
+	// 加入房间时的初始订阅
+	WatchOps []int32  `json:"watch_ops"` // 订阅的 op，为空时订阅 OpSendMsg
+	Topics   []string `json:"topics"`    // 订阅的主题模式
+}
+
+// parseJoinRoomBody 解析请求体，非 JSON 时整体作为用户名
+func parseJoinRoomBody(data []byte) joinRoomBody {
+	var body joinRoomBody
+	if len(data) > 0 && json.Unmarshal(data, &body) != nil {
+		body = joinRoomBody{UserName: string(data)}
+	}
+	return body
 }
 
 // TODO 之前的 server_websocket 是客户端写入的很多消息，一次性合并等所有消息都处理完，拿到 server 的 resp 之后，
@@ -173,6 +212,7 @@ func newProtoMessageHandler(server *ConnectNodeServer, channel *Channel,
 		channel:             channel,
 		protoPackageHandler: protoPackageHandler,
 		server:              server,
+		connID:              server.NextConnID(),
 		auth:                false,
 	}
 }
@@ -237,6 +277,7 @@ func (h *ProtoMessageHandler) dispatchWebsocket(session getty.Session) {
 			if err != nil {
 				log.Printf("❌ [ProtoHandler] 发送服务端推送消息失败: %v", err)
 			} else {
+				h.server.usage.delivered(h.channel.AppID, len(p.Body))
 				log.Printf("✅ [ProtoHandler] 服务端推送消息已发送给客户端")
 			}
 		}
@@ -258,50 +299,26 @@ func (h *ProtoMessageHandler) processClientRequest(session getty.Session, p *pro
 
 	// TODO: 根据 op 路由到不同的业务 handler
 	switch p.Op {
-	case 1: // 加入房间
-		log.Printf("🏠 [ProtoHandler] 加入房间: roomId=%s, userId=%s", p.Roomid, p.Userid)
-		// 这里可以调用具体的业务逻辑
+	case proto.OpJoinRoom: // 加入房间
+		return h.handleJoinRoom(session, p)
 
-		joinRoomRequest := controller.JoinRoomRequest{
-			RoomId: p.Roomid,
-			UserId: p.Userid,
-		}
+	case proto.OpPublish: // 客户端发言
+		return h.handlePublish(session, p)
 
-		log.Printf("🔄 [ProtoHandler] 调用 Controller.JoinRoom...")
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		
-		_, err := h.server.controllerClient.JoinRoom(ctx, &joinRoomRequest)
-		if err != nil {
-			log.Printf("❌ [ProtoHandler] join room error: %s", err.Error())
-			return err
-		}
-		log.Printf("✅ [ProtoHandler] JoinRoom 调用成功")
+	case proto.OpSubscribe: // 订阅主题
+		return h.handleSubscribe(session, p)
 
-		// 加入房间成功后，订阅消息推送操作码
-		// Op=2: OP_SEND_MSG (服务端推送的消息)
-		h.channel.Watch(2)
-		log.Printf("✅ [ProtoHandler] 已订阅消息推送: op=2")
+	case proto.OpUnsubscribe: // 取消订阅主题
+		return h.handleUnsubscribe(session, p)
 
-		// 处理完后，如果需要回复客户端
-		// 准备响应消息
-		resp := &proto.Proto{
-			Ver:    p.Ver,
-			Op:     p.Op + 1, // 回复 op
-			Seq:    p.Seq,
-			Roomid: p.Roomid,
-			Userid: p.Userid,
-			Body:   []byte("join room success"),
-		}
+	case proto.OpWatch: // 订阅 op
+		return h.handleWatch(session, p)
 
-		// 发送响应（通过 getty 的 WritePkg）
-		log.Printf("📤 [ProtoHandler] 发送加入房间响应...")
-		_, _, err = session.WritePkg(resp, 0)
-		if err != nil {
-			log.Printf("❌ [ProtoHandler] 发送响应失败: %v", err)
-			return err
-		}
-		log.Printf("✅ [ProtoHandler] 加入房间响应已发送")
+	case proto.OpUnWatch: // 取消订阅 op
+		return h.handleUnWatch(session, p)
+
+	case proto.OpServerResponse: // 客户端响应服务端请求
+		return h.handleServerResponse(p)
 
 	case 5: // 心跳包
 		log.Printf("💓 [ProtoHandler] 收到心跳: roomId=%s, userId=%s", p.Roomid, p.Userid)
@@ -325,13 +342,217 @@ func (h *ProtoMessageHandler) processClientRequest(session getty.Session, p *pro
 		log.Printf("⚠️  [ProtoHandler] 未知 op: %d", p.Op)
 		return nil
 	}
+}
+
+// handleJoinRoom 调用 Controller 加入房间，房间策略拒绝时回复失败原因
+func (h *ProtoMessageHandler) handleJoinRoom(session getty.Session, p *proto.Proto) error {
+	log.Printf("🏠 [ProtoHandler] 加入房间: roomId=%s, userId=%s", p.Roomid, p.Userid)
+
+	body := parseJoinRoomBody(p.Body)
+
+	// 房间和用户以鉴权时的为准（已带租户前缀）
+	joinRoomRequest := controller.JoinRoomRequest{
+		RoomId:   h.roomId,
+		UserId:   h.clientId,
+		UserName: body.UserName,
+		NodeId:   h.server.nodeID,
+		Password: body.Password,
+		ConnId:   h.connID,
+	}
+
+	log.Printf("🔄 [ProtoHandler] 调用 Controller.JoinRoom...")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	joinResp, err := h.server.controllerClient.JoinRoom(ctx, &joinRoomRequest)
+	if err != nil {
+		log.Printf("❌ [ProtoHandler] join room error: %s", err.Error())
+		return err
+	}
+
+	resp := &proto.Proto{
+		Ver:    p.Ver,
+		Op:     proto.OpJoinRoomReply,
+		Seq:    p.Seq,
+		Roomid: p.Roomid,
+		Userid: p.Userid,
+	}
+
+	if !joinResp.Success {
+		log.Printf("🚫 [ProtoHandler] 加入房间被拒绝: roomId=%s, userId=%s, reason=%s", p.Roomid, p.Userid, joinResp.Message)
+		resp.Body = []byte(proto.JoinRoomFailed + joinResp.Message)
+		_, _, err = session.WritePkg(resp, 0)
+		return err
+	}
+	log.Printf("✅ [ProtoHandler] JoinRoom 调用成功")
+
+	if err = h.bucket.JoinRoom(h.roomId, h.channel); err != nil {
+		log.Printf("❌ [ProtoHandler] 放入房间失败: roomId=%s, userId=%s, err=%v", p.Roomid, p.Userid, err)
+		resp.Body = []byte(proto.JoinRoomFailed + err.Error())
+		if _, _, werr := session.WritePkg(resp, 0); werr != nil {
+			return werr
+		}
+		return err
+	}
+
+	h.rwlock.Lock()
+	h.joined = true
+	h.rwlock.Unlock()
+
+	h.channel.SetRole(joinResp.Role)
+	if room := h.channel.Room; room != nil && joinResp.RoomInfo != nil {
+		room.SetPolicy(joinResp.RoomInfo.Policy)
+	}
+
+	// 加入房间成功后，按请求体中的初始订阅订阅 op 和主题（不允许的项跳过）
+	h.applyInitialSubscriptions(body)
+
+	// 发送响应（通过 getty 的 WritePkg）
+	resp.Body = []byte(proto.JoinRoomSuccess)
+	_, _, err = session.WritePkg(resp, 0)
+	if err != nil {
+		log.Printf("❌ [ProtoHandler] 发送响应失败: %v", err)
+		return err
+	}
+	log.Printf("✅ [ProtoHandler] 加入房间响应已发送")
+	return nil
+}
+
+// handlePublish 按房间策略校验后，通过 Push-Manager 将消息广播到房间
+func (h *ProtoMessageHandler) handlePublish(session getty.Session, p *proto.Proto) error {
+	h.rwlock.RLock()
+	joined := h.joined
+	h.rwlock.RUnlock()
+
+	err := h.checkPublish(joined, p)
+	if err == nil {
+		// Body 引用 ReadBuffer，发往 Push-Manager 前需要拷贝
+		body := make([]byte, len(p.Body))
+		copy(body, p.Body)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		// Push-Manager 按租户校验配额并加上租户前缀，客户端收到的是租户内 ID
+		appID := h.channel.AppID
+		roomID := pkg.UnscopeID(appID, h.roomId)
+		var reply *broadcast.BroadCastRoomReply
+		reply, err = h.server.pushClient.BroadcastToRoom(ctx, &broadcast.BroadCastRoomReq{
+			AppId:     appID,
+			RoomId:    roomID,
+			AssignSeq: true,
+			Proto: &proto.Proto{
+				Ver:    p.Ver,
+				Op:     proto.OpSendMsg,
+				Seq:    p.Seq,
+				Roomid: roomID,
+				Userid: pkg.UnscopeID(appID, h.clientId),
+				Body:   body,
+			},
+		})
+		cancel()
+		if err == nil && reply.Code != "0" {
+			err = fmt.Errorf("%s: %s", reply.Msg, reply.Desc)
+		}
+	}
+
+	resp := &proto.Proto{
+		Ver:    p.Ver,
+		Op:     proto.OpPublishReply,
+		Seq:    p.Seq,
+		Roomid: p.Roomid,
+		Userid: p.Userid,
+		Body:   []byte("publish success"),
+	}
+	if err != nil {
+		log.Printf("🚫 [ProtoHandler] 发言失败: roomId=%s, userId=%s, err=%v", h.roomId, h.clientId, err)
+		resp.Body = []byte("publish failed: " + err.Error())
+	}
 
+	if _, _, werr := session.WritePkg(resp, 0); werr != nil {
+		log.Printf("❌ [ProtoHandler] 发送发言响应失败: %v", werr)
+		return werr
+	}
+	return err
+}
+
+// checkPublish 校验用户是否已加入房间、角色和房间策略是否允许发言以及消息大小
+func (h *ProtoMessageHandler) checkPublish(joined bool, p *proto.Proto) error {
+	if !joined || h.channel.Room == nil {
+		return pkg.ErrNotInRoom
+	}
+	policy := h.channel.Room.Policy()
+	publishPolicy := ""
+	if policy != nil {
+		publishPolicy = policy.PublishPolicy
+	}
+	if !types.CanPublish(publishPolicy, h.channel.Role()) {
+		return pkg.ErrPublishForbidden
+	}
+	if policy == nil {
+		return nil
+	}
+	if policy.MaxMessageBytes > 0 && len(p.Body) > int(policy.MaxMessageBytes) {
+		return pkg.ErrMsgTooLarge
+	}
 	return nil
 }
 
+// notifyOnline 通知 Controller 登记用户连接
+func (h *ProtoMessageHandler) notifyOnline() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := h.server.controllerClient.NotifyUserOnline(ctx, &controller.NotifyUserOnlineRequest{
+		UserId:      h.clientId,
+		ConnId:      h.connID,
+		NodeId:      h.server.nodeID,
+		NodeAddress: h.server.nodeAddress,
+		DeviceId:    h.channel.DeviceID,
+		DeviceType:  h.channel.DeviceType,
+	})
+	if err != nil {
+		log.Printf("⚠️  [ProtoHandler] NotifyUserOnline 失败: userId=%s, err=%v", h.clientId, err)
+	}
+}
+
+// notifyOffline 通知 Controller 删除用户连接（OnError/OnClose 可能都会触发，只发送一次）
+func (h *ProtoMessageHandler) notifyOffline() {
+	if !h.auth {
+		return
+	}
+	h.offlineOnce.Do(func() {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			_, err := h.server.controllerClient.NotifyUserOffline(ctx, &controller.NotifyUserOfflineRequest{
+				UserId: h.clientId,
+				ConnId: h.connID,
+				NodeId: h.server.nodeID,
+			})
+			if err != nil {
+				log.Printf("⚠️  [ProtoHandler] NotifyUserOffline 失败: userId=%s, err=%v", h.clientId, err)
+			}
+		}()
+	})
+}
+
+// release 从 bucket 中移除本会话（同设备重连已被替换时 Del 不会误删新会话）
+func (h *ProtoMessageHandler) release() {
+	if !h.auth || h.bucket == nil {
+		return
+	}
+	h.releaseOnce.Do(func() {
+		h.bucket.Del(h.channel)
+		h.server.usage.releaseConnection(h.channel.AppID)
+	})
+}
+
 func (h *ProtoMessageHandler) OnError(session getty.Session, err error) {
 	log.Printf("❌ [ProtoHandler] Session 错误: %s, err=%v", session.Stat(), err)
 
+	h.notifyOffline()
+	h.release()
+
 	// 通知 dispatchWebsocket 退出
 	h.channel.Close()
 
@@ -342,6 +563,9 @@ func (h *ProtoMessageHandler) OnError(session getty.Session, err error) {
 func (h *ProtoMessageHandler) OnClose(session getty.Session) {
 	log.Printf("👋 [ProtoHandler] Session 关闭: %s", session.Stat())
 
+	h.notifyOffline()
+	h.release()
+
 	// 通知 dispatchWebsocket 退出
 	h.channel.Close()
 
@@ -355,22 +579,101 @@ func (h *ProtoMessageHandler) authWebsocket(p *proto.Proto, session getty.Sessio
 	if p.Roomid != "" && p.Userid != "" && (p.Op == proto.OpAuth || p.Op == 1) {
 		// redis check login session (这里可以添加实际的认证逻辑)
 
-		h.roomId = p.Roomid
-		h.clientId = p.Userid
+		// drain 模式下不再接受新连接，客户端重连到其他节点
+		if h.server.draining.Load() {
+			return fmt.Errorf("auth failed: userId=%s, err=%w", p.Userid, pkg.ErrDraining)
+		}
+
+		// 租户需存在且未停用；房间/用户 ID 不能包含租户分隔符，避免寻址到其他租户
+		body := parseJoinRoomBody(p.Body)
+		if err := pkg.ValidateLocalID(p.Roomid); err != nil {
+			return fmt.Errorf("auth failed: roomId=%s, err=%w", p.Roomid, err)
+		}
+		if err := pkg.ValidateLocalID(p.Userid); err != nil {
+			return fmt.Errorf("auth failed: userId=%s, err=%w", p.Userid, err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		tenant, err := h.resolveTenant(ctx, &body, p)
+		if err == nil {
+			err = h.server.usage.acquireConnection(ctx, tenant)
+		}
+		cancel()
+		if err != nil {
+			return fmt.Errorf("auth failed: userId=%s, err=%w", p.Userid, err)
+		}
+		if tenant != nil {
+			body.AppID = tenant.AppId
+		}
+
+		h.roomId = pkg.ScopeID(body.AppID, p.Roomid)
+		h.clientId = pkg.ScopeID(body.AppID, p.Userid)
 
-		h.bucket = h.server.Bucket(p.Userid)
+		// 同一用户的所有会话落在同一个 bucket，便于按用户推送
+		h.bucket = h.server.Bucket(h.clientId)
 
-		//connectNodeServer := session.GetAttribute("server").(*ConnectNodeServer)
-		h.bucket.Put(p.Roomid, h.channel)
+		// channel 以 userId/deviceId 作为 key，同一用户的多个设备可以同时在线
+		if body.DeviceID == "" {
+			body.DeviceID = h.connID
+		}
+		h.channel.AppID = body.AppID
+		h.channel.UserID = h.clientId
+		h.channel.DeviceID = body.DeviceID
+		h.channel.DeviceType = body.DeviceType
+		h.channel.ConnID = h.connID
+		h.channel.IP = remoteIP(session.RemoteAddr())
+		h.channel.ConnectedAt = time.Now()
+		h.channel.Key = SessionKey(h.clientId, body.DeviceID)
+
+		// 鉴权时只注册会话，Controller 接受加入房间后才放入房间（handleJoinRoom）
+		h.bucket.Put("", h.channel)
 
 		h.auth = true
-		log.Printf("✅ [ProtoHandler] 鉴权成功: roomId=%s, userId=%s", p.Roomid, p.Userid)
+		log.Printf("✅ [ProtoHandler] 鉴权成功: app=%s, roomId=%s, userId=%s, device=%s", body.AppID, p.Roomid, p.Userid, body.DeviceID)
+
+		go h.notifyOnline()
 		return nil
 	}
 
 	return fmt.Errorf("auth failed: op=%d, roomId=%s, userId=%s", p.Op, p.Roomid, p.Userid)
 }
 
+// resolveTenant 按连接凭证确定租户：连接令牌需与 userId（及 roomId）一致；
+// 未携带凭证时只有开启 allow_anonymous 才能进入默认租户。默认租户返回 nil
+func (h *ProtoMessageHandler) resolveTenant(ctx context.Context, body *joinRoomBody, p *proto.Proto) (*controller.Tenant, error) {
+	var appID string
+	var tenant *controller.Tenant
+	var err error
+	switch {
+	case body.Token != "":
+		var claims *pkg.ClientTokenClaims
+		claims, err = pkg.VerifyClientToken(h.server.config.Auth.ClientTokenSecret, body.Token)
+		if err != nil {
+			return nil, err
+		}
+		if claims.UserID != p.Userid || (claims.RoomID != "" && claims.RoomID != p.Roomid) {
+			return nil, pkg.ErrClientTokenInvalid
+		}
+		appID = claims.AppID
+		tenant, err = h.server.tenants.Tenant(ctx, appID)
+	case body.APIKey != "":
+		tenant, err = h.server.tenants.ResolveAPIKey(ctx, body.APIKey)
+		if tenant != nil {
+			appID = tenant.AppId
+		}
+	case h.server.config.Auth.AllowAnonymous && body.AppID == "":
+		return nil, nil
+	default:
+		return nil, pkg.ErrClientCredential
+	}
+	if err != nil {
+		return nil, err
+	}
+	if body.AppID != "" && body.AppID != appID {
+		return nil, fmt.Errorf("%w: app_id=%s 与凭证不一致", pkg.ErrClientCredential, body.AppID)
+	}
+	return tenant, nil
+}
+
 func (h *ProtoMessageHandler) OnMessage(session getty.Session, pkg any) {
 	p, ok := pkg.(*proto.Proto)
 	if !ok {
@@ -409,6 +712,14 @@ func (h *ProtoMessageHandler) OnMessage(session getty.Session, pkg any) {
 		p.Op, p.Seq, p.Roomid, p.Userid, len(p.Body))
 }
 
+// remoteIP 去掉远端地址中的端口
+func remoteIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
 func writeResp(session getty.Session, resp *proto.Proto) {
 	if _, _, err := session.WritePkg(resp, 5*time.Second); err != nil {
 		log.Printf("send failed: %v", err)
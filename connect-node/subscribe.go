@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	getty "github.com/AlexStocks/getty/transport"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	proto "github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+// topicsBody 主题订阅/取消订阅请求体
+type topicsBody struct {
+	Topics []string `json:"topics"`
+}
+
+// opsBody op 订阅/取消订阅请求体
+type opsBody struct {
+	Ops []int32 `json:"ops"`
+}
+
+// handleSubscribe 订阅主题模式（如 stock.AAPL.* / orders.user123.#），任一模式非法时整体拒绝
+func (h *ProtoMessageHandler) handleSubscribe(session getty.Session, p *proto.Proto) error {
+	var body topicsBody
+	err := json.Unmarshal(p.Body, &body)
+	if err == nil {
+		for _, pattern := range body.Topics {
+			if err = pkg.ValidateTopicPattern(pattern); err != nil {
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = h.bucket.SubscribeTopics(h.channel, body.Topics)
+	}
+
+	resp := subscribeReply(p, proto.OpSubscribeReply, "subscribe", err)
+	if err != nil {
+		log.Printf("🚫 [ProtoHandler] 订阅主题失败: userId=%s, topics=%v, err=%v", h.clientId, body.Topics, err)
+	} else {
+		log.Printf("✅ [ProtoHandler] 已订阅主题: userId=%s, topics=%v", h.clientId, body.Topics)
+	}
+	_, _, werr := session.WritePkg(resp, 0)
+	return werr
+}
+
+// handleUnsubscribe 取消订阅主题模式（未订阅的模式忽略）
+func (h *ProtoMessageHandler) handleUnsubscribe(session getty.Session, p *proto.Proto) error {
+	var body topicsBody
+	err := json.Unmarshal(p.Body, &body)
+	if err == nil {
+		h.bucket.UnsubscribeTopics(h.channel, body.Topics)
+		log.Printf("✅ [ProtoHandler] 已取消订阅主题: userId=%s, topics=%v", h.clientId, body.Topics)
+	}
+
+	_, _, werr := session.WritePkg(subscribeReply(p, proto.OpUnsubscribeReply, "unsubscribe", err), 0)
+	return werr
+}
+
+// handleWatch 订阅推送 op，任一 op 不在服务端白名单内时整体拒绝
+func (h *ProtoMessageHandler) handleWatch(session getty.Session, p *proto.Proto) error {
+	var body opsBody
+	err := json.Unmarshal(p.Body, &body)
+	if err == nil {
+		for _, op := range body.Ops {
+			if !h.watchAllowed(op) {
+				err = fmt.Errorf("%w: %d", pkg.ErrWatchOpNotAllowed, op)
+				break
+			}
+		}
+	}
+	if err == nil {
+		h.channel.Watch(body.Ops...)
+		log.Printf("✅ [ProtoHandler] 已订阅 op: userId=%s, ops=%v", h.clientId, body.Ops)
+	} else {
+		log.Printf("🚫 [ProtoHandler] 订阅 op 失败: userId=%s, ops=%v, err=%v", h.clientId, body.Ops, err)
+	}
+
+	_, _, werr := session.WritePkg(subscribeReply(p, proto.OpWatchReply, "watch", err), 0)
+	return werr
+}
+
+// handleUnWatch 取消订阅推送 op
+func (h *ProtoMessageHandler) handleUnWatch(session getty.Session, p *proto.Proto) error {
+	var body opsBody
+	err := json.Unmarshal(p.Body, &body)
+	if err == nil {
+		h.channel.UnWatch(body.Ops...)
+		log.Printf("✅ [ProtoHandler] 已取消订阅 op: userId=%s, ops=%v", h.clientId, body.Ops)
+	}
+
+	_, _, werr := session.WritePkg(subscribeReply(p, proto.OpUnWatchReply, "unwatch", err), 0)
+	return werr
+}
+
+// watchAllowed op 是否在服务端允许订阅的范围内
+func (h *ProtoMessageHandler) watchAllowed(op int32) bool {
+	for _, r := range h.server.config.Protocol.WatchOps {
+		if r.Contains(op) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyInitialSubscriptions 加入房间成功后应用请求体携带的初始订阅：
+// 未指定 op 时订阅 OpSendMsg（兼容旧客户端），不允许的 op 和非法的主题模式跳过
+func (h *ProtoMessageHandler) applyInitialSubscriptions(body joinRoomBody) {
+	ops := body.WatchOps
+	if len(ops) == 0 {
+		ops = []int32{proto.OpSendMsg}
+	}
+	allowed := make([]int32, 0, len(ops))
+	for _, op := range ops {
+		if h.watchAllowed(op) {
+			allowed = append(allowed, op)
+		} else {
+			log.Printf("⚠️  [ProtoHandler] 初始订阅跳过不允许的 op: userId=%s, op=%d", h.clientId, op)
+		}
+	}
+	h.channel.Watch(allowed...)
+	log.Printf("✅ [ProtoHandler] 已订阅消息推送: ops=%v", allowed)
+
+	if len(body.Topics) == 0 {
+		return
+	}
+	topics := make([]string, 0, len(body.Topics))
+	for _, pattern := range body.Topics {
+		if pkg.ValidateTopicPattern(pattern) == nil {
+			topics = append(topics, pattern)
+		} else {
+			log.Printf("⚠️  [ProtoHandler] 初始订阅跳过非法的主题模式: userId=%s, topic=%q", h.clientId, pattern)
+		}
+	}
+	if err := h.bucket.SubscribeTopics(h.channel, topics); err != nil {
+		log.Printf("⚠️  [ProtoHandler] 初始主题订阅失败: userId=%s, err=%v", h.clientId, err)
+		return
+	}
+	log.Printf("✅ [ProtoHandler] 已订阅主题: topics=%v", topics)
+}
+
+// subscribeReply 订阅类请求的响应（与加入房间、发言响应一致的文本格式）
+func subscribeReply(p *proto.Proto, op int32, action string, err error) *proto.Proto {
+	resp := &proto.Proto{
+		Ver:    p.Ver,
+		Op:     op,
+		Seq:    p.Seq,
+		Roomid: p.Roomid,
+		Userid: p.Userid,
+		Body:   []byte(action + " success"),
+	}
+	if err != nil {
+		resp.Body = []byte(action + " failed: " + err.Error())
+	}
+	return resp
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+)
+
+// maxChannelTopics 每个连接最多订阅的主题模式数
+const maxChannelTopics = 64
+
+// TopicTrie 主题订阅前缀树（每个 bucket 一棵）：订阅模式按 "." 分段插入，
+// 发布时沿主题分段向下匹配，"*" 匹配任意一段，"#" 匹配剩余的零或多段
+type TopicTrie struct {
+	mu   sync.RWMutex
+	root *topicNode
+}
+
+type topicNode struct {
+	children map[string]*topicNode
+	subs     map[*Channel]struct{}
+}
+
+func newTopicNode() *topicNode {
+	return &topicNode{
+		children: make(map[string]*topicNode),
+		subs:     make(map[*Channel]struct{}),
+	}
+}
+
+func NewTopicTrie() *TopicTrie {
+	return &TopicTrie{root: newTopicNode()}
+}
+
+// Subscribe 添加订阅（pattern 需已校验）
+func (t *TopicTrie) Subscribe(pattern string, ch *Channel) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	node := t.root
+	for _, seg := range strings.Split(pattern, pkg.TopicSeparator) {
+		child, ok := node.children[seg]
+		if !ok {
+			child = newTopicNode()
+			node.children[seg] = child
+		}
+		node = child
+	}
+	node.subs[ch] = struct{}{}
+}
+
+// Unsubscribe 取消订阅，并清理不再有订阅的分支
+func (t *TopicTrie) Unsubscribe(pattern string, ch *Channel) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	segs := strings.Split(pattern, pkg.TopicSeparator)
+	path := make([]*topicNode, 0, len(segs)+1)
+	node := t.root
+	path = append(path, node)
+	for _, seg := range segs {
+		child, ok := node.children[seg]
+		if !ok {
+			return
+		}
+		node = child
+		path = append(path, node)
+	}
+	delete(node.subs, ch)
+
+	for i := len(segs) - 1; i >= 0; i-- {
+		n := path[i+1]
+		if len(n.subs) > 0 || len(n.children) > 0 {
+			break
+		}
+		delete(path[i].children, segs[i])
+	}
+}
+
+// Match 订阅模式匹配 topic 的全部连接（同一连接的多个匹配模式只返回一次）
+func (t *TopicTrie) Match(topic string) []*Channel {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	matched := make(map[*Channel]struct{})
+	t.match(t.root, strings.Split(topic, pkg.TopicSeparator), matched)
+
+	chs := make([]*Channel, 0, len(matched))
+	for ch := range matched {
+		chs = append(chs, ch)
+	}
+	return chs
+}
+
+func (t *TopicTrie) match(node *topicNode, segs []string, matched map[*Channel]struct{}) {
+	// "#" 匹配剩余的零或多段
+	if all, ok := node.children[pkg.TopicWildcardAll]; ok {
+		for ch := range all.subs {
+			matched[ch] = struct{}{}
+		}
+	}
+	if len(segs) == 0 {
+		for ch := range node.subs {
+			matched[ch] = struct{}{}
+		}
+		return
+	}
+	if child, ok := node.children[segs[0]]; ok {
+		t.match(child, segs[1:], matched)
+	}
+	if one, ok := node.children[pkg.TopicWildcardOne]; ok {
+		t.match(one, segs[1:], matched)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestTopicTrie(t *testing.T) {
+	var (
+		exact  = &Channel{}
+		one    = &Channel{}
+		all    = &Channel{}
+		root   = &Channel{}
+		multi  = &Channel{}
+		names  = map[*Channel]string{exact: "exact", one: "one", all: "all", root: "root", multi: "multi"}
+		trie   = NewTopicTrie()
+		toList = func(chs []*Channel) []string {
+			list := make([]string, 0, len(chs))
+			for _, ch := range chs {
+				list = append(list, names[ch])
+			}
+			sort.Strings(list)
+			return list
+		}
+	)
+	trie.Subscribe("stock.AAPL.price", exact)
+	trie.Subscribe("stock.*.price", one)
+	trie.Subscribe("stock.#", all)
+	trie.Subscribe("#", root)
+	// 同一连接的多个匹配模式只返回一次
+	trie.Subscribe("orders.*", multi)
+	trie.Subscribe("orders.#", multi)
+
+	tests := []struct {
+		topic string
+		want  []string
+	}{
+		{"stock.AAPL.price", []string{"all", "exact", "one", "root"}},
+		{"stock.MSFT.price", []string{"all", "one", "root"}},
+		{"stock.AAPL", []string{"all", "root"}},
+		{"stock", []string{"all", "root"}},
+		{"stock.AAPL.price.extra", []string{"all", "root"}},
+		{"orders.user1", []string{"multi", "root"}},
+		{"orders", []string{"multi", "root"}},
+		{"news", []string{"root"}},
+	}
+	check := func() {
+		t.Helper()
+		for _, tt := range tests {
+			if got := toList(trie.Match(tt.topic)); !equalStrings(got, tt.want) {
+				t.Errorf("Match(%q) = %v, want %v", tt.topic, got, tt.want)
+			}
+		}
+	}
+	check()
+
+	// 取消不存在的订阅不影响已有订阅
+	trie.Unsubscribe("stock.AAPL.volume", exact)
+	trie.Unsubscribe("stock.#", exact)
+	check()
+
+	trie.Unsubscribe("stock.AAPL.price", exact)
+	trie.Unsubscribe("stock.*.price", one)
+	trie.Unsubscribe("stock.#", all)
+	trie.Unsubscribe("orders.*", multi)
+	if got := toList(trie.Match("stock.AAPL.price")); !equalStrings(got, []string{"root"}) {
+		t.Errorf("Match() after Unsubscribe = %v, want [root]", got)
+	}
+	if got := toList(trie.Match("orders.user1")); !equalStrings(got, []string{"multi", "root"}) {
+		t.Errorf("Match() with remaining orders.# = %v, want [multi root]", got)
+	}
+	// 不再有订阅的分支被清理
+	if _, ok := trie.root.children["stock"]; ok {
+		t.Error("stock branch not pruned after the last Unsubscribe")
+	}
+	trie.Unsubscribe("orders.#", multi)
+	trie.Unsubscribe("#", root)
+	if len(trie.root.children) != 0 {
+		t.Errorf("root has %d children after unsubscribing everything, want 0", len(trie.root.children))
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,186 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/pkg/metrics"
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+)
+
+const (
+	// usageFlushInterval 用量写入 Redis、刷新全集群连接数的周期
+	usageFlushInterval = 5 * time.Second
+	// usageConnTTL 节点连接数快照的有效期（节点停止上报后不再计入全集群连接数）
+	usageConnTTL = 3 * usageFlushInterval
+)
+
+// tenantUsage 本节点的租户用量：连接数实时维护，投递数和出站字节数在本地累积后定期写入 Redis。
+// 默认租户（appID 为空）不计量
+type tenantUsage struct {
+	nodeID  string
+	store   *redisstore.UsageStore // Redis 不可用时为 nil（只按本节点连接数限制）
+	metrics *metrics.MetricsCollector
+
+	mu      sync.Mutex
+	conns   map[string]int64                  // appID -> 本节点连接数
+	pending map[string]*redisstore.UsageDelta // 待写入的用量增量
+	cluster map[string]int64                  // 最近一次汇总的全集群连接数
+	synced  map[string]int64                  // 汇总时本节点上报的连接数
+}
+
+func newTenantUsage(nodeID string, store *redisstore.UsageStore, metricsCollector *metrics.MetricsCollector) *tenantUsage {
+	return &tenantUsage{
+		nodeID:  nodeID,
+		store:   store,
+		metrics: metricsCollector,
+		conns:   make(map[string]int64),
+		pending: make(map[string]*redisstore.UsageDelta),
+		cluster: make(map[string]int64),
+		synced:  make(map[string]int64),
+	}
+}
+
+// acquireConnection 按租户连接数限制准入并计入一个连接：超过硬限制返回 ErrTenantQuota，超过软限制只告警。
+// 全集群连接数 = 最近汇总值 - 汇总时本节点的连接数 + 本节点当前连接数
+func (u *tenantUsage) acquireConnection(ctx context.Context, tenant *controller.Tenant) error {
+	if tenant == nil {
+		return nil
+	}
+	appID := tenant.AppId
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	current := u.cluster[appID] - u.synced[appID] + u.conns[appID]
+	if tenant.MaxConnections > 0 && current >= int64(tenant.MaxConnections) {
+		u.delta(appID).RejectedConnections++
+		u.metrics.RecordTenantLimit(ctx, appID, "connections", "hard")
+		log.Printf("🚦 [ConnectNodeServer] 租户连接数超过硬限制: app=%s, current=%d, limit=%d", appID, current, tenant.MaxConnections)
+		return pkg.ErrTenantQuota
+	}
+	if tenant.SoftMaxConnections > 0 && current >= int64(tenant.SoftMaxConnections) {
+		u.metrics.RecordTenantLimit(ctx, appID, "connections", "soft")
+		log.Printf("⚠️  [ConnectNodeServer] 租户连接数超过软限制: app=%s, current=%d, soft=%d", appID, current+1, tenant.SoftMaxConnections)
+	}
+	u.conns[appID]++
+	return nil
+}
+
+// releaseConnection 连接关闭
+func (u *tenantUsage) releaseConnection(appID string) {
+	if appID == "" {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.conns[appID]--; u.conns[appID] <= 0 {
+		delete(u.conns, appID)
+	}
+}
+
+// delivered 计入一条投递到客户端的消息
+func (u *tenantUsage) delivered(appID string, bytes int) {
+	if appID == "" {
+		return
+	}
+	u.mu.Lock()
+	d := u.delta(appID)
+	d.Delivered++
+	d.BytesOut += int64(bytes)
+	u.mu.Unlock()
+}
+
+// delta 租户待写入的用量增量（调用者持有锁）
+func (u *tenantUsage) delta(appID string) *redisstore.UsageDelta {
+	d, ok := u.pending[appID]
+	if !ok {
+		d = &redisstore.UsageDelta{}
+		u.pending[appID] = d
+	}
+	return d
+}
+
+// flush 写入用量增量和本节点连接数快照，并刷新全集群连接数（写入失败的增量并入下次）
+func (u *tenantUsage) flush(ctx context.Context) {
+	u.mu.Lock()
+	pending := u.pending
+	u.pending = make(map[string]*redisstore.UsageDelta)
+	conns := make(map[string]int64, len(u.conns))
+	for appID, n := range u.conns {
+		conns[appID] = n
+	}
+	u.mu.Unlock()
+
+	now := time.Now()
+	if err := u.store.AddUsage(ctx, now, pending); err != nil {
+		log.Printf("⚠️  [ConnectNodeServer] 写入租户用量失败: %v", err)
+		u.mu.Lock()
+		for appID, d := range pending {
+			cur := u.delta(appID)
+			cur.Delivered += d.Delivered
+			cur.BytesOut += d.BytesOut
+			cur.RejectedConnections += d.RejectedConnections
+		}
+		u.mu.Unlock()
+	}
+
+	if err := u.store.SetNodeConnections(ctx, u.nodeID, conns, usageConnTTL); err != nil {
+		log.Printf("⚠️  [ConnectNodeServer] 上报租户连接数失败: %v", err)
+		return
+	}
+	cluster, err := u.store.ClusterConnections(ctx)
+	if err != nil {
+		log.Printf("⚠️  [ConnectNodeServer] 汇总租户连接数失败: %v", err)
+		return
+	}
+	if err := u.store.RecordPeakConnections(ctx, now, cluster); err != nil {
+		log.Printf("⚠️  [ConnectNodeServer] 记录连接数峰值失败: %v", err)
+	}
+
+	u.mu.Lock()
+	u.cluster = cluster
+	u.synced = conns
+	u.mu.Unlock()
+}
+
+// RunUsageReport 定期写入租户用量（Redis 不可用时不计量，连接数只按本节点限制）
+func (s *ConnectNodeServer) RunUsageReport(ctx context.Context) {
+	if s.usage.store == nil {
+		return
+	}
+	ticker := time.NewTicker(usageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// 退出前写入剩余用量，并清空本节点的连接数快照
+			flushCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			s.usage.flush(flushCtx)
+			s.usage.store.SetNodeConnections(flushCtx, s.nodeID, nil, usageConnTTL)
+			cancel()
+			return
+		case <-ticker.C:
+			s.usage.flush(ctx)
+		}
+	}
+}
@@ -0,0 +1,411 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg/database"
+	"github.com/livekit/psrpc/examples/pubsub/pkg/etcd"
+	"github.com/livekit/psrpc/examples/pubsub/pkg/metrics"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/admin"
+)
+
+const (
+	// adminCallTimeout 调用单个 Connect-Node / Push-Manager 的超时（不可达的节点不阻塞整体结果）
+	adminCallTimeout = 3 * time.Second
+	// adminConnPruneInterval 关闭已下线 Connect-Node 连接的周期
+	adminConnPruneInterval = time.Minute
+)
+
+// AdminServer 集群运维接口：节点表来自 MySQL，会话、分布和 drain 状态实时查询各 Connect-Node，
+// 出站队列和熔断器状态实时查询各 Push-Manager
+type AdminServer struct {
+	admin.UnimplementedAdminServiceServer
+
+	repo *database.Repository
+
+	// Push-Manager 服务发现（etcd 不可用时为 nil）
+	pushDiscovery *etcd.ServiceDiscovery
+
+	// 到 Connect-Node / Push-Manager 的连接（地址 -> 连接，按需建立）
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+
+	metrics *metrics.MetricsCollector
+}
+
+// NewAdminServer 创建运维服务
+func NewAdminServer(repo *database.Repository, pushDiscovery *etcd.ServiceDiscovery, metricsCollector *metrics.MetricsCollector) *AdminServer {
+	return &AdminServer{
+		repo:          repo,
+		pushDiscovery: pushDiscovery,
+		conns:         make(map[string]*grpc.ClientConn),
+		metrics:       metricsCollector,
+	}
+}
+
+// ListNodes 列出 Connect-Node 和 Push-Manager
+func (s *AdminServer) ListNodes(ctx context.Context, req *admin.ListNodesRequest) (*admin.ListNodesResponse, error) {
+	var (
+		nodes []*database.ConnectNode
+		err   error
+	)
+	if req.IncludeOffline {
+		nodes, err = s.repo.ListAllNodes(ctx)
+	} else {
+		nodes, err = s.repo.ListNodes(ctx)
+	}
+	if err != nil {
+		s.metrics.RecordAPIRequest(ctx, "Admin.ListNodes", false)
+		return nil, err
+	}
+
+	resp := &admin.ListNodesResponse{ConnectNodes: make([]*admin.ConnectNodeInfo, len(nodes))}
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		info := &admin.ConnectNodeInfo{
+			NodeId:         node.ID,
+			Address:        node.Address,
+			Region:         node.Region,
+			Status:         node.Status,
+			MaxConnections: int32(node.MaxConnections),
+			Connections:    int32(node.CurrentConnections),
+			Rooms:          int32(node.RoomCount),
+			CpuUsage:       node.CPUUsage,
+			MemoryUsage:    node.MemoryUsage,
+			LastHeartbeat:  node.LastHeartbeat.Unix(),
+		}
+		resp.ConnectNodes[i] = info
+		if node.Status != "online" {
+			continue
+		}
+		wg.Add(1)
+		go func(address string) {
+			defer wg.Done()
+			client, err := s.nodeClient(address)
+			if err != nil {
+				return
+			}
+			callCtx, cancel := context.WithTimeout(ctx, adminCallTimeout)
+			defer cancel()
+			if live, err := client.GetNodeStatus(callCtx, &admin.GetNodeStatusRequest{}); err == nil {
+				info.Reachable = true
+				info.Live = live
+			}
+		}(node.Address)
+	}
+
+	var managers []string
+	if s.pushDiscovery != nil {
+		managers, _ = s.pushDiscovery.GetEndpoints()
+		sort.Strings(managers)
+	}
+	resp.PushManagers = make([]*admin.PushManagerStatus, len(managers))
+	for i, address := range managers {
+		resp.PushManagers[i] = &admin.PushManagerStatus{Address: address}
+		wg.Add(1)
+		go func(status *admin.PushManagerStatus) {
+			defer wg.Done()
+			conn, err := s.conn(status.Address)
+			if err != nil {
+				return
+			}
+			callCtx, cancel := context.WithTimeout(ctx, adminCallTimeout)
+			defer cancel()
+			live, err := admin.NewPushAdminClient(conn).GetPushStatus(callCtx, &admin.GetPushStatusRequest{})
+			if err != nil {
+				return
+			}
+			status.ManagerId = live.ManagerId
+			status.Targets = live.Targets
+			status.Reachable = true
+		}(resp.PushManagers[i])
+	}
+	wg.Wait()
+
+	s.metrics.RecordAPIRequest(ctx, "Admin.ListNodes", true)
+	return resp, nil
+}
+
+// ListSessions 列出节点上的会话
+func (s *AdminServer) ListSessions(ctx context.Context, req *admin.ListSessionsRequest) (*admin.ListSessionsResponse, error) {
+	nodes, err := s.targetNodes(ctx, req.NodeId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &admin.ListSessionsResponse{}
+	var mu sync.Mutex
+	s.fanOut(ctx, nodes, func(callCtx context.Context, node *database.ConnectNode, client admin.NodeAdminClient) error {
+		reply, err := client.ListSessions(callCtx, req)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		resp.Sessions = append(resp.Sessions, reply.Sessions...)
+		resp.Truncated = resp.Truncated || reply.Truncated
+		mu.Unlock()
+		return nil
+	}, &resp.UnreachableNodes)
+
+	sort.Slice(resp.Sessions, func(i, j int) bool {
+		if resp.Sessions[i].NodeId != resp.Sessions[j].NodeId {
+			return resp.Sessions[i].NodeId < resp.Sessions[j].NodeId
+		}
+		return resp.Sessions[i].ConnectedAt < resp.Sessions[j].ConnectedAt
+	})
+	return resp, nil
+}
+
+// DisconnectSession 强制断开会话
+func (s *AdminServer) DisconnectSession(ctx context.Context, req *admin.DisconnectSessionRequest) (*admin.DisconnectSessionResponse, error) {
+	if req.UserId == "" {
+		return &admin.DisconnectSessionResponse{Success: false, Message: "user_id 不能为空"}, nil
+	}
+	nodes, err := s.targetNodes(ctx, req.NodeId)
+	if err != nil {
+		return &admin.DisconnectSessionResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	resp := &admin.DisconnectSessionResponse{Success: true}
+	var mu sync.Mutex
+	s.fanOut(ctx, nodes, func(callCtx context.Context, node *database.ConnectNode, client admin.NodeAdminClient) error {
+		reply, err := client.DisconnectSession(callCtx, req)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		resp.Disconnected += reply.Disconnected
+		mu.Unlock()
+		return nil
+	}, &resp.UnreachableNodes)
+
+	resp.Message = fmt.Sprintf("已断开 %d 个会话", resp.Disconnected)
+	log.Printf("🔌 [Controller] 运维断开会话: user=%s, device=%s, conn=%s, disconnected=%d, reason=%s\n",
+		req.UserId, req.DeviceId, req.ConnId, resp.Disconnected, req.Reason)
+	s.metrics.RecordAPIRequest(ctx, "Admin.DisconnectSession", true)
+	return resp, nil
+}
+
+// GetDistribution bucket 和房间分布
+func (s *AdminServer) GetDistribution(ctx context.Context, req *admin.GetDistributionRequest) (*admin.GetDistributionResponse, error) {
+	nodes, err := s.targetNodes(ctx, req.NodeId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &admin.GetDistributionResponse{}
+	var mu sync.Mutex
+	s.fanOut(ctx, nodes, func(callCtx context.Context, node *database.ConnectNode, client admin.NodeAdminClient) error {
+		dist, err := client.GetDistribution(callCtx, req)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		resp.Nodes = append(resp.Nodes, dist)
+		mu.Unlock()
+		return nil
+	}, &resp.UnreachableNodes)
+
+	sort.Slice(resp.Nodes, func(i, j int) bool { return resp.Nodes[i].NodeId < resp.Nodes[j].NodeId })
+	return resp, nil
+}
+
+// SetDrain 开启/关闭节点的 drain 模式（节点重启后恢复为关闭）
+func (s *AdminServer) SetDrain(ctx context.Context, req *admin.SetDrainRequest) (*admin.SetDrainResponse, error) {
+	if req.NodeId == "" {
+		return &admin.SetDrainResponse{Success: false, Message: "node_id 不能为空"}, nil
+	}
+	node, err := s.repo.GetNode(ctx, req.NodeId)
+	if err != nil {
+		return &admin.SetDrainResponse{Success: false, Message: err.Error()}, err
+	}
+	if node == nil {
+		return &admin.SetDrainResponse{Success: false, Message: "节点不存在: " + req.NodeId}, nil
+	}
+
+	client, err := s.nodeClient(node.Address)
+	if err != nil {
+		return &admin.SetDrainResponse{Success: false, Message: err.Error()}, nil
+	}
+	callCtx, cancel := context.WithTimeout(ctx, adminCallTimeout)
+	defer cancel()
+	resp, err := client.SetDrain(callCtx, req)
+	if err != nil {
+		s.metrics.RecordAPIRequest(ctx, "Admin.SetDrain", false)
+		return &admin.SetDrainResponse{Success: false, Message: "节点不可达: " + err.Error()}, nil
+	}
+
+	log.Printf("🚧 [Controller] 节点 drain 模式: node=%s, drain=%v, sessions=%d\n", req.NodeId, resp.Draining, resp.Sessions)
+	s.metrics.RecordAPIRequest(ctx, "Admin.SetDrain", true)
+	return resp, nil
+}
+
+// WatchPushManagers 消费 Push-Manager 上下线事件，关闭已下线实例的连接
+func (s *AdminServer) WatchPushManagers(ctx context.Context) {
+	if s.pushDiscovery == nil {
+		return
+	}
+	eventChan := s.pushDiscovery.GetEventChan()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-eventChan:
+			if !ok {
+				return
+			}
+			if event.Type == etcd.EventDelete {
+				s.forget(event.Addr)
+			}
+		}
+	}
+}
+
+// targetNodes nodeID 为空时为全部在线节点，否则为指定节点
+func (s *AdminServer) targetNodes(ctx context.Context, nodeID string) ([]*database.ConnectNode, error) {
+	if nodeID == "" {
+		return s.repo.ListNodes(ctx)
+	}
+	node, err := s.repo.GetNode(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, fmt.Errorf("node not found: %s", nodeID)
+	}
+	return []*database.ConnectNode{node}, nil
+}
+
+// fanOut 并发调用各节点，调用失败的节点 ID 记入 unreachable
+func (s *AdminServer) fanOut(ctx context.Context, nodes []*database.ConnectNode,
+	call func(ctx context.Context, node *database.ConnectNode, client admin.NodeAdminClient) error, unreachable *[]string) {
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, node := range nodes {
+		wg.Add(1)
+		go func(node *database.ConnectNode) {
+			defer wg.Done()
+			client, err := s.nodeClient(node.Address)
+			if err == nil {
+				callCtx, cancel := context.WithTimeout(ctx, adminCallTimeout)
+				err = call(callCtx, node, client)
+				cancel()
+			}
+			if err != nil {
+				log.Printf("⚠️  [Controller] 调用节点运维接口失败: node=%s, err=%v\n", node.ID, err)
+				mu.Lock()
+				*unreachable = append(*unreachable, node.ID)
+				mu.Unlock()
+			}
+		}(node)
+	}
+	wg.Wait()
+	sort.Strings(*unreachable)
+}
+
+func (s *AdminServer) nodeClient(address string) (admin.NodeAdminClient, error) {
+	conn, err := s.conn(address)
+	if err != nil {
+		return nil, err
+	}
+	return admin.NewNodeAdminClient(conn), nil
+}
+
+// conn 到 address 的连接（非阻塞建立，首次调用时连接）
+func (s *AdminServer) conn(address string) (*grpc.ClientConn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if conn, ok := s.conns[address]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	s.conns[address] = conn
+	return conn, nil
+}
+
+// PruneNodeConns 周期关闭到已不在线的 Connect-Node 的连接（节点下线由其他 Controller 副本清理时本副本收不到事件）
+func (s *AdminServer) PruneNodeConns(ctx context.Context) {
+	ticker := time.NewTicker(adminConnPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.pruneConns(ctx)
+		}
+	}
+}
+
+// pruneConns 关闭不属于在线 Connect-Node 或 Push-Manager 的连接
+func (s *AdminServer) pruneConns(ctx context.Context) {
+	nodes, err := s.repo.ListNodes(ctx)
+	if err != nil {
+		log.Printf("⚠️  [Controller] 查询在线节点失败，跳过清理运维连接: %v\n", err)
+		return
+	}
+	live := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		live[node.Address] = true
+	}
+	if s.pushDiscovery != nil {
+		managers, _ := s.pushDiscovery.GetEndpoints()
+		for _, address := range managers {
+			live[address] = true
+		}
+	}
+
+	s.mu.Lock()
+	var stale []string
+	for address := range s.conns {
+		if !live[address] {
+			stale = append(stale, address)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, address := range stale {
+		log.Printf("🔌 [Controller] 关闭已下线节点的运维连接: %s\n", address)
+		s.forget(address)
+	}
+}
+
+// forget 关闭并移除到 address 的连接
+func (s *AdminServer) forget(address string) {
+	s.mu.Lock()
+	conn, ok := s.conns[address]
+	delete(s.conns, address)
+	s.mu.Unlock()
+
+	if ok {
+		conn.Close()
+	}
+}
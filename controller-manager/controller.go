@@ -17,19 +17,28 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
-
-	"github.com/livekit/psrpc/examples/pubsub/protocol/push"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
 	"log"
+	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/config"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/database"
+	"github.com/livekit/psrpc/examples/pubsub/pkg/etcd"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/metrics"
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/tracing"
+	"github.com/livekit/psrpc/examples/pubsub/pkg/types"
 )
 
 const (
@@ -54,8 +63,23 @@ type ControllerServer struct {
 	// Redis 缓存
 	redis *redis.Client
 
-	// push-manager
-	pushClient *push.CometClient
+	// 用户 -> 连接索引（Redis 不可用时为 nil）
+	userConns *redisstore.UserConnStore
+
+	// 租户用量（GetUsageReport，Redis 不可用时为 nil）
+	usage *redisstore.UsageStore
+
+	// push-manager（推送房间在线状态变化）
+	pushClient broadcast.PushServerClient
+
+	// 生命周期事件：事件日志（WatchEvents，Redis 不可用时为 nil）、webhook（未配置 endpoint 时为 nil）与失败记录
+	eventLog     *redisstore.EventLog
+	webhooks     *webhookDispatcher
+	webhookStore *redisstore.WebhookStore
+
+	// 事件 ID 生成（Controller ID + 启动时间 + 自增序号）
+	eventPrefix string
+	eventSeq    atomic.Uint64
 
 	// Metrics
 	metrics *metrics.MetricsCollector
@@ -63,15 +87,29 @@ type ControllerServer struct {
 
 // NewControllerServer 创建 Controller 服务
 func NewControllerServer(cfg *config.Config, repo *database.Repository, redisClient *redis.Client,
-	pushClient *push.CometClient, metricsCollector *metrics.MetricsCollector) *ControllerServer {
-	return &ControllerServer{
+	pushClient broadcast.PushServerClient, metricsCollector *metrics.MetricsCollector) *ControllerServer {
+	s := &ControllerServer{
 		id:         cfg.Server.ID,
 		config:     cfg,
 		repo:       repo,
 		redis:      redisClient,
 		pushClient: pushClient,
 		metrics:    metricsCollector,
+
+		eventPrefix: fmt.Sprintf("%s-%d", cfg.Server.ID, time.Now().UnixNano()),
 	}
+	if redisClient != nil {
+		s.userConns = redisstore.NewUserConnStore(redisClient)
+		s.usage = redisstore.NewUsageStore(redisClient)
+		s.eventLog = redisstore.NewEventLog(redisClient, eventLogMaxLen)
+		s.webhookStore = redisstore.NewWebhookStore(redisClient, webhookFailureMaxLen)
+	}
+	var webhookQueue *redisstore.WebhookQueue
+	if redisClient != nil {
+		webhookQueue = redisstore.NewWebhookQueue(redisClient, webhookQueueMaxLen)
+	}
+	s.webhooks = newWebhookDispatcher(cfg.Webhook, s.webhookStore, webhookQueue, cfg.Server.ID, metricsCollector)
+	return s
 }
 
 // ========== Room Management ==========
@@ -88,26 +126,34 @@ func (s *ControllerServer) JoinRoom(ctx context.Context, req *controller.JoinRoo
 		tracing.AttrNodeID.String(req.NodeId),
 	)
 
-	log.Printf("👤 [Controller] 用户加入房间: %s -> %s (最大用户数: %d)\n",
-		req.UserName, req.RoomId, s.config.Room.DefaultMaxUsers)
+	log.Printf("👤 [Controller] 用户加入房间: %s -> %s\n", req.UserName, req.RoomId)
 
-	// 🔥 关键：使用 MySQL 事务保证一致性（支持多 Controller 节点）
+	// 🔥 关键：使用 MySQL 事务保证一致性（支持多 Controller 节点），容量和加入方式按房间策略校验
 	tracing.AddSpanEvent(ctx, "db_transaction_join_room")
-	err := s.repo.UserJoinRoom(ctx, req.UserId, req.UserName, req.RoomId, req.NodeId, int32(s.config.Room.DefaultMaxUsers))
+	room, member, err := s.repo.UserJoinRoom(ctx, &database.JoinRoomParams{
+		UserID:          req.UserId,
+		UserName:        req.UserName,
+		RoomID:          req.RoomId,
+		NodeID:          req.NodeId,
+		Password:        req.Password,
+		DefaultMaxUsers: int32(s.config.Room.DefaultMaxUsers),
+	})
 	if err != nil {
 		log.Printf("❌ [Controller] 加入房间失败: %v\n", err)
 		tracing.RecordError(ctx, err)
 
-		// 检查是否是房间已满
-		if err.Error() == "gorm.ErrInvalidData" {
+		// 房间策略拒绝不是系统错误，返回 Success=false
+		if msg, ok := joinRejectMessage(err); ok {
+			s.metrics.RecordAPIRequest(ctx, "JoinRoom", false)
 			return &controller.JoinRoomResponse{
 				Success: false,
-				Message: "房间已满",
+				Message: msg,
 			}, nil
 		}
 
 		return &controller.JoinRoomResponse{Success: false, Message: err.Error()}, err
 	}
+	s.invalidateRoomPolicy(ctx, req.RoomId)
 
 	// 缓存用户到房间的 Hash 中
 	roomUsersKey := fmt.Sprintf("room_users:%s", req.RoomId)
@@ -115,64 +161,102 @@ func (s *ControllerServer) JoinRoom(ctx context.Context, req *controller.JoinRoo
 		"user_name": req.UserName,
 		"node_id":   req.NodeId,
 		"room_id":   req.RoomId,
+		"role":      member.Role,
 		"timestamp": time.Now().Unix(),
 	}
+	// 写入和计数在同一个脚本中完成：只有让房间从空变为有人的那次加入发出 room.created
+	var (
+		userCount   int64
+		roomCreated bool
+	)
 	if data, err := json.Marshal(userOnlineData); err == nil {
-		s.redis.HSet(ctx, roomUsersKey, req.UserId, data)
-		s.redis.Expire(ctx, roomUsersKey, s.config.Room.CacheTTL)
+		roomCreated, userCount, err = redisstore.AddRoomUser(ctx, s.redis, roomUsersKey, req.UserId, data, s.config.Room.CacheTTL)
+		if err != nil {
+			log.Printf("⚠️  [Controller] 写入房间用户缓存失败: %v\n", err)
+		}
 	}
 
-	// 获取房间当前用户数（用于 metrics）
-	userCount, _ := s.redis.HLen(ctx, roomUsersKey).Result()
+	// 写入 user -> connections 索引
+	s.indexUserRoom(ctx, req.UserId, req.ConnId, req.NodeId, req.RoomId)
+
+	if roomCreated {
+		s.emitEvent(ctx, &redisstore.LifecycleEvent{Type: redisstore.EventRoomCreated, RoomID: req.RoomId, UserID: req.UserId})
+	}
 
 	tracing.AddSpanAttributes(ctx, tracing.AttrUserCount.Int(int(userCount)))
-	log.Printf("✅ [Controller] 用户加入成功: %s, 房间人数: %d\n", req.UserName, userCount)
+	log.Printf("✅ [Controller] 用户加入成功: %s (%s), 房间人数: %d\n", req.UserName, member.Role, userCount)
 
 	// 更新 metrics
 	s.metrics.SetRoomUserCount(req.RoomId, userCount)
 	s.metrics.RecordAPIRequest(ctx, "JoinRoom", true)
+	s.emitEvent(ctx, &redisstore.LifecycleEvent{
+		Type:   redisstore.EventRoomJoined,
+		UserID: req.UserId,
+		RoomID: req.RoomId,
+		ConnID: req.ConnId,
+		NodeID: req.NodeId,
+		Role:   member.Role,
+	})
 
 	tracing.SetSpanSuccess(ctx)
 	return &controller.JoinRoomResponse{
 		Success: true,
 		Message: "加入房间成功",
+		Role:    member.Role,
 		RoomInfo: &controller.RoomInfo{
 			RoomId: req.RoomId,
 			Metadata: &controller.RoomMetadata{
-				Name:        req.RoomId, // 默认使用 RoomId 作为名称
-				Description: "",
+				Name:        room.Name,
+				Description: room.Description,
+				MaxUsers:    s.effectiveMaxUsers(room),
 			},
-			CreatedAt: time.Now().Unix(),
-			UpdatedAt: time.Now().Unix(),
+			Policy:    s.roomPolicyToProto(room),
+			CreatedAt: room.CreatedAt.Unix(),
+			UpdatedAt: room.UpdatedAt.Unix(),
 		},
 	}, nil
 }
 
+// joinRejectMessage 将房间策略错误转换为返回给客户端的提示
+func joinRejectMessage(err error) (string, bool) {
+	switch {
+	case errors.Is(err, database.ErrRoomFull):
+		return "房间已满", true
+	case errors.Is(err, database.ErrNotInvited):
+		return "房间仅限受邀用户加入", true
+	case errors.Is(err, database.ErrWrongPassword):
+		return "房间密码错误", true
+	}
+	return "", false
+}
+
 // LeaveRoom 用户离开房间
 func (s *ControllerServer) LeaveRoom(ctx context.Context, req *controller.LeaveRoomRequest) (*controller.LeaveRoomResponse, error) {
 	log.Printf("👋 [Controller] 用户离开房间: %s <- %s\n", req.RoomId, req.UserId)
 
+	// 指定连接时只标记该连接所在节点的成员记录，否则（如踢人）标记全部节点
+	nodeID := ""
+	if req.ConnId != "" && s.userConns != nil {
+		if conn, err := s.userConns.GetConnection(ctx, req.UserId, req.ConnId); err == nil && conn != nil {
+			nodeID = conn.NodeID
+		}
+	}
+
 	// 从数据库更新（标记 left_at）
-	err := s.repo.UserLeaveRoom(ctx, req.UserId, req.RoomId)
+	left, err := s.repo.UserLeaveRoom(ctx, req.UserId, req.RoomId, nodeID)
 	if err != nil {
 		log.Printf("❌ [Controller] 离开房间失败: %v\n", err)
 		return &controller.LeaveRoomResponse{Success: false, Message: err.Error()}, err
 	}
 
-	// 从房间用户 Hash 中移除该用户
-	roomUsersKey := fmt.Sprintf("room_users:%s", req.RoomId)
-	s.redis.HDel(ctx, roomUsersKey, req.UserId)
-
-	// 获取房间当前用户数（从 Redis）
-	userCount, _ := s.redis.HLen(ctx, roomUsersKey).Result()
-
-	// 更新 metrics
-	if userCount == 0 {
-		s.metrics.DecrementRooms(ctx, 1)
-		s.metrics.RemoveRoom(req.RoomId)
-		log.Printf("🗑️  [Controller] 房间已空: %s\n", req.RoomId)
-	} else {
-		s.metrics.SetRoomUserCount(req.RoomId, userCount)
+	// 用户在其他节点上仍有会话时不算离开房间
+	if left {
+		s.afterUsersLeft(ctx, req.RoomId, []string{req.UserId}, LeaveReasonLeave)
+	}
+	if s.userConns != nil {
+		if err := s.userConns.RemoveRoom(ctx, req.UserId, req.ConnId, req.RoomId); err != nil {
+			log.Printf("⚠️  [Controller] 更新用户连接索引失败: %v\n", err)
+		}
 	}
 	s.metrics.RecordAPIRequest(ctx, "LeaveRoom", true)
 
@@ -180,6 +264,95 @@ func (s *ControllerServer) LeaveRoom(ctx context.Context, req *controller.LeaveR
 	return &controller.LeaveRoomResponse{Success: true, Message: "离开房间成功"}, nil
 }
 
+// 离开原因
+const (
+	LeaveReasonLeave    = "leave"     // 主动离开或被踢出
+	LeaveReasonNodeDown = "node_down" // 所在 Connect-Node 宕机
+)
+
+// afterUsersLeft 用户离开房间后：清理 Redis 房间用户 Hash、更新 metrics、发出离开/在线状态事件
+func (s *ControllerServer) afterUsersLeft(ctx context.Context, roomID string, userIDs []string, reason string) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	if s.redis != nil {
+		// 从房间用户 Hash 中移除这些用户，删除和计数原子完成：只有让房间变空的那次离开发出 room.deleted
+		roomUsersKey := fmt.Sprintf("room_users:%s", roomID)
+		emptied, userCount, err := redisstore.RemoveRoomUsers(ctx, s.redis, roomUsersKey, userIDs...)
+		if err != nil {
+			log.Printf("⚠️  [Controller] 清理房间用户缓存失败: %v\n", err)
+		}
+
+		// 更新 metrics
+		if emptied {
+			s.metrics.DecrementRooms(ctx, 1)
+			s.metrics.RemoveRoom(roomID)
+			log.Printf("🗑️  [Controller] 房间已空: %s\n", roomID)
+			// 房间删除事件在成员离开事件之后发出
+			defer s.emitEvent(ctx, &redisstore.LifecycleEvent{Type: redisstore.EventRoomDeleted, RoomID: roomID, Reason: reason})
+		} else if err == nil {
+			s.metrics.SetRoomUserCount(roomID, userCount)
+		}
+	}
+
+	for _, userID := range userIDs {
+		s.publishRoomEvent(ctx, &redisstore.RoomEvent{
+			Type:   redisstore.RoomEventLeave,
+			RoomID: roomID,
+			UserID: userID,
+			Reason: reason,
+		})
+		s.pushPresence(ctx, roomID, userID, "leave", reason)
+		s.emitEvent(ctx, &redisstore.LifecycleEvent{Type: redisstore.EventRoomLeft, UserID: userID, RoomID: roomID, Reason: reason})
+	}
+}
+
+// emitEvent 发出生命周期事件：写入事件日志（供 WatchEvents 订阅和断点续读）并投递 webhook
+func (s *ControllerServer) emitEvent(ctx context.Context, event *redisstore.LifecycleEvent) {
+	event.ID = fmt.Sprintf("%s-%d", s.eventPrefix, s.eventSeq.Add(1))
+	event.Timestamp = time.Now().UnixMilli()
+	if s.eventLog != nil {
+		if _, err := s.eventLog.Append(ctx, event); err != nil {
+			log.Printf("⚠️  [Controller] 写入事件日志失败: type=%s, err=%v\n", event.Type, err)
+		}
+	}
+	s.webhooks.Emit(event)
+}
+
+// pushPresence 通过 Push-Manager 向房间推送在线状态变化
+// roomID / userID 为集群内 ID，Push-Manager 按 AppId 重新加上租户前缀，客户端收到的是租户内 ID
+func (s *ControllerServer) pushPresence(ctx context.Context, roomID, userID, event, reason string) {
+	if s.pushClient == nil {
+		return
+	}
+	appID := pkg.TenantOf(roomID)
+	localRoomID := pkg.UnscopeID(appID, roomID)
+	localUserID := pkg.UnscopeID(appID, userID)
+	body, _ := json.Marshal(map[string]interface{}{
+		"event":     event,
+		"user_id":   localUserID,
+		"reason":    reason,
+		"timestamp": time.Now().Unix(),
+	})
+	reply, err := s.pushClient.BroadcastToRoom(ctx, &broadcast.BroadCastRoomReq{
+		AppId:  appID,
+		RoomId: localRoomID,
+		Proto: &protocol.Proto{
+			Op:     protocol.OpPresence,
+			Roomid: localRoomID,
+			Userid: localUserID,
+			Body:   body,
+		},
+	})
+	if err == nil && reply.Code != "0" {
+		err = fmt.Errorf("%s: %s", reply.Msg, reply.Desc)
+	}
+	if err != nil {
+		log.Printf("⚠️  [Controller] 推送在线状态失败: room=%s, user=%s, err=%v\n", roomID, userID, err)
+	}
+}
+
 // GetRoomInfo 获取房间信息（供 Push-Manager 查询）
 func (s *ControllerServer) GetRoomInfo(ctx context.Context, req *controller.GetRoomInfoRequest) (*controller.GetRoomInfoResponse, error) {
 	// 从 Redis Hash 获取房间用户列表
@@ -197,6 +370,7 @@ func (s *ControllerServer) GetRoomInfo(ctx context.Context, req *controller.GetR
 			if json.Unmarshal([]byte(userData), &userInfo) == nil {
 				userName, _ := userInfo["user_name"].(string)
 				nodeId, _ := userInfo["node_id"].(string)
+				role, _ := userInfo["role"].(string)
 				timestamp, _ := userInfo["timestamp"].(float64)
 
 				userInfos = append(userInfos, &controller.UserInfo{
@@ -204,23 +378,32 @@ func (s *ControllerServer) GetRoomInfo(ctx context.Context, req *controller.GetR
 					UserName: userName,
 					NodeId:   nodeId,
 					JoinedAt: int64(timestamp),
+					Role:     role,
 				})
 			}
 		}
 
-		return &controller.GetRoomInfoResponse{
-			RoomInfo: &controller.RoomInfo{
-				RoomId: req.RoomId,
-				Users:  userInfos,
-				Metadata: &controller.RoomMetadata{
-					Name:        req.RoomId,
-					Description: "",
-					MaxUsers:    int32(s.config.Room.DefaultMaxUsers),
-				},
-				CreatedAt: time.Now().Unix(),
-				UpdatedAt: time.Now().Unix(),
+		roomInfo := &controller.RoomInfo{
+			RoomId: req.RoomId,
+			Users:  userInfos,
+			Metadata: &controller.RoomMetadata{
+				Name:        req.RoomId,
+				Description: "",
+				MaxUsers:    int32(s.config.Room.DefaultMaxUsers),
 			},
-		}, nil
+			CreatedAt: time.Now().Unix(),
+			UpdatedAt: time.Now().Unix(),
+		}
+		if room, err := s.loadRoom(ctx, req.RoomId); err == nil && room != nil {
+			roomInfo.Metadata.Name = room.Name
+			roomInfo.Metadata.Description = room.Description
+			roomInfo.Metadata.MaxUsers = s.effectiveMaxUsers(room)
+			roomInfo.Policy = s.roomPolicyToProto(room)
+			roomInfo.CreatedAt = room.CreatedAt.Unix()
+			roomInfo.UpdatedAt = room.UpdatedAt.Unix()
+		}
+
+		return &controller.GetRoomInfoResponse{RoomInfo: roomInfo}, nil
 	}
 
 	// Redis 缓存未命中，从数据库获取
@@ -244,12 +427,14 @@ func (s *ControllerServer) GetRoomInfo(ctx context.Context, req *controller.GetR
 			UserName: u.UserName,
 			NodeId:   u.NodeID,
 			JoinedAt: u.JoinedAt.Unix(),
+			Role:     u.Role,
 		})
 
 		// 回填到 Redis
 		userOnlineData := map[string]interface{}{
 			"user_name": u.UserName,
 			"node_id":   u.NodeID,
+			"role":      u.Role,
 			"timestamp": u.JoinedAt.Unix(),
 		}
 		if data, err := json.Marshal(userOnlineData); err == nil {
@@ -270,8 +455,9 @@ func (s *ControllerServer) GetRoomInfo(ctx context.Context, req *controller.GetR
 			Metadata: &controller.RoomMetadata{
 				Name:        room.Name,
 				Description: room.Description,
-				MaxUsers:    int32(s.config.Room.DefaultMaxUsers),
+				MaxUsers:    s.effectiveMaxUsers(room),
 			},
+			Policy:    s.roomPolicyToProto(room),
 			CreatedAt: room.CreatedAt.Unix(),
 			UpdatedAt: room.UpdatedAt.Unix(),
 		},
@@ -287,18 +473,40 @@ func (s *ControllerServer) GetUserNode(ctx context.Context, req *controller.GetU
 
 	log.Printf("🔍 [Controller] 查询用户节点: %s\n", req.UserId)
 
-	// 1. 先从 Redis 查询（快速路径）
-	// 查找用户在哪个房间
-	var foundRoomID string
-	var foundNodeID string
-	var foundUserName string
+	// 1. 先从 Redis 用户连接索引查询（快速路径，一次 HGETALL）
+	if s.userConns != nil {
+		conns, err := s.userConns.GetConnections(ctx, req.UserId)
+		if err == nil && len(conns) > 0 {
+			resp := &controller.GetUserNodeResponse{
+				NodeId:      conns[0].NodeID,
+				NodeAddress: conns[0].NodeAddress,
+				Found:       true,
+				Connections: make([]*controller.UserConnection, 0, len(conns)),
+			}
+			for _, conn := range conns {
+				if resp.RoomId == "" && len(conn.Rooms) > 0 {
+					resp.RoomId = conn.Rooms[0]
+				}
+				resp.Connections = append(resp.Connections, &controller.UserConnection{
+					ConnId:      conn.ConnID,
+					NodeId:      conn.NodeID,
+					NodeAddress: conn.NodeAddress,
+					Rooms:       conn.Rooms,
+					ConnectedAt: conn.ConnectedAt,
+					DeviceId:    conn.DeviceID,
+					DeviceType:  conn.DeviceType,
+				})
+			}
 
-	// 遍历所有房间的缓存（这里简化处理，实际可以维护 user_id -> room_id 的映射）
-	// 为了性能，我们直接查询数据库
+			s.metrics.RecordAPIRequest(ctx, "GetUserNode", true)
+			tracing.SetSpanSuccess(ctx)
+			return resp, nil
+		}
+	}
 
-	// 2. 从数据库查询用户信息
-	user, err := s.repo.GetUserByID(ctx, req.UserId)
-	if err != nil || user == nil {
+	// 2. 索引未命中，从数据库查询用户的成员记录（每个节点一条）
+	members, err := s.repo.GetUserMemberships(ctx, req.UserId)
+	if err != nil || len(members) == 0 {
 		log.Printf("⚠️  [Controller] 用户不存在或不在线: %s\n", req.UserId)
 		s.metrics.RecordAPIRequest(ctx, "GetUserNode", false)
 		return &controller.GetUserNodeResponse{
@@ -309,38 +517,55 @@ func (s *ControllerServer) GetUserNode(ctx context.Context, req *controller.GetU
 		}, nil
 	}
 
-	foundNodeID = user.NodeID
-	foundRoomID = user.RoomID
-	foundUserName = user.UserName
-
-	// 3. 从 ETCD 或缓存获取节点地址（这里简化，直接返回节点ID）
-	nodeAddress := foundNodeID // 实际应该从 ETCD 获取节点的实际地址
+	// 3. 从节点心跳表获取节点 gRPC 地址，按节点汇总房间
+	resp := &controller.GetUserNodeResponse{
+		NodeId:      members[0].NodeID,
+		NodeAddress: s.resolveNodeAddress(ctx, members[0].NodeID),
+		Found:       true,
+		RoomId:      members[0].RoomID,
+	}
+	byNode := make(map[string]*controller.UserConnection)
+	for _, m := range members {
+		conn, ok := byNode[m.NodeID]
+		if !ok {
+			conn = &controller.UserConnection{
+				NodeId:      m.NodeID,
+				NodeAddress: resp.NodeAddress,
+				ConnectedAt: m.JoinedAt.Unix(),
+			}
+			if m.NodeID != resp.NodeId {
+				conn.NodeAddress = s.resolveNodeAddress(ctx, m.NodeID)
+			}
+			byNode[m.NodeID] = conn
+			resp.Connections = append(resp.Connections, conn)
+		}
+		conn.Rooms = append(conn.Rooms, m.RoomID)
+	}
 
-	log.Printf("✅ [Controller] 找到用户: %s (%s) -> node=%s, room=%s\n",
-		foundUserName, req.UserId, foundNodeID, foundRoomID)
+	log.Printf("✅ [Controller] 找到用户: %s (%s) -> node=%s, room=%s, 节点数=%d\n",
+		members[0].UserName, req.UserId, resp.NodeId, resp.RoomId, len(resp.Connections))
 
 	s.metrics.RecordAPIRequest(ctx, "GetUserNode", true)
 	tracing.SetSpanSuccess(ctx)
 
-	return &controller.GetUserNodeResponse{
-		NodeId:      foundNodeID,
-		NodeAddress: nodeAddress,
-		Found:       true,
-		RoomId:      foundRoomID,
-	}, nil
+	return resp, nil
 }
 
-// GetRoomStats 获取房间统计
+// GetRoomStats 获取租户的房间统计（all_tenants 时统计全部房间）
 func (s *ControllerServer) GetRoomStats(ctx context.Context, req *controller.GetRoomStatsRequest) (*controller.GetRoomStatsResponse, error) {
 	// 从数据库获取统计
-	totalRooms, totalUsers, err := s.repo.GetRoomStats(ctx)
+	appID := req.AppId
+	if req.AllTenants {
+		appID = database.AllTenants
+	}
+	totalRooms, totalUsers, err := s.repo.GetRoomStats(ctx, appID)
 	if err != nil {
 		log.Printf("❌ [Controller] 获取统计失败: %v\n", err)
 		return &controller.GetRoomStatsResponse{}, err
 	}
 
 	// 获取所有房间列表
-	rooms, err := s.repo.ListRooms(ctx, 100, 0)
+	rooms, err := s.repo.ListRooms(ctx, appID, 100, 0)
 	if err != nil {
 		return &controller.GetRoomStatsResponse{
 			TotalRooms: int32(totalRooms),
@@ -365,3 +590,705 @@ func (s *ControllerServer) GetRoomStats(ctx context.Context, req *controller.Get
 		Rooms:      roomStats,
 	}, nil
 }
+
+// ========== Room Policy ==========
+
+// UpdateRoomPolicy 更新房间策略（需要 settings 权限）
+func (s *ControllerServer) UpdateRoomPolicy(ctx context.Context, req *controller.UpdateRoomPolicyRequest) (*controller.UpdateRoomPolicyResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "Controller.UpdateRoomPolicy")
+	defer span.End()
+
+	tracing.AddSpanAttributes(ctx, tracing.AttrRoomID.String(req.RoomId), tracing.AttrUserID.String(req.OperatorId))
+
+	if req.RoomId == "" || req.Policy == nil {
+		return &controller.UpdateRoomPolicyResponse{Success: false, Message: "room_id 和 policy 不能为空"}, nil
+	}
+
+	room, _, allowed, err := s.authorize(ctx, req.RoomId, req.OperatorId, types.PermissionSettings)
+	if err != nil {
+		return &controller.UpdateRoomPolicyResponse{Success: false, Message: err.Error()}, err
+	}
+	if room == nil {
+		return &controller.UpdateRoomPolicyResponse{Success: false, Message: "房间不存在"}, nil
+	}
+	if !allowed {
+		s.metrics.RecordAPIRequest(ctx, "UpdateRoomPolicy", false)
+		return &controller.UpdateRoomPolicyResponse{Success: false, Message: "没有修改房间策略的权限"}, nil
+	}
+	previousOwner := room.OwnerID
+
+	policy := req.Policy
+	fields, err := newUpdateFields(req.UpdateMask, policy)
+	if err != nil {
+		return &controller.UpdateRoomPolicyResponse{Success: false, Message: err.Error()}, nil
+	}
+	updates := make(map[string]interface{})
+	if fields.has("max_users", policy.MaxUsers != 0) {
+		if policy.MaxUsers < 0 {
+			return &controller.UpdateRoomPolicyResponse{Success: false, Message: "max_users 不能为负数"}, nil
+		}
+		updates["max_users"] = policy.MaxUsers
+	}
+	joinMode := room.JoinMode
+	if fields.has("join_mode", policy.JoinMode != "") {
+		if !types.ValidJoinMode(policy.JoinMode) {
+			return &controller.UpdateRoomPolicyResponse{Success: false, Message: "非法的 join_mode: " + policy.JoinMode}, nil
+		}
+		joinMode = policy.JoinMode
+		updates["join_mode"] = policy.JoinMode
+	}
+	passwordHash := room.PasswordHash
+	if fields.has("password", policy.Password != "") {
+		passwordHash = ""
+		if policy.Password != "" {
+			passwordHash = database.HashRoomPassword(req.RoomId, policy.Password)
+		}
+		updates["password_hash"] = passwordHash
+	}
+	if joinMode == types.JoinModePassword && passwordHash == "" {
+		return &controller.UpdateRoomPolicyResponse{Success: false, Message: "password 模式需要设置密码"}, nil
+	}
+	if fields.has("publish_policy", policy.PublishPolicy != "") {
+		if !types.ValidPublishPolicy(policy.PublishPolicy) {
+			return &controller.UpdateRoomPolicyResponse{Success: false, Message: "非法的 publish_policy: " + policy.PublishPolicy}, nil
+		}
+		updates["publish_policy"] = policy.PublishPolicy
+	}
+	if fields.has("max_message_bytes", policy.MaxMessageBytes != 0) {
+		if policy.MaxMessageBytes < 0 {
+			return &controller.UpdateRoomPolicyResponse{Success: false, Message: "max_message_bytes 不能为负数"}, nil
+		}
+		updates["max_message_bytes"] = policy.MaxMessageBytes
+	}
+	if fields.has("owner_id", policy.OwnerId != "") {
+		if policy.OwnerId == "" {
+			return &controller.UpdateRoomPolicyResponse{Success: false, Message: "owner_id 不能为空"}, nil
+		}
+		updates["owner_id"] = policy.OwnerId
+	}
+	if len(updates) == 0 {
+		return &controller.UpdateRoomPolicyResponse{Success: true, Message: "没有需要修改的字段", Policy: s.roomPolicyToProto(room)}, nil
+	}
+
+	room, err = s.repo.UpdateRoomPolicy(ctx, req.RoomId, updates)
+	if err != nil {
+		log.Printf("❌ [Controller] 更新房间策略失败: %v\n", err)
+		tracing.RecordError(ctx, err)
+		s.metrics.RecordAPIRequest(ctx, "UpdateRoomPolicy", false)
+		return &controller.UpdateRoomPolicyResponse{Success: false, Message: err.Error()}, err
+	}
+	s.invalidateRoomPolicy(ctx, req.RoomId)
+
+	// 房主转让：新房主角色为 owner，原房主降为 moderator
+	if room.OwnerID != previousOwner {
+		s.changeRole(ctx, req.RoomId, room.OwnerID, types.RoleOwner)
+		if previousOwner != "" {
+			s.changeRole(ctx, req.RoomId, previousOwner, types.RoleModerator)
+		}
+	}
+	s.publishRoomEvent(ctx, &redisstore.RoomEvent{Type: redisstore.RoomEventPolicy, RoomID: req.RoomId})
+
+	log.Printf("⚙️  [Controller] 房间策略已更新: %s, join_mode=%s, publish=%s, max_users=%d, max_msg=%d\n",
+		room.ID, room.JoinMode, room.PublishPolicy, room.MaxUsers, room.MaxMessageBytes)
+
+	s.metrics.RecordAPIRequest(ctx, "UpdateRoomPolicy", true)
+	tracing.SetSpanSuccess(ctx)
+	return &controller.UpdateRoomPolicyResponse{
+		Success: true,
+		Message: "房间策略已更新",
+		Policy:  s.roomPolicyToProto(room),
+	}, nil
+}
+
+// updateFields 部分更新请求要修改的字段：update_mask 不为空时以其为准（可以设置为零值），
+// 为空时只修改非零值字段（proto3 标量字段无法区分未设置和零值）
+type updateFields map[string]bool
+
+// newUpdateFields 校验 update_mask 中的字段名都属于 msg
+func newUpdateFields(mask *fieldmaskpb.FieldMask, msg proto.Message) (updateFields, error) {
+	if len(mask.GetPaths()) == 0 {
+		return nil, nil
+	}
+	if !mask.IsValid(msg) {
+		return nil, fmt.Errorf("update_mask 包含未知字段: %v", mask.GetPaths())
+	}
+	fields := make(updateFields, len(mask.GetPaths()))
+	for _, path := range mask.GetPaths() {
+		fields[path] = true
+	}
+	return fields, nil
+}
+
+// has 字段是否需要修改，nonZero 为请求中该字段是否为非零值
+func (f updateFields) has(name string, nonZero bool) bool {
+	if f == nil {
+		return nonZero
+	}
+	return f[name]
+}
+
+// InviteToRoom 邀请用户加入房间（需要 invite 权限）
+func (s *ControllerServer) InviteToRoom(ctx context.Context, req *controller.InviteToRoomRequest) (*controller.InviteToRoomResponse, error) {
+	room, _, allowed, err := s.authorize(ctx, req.RoomId, req.OperatorId, types.PermissionInvite)
+	if err != nil {
+		return &controller.InviteToRoomResponse{Success: false, Message: err.Error()}, err
+	}
+	if room == nil {
+		return &controller.InviteToRoomResponse{Success: false, Message: "房间不存在"}, nil
+	}
+	if !allowed {
+		s.metrics.RecordAPIRequest(ctx, "InviteToRoom", false)
+		return &controller.InviteToRoomResponse{Success: false, Message: "没有邀请用户的权限"}, nil
+	}
+
+	invited, err := s.repo.InviteUsers(ctx, req.RoomId, req.OperatorId, req.UserIds)
+	if err != nil {
+		log.Printf("❌ [Controller] 邀请用户失败: %v\n", err)
+		s.metrics.RecordAPIRequest(ctx, "InviteToRoom", false)
+		return &controller.InviteToRoomResponse{Success: false, Message: err.Error()}, err
+	}
+
+	log.Printf("✉️  [Controller] 房间 %s 新增邀请 %d 人\n", req.RoomId, invited)
+	s.metrics.RecordAPIRequest(ctx, "InviteToRoom", true)
+	return &controller.InviteToRoomResponse{Success: true, Message: "邀请成功", Invited: int32(invited)}, nil
+}
+
+// effectiveMaxUsers 房间实际生效的最大用户数
+func (s *ControllerServer) effectiveMaxUsers(room *database.Room) int32 {
+	if room.MaxUsers > 0 {
+		return int32(room.MaxUsers)
+	}
+	return int32(s.config.Room.DefaultMaxUsers)
+}
+
+// roomPolicyToProto 转换房间策略（不返回密码）
+func (s *ControllerServer) roomPolicyToProto(room *database.Room) *controller.RoomPolicy {
+	return &controller.RoomPolicy{
+		MaxUsers:        s.effectiveMaxUsers(room),
+		JoinMode:        room.JoinMode,
+		PublishPolicy:   room.PublishPolicy,
+		MaxMessageBytes: int32(room.MaxMessageBytes),
+		OwnerId:         room.OwnerID,
+	}
+}
+
+// loadRoom 获取房间（优先 Redis 缓存）
+func (s *ControllerServer) loadRoom(ctx context.Context, roomID string) (*database.Room, error) {
+	key := fmt.Sprintf("room_policy:%s", roomID)
+	if s.redis != nil {
+		if data, err := s.redis.Get(ctx, key).Bytes(); err == nil {
+			var room database.Room
+			if json.Unmarshal(data, &room) == nil {
+				return &room, nil
+			}
+		}
+	}
+
+	room, err := s.repo.GetRoom(ctx, roomID)
+	if err != nil || room == nil {
+		return room, err
+	}
+
+	if s.redis != nil {
+		if data, err := json.Marshal(room); err == nil {
+			s.redis.Set(ctx, key, data, RoomCacheTTL)
+		}
+	}
+	return room, nil
+}
+
+// invalidateRoomPolicy 房间策略变更后清除缓存
+func (s *ControllerServer) invalidateRoomPolicy(ctx context.Context, roomID string) {
+	if s.redis != nil {
+		s.redis.Del(ctx, fmt.Sprintf("room_policy:%s", roomID))
+	}
+}
+
+// ========== Access Control ==========
+
+// CheckPermission 检查用户在房间中是否拥有某项权限
+func (s *ControllerServer) CheckPermission(ctx context.Context, req *controller.CheckPermissionRequest) (*controller.CheckPermissionResponse, error) {
+	if !types.ValidPermission(req.Permission) {
+		return &controller.CheckPermissionResponse{Allowed: false, Message: "非法的 permission: " + req.Permission}, nil
+	}
+
+	room, role, allowed, err := s.authorize(ctx, req.RoomId, req.UserId, req.Permission)
+	if err != nil {
+		return &controller.CheckPermissionResponse{Allowed: false, Message: err.Error()}, err
+	}
+	if room == nil {
+		return &controller.CheckPermissionResponse{Allowed: false, Message: "房间不存在"}, nil
+	}
+
+	// 发言还受房间发言策略限制
+	if allowed && req.Permission == types.PermissionPublish {
+		allowed = types.CanPublish(room.PublishPolicy, role)
+	}
+
+	s.metrics.RecordAPIRequest(ctx, "CheckPermission", true)
+	return &controller.CheckPermissionResponse{Allowed: allowed, Role: role}, nil
+}
+
+// SetUserRole 设置房间成员角色（需要 settings 权限，房主通过 UpdateRoomPolicy 转让）
+func (s *ControllerServer) SetUserRole(ctx context.Context, req *controller.SetUserRoleRequest) (*controller.SetUserRoleResponse, error) {
+	if !types.ValidRole(req.Role) || req.Role == types.RoleOwner {
+		return &controller.SetUserRoleResponse{Success: false, Message: "非法的 role: " + req.Role}, nil
+	}
+
+	room, _, allowed, err := s.authorize(ctx, req.RoomId, req.OperatorId, types.PermissionSettings)
+	if err != nil {
+		return &controller.SetUserRoleResponse{Success: false, Message: err.Error()}, err
+	}
+	if room == nil {
+		return &controller.SetUserRoleResponse{Success: false, Message: "房间不存在"}, nil
+	}
+	if !allowed {
+		s.metrics.RecordAPIRequest(ctx, "SetUserRole", false)
+		return &controller.SetUserRoleResponse{Success: false, Message: "没有设置成员角色的权限"}, nil
+	}
+	if req.UserId == room.OwnerID {
+		return &controller.SetUserRoleResponse{Success: false, Message: "不能修改房主的角色"}, nil
+	}
+
+	if err := s.repo.SetMemberRole(ctx, req.RoomId, req.UserId, req.Role); err != nil {
+		if errors.Is(err, database.ErrMemberNotFound) {
+			return &controller.SetUserRoleResponse{Success: false, Message: "用户不是房间成员"}, nil
+		}
+		log.Printf("❌ [Controller] 设置成员角色失败: %v\n", err)
+		s.metrics.RecordAPIRequest(ctx, "SetUserRole", false)
+		return &controller.SetUserRoleResponse{Success: false, Message: err.Error()}, err
+	}
+	s.afterRoleChange(ctx, req.RoomId, req.UserId, req.Role)
+
+	log.Printf("🎭 [Controller] 成员角色已更新: room=%s, user=%s, role=%s, operator=%s\n",
+		req.RoomId, req.UserId, req.Role, req.OperatorId)
+	s.metrics.RecordAPIRequest(ctx, "SetUserRole", true)
+	return &controller.SetUserRoleResponse{Success: true, Message: "角色已更新"}, nil
+}
+
+// KickUser 将用户踢出房间（需要 kick 权限，且只能踢出比自己等级低的成员）
+func (s *ControllerServer) KickUser(ctx context.Context, req *controller.KickUserRequest) (*controller.KickUserResponse, error) {
+	room, operatorRole, allowed, err := s.authorize(ctx, req.RoomId, req.OperatorId, types.PermissionKick)
+	if err != nil {
+		return &controller.KickUserResponse{Success: false, Message: err.Error()}, err
+	}
+	if room == nil {
+		return &controller.KickUserResponse{Success: false, Message: "房间不存在"}, nil
+	}
+	if !allowed {
+		s.metrics.RecordAPIRequest(ctx, "KickUser", false)
+		return &controller.KickUserResponse{Success: false, Message: "没有踢人的权限"}, nil
+	}
+
+	target, err := s.repo.GetRoomMember(ctx, req.RoomId, req.UserId)
+	if err != nil {
+		return &controller.KickUserResponse{Success: false, Message: err.Error()}, err
+	}
+	if target == nil {
+		return &controller.KickUserResponse{Success: false, Message: "用户不在房间中"}, nil
+	}
+	if !types.Outranks(operatorRole, target.Role) {
+		s.metrics.RecordAPIRequest(ctx, "KickUser", false)
+		return &controller.KickUserResponse{Success: false, Message: "不能踢出同级或更高等级的成员"}, nil
+	}
+
+	if _, err := s.LeaveRoom(ctx, &controller.LeaveRoomRequest{UserId: req.UserId, RoomId: req.RoomId}); err != nil {
+		return &controller.KickUserResponse{Success: false, Message: err.Error()}, err
+	}
+	s.publishRoomEvent(ctx, &redisstore.RoomEvent{
+		Type:   redisstore.RoomEventKick,
+		RoomID: req.RoomId,
+		UserID: req.UserId,
+		Reason: req.Reason,
+	})
+
+	s.emitEvent(ctx, &redisstore.LifecycleEvent{
+		Type:       redisstore.EventRoomKicked,
+		UserID:     req.UserId,
+		RoomID:     req.RoomId,
+		OperatorID: req.OperatorId,
+		Reason:     req.Reason,
+	})
+
+	log.Printf("🦶 [Controller] 用户被踢出房间: room=%s, user=%s, operator=%s, reason=%s\n",
+		req.RoomId, req.UserId, req.OperatorId, req.Reason)
+	s.metrics.RecordAPIRequest(ctx, "KickUser", true)
+	return &controller.KickUserResponse{Success: true, Message: "已踢出"}, nil
+}
+
+// authorize 查询用户在房间中的角色并判断是否拥有权限，房间不存在时返回 nil room
+func (s *ControllerServer) authorize(ctx context.Context, roomID, userID, permission string) (*database.Room, string, bool, error) {
+	room, err := s.repo.GetRoom(ctx, roomID)
+	if err != nil || room == nil {
+		return nil, "", false, err
+	}
+
+	// 没有房主的旧房间（启动时未能补齐房主）只能由管理员管理，成员只有其成员角色的权限
+	var role string
+	if room.OwnerID != "" && userID == room.OwnerID {
+		// 房主即使不在线也拥有全部权限
+		role = types.RoleOwner
+	} else {
+		member, err := s.repo.GetRoomMember(ctx, roomID, userID)
+		if err != nil {
+			return room, "", false, err
+		}
+		if member != nil {
+			role = member.Role
+		}
+	}
+
+	return room, role, types.HasPermission(role, permission), nil
+}
+
+// changeRole 更新成员角色（用于房主转让，成员不存在时忽略）
+func (s *ControllerServer) changeRole(ctx context.Context, roomID, userID, role string) {
+	if err := s.repo.SetMemberRole(ctx, roomID, userID, role); err != nil {
+		if !errors.Is(err, database.ErrMemberNotFound) {
+			log.Printf("⚠️  [Controller] 更新成员角色失败: room=%s, user=%s, err=%v\n", roomID, userID, err)
+		}
+		return
+	}
+	s.afterRoleChange(ctx, roomID, userID, role)
+}
+
+// afterRoleChange 角色变更后清理房间用户缓存并通知 Connect-Node 刷新
+func (s *ControllerServer) afterRoleChange(ctx context.Context, roomID, userID, role string) {
+	if s.redis != nil {
+		s.redis.Del(ctx, fmt.Sprintf("room_users:%s", roomID))
+	}
+	s.publishRoomEvent(ctx, &redisstore.RoomEvent{
+		Type:   redisstore.RoomEventRole,
+		RoomID: roomID,
+		UserID: userID,
+		Role:   role,
+	})
+}
+
+// publishRoomEvent 通过 Redis Pub/Sub 通知所有 Connect-Node
+func (s *ControllerServer) publishRoomEvent(ctx context.Context, event *redisstore.RoomEvent) {
+	if s.redis == nil {
+		return
+	}
+	if err := redisstore.PublishRoomEvent(ctx, s.redis, event); err != nil {
+		log.Printf("⚠️  [Controller] 发布房间事件失败: type=%s, room=%s, err=%v\n", event.Type, event.RoomID, err)
+	}
+}
+
+// ========== Node Management ==========
+
+// NodeHeartbeat 接收 Connect-Node 心跳，写入 connect_nodes 表
+func (s *ControllerServer) NodeHeartbeat(ctx context.Context, req *controller.NodeHeartbeatRequest) (*controller.NodeHeartbeatResponse, error) {
+	if req.NodeId == "" || req.Address == "" {
+		return &controller.NodeHeartbeatResponse{Success: false, Message: "node_id 和 address 不能为空"}, nil
+	}
+
+	previous, err := s.repo.GetNode(ctx, req.NodeId)
+	if err != nil {
+		s.metrics.RecordAPIRequest(ctx, "NodeHeartbeat", false)
+		return &controller.NodeHeartbeatResponse{Success: false, Message: err.Error()}, err
+	}
+
+	node := &database.ConnectNode{
+		ID:                 req.NodeId,
+		Address:            req.Address,
+		Region:             req.Region,
+		MaxConnections:     int(req.MaxConnections),
+		CurrentConnections: int(req.CurrentConnections),
+		RoomCount:          int(req.RoomCount),
+		CPUUsage:           req.CpuUsage,
+		MemoryUsage:        req.MemoryUsage,
+	}
+	if err := s.repo.UpsertNodeHeartbeat(ctx, node); err != nil {
+		log.Printf("❌ [Controller] 写入节点心跳失败: node=%s, err=%v\n", req.NodeId, err)
+		s.metrics.RecordAPIRequest(ctx, "NodeHeartbeat", false)
+		return &controller.NodeHeartbeatResponse{Success: false, Message: err.Error()}, err
+	}
+
+	if previous == nil || previous.Status != "online" {
+		log.Printf("🟢 [Controller] 节点上线: %s (%s)\n", req.NodeId, req.Address)
+		s.metrics.IncrementNodes(ctx)
+		s.emitEvent(ctx, &redisstore.LifecycleEvent{Type: redisstore.EventNodeUp, NodeID: req.NodeId})
+	}
+	s.metrics.SetNodeConnections(req.NodeId, int64(req.CurrentConnections))
+	s.metrics.RecordAPIRequest(ctx, "NodeHeartbeat", true)
+
+	return &controller.NodeHeartbeatResponse{
+		Success:             true,
+		Message:             "OK",
+		HeartbeatIntervalMs: s.config.Node.HeartbeatInterval.Milliseconds(),
+	}, nil
+}
+
+// RunNodeHealthCheck 定期将超时未上报心跳的节点标记为 unhealthy（多个 Controller 同时执行是幂等的）
+func (s *ControllerServer) RunNodeHealthCheck(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Node.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			marked, err := s.repo.MarkUnhealthyNodes(ctx, s.config.Node.HeartbeatTimeout)
+			if err != nil {
+				log.Printf("⚠️  [Controller] 节点健康检查失败: %v\n", err)
+				continue
+			}
+			if marked > 0 {
+				log.Printf("🔴 [Controller] %d 个节点心跳超时，已标记为 unhealthy\n", marked)
+				for i := int64(0); i < marked; i++ {
+					s.metrics.DecrementNodes(ctx)
+				}
+			}
+			s.ReapDeadNodes(ctx)
+		}
+	}
+}
+
+// WatchNodeLeases 监听 Connect-Node 的 etcd 租约，租约消失时立即将节点标记为 unhealthy 并清理
+func (s *ControllerServer) WatchNodeLeases(ctx context.Context, discovery *etcd.ServiceDiscovery) {
+	eventChan := discovery.GetEventChan()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-eventChan:
+			if !ok {
+				return
+			}
+			if event.Type != etcd.EventDelete {
+				continue
+			}
+			marked, err := s.repo.MarkNodeUnhealthyByAddress(ctx, event.Addr)
+			if err != nil {
+				log.Printf("⚠️  [Controller] 标记节点 unhealthy 失败: addr=%s, err=%v\n", event.Addr, err)
+				continue
+			}
+			if marked > 0 {
+				log.Printf("🔴 [Controller] 节点 etcd 租约消失: %s\n", event.Addr)
+				s.metrics.DecrementNodes(ctx)
+				s.ReapDeadNodes(ctx)
+			}
+		}
+	}
+}
+
+// ReapDeadNodes 清理 unhealthy 节点上的在线用户
+// 多个 Controller 副本同时执行时，通过 ReapDeadNode 的条件更新保证每个节点只被一个副本清理
+func (s *ControllerServer) ReapDeadNodes(ctx context.Context) {
+	nodes, err := s.repo.ListUnhealthyNodes(ctx)
+	if err != nil {
+		log.Printf("⚠️  [Controller] 查询 unhealthy 节点失败: %v\n", err)
+		return
+	}
+
+	for _, node := range nodes {
+		claimed, users, err := s.repo.ReapDeadNode(ctx, node.ID)
+		if err != nil {
+			// 事务已回滚，节点仍为 unhealthy，下一轮健康检查重试
+			log.Printf("❌ [Controller] 清理节点用户失败: node=%s, err=%v\n", node.ID, err)
+			continue
+		}
+		if !claimed {
+			// 已被其他 Controller 副本认领，或节点恢复了心跳
+			continue
+		}
+
+		s.emitEvent(ctx, &redisstore.LifecycleEvent{Type: redisstore.EventNodeDown, NodeID: node.ID})
+
+		// 按房间分组，批量清理 Redis 并发出离开事件
+		byRoom := make(map[string][]string)
+		for _, u := range users {
+			byRoom[u.RoomID] = append(byRoom[u.RoomID], u.UserID)
+		}
+		for roomID, userIDs := range byRoom {
+			s.afterUsersLeft(ctx, roomID, userIDs, LeaveReasonNodeDown)
+		}
+		if s.userConns != nil {
+			for _, u := range users {
+				s.userConns.RemoveNodeConnections(ctx, u.UserID, node.ID)
+			}
+		}
+
+		log.Printf("🧹 [Controller] 节点 %s 已下线，清理在线用户 %d 个，涉及房间 %d 个\n",
+			node.ID, len(users), len(byRoom))
+	}
+}
+
+// ========== User Connection Index ==========
+
+// NotifyUserOnline 用户连接鉴权成功，写入 user -> connections 索引
+func (s *ControllerServer) NotifyUserOnline(ctx context.Context, req *controller.NotifyUserOnlineRequest) (*controller.NotifyUserOnlineResponse, error) {
+	if req.UserId == "" || req.ConnId == "" {
+		return &controller.NotifyUserOnlineResponse{Success: false, Message: "user_id 和 conn_id 不能为空"}, nil
+	}
+	if s.userConns == nil {
+		return &controller.NotifyUserOnlineResponse{Success: false, Message: "Redis 不可用"}, nil
+	}
+
+	nodeAddress := req.NodeAddress
+	if nodeAddress == "" {
+		nodeAddress = s.resolveNodeAddress(ctx, req.NodeId)
+	}
+
+	conn := &redisstore.UserConnection{
+		ConnID:      req.ConnId,
+		NodeID:      req.NodeId,
+		NodeAddress: nodeAddress,
+		DeviceID:    req.DeviceId,
+		DeviceType:  req.DeviceType,
+		ConnectedAt: time.Now().Unix(),
+	}
+
+	existing, err := s.userConns.GetConnections(ctx, req.UserId)
+	if err != nil {
+		log.Printf("⚠️  [Controller] 读取用户连接索引失败: user=%s, err=%v\n", req.UserId, err)
+	}
+	others := make([]*redisstore.UserConnection, 0, len(existing))
+	for _, c := range existing {
+		if c.ConnID != req.ConnId {
+			others = append(others, c)
+		}
+	}
+
+	// JoinRoom 可能先于本通知到达（并发写入同一连接），原子地保留已登记的房间列表
+	err = s.userConns.UpdateConnection(ctx, req.UserId, req.ConnId, func(current *redisstore.UserConnection) *redisstore.UserConnection {
+		conn.Rooms = nil
+		if current != nil {
+			conn.Rooms = current.Rooms
+		}
+		return conn
+	})
+	if err != nil {
+		log.Printf("❌ [Controller] 写入用户连接索引失败: user=%s, err=%v\n", req.UserId, err)
+		s.metrics.RecordAPIRequest(ctx, "NotifyUserOnline", false)
+		return &controller.NotifyUserOnlineResponse{Success: false, Message: err.Error()}, err
+	}
+
+	s.emitEvent(ctx, &redisstore.LifecycleEvent{
+		Type:       redisstore.EventUserConnected,
+		UserID:     req.UserId,
+		ConnID:     req.ConnId,
+		NodeID:     req.NodeId,
+		DeviceID:   req.DeviceId,
+		DeviceType: req.DeviceType,
+	})
+	evicted := s.evictSessions(ctx, req.UserId, conn, others)
+
+	s.metrics.RecordAPIRequest(ctx, "NotifyUserOnline", true)
+	return &controller.NotifyUserOnlineResponse{Success: true, Message: "OK", EvictedConnIds: evicted}, nil
+}
+
+// selectEvictions 按会话策略选出需要挤下线的旧会话：
+// 同一设备的旧会话、同类型设备的旧会话（single_per_device_type），以及超出 max_devices 的最早会话
+func selectEvictions(cfg *config.SessionConfig, conn *redisstore.UserConnection, others []*redisstore.UserConnection) []*redisstore.UserConnection {
+	var evicted, remaining []*redisstore.UserConnection
+	for _, c := range others {
+		sameDevice := conn.DeviceID != "" && c.DeviceID == conn.DeviceID
+		sameType := cfg != nil && cfg.SinglePerDeviceType && conn.DeviceType != "" && c.DeviceType == conn.DeviceType
+		if sameDevice || sameType {
+			evicted = append(evicted, c)
+		} else {
+			remaining = append(remaining, c)
+		}
+	}
+
+	if cfg != nil && cfg.MaxDevices > 0 && len(remaining)+1 > cfg.MaxDevices {
+		sort.Slice(remaining, func(i, j int) bool {
+			return remaining[i].ConnectedAt < remaining[j].ConnectedAt
+		})
+		evicted = append(evicted, remaining[:len(remaining)+1-cfg.MaxDevices]...)
+	}
+	return evicted
+}
+
+// evictSessions 删除被挤下线会话的索引，并通知其所在 Connect-Node 关闭连接
+func (s *ControllerServer) evictSessions(ctx context.Context, userID string, conn *redisstore.UserConnection, others []*redisstore.UserConnection) []string {
+	evicted := selectEvictions(s.config.Session, conn, others)
+	if len(evicted) == 0 {
+		return nil
+	}
+
+	connIDs := make([]string, 0, len(evicted))
+	for _, c := range evicted {
+		if err := s.userConns.RemoveConnection(ctx, userID, c.ConnID); err != nil {
+			log.Printf("⚠️  [Controller] 删除被挤下线会话索引失败: user=%s, conn=%s, err=%v\n", userID, c.ConnID, err)
+		}
+		s.publishRoomEvent(ctx, &redisstore.RoomEvent{
+			Type:   redisstore.RoomEventEvict,
+			UserID: userID,
+			ConnID: c.ConnID,
+			Reason: "replaced by " + conn.ConnID,
+		})
+		s.emitEvent(ctx, &redisstore.LifecycleEvent{
+			Type:       redisstore.EventUserEvicted,
+			UserID:     userID,
+			ConnID:     c.ConnID,
+			NodeID:     c.NodeID,
+			DeviceID:   c.DeviceID,
+			DeviceType: c.DeviceType,
+			Reason:     "replaced by " + conn.ConnID,
+		})
+		connIDs = append(connIDs, c.ConnID)
+		log.Printf("📴 [Controller] 会话被挤下线: user=%s, conn=%s, device=%s/%s\n", userID, c.ConnID, c.DeviceType, c.DeviceID)
+	}
+	return connIDs
+}
+
+// NotifyUserOffline 用户连接断开，删除索引
+func (s *ControllerServer) NotifyUserOffline(ctx context.Context, req *controller.NotifyUserOfflineRequest) (*controller.NotifyUserOfflineResponse, error) {
+	if s.userConns == nil {
+		return &controller.NotifyUserOfflineResponse{Success: false, Message: "Redis 不可用"}, nil
+	}
+
+	if err := s.userConns.RemoveConnection(ctx, req.UserId, req.ConnId); err != nil {
+		log.Printf("❌ [Controller] 删除用户连接索引失败: user=%s, err=%v\n", req.UserId, err)
+		s.metrics.RecordAPIRequest(ctx, "NotifyUserOffline", false)
+		return &controller.NotifyUserOfflineResponse{Success: false, Message: err.Error()}, err
+	}
+
+	s.metrics.RecordAPIRequest(ctx, "NotifyUserOffline", true)
+	s.emitEvent(ctx, &redisstore.LifecycleEvent{Type: redisstore.EventUserDisconnected, UserID: req.UserId, ConnID: req.ConnId, NodeID: req.NodeId})
+	return &controller.NotifyUserOfflineResponse{Success: true, Message: "OK"}, nil
+}
+
+// indexUserRoom 将房间加入用户连接的房间列表（连接尚未登记时补登记）
+func (s *ControllerServer) indexUserRoom(ctx context.Context, userID, connID, nodeID, roomID string) {
+	if s.userConns == nil || connID == "" {
+		return
+	}
+
+	var nodeAddress string
+	err := s.userConns.UpdateConnection(ctx, userID, connID, func(conn *redisstore.UserConnection) *redisstore.UserConnection {
+		if conn == nil {
+			if nodeAddress == "" {
+				nodeAddress = s.resolveNodeAddress(ctx, nodeID)
+			}
+			conn = &redisstore.UserConnection{
+				ConnID:      connID,
+				NodeID:      nodeID,
+				NodeAddress: nodeAddress,
+				ConnectedAt: time.Now().Unix(),
+			}
+		}
+		for _, r := range conn.Rooms {
+			if r == roomID {
+				return nil
+			}
+		}
+		conn.Rooms = append(conn.Rooms, roomID)
+		return conn
+	})
+	if err != nil {
+		log.Printf("⚠️  [Controller] 更新用户连接索引失败: %v\n", err)
+	}
+}
+
+// resolveNodeAddress 通过节点心跳表解析节点 gRPC 地址
+func (s *ControllerServer) resolveNodeAddress(ctx context.Context, nodeID string) string {
+	if node, err := s.repo.GetNode(ctx, nodeID); err == nil && node != nil {
+		return node.Address
+	}
+	return ""
+}
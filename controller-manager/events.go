@@ -0,0 +1,127 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+)
+
+const (
+	// eventLogMaxLen 事件日志保留的近似最大事件数，更早的事件无法续读
+	eventLogMaxLen = 1000000
+	// watchEventsBatch WatchEvents 每次从事件日志读取的事件数
+	watchEventsBatch = 256
+	// watchEventsBlock 没有新事件时单次阻塞等待的时间（之后检查订阅方是否已断开）
+	watchEventsBlock = 5 * time.Second
+)
+
+// WatchEvents 按游标推送生命周期事件：先补齐游标之后的历史事件，再持续推送新事件。
+// 被过滤掉的事件同样推进游标，订阅方以最后收到事件的 cursor 续读；游标之后的事件已被裁剪时返回 OutOfRange
+func (s *ControllerServer) WatchEvents(req *controller.WatchEventsRequest, stream controller.ControllerService_WatchEventsServer) error {
+	if s.eventLog == nil {
+		return fmt.Errorf("event log unavailable: redis not connected")
+	}
+	ctx := stream.Context()
+
+	cursor := req.Cursor
+	if cursor == "" {
+		if req.FromOldest {
+			cursor = "0"
+		} else {
+			var err error
+			if cursor, err = s.eventLog.LastCursor(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	log.Printf("👀 [Controller] 事件订阅开始: cursor=%s, types=%v, room_prefix=%q, user_prefix=%q\n",
+		cursor, req.Types, req.RoomPrefix, req.UserPrefix)
+
+	for {
+		// 游标之后的事件已被裁剪（订阅方离线太久或消费太慢）时返回 OutOfRange，订阅方需重新同步状态后
+		// 以空 cursor 或 from_oldest 重新订阅，不能静默跳过缺失的事件
+		if err := s.eventLog.CheckCursor(ctx, cursor); err != nil {
+			if errors.Is(err, redisstore.ErrCursorTrimmed) {
+				log.Printf("⚠️  [Controller] 事件订阅游标已被裁剪: cursor=%s\n", cursor)
+				return status.Errorf(codes.OutOfRange, "cursor %s has been trimmed from the event log, resync and resubscribe", cursor)
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		events, err := s.eventLog.Read(ctx, cursor, watchEventsBatch, watchEventsBlock)
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Printf("👋 [Controller] 事件订阅结束: cursor=%s\n", cursor)
+				return nil
+			}
+			return err
+		}
+		for _, event := range events {
+			cursor = event.Cursor
+			if !matchWatchFilter(req, event) {
+				continue
+			}
+			if err := stream.Send(toLifecycleEventPB(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// matchWatchFilter 事件是否满足订阅的过滤条件（指定前缀时不带对应字段的事件不匹配）
+func matchWatchFilter(req *controller.WatchEventsRequest, event *redisstore.LifecycleEvent) bool {
+	if !pkg.MatchEventType(req.Types, event.Type) {
+		return false
+	}
+	if req.RoomPrefix != "" && (event.RoomID == "" || !strings.HasPrefix(event.RoomID, req.RoomPrefix)) {
+		return false
+	}
+	if req.UserPrefix != "" && (event.UserID == "" || !strings.HasPrefix(event.UserID, req.UserPrefix)) {
+		return false
+	}
+	return true
+}
+
+func toLifecycleEventPB(event *redisstore.LifecycleEvent) *controller.LifecycleEvent {
+	return &controller.LifecycleEvent{
+		Cursor:     event.Cursor,
+		Id:         event.ID,
+		Type:       event.Type,
+		Timestamp:  event.Timestamp,
+		UserId:     event.UserID,
+		RoomId:     event.RoomID,
+		ConnId:     event.ConnID,
+		NodeId:     event.NodeID,
+		DeviceId:   event.DeviceID,
+		DeviceType: event.DeviceType,
+		Role:       event.Role,
+		OperatorId: event.OperatorID,
+		Reason:     event.Reason,
+	}
+}
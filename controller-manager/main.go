@@ -17,8 +17,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/admin"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
-	"github.com/livekit/psrpc/examples/pubsub/protocol/push"
 	"log"
 	"net"
 	"os"
@@ -141,16 +142,50 @@ func main() {
 	// 7️⃣ 创建 Repository 和 Controller Server
 	log.Println("🏗️  创建 Controller Server...")
 	repo := database.NewRepository(db)
-	controllerServer := NewControllerServer(cfg, repo, redisClient, &pushClient, metricsCollector)
+	if filled, err := repo.BackfillRoomOwners(ctx); err != nil {
+		log.Printf("⚠️  补齐旧房间房主失败: %v\n", err)
+	} else if filled > 0 {
+		log.Printf("👑 已为 %d 个旧房间补齐房主\n", filled)
+	}
+	controllerServer := NewControllerServer(cfg, repo, redisClient, pushClient, metricsCollector)
 	log.Println("✅ Controller Server 创建成功")
 	log.Println()
 
+	// 启动节点健康检查（心跳超时标记 unhealthy）
+	healthCtx, healthCancel := context.WithCancel(ctx)
+	defer healthCancel()
+	go controllerServer.RunNodeHealthCheck(healthCtx)
+
+	// 启动生命周期事件 webhook 投递
+	controllerServer.webhooks.Run(healthCtx)
+
+	// 监听 Connect-Node 的 etcd 租约（节点宕机时立即清理其在线用户）
+	nodeDiscovery, err := etcd.NewServiceDiscovery(cfg.ETCD.Endpoints, "connect-node")
+	if err != nil {
+		log.Printf("⚠️  Connect-Node 服务发现创建失败（仅依赖心跳超时清理）: %v\n", err)
+	} else {
+		defer nodeDiscovery.Close()
+		go controllerServer.WatchNodeLeases(healthCtx, nodeDiscovery)
+	}
+
+	// 运维接口（汇总 Connect-Node 与 Push-Manager 的实时状态）
+	pushDiscovery, err := etcd.NewServiceDiscovery(cfg.ETCD.Endpoints, "push-manager")
+	if err != nil {
+		log.Printf("⚠️  Push-Manager 服务发现创建失败（ListNodes 不含 Push-Manager）: %v\n", err)
+	} else {
+		defer pushDiscovery.Close()
+	}
+	adminServer := NewAdminServer(repo, pushDiscovery, metricsCollector)
+	go adminServer.WatchPushManagers(healthCtx)
+	go adminServer.PruneNodeConns(healthCtx)
+
 	// 8️⃣ 创建 gRPC Server（带 OpenTelemetry）
 	log.Println("🔧 创建 gRPC Server...")
 	grpcOpts := tracing.GetGRPCServerOptions()
 	grpcServer := grpc.NewServer(grpcOpts...)
 
 	controller.RegisterControllerServiceServer(grpcServer, controllerServer)
+	admin.RegisterAdminServiceServer(grpcServer, adminServer)
 
 	// 9️⃣ 启动 gRPC Server
 	listen, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Server.Port))
@@ -175,14 +210,26 @@ func main() {
 	log.Printf("  - 默认最大用户数: %d\n", cfg.Room.DefaultMaxUsers)
 	log.Printf("  - 缓存 TTL: %v\n", cfg.Room.CacheTTL)
 	log.Println()
+	log.Println("💓 节点健康检查:")
+	log.Printf("  - 心跳间隔: %v\n", cfg.Node.HeartbeatInterval)
+	log.Printf("  - 心跳超时: %v\n", cfg.Node.HeartbeatTimeout)
+	log.Println()
+	log.Println("🪝 Webhook:")
+	log.Printf("  - endpoints: %d\n", len(cfg.Webhook.Endpoints))
+	log.Printf("  - 最多投递次数: %d\n", cfg.Webhook.MaxAttempts)
+	log.Println()
 	log.Println("🔌 gRPC 方法:")
-	log.Println("  - NotifyUserOnline: Connect-Node 通知用户上线")
-	log.Println("  - NotifyUserOffline: Connect-Node 通知用户下线")
 	log.Println("  - JoinRoom: 用户加入房间")
 	log.Println("  - LeaveRoom: 用户离开房间")
 	log.Println("  - GetRoomInfo: 获取房间信息")
 	log.Println("  - GetRoomStats: 获取房间统计")
-	log.Println("  - SelectConnectNode: 选择节点（负载均衡）")
+	log.Println("  - UpdateRoomPolicy / InviteToRoom: 房间策略与邀请")
+	log.Println("  - CheckPermission / SetUserRole / KickUser: 房间角色与权限")
+	log.Println("  - GetUserNode: 查询用户所在节点")
+	log.Println("  - NodeHeartbeat: Connect-Node 心跳上报")
+	log.Println("  - ListFailedWebhooks / ReplayWebhooks: webhook 失败事件查询与重放")
+	log.Println("  - WatchEvents: 订阅生命周期事件（server-streaming，按游标续读）")
+	log.Println("  - AdminService: 节点负载、会话列表、强制断开、bucket/房间分布、drain 模式")
 	log.Println()
 	log.Println("💡 使用示例:")
 	log.Println("  grpcurl -plaintext localhost:50051 list")
@@ -210,7 +257,7 @@ func main() {
 	log.Println("👋 服务已关闭")
 }
 
-func newPushClient(c *config.RpcConfig, etcdEndpoints []string) (controllerClient push.CometClient) {
+func newPushClient(c *config.RpcConfig, etcdEndpoints []string) (pushClient broadcast.PushServerClient) {
 
 	log.Printf("🔍 连接 ETCD: %v", etcdEndpoints)
 	resolverBuilder, err := etcd.GetETCDResolverBuilder(etcdEndpoints)
@@ -238,7 +285,7 @@ func newPushClient(c *config.RpcConfig, etcdEndpoints []string) (controllerClien
 
 	log.Printf("✅ Push-Manager 客户端已创建（将在后台建立连接）")
 
-	return push.NewCometClient(conn)
+	return broadcast.NewPushServerClient(conn)
 
 }
 
@@ -0,0 +1,312 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/pkg/database"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+)
+
+// CreateTenant 创建租户
+func (s *ControllerServer) CreateTenant(ctx context.Context, req *controller.CreateTenantRequest) (*controller.CreateTenantResponse, error) {
+	t := req.Tenant
+	if t == nil || pkg.ValidateAppID(t.AppId) != nil {
+		return &controller.CreateTenantResponse{Success: false, Message: "app_id 只能包含小写字母、数字、- 和 _，最长 32 个字符"}, nil
+	}
+	if t.Status == "" {
+		t.Status = pkg.TenantActive
+	}
+	if msg, ok := validateTenant(t); !ok {
+		return &controller.CreateTenantResponse{Success: false, Message: msg}, nil
+	}
+	name := t.Name
+	if name == "" {
+		name = t.AppId
+	}
+
+	tenant := &database.Tenant{
+		AppID:              t.AppId,
+		Name:               name,
+		Status:             t.Status,
+		MaxMessageBytes:    int(t.MaxMessageBytes),
+		PublishRate:        int(t.PublishRate),
+		SoftMaxConnections: int(t.SoftMaxConnections),
+		MaxConnections:     int(t.MaxConnections),
+		SoftDailyMessages:  t.SoftDailyMessages,
+		MaxDailyMessages:   t.MaxDailyMessages,
+	}
+	if err := s.repo.CreateTenant(ctx, tenant); err != nil {
+		if errors.Is(err, database.ErrTenantExists) {
+			return &controller.CreateTenantResponse{Success: false, Message: "租户已存在: " + t.AppId}, nil
+		}
+		log.Printf("❌ [Controller] 创建租户失败: app=%s, err=%v\n", t.AppId, err)
+		s.metrics.RecordAPIRequest(ctx, "CreateTenant", false)
+		return &controller.CreateTenantResponse{Success: false, Message: err.Error()}, err
+	}
+
+	log.Printf("🏢 [Controller] 租户已创建: app=%s, name=%s\n", tenant.AppID, tenant.Name)
+	s.metrics.RecordAPIRequest(ctx, "CreateTenant", true)
+	return &controller.CreateTenantResponse{Success: true, Message: "租户已创建", Tenant: tenantToProto(tenant)}, nil
+}
+
+// UpdateTenant 更新租户名称、状态和配额（按 update_mask 修改，未设置时只修改非零值字段）
+func (s *ControllerServer) UpdateTenant(ctx context.Context, req *controller.UpdateTenantRequest) (*controller.UpdateTenantResponse, error) {
+	t := req.Tenant
+	if t == nil || t.AppId == "" {
+		return &controller.UpdateTenantResponse{Success: false, Message: "app_id 不能为空"}, nil
+	}
+	fields, err := newUpdateFields(req.UpdateMask, t)
+	if err != nil {
+		return &controller.UpdateTenantResponse{Success: false, Message: err.Error()}, nil
+	}
+	if fields["app_id"] || fields["created_at"] || fields["updated_at"] {
+		return &controller.UpdateTenantResponse{Success: false, Message: "update_mask 包含只读字段"}, nil
+	}
+
+	// 与当前配置合并后校验（软限制不能超过硬限制）
+	current, err := s.repo.GetTenant(ctx, t.AppId)
+	if err != nil {
+		s.metrics.RecordAPIRequest(ctx, "UpdateTenant", false)
+		return &controller.UpdateTenantResponse{Success: false, Message: err.Error()}, err
+	}
+	if current == nil {
+		return &controller.UpdateTenantResponse{Success: false, Message: "租户不存在: " + t.AppId}, nil
+	}
+	merged := tenantToProto(current)
+	updates := make(map[string]interface{})
+	if fields.has("name", t.Name != "") {
+		merged.Name = t.Name
+		updates["name"] = t.Name
+	}
+	if fields.has("status", t.Status != "") {
+		merged.Status = t.Status
+		updates["status"] = t.Status
+	}
+	if fields.has("max_message_bytes", t.MaxMessageBytes != 0) {
+		merged.MaxMessageBytes = t.MaxMessageBytes
+		updates["max_message_bytes"] = t.MaxMessageBytes
+	}
+	if fields.has("publish_rate", t.PublishRate != 0) {
+		merged.PublishRate = t.PublishRate
+		updates["publish_rate"] = t.PublishRate
+	}
+	if fields.has("soft_max_connections", t.SoftMaxConnections != 0) {
+		merged.SoftMaxConnections = t.SoftMaxConnections
+		updates["soft_max_connections"] = t.SoftMaxConnections
+	}
+	if fields.has("max_connections", t.MaxConnections != 0) {
+		merged.MaxConnections = t.MaxConnections
+		updates["max_connections"] = t.MaxConnections
+	}
+	if fields.has("soft_daily_messages", t.SoftDailyMessages != 0) {
+		merged.SoftDailyMessages = t.SoftDailyMessages
+		updates["soft_daily_messages"] = t.SoftDailyMessages
+	}
+	if fields.has("max_daily_messages", t.MaxDailyMessages != 0) {
+		merged.MaxDailyMessages = t.MaxDailyMessages
+		updates["max_daily_messages"] = t.MaxDailyMessages
+	}
+	if msg, ok := validateTenant(merged); !ok {
+		return &controller.UpdateTenantResponse{Success: false, Message: msg}, nil
+	}
+	if len(updates) == 0 {
+		return &controller.UpdateTenantResponse{Success: true, Message: "没有需要修改的字段", Tenant: merged}, nil
+	}
+
+	tenant, err := s.repo.UpdateTenant(ctx, t.AppId, updates)
+	if err != nil {
+		if errors.Is(err, database.ErrTenantNotFound) {
+			return &controller.UpdateTenantResponse{Success: false, Message: "租户不存在: " + t.AppId}, nil
+		}
+		log.Printf("❌ [Controller] 更新租户失败: app=%s, err=%v\n", t.AppId, err)
+		s.metrics.RecordAPIRequest(ctx, "UpdateTenant", false)
+		return &controller.UpdateTenantResponse{Success: false, Message: err.Error()}, err
+	}
+
+	log.Printf("⚙️  [Controller] 租户已更新: app=%s, status=%s, max_msg=%d, publish_rate=%d, connections=%d/%d, daily_messages=%d/%d\n",
+		tenant.AppID, tenant.Status, tenant.MaxMessageBytes, tenant.PublishRate,
+		tenant.SoftMaxConnections, tenant.MaxConnections, tenant.SoftDailyMessages, tenant.MaxDailyMessages)
+	s.metrics.RecordAPIRequest(ctx, "UpdateTenant", true)
+	return &controller.UpdateTenantResponse{Success: true, Message: "租户已更新", Tenant: tenantToProto(tenant)}, nil
+}
+
+// GetTenant 查询租户
+func (s *ControllerServer) GetTenant(ctx context.Context, req *controller.GetTenantRequest) (*controller.GetTenantResponse, error) {
+	tenant, err := s.repo.GetTenant(ctx, req.AppId)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return &controller.GetTenantResponse{Found: false}, nil
+	}
+	return &controller.GetTenantResponse{Found: true, Tenant: tenantToProto(tenant)}, nil
+}
+
+// ListTenants 列出租户
+func (s *ControllerServer) ListTenants(ctx context.Context, req *controller.ListTenantsRequest) (*controller.ListTenantsResponse, error) {
+	tenants, err := s.repo.ListTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &controller.ListTenantsResponse{Tenants: make([]*controller.Tenant, 0, len(tenants))}
+	for _, tenant := range tenants {
+		resp.Tenants = append(resp.Tenants, tenantToProto(tenant))
+	}
+	return resp, nil
+}
+
+// CreateAPIKey 为租户创建 API key，只保存哈希，明文在响应中返回一次
+func (s *ControllerServer) CreateAPIKey(ctx context.Context, req *controller.CreateAPIKeyRequest) (*controller.CreateAPIKeyResponse, error) {
+	if req.AppId == "" {
+		return &controller.CreateAPIKeyResponse{Success: false, Message: "app_id 不能为空"}, nil
+	}
+	keyID, secret, err := pkg.GenerateAPIKey()
+	if err != nil {
+		return &controller.CreateAPIKeyResponse{Success: false, Message: err.Error()}, err
+	}
+
+	key := &database.APIKey{
+		KeyID:     keyID,
+		AppID:     req.AppId,
+		Name:      req.Name,
+		KeyHash:   pkg.HashAPIKey(secret),
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.CreateAPIKey(ctx, key); err != nil {
+		if errors.Is(err, database.ErrTenantNotFound) {
+			return &controller.CreateAPIKeyResponse{Success: false, Message: "租户不存在: " + req.AppId}, nil
+		}
+		log.Printf("❌ [Controller] 创建 API key 失败: app=%s, err=%v\n", req.AppId, err)
+		s.metrics.RecordAPIRequest(ctx, "CreateAPIKey", false)
+		return &controller.CreateAPIKeyResponse{Success: false, Message: err.Error()}, err
+	}
+
+	log.Printf("🔑 [Controller] API key 已创建: app=%s, key=%s, name=%s\n", key.AppID, key.KeyID, key.Name)
+	s.metrics.RecordAPIRequest(ctx, "CreateAPIKey", true)
+	return &controller.CreateAPIKeyResponse{
+		Success: true,
+		Message: "API key 已创建，请妥善保存，之后无法再次查看",
+		Key:     apiKeyToProto(key),
+		Secret:  secret,
+	}, nil
+}
+
+// ListAPIKeys 列出租户的 API key（不含明文）
+func (s *ControllerServer) ListAPIKeys(ctx context.Context, req *controller.ListAPIKeysRequest) (*controller.ListAPIKeysResponse, error) {
+	keys, err := s.repo.ListAPIKeys(ctx, req.AppId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &controller.ListAPIKeysResponse{Keys: make([]*controller.APIKey, 0, len(keys))}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, apiKeyToProto(key))
+	}
+	return resp, nil
+}
+
+// RevokeAPIKey 吊销 API key（接入层缓存过期后生效）
+func (s *ControllerServer) RevokeAPIKey(ctx context.Context, req *controller.RevokeAPIKeyRequest) (*controller.RevokeAPIKeyResponse, error) {
+	if req.KeyId == "" {
+		return &controller.RevokeAPIKeyResponse{Success: false, Message: "key_id 不能为空"}, nil
+	}
+	if err := s.repo.RevokeAPIKey(ctx, req.KeyId); err != nil {
+		if errors.Is(err, database.ErrAPIKeyNotFound) {
+			return &controller.RevokeAPIKeyResponse{Success: false, Message: "API key 不存在: " + req.KeyId}, nil
+		}
+		s.metrics.RecordAPIRequest(ctx, "RevokeAPIKey", false)
+		return &controller.RevokeAPIKeyResponse{Success: false, Message: err.Error()}, err
+	}
+
+	log.Printf("🔒 [Controller] API key 已吊销: key=%s\n", req.KeyId)
+	s.metrics.RecordAPIRequest(ctx, "RevokeAPIKey", true)
+	return &controller.RevokeAPIKeyResponse{Success: true, Message: "API key 已吊销"}, nil
+}
+
+// ResolveAPIKey 按哈希查询 API key 所属租户（key 已吊销时 found=false）
+func (s *ControllerServer) ResolveAPIKey(ctx context.Context, req *controller.ResolveAPIKeyRequest) (*controller.ResolveAPIKeyResponse, error) {
+	if req.KeyHash == "" {
+		return &controller.ResolveAPIKeyResponse{Found: false}, nil
+	}
+	key, err := s.repo.GetAPIKeyByHash(ctx, strings.ToLower(req.KeyHash))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return &controller.ResolveAPIKeyResponse{Found: false}, nil
+	}
+	tenant, err := s.repo.GetTenant(ctx, key.AppID)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return &controller.ResolveAPIKeyResponse{Found: false}, nil
+	}
+	return &controller.ResolveAPIKeyResponse{Found: true, KeyId: key.KeyID, Tenant: tenantToProto(tenant)}, nil
+}
+
+// validateTenant 校验租户状态和配额
+func validateTenant(t *controller.Tenant) (string, bool) {
+	if t.Status != pkg.TenantActive && t.Status != pkg.TenantDisabled {
+		return "非法的 status: " + t.Status, false
+	}
+	if t.MaxMessageBytes < 0 || t.PublishRate < 0 ||
+		t.SoftMaxConnections < 0 || t.MaxConnections < 0 || t.SoftDailyMessages < 0 || t.MaxDailyMessages < 0 {
+		return "配额不能为负数", false
+	}
+	if t.MaxConnections > 0 && t.SoftMaxConnections > t.MaxConnections {
+		return "soft_max_connections 不能大于 max_connections", false
+	}
+	if t.MaxDailyMessages > 0 && t.SoftDailyMessages > t.MaxDailyMessages {
+		return "soft_daily_messages 不能大于 max_daily_messages", false
+	}
+	return "", true
+}
+
+func tenantToProto(t *database.Tenant) *controller.Tenant {
+	return &controller.Tenant{
+		AppId:           t.AppID,
+		Name:            t.Name,
+		Status:          t.Status,
+		MaxMessageBytes: int32(t.MaxMessageBytes),
+		PublishRate:     int32(t.PublishRate),
+		CreatedAt:       t.CreatedAt.Unix(),
+		UpdatedAt:       t.UpdatedAt.Unix(),
+
+		SoftMaxConnections: int32(t.SoftMaxConnections),
+		MaxConnections:     int32(t.MaxConnections),
+		SoftDailyMessages:  t.SoftDailyMessages,
+		MaxDailyMessages:   t.MaxDailyMessages,
+	}
+}
+
+func apiKeyToProto(k *database.APIKey) *controller.APIKey {
+	key := &controller.APIKey{
+		KeyId:     k.KeyID,
+		AppId:     k.AppID,
+		Name:      k.Name,
+		CreatedAt: k.CreatedAt.Unix(),
+	}
+	if k.RevokedAt != nil {
+		key.RevokedAt = k.RevokedAt.Unix()
+	}
+	return key
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+)
+
+// usageReportMaxDays 用量报表最多覆盖的天数
+const usageReportMaxDays = 31
+
+// GetUsageReport 租户用量报表：按天（或按小时）输出推送、投递、出站字节、连接数峰值和被拒绝次数，
+// 以及当前全集群连接数和已超过的软限制
+func (s *ControllerServer) GetUsageReport(ctx context.Context, req *controller.GetUsageReportRequest) (*controller.GetUsageReportResponse, error) {
+	if s.usage == nil {
+		return nil, status.Error(codes.Unavailable, "usage unavailable: redis not connected")
+	}
+
+	from, to, err := parseUsageDays(req.FromDay, req.ToDay)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var tenants []*controller.Tenant
+	if req.AppId != "" {
+		tenant, err := s.repo.GetTenant(ctx, req.AppId)
+		if err != nil {
+			return nil, err
+		}
+		if tenant == nil {
+			return nil, status.Errorf(codes.NotFound, "tenant not found: %s", req.AppId)
+		}
+		tenants = append(tenants, tenantToProto(tenant))
+	} else {
+		list, err := s.repo.ListTenants(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, tenant := range list {
+			tenants = append(tenants, tenantToProto(tenant))
+		}
+	}
+
+	conns, err := s.usage.ClusterConnections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	resp := &controller.GetUsageReportResponse{Reports: make([]*controller.TenantUsage, 0, len(tenants))}
+	for _, tenant := range tenants {
+		report := &controller.TenantUsage{Tenant: tenant, Connections: conns[tenant.AppId]}
+		var publishedToday int64
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			totals, hours, err := s.usage.DayUsage(ctx, tenant.AppId, day)
+			if err != nil {
+				return nil, err
+			}
+			if day.Equal(today) {
+				publishedToday = totals[redisstore.UsagePublished]
+			}
+			if !req.Hourly {
+				report.Windows = append(report.Windows, usageWindow(day, day.AddDate(0, 0, 1), totals))
+				continue
+			}
+			for h, values := range hours {
+				start := day.Add(time.Duration(h) * time.Hour)
+				report.Windows = append(report.Windows, usageWindow(start, start.Add(time.Hour), values))
+			}
+		}
+		if today.Before(from) || today.After(to) {
+			// 软限制按今天的推送数判断，报表范围不含今天时单独查询
+			if totals, _, err := s.usage.DayUsage(ctx, tenant.AppId, today); err == nil {
+				publishedToday = totals[redisstore.UsagePublished]
+			}
+		}
+
+		if tenant.SoftMaxConnections > 0 && report.Connections > int64(tenant.SoftMaxConnections) {
+			report.SoftLimitsExceeded = append(report.SoftLimitsExceeded, "connections")
+		}
+		if tenant.SoftDailyMessages > 0 && publishedToday > tenant.SoftDailyMessages {
+			report.SoftLimitsExceeded = append(report.SoftLimitsExceeded, "daily_messages")
+		}
+		resp.Reports = append(resp.Reports, report)
+	}
+	return resp, nil
+}
+
+// parseUsageDays 解析报表日期范围（UTC），from 为空时为今天，to 为空时与 from 相同
+func parseUsageDays(fromDay, toDay string) (time.Time, time.Time, error) {
+	from := time.Now().UTC().Truncate(24 * time.Hour)
+	if fromDay != "" {
+		t, err := time.Parse("2006-01-02", fromDay)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from_day: %s", fromDay)
+		}
+		from = t
+	}
+	to := from
+	if toDay != "" {
+		t, err := time.Parse("2006-01-02", toDay)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to_day: %s", toDay)
+		}
+		to = t
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("to_day is before from_day")
+	}
+	if to.Sub(from) >= usageReportMaxDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("usage report covers at most %d days", usageReportMaxDays)
+	}
+	return from, to, nil
+}
+
+func usageWindow(start, end time.Time, values map[string]int64) *controller.UsageWindow {
+	return &controller.UsageWindow{
+		Start:               start.Unix(),
+		End:                 end.Unix(),
+		Published:           values[redisstore.UsagePublished],
+		Delivered:           values[redisstore.UsageDelivered],
+		BytesOut:            values[redisstore.UsageBytesOut],
+		PeakConnections:     values[redisstore.UsagePeakConnections],
+		RejectedPublishes:   values[redisstore.UsageRejectedPublishes],
+		RejectedConnections: values[redisstore.UsageRejectedConnections],
+	}
+}
@@ -0,0 +1,425 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/pkg/config"
+	"github.com/livekit/psrpc/examples/pubsub/pkg/metrics"
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+)
+
+const (
+	// webhookFailureMaxLen 失败记录 stream 的近似最大长度
+	webhookFailureMaxLen = 100000
+	// defaultWebhookListLimit 列出失败记录的默认条数
+	defaultWebhookListLimit = 100
+	// webhookQueueMaxLen 每个 endpoint 待投递 stream 的近似最大长度
+	webhookQueueMaxLen = 100000
+	// webhookReadCount 每次从待投递 stream 读取的事件数
+	webhookReadCount = 10
+	// webhookReadBlock 读取阻塞时间（同时决定 ctx 取消后的退出延迟）
+	webhookReadBlock = 2 * time.Second
+	// webhookEnqueueTimeout 写入待投递 stream 的超时
+	webhookEnqueueTimeout = 2 * time.Second
+)
+
+// webhookDelivery 待投递给某个 endpoint 的事件
+type webhookDelivery struct {
+	id        string // 待投递 stream 的 entry id，内存队列模式为空
+	eventID   string
+	eventType string
+	payload   []byte
+}
+
+// webhookEndpoint endpoint 配置及其内存待投递队列（Redis 不可用或写入失败时使用）
+type webhookEndpoint struct {
+	config.WebhookEndpoint
+	queue chan *webhookDelivery
+}
+
+// webhookDispatcher 生命周期事件 webhook 投递：事件先写入 Redis 待投递 stream（Controller 重启不丢失，
+// Redis 不可用时退化为内存队列），每个 endpoint 一个投递协程；签名 JSON POST，失败按退避重试，
+// 次数耗尽后记入 Redis 失败记录（Redis 不可用时只记录日志），可通过 ReplayWebhooks 重放
+type webhookDispatcher struct {
+	cfg       *config.WebhookConfig
+	client    *http.Client
+	store     *redisstore.WebhookStore
+	endpoints map[string]*webhookEndpoint
+	metrics   *metrics.MetricsCollector
+
+	// 持久化待投递队列（Redis 不可用时为 nil），consumer 为本 Controller 的 ID
+	queue    *redisstore.WebhookQueue
+	consumer string
+
+	maxAttempts int
+	claimIdle   time.Duration // 未确认事件空闲超过该时间后被认领（大于单个事件全部重试的最长耗时）
+}
+
+// newWebhookDispatcher 创建 webhook 投递器，没有配置 endpoint 时返回 nil
+func newWebhookDispatcher(cfg *config.WebhookConfig, store *redisstore.WebhookStore, queue *redisstore.WebhookQueue,
+	consumer string, metricsCollector *metrics.MetricsCollector) *webhookDispatcher {
+	if cfg == nil || len(cfg.Endpoints) == 0 {
+		return nil
+	}
+	d := &webhookDispatcher{
+		cfg:       cfg,
+		client:    &http.Client{Timeout: cfg.Timeout},
+		store:     store,
+		endpoints: make(map[string]*webhookEndpoint, len(cfg.Endpoints)),
+		metrics:   metricsCollector,
+		queue:     queue,
+		consumer:  consumer,
+
+		maxAttempts: max(cfg.MaxAttempts, 1),
+	}
+	d.claimIdle = time.Duration(d.maxAttempts)*(cfg.Timeout+cfg.RetryMaxDelay) + time.Minute
+	for _, ep := range cfg.Endpoints {
+		d.endpoints[ep.Name] = &webhookEndpoint{
+			WebhookEndpoint: ep,
+			queue:           make(chan *webhookDelivery, cfg.QueueSize),
+		}
+	}
+	return d
+}
+
+// Run 启动每个 endpoint 的投递协程，ctx 取消时退出（Redis 队列中未确认的事件保留，内存队列中的丢弃）
+func (d *webhookDispatcher) Run(ctx context.Context) {
+	if d == nil {
+		return
+	}
+	for _, ep := range d.endpoints {
+		log.Printf("🪝 [Controller] webhook endpoint: name=%s, url=%s, events=%v\n", ep.Name, ep.URL, ep.Events)
+		go d.deliverLoop(ctx, ep)
+		if d.queue != nil {
+			go d.streamLoop(ctx, ep)
+		}
+	}
+}
+
+// Emit 将事件加入订阅了该类型的 endpoint 队列（内存队列满时丢弃）
+func (d *webhookDispatcher) Emit(event *redisstore.LifecycleEvent) {
+	if d == nil {
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("❌ [Controller] webhook 事件序列化失败: type=%s, err=%v\n", event.Type, err)
+		return
+	}
+
+	delivery := &webhookDelivery{eventID: event.ID, eventType: event.Type, payload: payload}
+	for _, ep := range d.endpoints {
+		if ep.Accepts(event.Type) && !d.enqueue(ep, delivery) {
+			log.Printf("⚠️  [Controller] webhook 队列已满，丢弃事件: endpoint=%s, type=%s, id=%s\n", ep.Name, event.Type, event.ID)
+			d.metrics.RecordWebhookDelivery(context.Background(), ep.Name, event.Type, "dropped")
+		}
+	}
+}
+
+// enqueue 优先写入 Redis 待投递 stream，失败或未配置 Redis 时退化为内存队列（满时返回 false）
+func (d *webhookDispatcher) enqueue(ep *webhookEndpoint, delivery *webhookDelivery) bool {
+	if d.queue != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookEnqueueTimeout)
+		err := d.queue.Enqueue(ctx, ep.Name, delivery.eventID, delivery.eventType, delivery.payload)
+		cancel()
+		if err == nil {
+			return true
+		}
+		log.Printf("⚠️  [Controller] 写入 webhook 队列失败，改用内存队列: endpoint=%s, err=%v\n", ep.Name, err)
+	}
+	select {
+	case ep.queue <- delivery:
+		return true
+	default:
+		return false
+	}
+}
+
+// deliverLoop 按顺序投递 endpoint 内存队列中的事件，单个事件重试期间后续事件等待
+func (d *webhookDispatcher) deliverLoop(ctx context.Context, ep *webhookEndpoint) {
+	for {
+		select {
+		case delivery := <-ep.queue:
+			d.deliver(ctx, ep, delivery)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// streamLoop 从 Redis 待投递 stream 读取并投递 endpoint 的事件，处理完成后确认；
+// 启动时及周期性认领其他 Controller 遗留的未确认事件
+func (d *webhookDispatcher) streamLoop(ctx context.Context, ep *webhookEndpoint) {
+	if err := d.queue.EnsureGroup(ctx, ep.Name); err != nil {
+		log.Printf("❌ [Controller] 初始化 webhook 队列失败: endpoint=%s, err=%v\n", ep.Name, err)
+	}
+	lastClaim := time.Time{}
+	for ctx.Err() == nil {
+		var (
+			queued []*redisstore.QueuedWebhook
+			err    error
+		)
+		if time.Since(lastClaim) >= d.claimIdle {
+			lastClaim = time.Now()
+			queued, err = d.queue.Claim(ctx, ep.Name, d.consumer, d.claimIdle, webhookReadCount)
+			if len(queued) > 0 {
+				log.Printf("♻️  [Controller] 认领未确认的 webhook 事件: endpoint=%s, count=%d\n", ep.Name, len(queued))
+			}
+		}
+		if len(queued) == 0 && err == nil {
+			queued, err = d.queue.Read(ctx, ep.Name, d.consumer, webhookReadCount, webhookReadBlock)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("⚠️  [Controller] 读取 webhook 队列失败: endpoint=%s, err=%v\n", ep.Name, err)
+			sleepCtx(ctx, time.Second)
+			continue
+		}
+
+		for _, q := range queued {
+			delivery := &webhookDelivery{id: q.ID, eventID: q.EventID, eventType: q.EventType, payload: q.Payload}
+			if !d.deliver(ctx, ep, delivery) {
+				// ctx 取消：事件保留在 stream 中，重启后或由其他副本继续投递
+				return
+			}
+			if err := d.queue.Ack(ctx, ep.Name, q.ID); err != nil {
+				log.Printf("⚠️  [Controller] 确认 webhook 事件失败: endpoint=%s, id=%s, err=%v\n", ep.Name, q.ID, err)
+			}
+		}
+	}
+}
+
+// deliver 投递一个事件：2xx 为成功；408/429/5xx 和网络错误按退避重试，其他 4xx 不重试；
+// 失败时记入失败记录。返回 false 表示 ctx 取消、事件未处理完
+func (d *webhookDispatcher) deliver(ctx context.Context, ep *webhookEndpoint, delivery *webhookDelivery) bool {
+	var (
+		err     error
+		attempt int
+	)
+	for attempt = 1; ; attempt++ {
+		var retryable bool
+		retryable, err = d.post(ctx, ep, delivery)
+		if err == nil {
+			d.metrics.RecordWebhookDelivery(ctx, ep.Name, delivery.eventType, "success")
+			return true
+		}
+		if !retryable || attempt >= d.maxAttempts {
+			break
+		}
+		d.metrics.RecordWebhookDelivery(ctx, ep.Name, delivery.eventType, "retry")
+		delay := webhookBackoff(d.cfg.RetryBaseDelay, d.cfg.RetryMaxDelay, attempt)
+		log.Printf("⚠️  [Controller] webhook 投递失败，%v 后重试: endpoint=%s, id=%s, attempt=%d, err=%v\n", delay, ep.Name, delivery.eventID, attempt, err)
+		if !sleepCtx(ctx, delay) {
+			return false
+		}
+	}
+
+	d.metrics.RecordWebhookDelivery(ctx, ep.Name, delivery.eventType, "failed")
+	log.Printf("❌ [Controller] webhook 投递失败: endpoint=%s, type=%s, id=%s, attempts=%d, err=%v\n", ep.Name, delivery.eventType, delivery.eventID, attempt, err)
+	if d.store == nil {
+		return true
+	}
+	ferr := d.store.Fail(ctx, &redisstore.FailedWebhook{
+		Endpoint:  ep.Name,
+		EventID:   delivery.eventID,
+		EventType: delivery.eventType,
+		Payload:   delivery.payload,
+		Attempts:  attempt,
+		Error:     err.Error(),
+	})
+	if ferr != nil {
+		log.Printf("❌ [Controller] 记录 webhook 失败事件失败: %v\n", ferr)
+	}
+	return true
+}
+
+// post 发送一次签名请求，返回失败是否可重试
+func (d *webhookDispatcher) post(ctx context.Context, ep *webhookEndpoint, delivery *webhookDelivery) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(delivery.payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(pkg.WebhookEventHeader, delivery.eventType)
+	req.Header.Set(pkg.WebhookDeliveryHeader, delivery.eventID)
+	req.Header.Set(pkg.WebhookSignatureHeader, pkg.SignWebhook(ep.Secret, time.Now().Unix(), delivery.payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return true, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
+
+// hasEndpoint endpoint 是否仍在配置中
+func (d *webhookDispatcher) hasEndpoint(name string) bool {
+	if d == nil {
+		return false
+	}
+	_, ok := d.endpoints[name]
+	return ok
+}
+
+// replay 将失败事件重新加入 endpoint 队列（重新计算投递次数），endpoint 已移除或队列已满时返回 false
+func (d *webhookDispatcher) replay(f *redisstore.FailedWebhook) bool {
+	if d == nil {
+		return false
+	}
+	ep, ok := d.endpoints[f.Endpoint]
+	if !ok {
+		return false
+	}
+	return d.enqueue(ep, &webhookDelivery{eventID: f.EventID, eventType: f.EventType, payload: f.Payload})
+}
+
+// webhookBackoff 第 attempt 次失败后的重试间隔（指数退避，不超过 max）
+func webhookBackoff(base, max time.Duration, attempt int) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// sleepCtx 等待 d，ctx 取消时提前返回 false
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// ========== Webhook 管理 RPC ==========
+
+// ListFailedWebhooks 列出投递次数耗尽的 webhook 事件
+func (s *ControllerServer) ListFailedWebhooks(ctx context.Context, req *controller.ListFailedWebhooksRequest) (*controller.ListFailedWebhooksResponse, error) {
+	if s.webhookStore == nil {
+		return nil, fmt.Errorf("webhook failure log unavailable: redis not connected")
+	}
+	limit := int64(req.Limit)
+	if limit <= 0 {
+		limit = defaultWebhookListLimit
+	}
+	failures, next, err := s.webhookStore.List(ctx, req.Endpoint, req.StartId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &controller.ListFailedWebhooksResponse{NextId: next}
+	for _, f := range failures {
+		resp.Failures = append(resp.Failures, &controller.FailedWebhook{
+			Id:        f.ID,
+			Endpoint:  f.Endpoint,
+			EventId:   f.EventID,
+			EventType: f.EventType,
+			Payload:   f.Payload,
+			Attempts:  int32(f.Attempts),
+			Error:     f.Error,
+			FailedAt:  f.FailedAt,
+		})
+	}
+	return resp, nil
+}
+
+// ReplayWebhooks 重新投递失败的 webhook 事件；endpoint 已不在配置中或队列已满时保留记录（队列已满时重新记录）
+func (s *ControllerServer) ReplayWebhooks(ctx context.Context, req *controller.ReplayWebhooksRequest) (*controller.ReplayWebhooksResponse, error) {
+	if s.webhookStore == nil {
+		return nil, fmt.Errorf("webhook failure log unavailable: redis not connected")
+	}
+
+	var failures []*redisstore.FailedWebhook
+	if req.All {
+		start := ""
+		for {
+			page, next, err := s.webhookStore.List(ctx, req.Endpoint, start, defaultWebhookListLimit)
+			if err != nil {
+				return nil, err
+			}
+			failures = append(failures, page...)
+			if next == "" {
+				break
+			}
+			start = next
+		}
+	} else {
+		for _, id := range req.Ids {
+			f, err := s.webhookStore.Get(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if f != nil {
+				failures = append(failures, f)
+			}
+		}
+	}
+
+	resp := &controller.ReplayWebhooksResponse{}
+	for _, f := range failures {
+		if !s.webhooks.hasEndpoint(f.Endpoint) {
+			log.Printf("⚠️  [Controller] webhook endpoint 已不在配置中，跳过重放: id=%s, endpoint=%s\n", f.ID, f.Endpoint)
+			resp.FailedIds = append(resp.FailedIds, f.ID)
+			continue
+		}
+		// 先删除记录再入队：多个 Controller 副本同时重放时只有删除成功的一方投递
+		deleted, err := s.webhookStore.Delete(ctx, f.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !deleted {
+			continue
+		}
+		if !s.webhooks.replay(f) {
+			// 队列已满：重新记录（失败记录 ID 会变化）
+			log.Printf("⚠️  [Controller] webhook 队列已满，无法重放: id=%s, endpoint=%s\n", f.ID, f.Endpoint)
+			if err := s.webhookStore.Fail(ctx, f); err != nil {
+				log.Printf("❌ [Controller] 恢复 webhook 失败记录失败: %v\n", err)
+			}
+			resp.FailedIds = append(resp.FailedIds, f.ID)
+			continue
+		}
+		resp.Replayed++
+	}
+	log.Printf("🔁 [Controller] 重放 webhook 事件: replayed=%d, failed=%d\n", resp.Replayed, len(resp.FailedIds))
+	return resp, nil
+}
@@ -1,10 +1,16 @@
 -- 初始化数据库脚本
 -- 创建 rooms 表
+-- 房间/用户 ID 在多租户下带 "<app_id>:" 前缀，长度按 128 预留
 CREATE TABLE IF NOT EXISTS rooms (
-    id VARCHAR(64) PRIMARY KEY,
+    id VARCHAR(128) PRIMARY KEY,
     name VARCHAR(128) NOT NULL,
     description TEXT,
     max_users INT DEFAULT 100,
+    join_mode VARCHAR(16) DEFAULT 'open',
+    password_hash VARCHAR(64),
+    publish_policy VARCHAR(16) DEFAULT 'anyone',
+    max_message_bytes INT DEFAULT 0,
+    owner_id VARCHAR(128),
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP NULL,
@@ -15,10 +21,11 @@ CREATE TABLE IF NOT EXISTS rooms (
 -- 创建 room_users 表
 CREATE TABLE IF NOT EXISTS room_users (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
-    user_id VARCHAR(64) NOT NULL,
+    user_id VARCHAR(128) NOT NULL,
     user_name VARCHAR(128) NOT NULL,
-    room_id VARCHAR(64) NOT NULL,
+    room_id VARCHAR(128) NOT NULL,
     node_id VARCHAR(64) NOT NULL,
+    role VARCHAR(16) NOT NULL DEFAULT 'member' COMMENT 'owner, moderator, member, viewer',
     joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     left_at TIMESTAMP NULL,
     deleted_at TIMESTAMP NULL,
@@ -31,6 +38,16 @@ CREATE TABLE IF NOT EXISTS room_users (
     INDEX idx_deleted_at (deleted_at)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- 创建 room_invites 表
+CREATE TABLE IF NOT EXISTS room_invites (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    room_id VARCHAR(128) NOT NULL,
+    user_id VARCHAR(128) NOT NULL,
+    invited_by VARCHAR(128),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_room_invite (room_id, user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- 创建 connect_nodes 表
 CREATE TABLE IF NOT EXISTS connect_nodes (
     id VARCHAR(64) PRIMARY KEY,
@@ -38,6 +55,7 @@ CREATE TABLE IF NOT EXISTS connect_nodes (
     region VARCHAR(64),
     max_connections INT DEFAULT 10000,
     current_connections INT DEFAULT 0,
+    room_count INT DEFAULT 0,
     cpu_usage FLOAT DEFAULT 0,
     memory_usage FLOAT DEFAULT 0,
     status VARCHAR(32) DEFAULT 'online',
@@ -49,4 +67,30 @@ CREATE TABLE IF NOT EXISTS connect_nodes (
     INDEX idx_current_connections (current_connections)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- 创建 tenants 表
+CREATE TABLE IF NOT EXISTS tenants (
+    app_id VARCHAR(32) PRIMARY KEY,
+    name VARCHAR(128) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'active' COMMENT 'active, disabled',
+    max_message_bytes INT DEFAULT 0,
+    publish_rate INT DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    soft_max_connections INT DEFAULT 0 COMMENT '同时在线连接数软限制，0 表示不限制',
+    max_connections INT DEFAULT 0 COMMENT '同时在线连接数硬限制',
+    soft_daily_messages BIGINT DEFAULT 0 COMMENT '每天推送请求数软限制',
+    max_daily_messages BIGINT DEFAULT 0 COMMENT '每天推送请求数硬限制'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 创建 api_keys 表（只保存 key 的 SHA-256 哈希）
+CREATE TABLE IF NOT EXISTS api_keys (
+    key_id VARCHAR(16) PRIMARY KEY,
+    app_id VARCHAR(32) NOT NULL,
+    name VARCHAR(128),
+    key_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_key_hash (key_hash),
+    INDEX idx_app_id (app_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
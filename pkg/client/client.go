@@ -0,0 +1,610 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	mrand "math/rand"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+const (
+	opHeartbeat      = int32(5)
+	opHeartbeatReply = int32(6)
+
+	protoVersion = int32(1)
+
+	defaultPath              = "/connect"
+	defaultHeartbeatInterval = 20 * time.Second
+	defaultDialTimeout       = 5 * time.Second
+	defaultRequestTimeout    = 5 * time.Second
+	defaultWriteTimeout      = 5 * time.Second
+	defaultMinBackoff        = 500 * time.Millisecond
+	defaultMaxBackoff        = 30 * time.Second
+)
+
+var (
+	// ErrClosed 客户端已关闭
+	ErrClosed = errors.New("client: closed")
+	// ErrDisconnected 请求等待响应时连接断开
+	ErrDisconnected = errors.New("client: disconnected")
+	// ErrJoinRejected 加入房间被拒绝（房间策略、密码等），不会重试
+	ErrJoinRejected = errors.New("client: join room rejected")
+	// ErrRequestFailed 服务端返回失败响应（"xxx failed: ..."）
+	ErrRequestFailed = errors.New("client: request failed")
+	// ErrNoNodes 服务发现没有返回可用节点
+	ErrNoNodes = errors.New("client: no connect node available")
+	// ErrTooManyAttempts 连续重连失败次数超过 MaxAttempts
+	ErrTooManyAttempts = errors.New("client: too many reconnect attempts")
+	// ErrAlreadyRunning Run 已在运行
+	ErrAlreadyRunning = errors.New("client: already running")
+)
+
+// Config 客户端配置
+type Config struct {
+	// Discovery 提供 Connect-Node 地址，每次重连前重新查询
+	Discovery Discovery
+
+	// 连接凭证（二选一）：租户后端经 Web /api/v1/tokens 签发的连接令牌，或租户 API key（仅限服务端）；
+	// 都为空时只能连接开启了 allow_anonymous 的节点（默认租户）
+	Token  string
+	APIKey string
+
+	AppID      string // 租户 ID（可选，需与凭证所属租户一致），用于把推送中的集群内 ID 转回租户内 ID
+	RoomID     string
+	UserID     string
+	UserName   string
+	Password   string // 房间密码
+	DeviceID   string // 为空时生成随机 ID；重连沿用同一个 ID，节点上残留的旧会话会被替换
+	DeviceType string
+
+	// 加入房间时订阅的 op 和主题，为空时服务端只推送 OpSendMsg。
+	// 之后通过 Watch/Subscribe 修改的订阅在重连后自动恢复
+	WatchOps []int32
+	Topics   []string
+
+	Path              string        // WebSocket 路径，默认 /connect（节点地址为完整 URL 时忽略）
+	HeartbeatInterval time.Duration // 心跳间隔，默认 20s
+	ReadTimeout       time.Duration // 超过该时间没有收到任何数据（含心跳响应）时重连，默认 3 倍心跳间隔
+	DialTimeout       time.Duration // 建立连接超时，默认 5s
+	RequestTimeout    time.Duration // 加入房间和 ctx 没有截止时间的请求的超时，默认 5s
+	WriteTimeout      time.Duration // 写超时，默认 5s
+	MinBackoff        time.Duration // 重连退避初始值，默认 500ms
+	MaxBackoff        time.Duration // 重连退避上限，默认 30s
+	MaxAttempts       int           // 连续重连失败的最大次数，0 表示不限
+}
+
+// State 连接状态
+type State int32
+
+const (
+	// StateIdle 尚未调用 Run
+	StateIdle State = iota
+	// StateConnecting 首次连接中
+	StateConnecting
+	// StateConnected 已连接并加入房间
+	StateConnected
+	// StateReconnecting 连接断开，等待重连
+	StateReconnecting
+	// StateClosed 已停止
+	StateClosed
+)
+
+func (s State) String() string {
+	switch s {
+	case StateIdle:
+		return "idle"
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateClosed:
+		return "closed"
+	}
+	return fmt.Sprintf("state(%d)", int32(s))
+}
+
+// Client Connect-Node 客户端：负责心跳、断线重连（指数退避 + 节点轮换）、重新鉴权和加入房间、
+// 恢复订阅，按 op 分发推送消息，并根据 Seq 检测消息缺口
+type Client struct {
+	cfg Config
+
+	handlersMu    sync.RWMutex
+	handlers      map[int32]Handler
+	serverRequest ServerRequestHandler
+	onState       func(State, error)
+	onGap         func(Gap)
+
+	mu        sync.Mutex
+	state     State
+	conn      *connection     // 当前已加入房间的连接
+	connected chan struct{}   // 加入房间后关闭，断开后重建
+	watchOps  map[int32]bool  // 重连时恢复的 op 订阅
+	topics    map[string]bool // 重连时恢复的主题订阅
+
+	seq        atomic.Int32 // 控制请求（加入房间、订阅、心跳）的 Seq
+	publishMu  sync.Mutex   // 串行发言，被拒绝的发言可以复用 Seq
+	publishSeq int32        // 最后一条发出的发言 Seq（Push-Manager 没有 Redis、无法分配 Seq 时广播沿用，需保持连续）
+	gaps       *gapTracker
+	running    atomic.Bool
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	done      chan struct{}
+	runErr    error
+}
+
+// New 创建客户端（不会建立连接，调用 Run 或 Connect 开始连接）
+func New(cfg Config) (*Client, error) {
+	if cfg.Discovery == nil {
+		return nil, errors.New("client: Discovery is required")
+	}
+	if err := pkg.ValidateLocalID(cfg.RoomID); err != nil || cfg.RoomID == "" {
+		return nil, fmt.Errorf("client: invalid RoomID %q", cfg.RoomID)
+	}
+	if err := pkg.ValidateLocalID(cfg.UserID); err != nil || cfg.UserID == "" {
+		return nil, fmt.Errorf("client: invalid UserID %q", cfg.UserID)
+	}
+	if cfg.DeviceID == "" {
+		cfg.DeviceID = randomID()
+	}
+	if cfg.Path == "" {
+		cfg.Path = defaultPath
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = defaultHeartbeatInterval
+	}
+	if cfg.ReadTimeout <= 0 {
+		cfg.ReadTimeout = 3 * cfg.HeartbeatInterval
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defaultDialTimeout
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = defaultRequestTimeout
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = defaultWriteTimeout
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaultMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = defaultMaxBackoff
+		if cfg.MaxBackoff < cfg.MinBackoff {
+			cfg.MaxBackoff = cfg.MinBackoff
+		}
+	}
+
+	c := &Client{
+		cfg:       cfg,
+		handlers:  make(map[int32]Handler),
+		connected: make(chan struct{}),
+		watchOps:  make(map[int32]bool),
+		topics:    make(map[string]bool),
+		gaps:      newGapTracker(),
+		closed:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	// 与服务端默认行为一致：未指定 op 时订阅 OpSendMsg
+	if len(cfg.WatchOps) == 0 {
+		c.watchOps[protocol.OpSendMsg] = true
+	}
+	for _, op := range cfg.WatchOps {
+		c.watchOps[op] = true
+	}
+	for _, topic := range cfg.Topics {
+		c.topics[topic] = true
+	}
+	return c, nil
+}
+
+// Run 连接并保持连接，直到 ctx 取消（返回 ctx.Err()）、调用 Close（返回 nil）、
+// 加入房间被拒绝或超过重连次数
+func (c *Client) Run(ctx context.Context) error {
+	if !c.running.CompareAndSwap(false, true) {
+		return ErrAlreadyRunning
+	}
+	err := c.run(ctx)
+	c.runErr = err
+	close(c.done)
+	return err
+}
+
+// Connect 在后台运行 Run，并等待首次加入房间成功
+func (c *Client) Connect(ctx context.Context) error {
+	if c.running.Load() {
+		return ErrAlreadyRunning
+	}
+	go c.Run(context.Background())
+	select {
+	case <-c.waitConnected():
+		return nil
+	case <-c.done:
+		if c.runErr != nil {
+			return c.runErr
+		}
+		return ErrClosed
+	case <-ctx.Done():
+		c.Close()
+		return ctx.Err()
+	}
+}
+
+// Close 断开连接并停止重连，等待 Run 返回
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	if c.running.Load() {
+		<-c.done
+	}
+	return nil
+}
+
+// Done Run 返回后关闭
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err Run 的返回值（Done 关闭之后有效）
+func (c *Client) Err() error {
+	select {
+	case <-c.done:
+		return c.runErr
+	default:
+		return nil
+	}
+}
+
+// State 当前连接状态
+func (c *Client) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// Node 当前连接的节点，未连接时为空
+func (c *Client) Node() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return ""
+	}
+	return c.conn.node
+}
+
+// DeviceID 本客户端的设备 ID
+func (c *Client) DeviceID() string {
+	return c.cfg.DeviceID
+}
+
+// OnStateChange 注册连接状态变化回调，err 为断开或停止的原因
+func (c *Client) OnStateChange(fn func(state State, err error)) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+	c.onState = fn
+}
+
+func (c *Client) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-c.closed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	c.setState(StateConnecting, nil)
+	var (
+		last     string
+		start    = mrand.Int()
+		failures int
+	)
+	for {
+		node, err := c.pickNode(ctx, last, start)
+		if err == nil {
+			last = node
+			var joined bool
+			joined, err = c.session(ctx, node)
+			if joined {
+				failures = 0
+			}
+		}
+
+		select {
+		case <-c.closed:
+			c.setState(StateClosed, nil)
+			return nil
+		default:
+		}
+		if ctx.Err() != nil {
+			c.setState(StateClosed, ctx.Err())
+			return ctx.Err()
+		}
+		if errors.Is(err, ErrJoinRejected) {
+			c.setState(StateClosed, err)
+			return err
+		}
+
+		failures++
+		if c.cfg.MaxAttempts > 0 && failures > c.cfg.MaxAttempts {
+			err = fmt.Errorf("%w: %v", ErrTooManyAttempts, err)
+			c.setState(StateClosed, err)
+			return err
+		}
+		c.setState(StateReconnecting, err)
+
+		timer := time.NewTimer(c.backoff(failures))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+}
+
+// pickNode 重新查询节点列表，从上次连接的节点之后轮转选择（断开通常意味着节点下线或 drain）
+func (c *Client) pickNode(ctx context.Context, last string, start int) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.RequestTimeout)
+	defer cancel()
+	nodes, err := c.cfg.Discovery.Nodes(ctx)
+	if err != nil {
+		return "", fmt.Errorf("discover nodes: %w", err)
+	}
+	if len(nodes) == 0 {
+		return "", ErrNoNodes
+	}
+	return nextNode(nodes, last, start), nil
+}
+
+// backoff 第 n 次失败后的等待时间：指数增长并加入抖动，避免节点故障后客户端同时重连
+func (c *Client) backoff(n int) time.Duration {
+	d := c.cfg.MinBackoff
+	for i := 1; i < n && d < c.cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > c.cfg.MaxBackoff {
+		d = c.cfg.MaxBackoff
+	}
+	return d/2 + time.Duration(mrand.Int63n(int64(d/2)+1))
+}
+
+// session 建立连接、鉴权并加入房间，之后发送心跳直到连接断开。joined 表示本次是否加入成功
+func (c *Client) session(ctx context.Context, node string) (joined bool, err error) {
+	target, err := nodeURL(node, c.cfg.Path)
+	if err != nil {
+		return false, err
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, c.cfg.DialTimeout)
+	ws, _, err := websocket.DefaultDialer.DialContext(dialCtx, target, nil)
+	cancel()
+	if err != nil {
+		return false, fmt.Errorf("dial %s: %w", node, err)
+	}
+	conn := newConnection(ctx, ws, node, c.cfg.WriteTimeout)
+	defer conn.close()
+
+	readErr := make(chan error, 1)
+	go func() { readErr <- c.readLoop(conn) }()
+
+	// 鉴权失败（如节点 drain、租户停用）时服务端不回复，超时后换下一个节点
+	joinCtx, cancel := context.WithTimeout(ctx, c.cfg.RequestTimeout)
+	reply, err := conn.request(joinCtx, c.joinProto(), protocol.OpJoinRoomReply)
+	cancel()
+	if err != nil {
+		return false, fmt.Errorf("join room on %s: %w", node, err)
+	}
+	if _, rejected, reason := protocol.JoinRoomReply(reply); rejected {
+		return false, fmt.Errorf("%w: %s", ErrJoinRejected, reason)
+	}
+
+	c.setConnected(conn)
+	defer c.setDisconnected(conn)
+
+	ticker := time.NewTicker(c.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case err := <-readErr:
+			return true, err
+		case <-ticker.C:
+			if err := conn.write(c.newProto(opHeartbeat, nil)); err != nil {
+				return true, err
+			}
+		}
+	}
+}
+
+// joinRoomBody 加入房间请求体（与 Connect-Node 的 joinRoomBody 一致）
+type joinRoomBody struct {
+	Token      string   `json:"token,omitempty"`
+	APIKey     string   `json:"api_key,omitempty"`
+	AppID      string   `json:"app_id,omitempty"`
+	UserName   string   `json:"user_name,omitempty"`
+	Password   string   `json:"password,omitempty"`
+	DeviceID   string   `json:"device_id,omitempty"`
+	DeviceType string   `json:"device_type,omitempty"`
+	WatchOps   []int32  `json:"watch_ops,omitempty"`
+	Topics     []string `json:"topics,omitempty"`
+}
+
+// joinProto 加入房间请求，携带当前的 op 和主题订阅，重连后恢复订阅
+func (c *Client) joinProto() *protocol.Proto {
+	c.mu.Lock()
+	body := joinRoomBody{
+		Token:      c.cfg.Token,
+		APIKey:     c.cfg.APIKey,
+		AppID:      c.cfg.AppID,
+		UserName:   c.cfg.UserName,
+		Password:   c.cfg.Password,
+		DeviceID:   c.cfg.DeviceID,
+		DeviceType: c.cfg.DeviceType,
+	}
+	for op := range c.watchOps {
+		body.WatchOps = append(body.WatchOps, op)
+	}
+	for topic := range c.topics {
+		body.Topics = append(body.Topics, topic)
+	}
+	c.mu.Unlock()
+
+	data, _ := json.Marshal(body)
+	return c.newProto(protocol.OpJoinRoom, data)
+}
+
+// newProto 构造请求，Seq 由客户端分配，用于匹配响应（发言的 Seq 由 Publish 分配）
+func (c *Client) newProto(op int32, body []byte) *protocol.Proto {
+	return &protocol.Proto{
+		Ver:    protoVersion,
+		Op:     op,
+		Seq:    c.seq.Add(1),
+		Roomid: c.cfg.RoomID,
+		Userid: c.cfg.UserID,
+		Body:   body,
+	}
+}
+
+// readLoop 读取并分发消息，超过 ReadTimeout 没有数据时返回错误触发重连
+func (c *Client) readLoop(conn *connection) error {
+	for {
+		conn.ws.SetReadDeadline(time.Now().Add(c.cfg.ReadTimeout))
+		_, data, err := conn.ws.ReadMessage()
+		if err != nil {
+			return err
+		}
+		protos, err := decodeProtos(data)
+		for _, p := range protos {
+			c.dispatch(conn, p)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) setConnected(conn *connection) {
+	c.mu.Lock()
+	c.conn = conn
+	close(c.connected)
+	c.mu.Unlock()
+	c.setState(StateConnected, nil)
+}
+
+func (c *Client) setDisconnected(conn *connection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == conn {
+		c.conn = nil
+		c.connected = make(chan struct{})
+	}
+}
+
+// waitConnected 返回在加入房间后关闭的 channel
+func (c *Client) waitConnected() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+// currentConn 等待连接可用（重连期间的请求在恢复后发送）
+func (c *Client) currentConn(ctx context.Context) (*connection, error) {
+	for {
+		c.mu.Lock()
+		conn, connected := c.conn, c.connected
+		c.mu.Unlock()
+		if conn != nil {
+			return conn, nil
+		}
+		select {
+		case <-connected:
+		case <-c.closed:
+			return nil, ErrClosed
+		case <-c.done:
+			return nil, ErrClosed
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (c *Client) setState(state State, err error) {
+	c.mu.Lock()
+	changed := c.state != state
+	c.state = state
+	c.mu.Unlock()
+
+	c.handlersMu.RLock()
+	fn := c.onState
+	c.handlersMu.RUnlock()
+	if fn != nil && (changed || err != nil) {
+		fn(state, err)
+	}
+}
+
+// nodeURL 节点地址转为 WebSocket URL（host:port 使用 ws:// 和配置的路径）
+func nodeURL(node, path string) (string, error) {
+	if !strings.Contains(node, "://") {
+		node = "ws://" + node + path
+	}
+	u, err := url.Parse(node)
+	if err != nil {
+		return "", fmt.Errorf("invalid node address %q: %w", node, err)
+	}
+	return u.String(), nil
+}
+
+// replyFailure 解析 "xxx failed: reason" 格式的失败响应
+func replyFailure(p *protocol.Proto) (reason string, failed bool) {
+	body := string(p.Body)
+	if i := strings.Index(body, " failed: "); i >= 0 {
+		return body[i+len(" failed: "):], true
+	}
+	return "", strings.HasSuffix(body, " failed")
+}
+
+// replyError 失败响应转为 ErrRequestFailed
+func replyError(p *protocol.Proto) error {
+	if reason, failed := replyFailure(p); failed {
+		return fmt.Errorf("%w: %s", ErrRequestFailed, reason)
+	}
+	return nil
+}
+
+func randomID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+// 与 pkg/getty 的 ProtoPackageHandler 相同的帧格式：
+// [packLen(4)] [headerLen(2)] [Ver(2)] [Op(4)] [Seq(4)] [RoomIdLen(2)] [RoomId(...)] [UserIdLen(2)] [UserId(...)] [Body(...)]
+const (
+	rawHeaderSize = 16
+	stringLenSize = 2
+	maxPackSize   = 1 << 20
+)
+
+var errBadFrame = errors.New("client: malformed frame")
+
+// encodeProto 编码一个帧
+func encodeProto(p *protocol.Proto) []byte {
+	packLen := rawHeaderSize + stringLenSize + len(p.Roomid) + stringLenSize + len(p.Userid) + len(p.Body)
+	buf := make([]byte, packLen)
+	binary.BigEndian.PutUint32(buf[0:], uint32(packLen))
+	binary.BigEndian.PutUint16(buf[4:], rawHeaderSize)
+	binary.BigEndian.PutUint16(buf[6:], uint16(p.Ver))
+	binary.BigEndian.PutUint32(buf[8:], uint32(p.Op))
+	binary.BigEndian.PutUint32(buf[12:], uint32(p.Seq))
+
+	off := rawHeaderSize
+	binary.BigEndian.PutUint16(buf[off:], uint16(len(p.Roomid)))
+	off += stringLenSize
+	off += copy(buf[off:], p.Roomid)
+	binary.BigEndian.PutUint16(buf[off:], uint16(len(p.Userid)))
+	off += stringLenSize
+	off += copy(buf[off:], p.Userid)
+	copy(buf[off:], p.Body)
+	return buf
+}
+
+// decodeProtos 解码一条 WebSocket 消息中的全部帧（Body 为独立拷贝，可以在回调之外持有）
+func decodeProtos(data []byte) ([]*protocol.Proto, error) {
+	var protos []*protocol.Proto
+	for len(data) > 0 {
+		if len(data) < rawHeaderSize {
+			return protos, errBadFrame
+		}
+		packLen := int(binary.BigEndian.Uint32(data[0:]))
+		if packLen < rawHeaderSize+2*stringLenSize || packLen > maxPackSize || packLen > len(data) ||
+			binary.BigEndian.Uint16(data[4:]) != rawHeaderSize {
+			return protos, errBadFrame
+		}
+		frame := data[:packLen]
+		data = data[packLen:]
+
+		p := &protocol.Proto{
+			Ver: int32(binary.BigEndian.Uint16(frame[6:])),
+			Op:  int32(binary.BigEndian.Uint32(frame[8:])),
+			Seq: int32(binary.BigEndian.Uint32(frame[12:])),
+		}
+		off := rawHeaderSize
+		var ok bool
+		if p.Roomid, off, ok = readString(frame, off); !ok {
+			return protos, errBadFrame
+		}
+		if p.Userid, off, ok = readString(frame, off); !ok {
+			return protos, errBadFrame
+		}
+		if off < len(frame) {
+			p.Body = append([]byte(nil), frame[off:]...)
+		}
+		protos = append(protos, p)
+	}
+	return protos, nil
+}
+
+// readString 读取 2 字节长度前缀的字符串
+func readString(frame []byte, off int) (string, int, bool) {
+	if off+stringLenSize > len(frame) {
+		return "", off, false
+	}
+	n := int(binary.BigEndian.Uint16(frame[off:]))
+	off += stringLenSize
+	if off+n > len(frame) {
+		return "", off, false
+	}
+	return string(frame[off : off+n]), off + n, true
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	gettypkg "github.com/livekit/psrpc/examples/pubsub/pkg/getty"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+var codecCases = []*protocol.Proto{
+	{Ver: 1, Op: protocol.OpPublish, Seq: 7, Roomid: "room-1", Userid: "alice", Body: []byte("hello")},
+	{Ver: 1, Op: protocol.OpJoinRoomReply, Seq: 1, Roomid: "room-1", Userid: "", Body: []byte(protocol.JoinRoomSuccess)},
+	{Ver: 1, Op: 3, Seq: 0, Roomid: "", Userid: "", Body: nil},
+	{Ver: 1, Op: protocol.OpServerRequest, Seq: -1, Roomid: "房间", Userid: "用户", Body: []byte{0, 1, 2}},
+}
+
+func equalProto(a, b *protocol.Proto) bool {
+	return a.Ver == b.Ver && a.Op == b.Op && a.Seq == b.Seq && a.Roomid == b.Roomid && a.Userid == b.Userid &&
+		bytes.Equal(a.Body, b.Body)
+}
+
+// TestCodecMatchesGetty 客户端编解码与服务端 pkg/getty 的 ProtoPackageHandler 互通
+func TestCodecMatchesGetty(t *testing.T) {
+	h := gettypkg.NewProtoPackageHandler(pkg.NewPool(1, 4096), nil)
+	defer h.Close()
+
+	for _, want := range codecCases {
+		data := encodeProto(want)
+		got, n, err := h.Read(nil, data)
+		if err != nil || n != len(data) {
+			t.Fatalf("getty Read(encodeProto(%+v)) = %d, %v", want, n, err)
+		}
+		if !equalProto(got.(*protocol.Proto), want) {
+			t.Errorf("getty Read(encodeProto()) = %+v, want %+v", got, want)
+		}
+
+		server, err := h.Write(nil, want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(server, data) {
+			t.Errorf("getty Write(%+v) = %x, encodeProto = %x", want, server, data)
+		}
+		protos, err := decodeProtos(server)
+		if err != nil || len(protos) != 1 || !equalProto(protos[0], want) {
+			t.Errorf("decodeProtos(getty Write(%+v)) = %+v, %v", want, protos, err)
+		}
+	}
+}
+
+func TestDecodeProtos(t *testing.T) {
+	var data []byte
+	for _, p := range codecCases {
+		data = append(data, encodeProto(p)...)
+	}
+	protos, err := decodeProtos(data)
+	if err != nil || len(protos) != len(codecCases) {
+		t.Fatalf("decodeProtos() = %d protos, %v, want %d", len(protos), err, len(codecCases))
+	}
+	for i, p := range protos {
+		if !equalProto(p, codecCases[i]) {
+			t.Errorf("decodeProtos()[%d] = %+v, want %+v", i, p, codecCases[i])
+		}
+	}
+
+	// Body 为独立拷贝
+	first := encodeProto(codecCases[0])
+	protos, _ = decodeProtos(first)
+	first[len(first)-1] = 'X'
+	if string(protos[0].Body) != "hello" {
+		t.Errorf("decodeProtos() Body = %q, want a copy", protos[0].Body)
+	}
+
+	valid := encodeProto(codecCases[0])
+	badHeader := append([]byte(nil), valid...)
+	badHeader[5] = 20
+	badRoomLen := append([]byte(nil), valid...)
+	badRoomLen[rawHeaderSize], badRoomLen[rawHeaderSize+1] = 0xff, 0xff
+	tests := []struct {
+		name  string
+		data  []byte
+		valid int
+	}{
+		{"short", valid[:rawHeaderSize-1], 0},
+		{"truncated", valid[:len(valid)-1], 0},
+		{"bad header len", badHeader, 0},
+		{"bad room len", badRoomLen, 0},
+		{"trailing garbage", append(append([]byte(nil), valid...), 1, 2, 3), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			protos, err := decodeProtos(tt.data)
+			if !errors.Is(err, errBadFrame) || len(protos) != tt.valid {
+				t.Errorf("decodeProtos() = %d protos, %v, want %d, errBadFrame", len(protos), err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+// connection 一条 WebSocket 连接及其上等待响应的请求（按 Seq 匹配）
+type connection struct {
+	ws           *websocket.Conn
+	node         string
+	writeTimeout time.Duration
+
+	// ctx 在连接关闭时取消，用于该连接上的服务端请求处理
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[pendingKey]*pendingRequest
+	closed  bool
+}
+
+// pendingKey 响应按 op 和 Seq 匹配（发言与其他请求使用不同的 Seq 序列）
+type pendingKey struct {
+	op  int32
+	seq int32
+}
+
+// pendingRequest 等待响应的请求
+type pendingRequest struct {
+	ch chan *protocol.Proto
+}
+
+func newConnection(ctx context.Context, ws *websocket.Conn, node string, writeTimeout time.Duration) *connection {
+	ctx, cancel := context.WithCancel(ctx)
+	return &connection{
+		ws:           ws,
+		node:         node,
+		writeTimeout: writeTimeout,
+		ctx:          ctx,
+		cancel:       cancel,
+		pending:      make(map[pendingKey]*pendingRequest),
+	}
+}
+
+// write 发送一个帧（并发安全）
+func (c *connection) write(p *protocol.Proto) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.ws.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	return c.ws.WriteMessage(websocket.BinaryMessage, encodeProto(p))
+}
+
+// request 发送请求并等待 Seq 相同、op 为 replyOp 的响应
+func (c *connection) request(ctx context.Context, p *protocol.Proto, replyOp int32) (*protocol.Proto, error) {
+	key := pendingKey{op: replyOp, seq: p.Seq}
+	req := &pendingRequest{ch: make(chan *protocol.Proto, 1)}
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrDisconnected
+	}
+	c.pending[key] = req
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		if c.pending[key] == req {
+			delete(c.pending, key)
+		}
+		c.mu.Unlock()
+	}()
+
+	if err := c.write(p); err != nil {
+		return nil, err
+	}
+	select {
+	case reply, ok := <-req.ch:
+		if !ok {
+			return nil, ErrDisconnected
+		}
+		return reply, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// resolve 将响应交给等待的请求，返回是否匹配。
+// 加入房间响应与推送消息同为 op 2，按响应体区分
+func (c *connection) resolve(p *protocol.Proto) bool {
+	if p.Op == protocol.OpJoinRoomReply {
+		if isReply, _, _ := protocol.JoinRoomReply(p); !isReply {
+			return false
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := pendingKey{op: p.Op, seq: p.Seq}
+	req := c.pending[key]
+	if req == nil {
+		return false
+	}
+	delete(c.pending, key)
+	req.ch <- p
+	return true
+}
+
+// close 关闭连接，等待中的请求返回 ErrDisconnected
+func (c *connection) close() {
+	c.cancel()
+	c.ws.Close()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	for key, req := range c.pending {
+		close(req.ch)
+		delete(c.pending, key)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+)
+
+// Discovery 提供可连接的 Connect-Node 地址（host:port 或完整的 ws:// / wss:// URL）。
+// 每次（重新）连接前都会调用，实现可以返回最新的节点列表，例如从负载均衡器或服务端接口查询
+type Discovery interface {
+	Nodes(ctx context.Context) ([]string, error)
+}
+
+// StaticNodes 固定的节点列表
+type StaticNodes []string
+
+// Nodes 实现 Discovery
+func (s StaticNodes) Nodes(ctx context.Context) ([]string, error) {
+	return s, nil
+}
+
+// DiscoveryFunc 函数形式的 Discovery
+type DiscoveryFunc func(ctx context.Context) ([]string, error)
+
+// Nodes 实现 Discovery
+func (f DiscoveryFunc) Nodes(ctx context.Context) ([]string, error) {
+	return f(ctx)
+}
+
+// nextNode 选择下一个节点：从上次连接的节点之后轮转，上次节点不在列表中时从 start 开始
+func nextNode(nodes []string, last string, start int) string {
+	for i, node := range nodes {
+		if node == last {
+			return nodes[(i+1)%len(nodes)]
+		}
+	}
+	return nodes[start%len(nodes)]
+}
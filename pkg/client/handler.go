@@ -0,0 +1,189 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+// maxGapStreams 参与缺口检测的消息流上限，超过后清空重新统计
+const maxGapStreams = 10000
+
+// Handler 推送消息处理函数。在读协程中按顺序调用，耗时的处理应自行异步，
+// 否则会阻塞后续消息和心跳响应的读取
+type Handler func(p *protocol.Proto)
+
+// ServerRequestHandler 处理服务端请求（OpServerRequest），返回的 body 以 OpServerResponse 回复。
+// 返回错误时不回复，服务端在超时后返回失败；ctx 在连接断开时取消
+type ServerRequestHandler func(ctx context.Context, body []byte) ([]byte, error)
+
+// Presence 房间在线状态变化
+type Presence struct {
+	Event     string `json:"event"` // join / leave
+	UserID    string `json:"user_id"`
+	Reason    string `json:"reason"`
+	Timestamp int64  `json:"timestamp"` // Unix 秒
+}
+
+// Gap 检测到的消息缺口：同一消息流（op + 房间 + 发送者）的 Seq 不连续，
+// Seq 在 [Expected, Got) 之间的消息没有收到（可能在重连期间丢失），
+// 房间消息可以通过 Push-Manager 的 ListRoomHistory 补齐
+type Gap struct {
+	Op       int32
+	RoomID   string
+	UserID   string
+	Expected int32
+	Got      int32
+}
+
+// Missed 缺失的消息数
+func (g Gap) Missed() int32 {
+	return g.Got - g.Expected
+}
+
+// Handle 注册 op 的处理函数（覆盖已有的）。在 Run 之前注册的 op 会在加入房间时订阅，
+// 连接之后新增的 op 需要调用 Watch
+func (c *Client) Handle(op int32, h Handler) {
+	c.handlersMu.Lock()
+	c.handlers[op] = h
+	c.handlersMu.Unlock()
+
+	c.mu.Lock()
+	c.watchOps[op] = true
+	c.mu.Unlock()
+}
+
+// OnMessage 注册房间消息（OpSendMsg）处理函数
+func (c *Client) OnMessage(h Handler) {
+	c.Handle(protocol.OpSendMsg, h)
+}
+
+// OnPresence 注册在线状态变化（OpPresence）处理函数
+func (c *Client) OnPresence(fn func(Presence)) {
+	c.Handle(protocol.OpPresence, func(p *protocol.Proto) {
+		var presence Presence
+		if err := json.Unmarshal(p.Body, &presence); err != nil {
+			return
+		}
+		if presence.UserID == "" {
+			presence.UserID = p.Userid
+		}
+		presence.UserID = pkg.UnscopeID(c.cfg.AppID, presence.UserID)
+		fn(presence)
+	})
+}
+
+// OnServerRequest 注册服务端请求处理函数，每个请求在独立的协程中处理
+func (c *Client) OnServerRequest(fn ServerRequestHandler) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+	c.serverRequest = fn
+}
+
+// OnGap 注册消息缺口回调
+func (c *Client) OnGap(fn func(Gap)) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+	c.onGap = fn
+}
+
+// dispatch 分发一个收到的帧：请求响应、心跳响应、服务端请求、推送消息
+func (c *Client) dispatch(conn *connection, p *protocol.Proto) {
+	if conn.resolve(p) {
+		return
+	}
+
+	c.handlersMu.RLock()
+	handler := c.handlers[p.Op]
+	serverRequest := c.serverRequest
+	onGap := c.onGap
+	c.handlersMu.RUnlock()
+
+	switch p.Op {
+	case opHeartbeatReply:
+		return
+	case protocol.OpServerRequest:
+		if serverRequest != nil {
+			go c.serveRequest(conn, p, serverRequest)
+		}
+		return
+	}
+
+	if gap, ok := c.gaps.observe(p); ok && onGap != nil {
+		onGap(gap)
+	}
+	if handler != nil {
+		handler(p)
+	}
+}
+
+// serveRequest 处理服务端请求并以相同的 Seq 回复
+func (c *Client) serveRequest(conn *connection, p *protocol.Proto, fn ServerRequestHandler) {
+	body, err := fn(conn.ctx, p.Body)
+	if err != nil {
+		return
+	}
+	conn.write(&protocol.Proto{
+		Ver:    protoVersion,
+		Op:     protocol.OpServerResponse,
+		Seq:    p.Seq,
+		Roomid: c.cfg.RoomID,
+		Userid: c.cfg.UserID,
+		Body:   body,
+	})
+}
+
+// streamKey 消息流：发送者为同一消息流分配递增的 Seq（客户端发言由 Push-Manager 按房间和用户分配，不区分设备）
+type streamKey struct {
+	op     int32
+	roomID string
+	userID string
+}
+
+// gapTracker 记录每个消息流最后收到的 Seq（跨重连保留，重连期间丢失的消息在下一条消息到达时报告）
+type gapTracker struct {
+	mu   sync.Mutex
+	last map[streamKey]int32
+}
+
+func newGapTracker() *gapTracker {
+	return &gapTracker{last: make(map[streamKey]int32)}
+}
+
+// observe 记录消息的 Seq，不连续时返回缺口。Seq 为 0 的消息不参与检测，
+// Seq 回退视为发送者重启，从新的 Seq 重新开始
+func (g *gapTracker) observe(p *protocol.Proto) (Gap, bool) {
+	if p.Seq <= 0 {
+		return Gap{}, false
+	}
+	key := streamKey{op: p.Op, roomID: p.Roomid, userID: p.Userid}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	last, ok := g.last[key]
+	if !ok && len(g.last) >= maxGapStreams {
+		g.last = make(map[streamKey]int32)
+	}
+	g.last[key] = p.Seq
+	if !ok || p.Seq <= last+1 {
+		return Gap{}, false
+	}
+	return Gap{Op: p.Op, RoomID: p.Roomid, UserID: p.Userid, Expected: last + 1, Got: p.Seq}, true
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+func TestGapTrackerObserve(t *testing.T) {
+	msg := func(op, seq int32, roomID, userID string) *protocol.Proto {
+		return &protocol.Proto{Op: op, Seq: seq, Roomid: roomID, Userid: userID}
+	}
+	tests := []struct {
+		name string
+		p    *protocol.Proto
+		want *Gap
+	}{
+		{"first message", msg(2, 5, "room-1", "alice"), nil},
+		{"consecutive", msg(2, 6, "room-1", "alice"), nil},
+		{"duplicate", msg(2, 6, "room-1", "alice"), nil},
+		{"gap", msg(2, 9, "room-1", "alice"), &Gap{Op: 2, RoomID: "room-1", UserID: "alice", Expected: 7, Got: 9}},
+		{"other sender", msg(2, 1, "room-1", "bob"), nil},
+		{"other op", msg(12, 100, "room-1", "alice"), nil},
+		{"other room", msg(2, 50, "room-2", "alice"), nil},
+		{"sender restart", msg(2, 1, "room-1", "alice"), nil},
+		{"after restart", msg(2, 3, "room-1", "alice"), &Gap{Op: 2, RoomID: "room-1", UserID: "alice", Expected: 2, Got: 3}},
+		{"zero seq", msg(2, 0, "room-1", "alice"), nil},
+		{"after zero seq", msg(2, 4, "room-1", "alice"), nil},
+	}
+
+	g := newGapTracker()
+	for _, tt := range tests {
+		gap, ok := g.observe(tt.p)
+		if tt.want == nil {
+			if ok {
+				t.Errorf("%s: observe() = %+v, want no gap", tt.name, gap)
+			}
+			continue
+		}
+		if !ok || gap != *tt.want {
+			t.Errorf("%s: observe() = %+v, %v, want %+v", tt.name, gap, ok, *tt.want)
+		} else if gap.Missed() != tt.want.Got-tt.want.Expected {
+			t.Errorf("%s: Missed() = %d", tt.name, gap.Missed())
+		}
+	}
+}
+
+func TestGapTrackerLimit(t *testing.T) {
+	g := newGapTracker()
+	for i := 0; i < maxGapStreams; i++ {
+		g.observe(&protocol.Proto{Op: 2, Seq: 1, Roomid: "room-1", Userid: strconv.Itoa(i)})
+	}
+	if len(g.last) != maxGapStreams {
+		t.Fatalf("len(last) = %d, want %d", len(g.last), maxGapStreams)
+	}
+	g.observe(&protocol.Proto{Op: 2, Seq: 1, Roomid: "room-2", Userid: "alice"})
+	if len(g.last) != 1 {
+		t.Errorf("len(last) = %d after exceeding limit, want 1", len(g.last))
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+// Publish 在房间内发言（OpPublish），服务端按房间策略校验后广播给房间成员。
+// 未连接时等待重连完成后发送；ctx 没有截止时间时使用 RequestTimeout。
+// 广播给房间成员的 Seq 由 Push-Manager 按 (房间, 用户) 分配，同一用户的多个设备共用一个连续的消息流。
+// Push-Manager 无法分配时沿用客户端的 Seq：发言串行发送，Seq 只在服务端接受后推进；
+// 超时或连接断开时服务端可能已广播，Seq 照常推进
+func (c *Client) Publish(ctx context.Context, body []byte) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+	conn, err := c.currentConn(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.publishMu.Lock()
+	defer c.publishMu.Unlock()
+	p := c.newProto(protocol.OpPublish, body)
+	p.Seq = c.publishSeq + 1
+	reply, err := conn.request(ctx, p, protocol.OpPublishReply)
+	if err == nil {
+		err = replyError(reply)
+	}
+	if !errors.Is(err, ErrRequestFailed) {
+		c.publishSeq = p.Seq
+	}
+	return err
+}
+
+// Subscribe 订阅主题模式（如 stock.AAPL.* / orders.user123.#），成功后重连时自动恢复
+func (c *Client) Subscribe(ctx context.Context, topics ...string) error {
+	if err := c.requestJSON(ctx, protocol.OpSubscribe, protocol.OpSubscribeReply, map[string][]string{"topics": topics}); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	for _, topic := range topics {
+		c.topics[topic] = true
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// Unsubscribe 取消订阅主题模式
+func (c *Client) Unsubscribe(ctx context.Context, topics ...string) error {
+	if err := c.requestJSON(ctx, protocol.OpUnsubscribe, protocol.OpUnsubscribeReply, map[string][]string{"topics": topics}); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	for _, topic := range topics {
+		delete(c.topics, topic)
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// Watch 订阅推送 op（需在服务端白名单内），成功后重连时自动恢复
+func (c *Client) Watch(ctx context.Context, ops ...int32) error {
+	if err := c.requestJSON(ctx, protocol.OpWatch, protocol.OpWatchReply, map[string][]int32{"ops": ops}); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	for _, op := range ops {
+		c.watchOps[op] = true
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// UnWatch 取消订阅推送 op
+func (c *Client) UnWatch(ctx context.Context, ops ...int32) error {
+	if err := c.requestJSON(ctx, protocol.OpUnWatch, protocol.OpUnWatchReply, map[string][]int32{"ops": ops}); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	for _, op := range ops {
+		delete(c.watchOps, op)
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Client) requestJSON(ctx context.Context, op, replyOp int32, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	_, err = c.request(ctx, op, replyOp, data)
+	return err
+}
+
+// request 在当前连接上发送请求并等待响应，失败响应转为 ErrRequestFailed。
+// 请求不会自动重发：连接在等待响应时断开返回 ErrDisconnected，服务端可能已处理
+func (c *Client) request(ctx context.Context, op, replyOp int32, body []byte) (*protocol.Proto, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+	conn, err := c.currentConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := conn.request(ctx, c.newProto(op, body), replyOp)
+	if err != nil {
+		return nil, err
+	}
+	return reply, replyError(reply)
+}
+
+// requestContext ctx 没有截止时间时使用 RequestTimeout
+func (c *Client) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.cfg.RequestTimeout)
+}
@@ -24,6 +24,8 @@ import (
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
 )
 
 // Config 应用配置
@@ -33,6 +35,11 @@ type Config struct {
 	Redis       *RedisConfig
 	ETCD        *ETCDConfig
 	Room        *RoomConfig
+	Node        *NodeConfig
+	Session     *SessionConfig
+	Push        *PushConfig
+	Webhook     *WebhookConfig
+	Auth        *AuthConfig
 	Bucket      *BucketConfig
 	TCPConfig   *TcpConfig
 	Protocol    *Protocol
@@ -95,6 +102,37 @@ type Protocol struct {
 	SvrProto         int
 	CliProto         int
 	HandshakeTimeout time.Duration
+	WatchOps         []OpRange // 客户端允许订阅（Watch）的 op 范围
+}
+
+// OpRange 闭区间 op 范围
+type OpRange struct {
+	Min int32
+	Max int32
+}
+
+// Contains op 是否在范围内
+func (r OpRange) Contains(op int32) bool {
+	return op >= r.Min && op <= r.Max
+}
+
+// parseOpRanges 解析 "2" / "1000-1999" 形式的 op 范围，非法项忽略
+func parseOpRanges(values []string) []OpRange {
+	ranges := make([]OpRange, 0, len(values))
+	for _, v := range values {
+		lo, hi, found := strings.Cut(v, "-")
+		if !found {
+			hi = lo
+		}
+		min, err1 := strconv.ParseInt(strings.TrimSpace(lo), 10, 32)
+		max, err2 := strconv.ParseInt(strings.TrimSpace(hi), 10, 32)
+		if err1 != nil || err2 != nil || min > max {
+			log.Printf("⚠️  忽略非法的 op 范围: %q\n", v)
+			continue
+		}
+		ranges = append(ranges, OpRange{Min: int32(min), Max: int32(max)})
+	}
+	return ranges
 }
 
 type TcpConfig struct {
@@ -141,10 +179,117 @@ type ETCDConfig struct {
 
 // RoomConfig 房间配置
 type RoomConfig struct {
-	DefaultMaxUsers int           // 默认房间最大用户数
+	DefaultMaxUsers int           // 默认房间最大用户数（房间未设置 max_users 时使用）
 	CacheTTL        time.Duration // 房间缓存 TTL
 }
 
+// NodeConfig Connect-Node 心跳与健康检查配置
+type NodeConfig struct {
+	Region            string        // 节点所在区域
+	MaxConnections    int           // 节点最大连接数
+	HeartbeatInterval time.Duration // Connect-Node 上报心跳间隔
+	HeartbeatTimeout  time.Duration // 超过该时间未收到心跳，Controller 将节点标记为 unhealthy
+}
+
+// SessionConfig 多设备会话策略（由 Controller 在用户上线时执行）
+type SessionConfig struct {
+	MaxDevices          int  // 每个用户最多同时在线的会话数，0 表示不限制，超出时挤掉最早的会话
+	SinglePerDeviceType bool // 每种设备类型只允许一个会话，同类型设备登录时挤掉旧会话
+}
+
+// PushConfig Push-Manager 出站队列配置（Redis Stream，每个 Connect-Node 一个）
+type PushConfig struct {
+	QueueMaxLen    int           // 每个节点出站队列的近似最大长度
+	MaxAttempts    int           // 推送失败最多尝试次数，耗尽后进入死信队列
+	RetryBaseDelay time.Duration // 重试退避初始间隔（每次翻倍）
+	RetryMaxDelay  time.Duration // 重试退避最大间隔
+	ClaimIdle      time.Duration // 未确认消息空闲超过该时间后由其他 Push-Manager 认领重投
+	AckTimeout     time.Duration // 推送流批次确认超时，超时视为节点无响应
+
+	// 节点从 etcd 下线后其出站 stream 的保留时间：期间节点恢复则继续投递，超时后由 Redis 删除（0 表示不清理）
+	OrphanStreamTTL time.Duration
+
+	// 每个 Connect-Node 的熔断器
+	BreakerFailureThreshold int           // 连续失败次数达到该值时熔断（open）
+	BreakerOpenTimeout      time.Duration // 熔断持续时间，之后进入 half-open 放行一次探测
+
+	// 推送请求幂等（按 message_id 去重）
+	DedupeWindow  time.Duration // 去重窗口
+	DedupeLRUSize int           // 未连接 Redis 时本地 LRU 的容量（仅单实例有效）
+}
+
+// WebhookConfig 连接和房间生命周期事件的 webhook 配置（由 Controller 投递）
+type WebhookConfig struct {
+	Endpoints      []WebhookEndpoint
+	Timeout        time.Duration // 单次投递的 HTTP 超时
+	MaxAttempts    int           // 最多投递次数，耗尽后记入失败列表（可重放）
+	RetryBaseDelay time.Duration // 重试退避初始间隔（每次翻倍）
+	RetryMaxDelay  time.Duration // 重试退避最大间隔
+	QueueSize      int           // 每个 endpoint 内存待投递队列容量（Redis 不可用时使用），满时丢弃新事件
+}
+
+// AuthConfig 客户端连接鉴权（Connect-Node 校验，Web 签发令牌）
+type AuthConfig struct {
+	ClientTokenSecret string // 客户端连接令牌签名密钥（与 Web 的 CLIENT_TOKEN_SECRET 一致），为空时只接受 API key
+	AllowAnonymous    bool   // 不带凭证的连接进入默认租户（本地演示用）
+}
+
+// WebhookEndpoint webhook 接收地址
+type WebhookEndpoint struct {
+	Name   string   // 唯一名称（失败记录和重放按名称关联）
+	URL    string   // 接收事件的 URL（POST JSON）
+	Secret string   // 签名密钥
+	Events []string // 订阅的事件类型，支持 "room.*" 前缀匹配，为空表示全部
+}
+
+// Accepts endpoint 是否订阅了该事件类型
+func (e WebhookEndpoint) Accepts(eventType string) bool {
+	return pkg.MatchEventType(e.Events, eventType)
+}
+
+// parseWebhookEndpoints 解析 webhook.endpoints；设置了 WEBHOOK_URL 时只使用环境变量定义的一个 endpoint
+func parseWebhookEndpoints(yamlCfg RawYAMLConfig) []WebhookEndpoint {
+	if url := os.Getenv("WEBHOOK_URL"); url != "" {
+		return []WebhookEndpoint{{
+			Name:   "default",
+			URL:    url,
+			Secret: os.Getenv("WEBHOOK_SECRET"),
+			Events: getEnvOrYAMLStrSlice(nil, "WEBHOOK_EVENTS", "", nil),
+		}}
+	}
+
+	items, _ := getYAMLValue(yamlCfg, "webhook.endpoints").([]interface{})
+	endpoints := make([]WebhookEndpoint, 0, len(items))
+	seen := make(map[string]bool)
+	for i, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ep := WebhookEndpoint{}
+		ep.Name, _ = m["name"].(string)
+		ep.URL, _ = m["url"].(string)
+		ep.Secret, _ = m["secret"].(string)
+		if events, ok := m["events"].([]interface{}); ok {
+			for _, e := range events {
+				if str, ok := e.(string); ok {
+					ep.Events = append(ep.Events, str)
+				}
+			}
+		}
+		if ep.Name == "" {
+			ep.Name = fmt.Sprintf("endpoint-%d", i)
+		}
+		if ep.URL == "" || seen[ep.Name] {
+			log.Printf("⚠️  忽略非法的 webhook endpoint: name=%q, url=%q\n", ep.Name, ep.URL)
+			continue
+		}
+		seen[ep.Name] = true
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints
+}
+
 // RawYAMLConfig 原始 YAML 配置
 type RawYAMLConfig map[string]interface{}
 
@@ -186,9 +331,47 @@ func LoadConfigFromFile(filename string) *Config {
 			Endpoints: getEnvOrYAMLStrSlice(yamlCfg, "ETCD_ENDPOINTS", "etcd.endpoints", []string{"localhost:2379"}),
 		},
 		Room: &RoomConfig{
-			DefaultMaxUsers: getEnvOrYAMLInt(yamlCfg, "ROOM_MAX_USERS", "room.bucket_size", 100),
+			DefaultMaxUsers: getEnvOrYAMLInt(yamlCfg, "ROOM_MAX_USERS", "room.default_max_users", 100),
 			CacheTTL:        time.Duration(getEnvOrYAMLInt(yamlCfg, "ROOM_CACHE_TTL_MINUTES", "", 10)) * time.Minute,
 		},
+		Node: &NodeConfig{
+			Region:            getEnvOrYAMLStr(yamlCfg, "NODE_REGION", "node.region", ""),
+			MaxConnections:    getEnvOrYAMLInt(yamlCfg, "NODE_MAX_CONNECTIONS", "node.max_connections", 10000),
+			HeartbeatInterval: getEnvOrYAMLDuration(yamlCfg, "NODE_HEARTBEAT_INTERVAL", "node.heartbeat_interval", 10*time.Second),
+			HeartbeatTimeout:  getEnvOrYAMLDuration(yamlCfg, "NODE_HEARTBEAT_TIMEOUT", "node.heartbeat_timeout", 30*time.Second),
+		},
+		Session: &SessionConfig{
+			MaxDevices:          getEnvOrYAMLInt(yamlCfg, "SESSION_MAX_DEVICES", "session.max_devices", 0),
+			SinglePerDeviceType: getEnvOrYAMLBool(yamlCfg, "SESSION_SINGLE_PER_DEVICE_TYPE", "session.single_per_device_type", false),
+		},
+		Push: &PushConfig{
+			QueueMaxLen:    getEnvOrYAMLInt(yamlCfg, "PUSH_QUEUE_MAX_LEN", "push.queue_max_len", 100000),
+			MaxAttempts:    getEnvOrYAMLInt(yamlCfg, "PUSH_MAX_ATTEMPTS", "push.max_attempts", 5),
+			RetryBaseDelay: getEnvOrYAMLDuration(yamlCfg, "PUSH_RETRY_BASE_DELAY", "push.retry_base_delay", 200*time.Millisecond),
+			RetryMaxDelay:  getEnvOrYAMLDuration(yamlCfg, "PUSH_RETRY_MAX_DELAY", "push.retry_max_delay", 10*time.Second),
+			ClaimIdle:      getEnvOrYAMLDuration(yamlCfg, "PUSH_CLAIM_IDLE", "push.claim_idle", 2*time.Minute),
+			AckTimeout:     getEnvOrYAMLDuration(yamlCfg, "PUSH_ACK_TIMEOUT", "push.ack_timeout", 10*time.Second),
+
+			OrphanStreamTTL: getEnvOrYAMLDuration(yamlCfg, "PUSH_ORPHAN_STREAM_TTL", "push.orphan_stream_ttl", 24*time.Hour),
+
+			BreakerFailureThreshold: getEnvOrYAMLInt(yamlCfg, "PUSH_BREAKER_FAILURE_THRESHOLD", "push.breaker_failure_threshold", 5),
+			BreakerOpenTimeout:      getEnvOrYAMLDuration(yamlCfg, "PUSH_BREAKER_OPEN_TIMEOUT", "push.breaker_open_timeout", 30*time.Second),
+
+			DedupeWindow:  getEnvOrYAMLDuration(yamlCfg, "PUSH_DEDUPE_WINDOW", "push.dedupe_window", 5*time.Minute),
+			DedupeLRUSize: getEnvOrYAMLInt(yamlCfg, "PUSH_DEDUPE_LRU_SIZE", "push.dedupe_lru_size", 10000),
+		},
+		Webhook: &WebhookConfig{
+			Endpoints:      parseWebhookEndpoints(yamlCfg),
+			Timeout:        getEnvOrYAMLDuration(yamlCfg, "WEBHOOK_TIMEOUT", "webhook.timeout", 5*time.Second),
+			MaxAttempts:    getEnvOrYAMLInt(yamlCfg, "WEBHOOK_MAX_ATTEMPTS", "webhook.max_attempts", 5),
+			RetryBaseDelay: getEnvOrYAMLDuration(yamlCfg, "WEBHOOK_RETRY_BASE_DELAY", "webhook.retry_base_delay", time.Second),
+			RetryMaxDelay:  getEnvOrYAMLDuration(yamlCfg, "WEBHOOK_RETRY_MAX_DELAY", "webhook.retry_max_delay", time.Minute),
+			QueueSize:      getEnvOrYAMLInt(yamlCfg, "WEBHOOK_QUEUE_SIZE", "webhook.queue_size", 10000),
+		},
+		Auth: &AuthConfig{
+			ClientTokenSecret: getEnvOrYAMLStr(yamlCfg, "CLIENT_TOKEN_SECRET", "auth.client_token_secret", ""),
+			AllowAnonymous:    getEnvOrYAMLBool(yamlCfg, "AUTH_ALLOW_ANONYMOUS", "auth.allow_anonymous", false),
+		},
 		RpcConfig: &RpcConfig{
 			TimeOut: getEnvOrYAMLDuration(yamlCfg, "RPC_TIMEOUT_SECONDS", "rpc.timeout", 10*time.Second),
 		},
@@ -217,6 +400,7 @@ func LoadConfigFromFile(filename string) *Config {
 			SvrProto:         getEnvOrYAMLInt(yamlCfg, "PROTOCOL_SVR_PROTO", "", 10),
 			CliProto:         getEnvOrYAMLInt(yamlCfg, "PROTOCOL_CLI_PROTO", "", 5),
 			HandshakeTimeout: getEnvOrYAMLDuration(yamlCfg, "PROTOCOL_HANDSHAKE_TIMEOUT_SECONDS", "", 5*time.Second),
+			WatchOps:         parseOpRanges(getEnvOrYAMLStrSlice(yamlCfg, "PROTOCOL_WATCH_OPS", "protocol.watch_ops", []string{"2", "12", "1000-1999"})),
 		},
 		GettyConfig: &GettyConfig{
 			AppName:         getEnvOrYAMLStr(yamlCfg, "GETTY_APP_NAME", "", "pubsub-server"),
@@ -0,0 +1,42 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "testing"
+
+func TestParseOpRanges(t *testing.T) {
+	got := parseOpRanges([]string{"2", "1000-1999", " 5 - 7 ", "abc", "9-3", "1-", "-5", "3000000000"})
+	want := []OpRange{{2, 2}, {1000, 1999}, {5, 7}}
+	if len(got) != len(want) {
+		t.Fatalf("parseOpRanges() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parseOpRanges()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if got := parseOpRanges(nil); len(got) != 0 {
+		t.Errorf("parseOpRanges(nil) = %v, want empty", got)
+	}
+}
+
+func TestOpRangeContains(t *testing.T) {
+	r := OpRange{Min: 1000, Max: 1999}
+	for op, want := range map[int32]bool{999: false, 1000: true, 1500: true, 1999: true, 2000: false} {
+		if got := r.Contains(op); got != want {
+			t.Errorf("%v.Contains(%d) = %v, want %v", r, op, got, want)
+		}
+	}
+}
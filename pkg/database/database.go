@@ -87,7 +87,10 @@ func AutoMigrate(db *gorm.DB) error {
 	err := db.AutoMigrate(
 		&Room{},
 		&RoomUser{},
+		&RoomInvite{},
 		&ConnectNode{},
+		&Tenant{},
+		&APIKey{},
 	)
 	if err != nil {
 		return fmt.Errorf("auto migrate failed: %w", err)
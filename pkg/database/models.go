@@ -22,36 +22,53 @@ import (
 
 // Room 房间模型
 type Room struct {
-	ID          string         `gorm:"column:id;primaryKey;size:64" json:"id"`              // 主键，对应数据库的 id 字段
-	Name        string         `gorm:"column:name;size:128;not null" json:"name"`
-	Description string         `gorm:"column:description;type:text" json:"description"`
-	MaxUsers    int            `gorm:"column:max_users;default:100" json:"max_users"`
-	CreatedAt   time.Time      `gorm:"column:created_at" json:"created_at"`
-	UpdatedAt   time.Time      `gorm:"column:updated_at" json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-"`
+	ID          string `gorm:"column:id;primaryKey;size:128" json:"id"` // 主键，多租户下带 "<app_id>:" 前缀
+	Name        string `gorm:"column:name;size:128;not null" json:"name"`
+	Description string `gorm:"column:description;type:text" json:"description"`
+	MaxUsers    int    `gorm:"column:max_users;default:100" json:"max_users"`
+	// 房间策略
+	JoinMode        string         `gorm:"column:join_mode;size:16;default:'open'" json:"join_mode"`             // open, invite_only, password
+	PasswordHash    string         `gorm:"column:password_hash;size:64" json:"-"`                                // password 模式下的密码哈希
+	PublishPolicy   string         `gorm:"column:publish_policy;size:16;default:'anyone'" json:"publish_policy"` // anyone, owner, none
+	MaxMessageBytes int            `gorm:"column:max_message_bytes;default:0" json:"max_message_bytes"`          // 0 表示不限制
+	OwnerID         string         `gorm:"column:owner_id;size:128" json:"owner_id"`
+	CreatedAt       time.Time      `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt       time.Time      `gorm:"column:updated_at" json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-"`
 }
 
 // RoomUser 用户-房间关系表（多对多）
 type RoomUser struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
-	UserID    string         `gorm:"size:64;not null;index:idx_user_room" json:"user_id"`
+	UserID    string         `gorm:"size:128;not null;index:idx_user_room" json:"user_id"`
 	UserName  string         `gorm:"size:128;not null" json:"user_name"`
-	RoomID    string         `gorm:"size:64;not null;index:idx_user_room" json:"room_id"`
+	RoomID    string         `gorm:"size:128;not null;index:idx_user_room" json:"room_id"`
 	NodeID    string         `gorm:"size:64;not null" json:"node_id"`
+	Role      string         `gorm:"size:16;not null;default:'member'" json:"role"` // owner, moderator, member, viewer
 	JoinedAt  time.Time      `gorm:"not null" json:"joined_at"`
-	LeftAt    *time.Time     `json:"left_at,omitempty"`      // NULL 表示在线，非 NULL 表示已离线
+	LeftAt    *time.Time     `json:"left_at,omitempty"` // NULL 表示在线，非 NULL 表示已离线
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// RoomInvite 房间邀请（invite_only 模式下的白名单）
+type RoomInvite struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	RoomID    string    `gorm:"size:128;not null;uniqueIndex:idx_room_invite" json:"room_id"`
+	UserID    string    `gorm:"size:128;not null;uniqueIndex:idx_room_invite" json:"user_id"`
+	InvitedBy string    `gorm:"size:128" json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // ConnectNode 连接节点模型
 type ConnectNode struct {
 	ID                 string    `gorm:"column:id;primaryKey;size:64" json:"id"`
 	Address            string    `gorm:"column:address;size:256;not null" json:"address"`
-	Region             string    `gorm:"column:region;size:64" json:"region"`                      // 数据库有此字段
+	Region             string    `gorm:"column:region;size:64" json:"region"`                         // 数据库有此字段
 	MaxConnections     int       `gorm:"column:max_connections;default:10000" json:"max_connections"` // 修正默认值
 	CurrentConnections int       `gorm:"column:current_connections;default:0" json:"current_connections"`
-	CPUUsage           float32   `gorm:"column:cpu_usage;default:0" json:"cpu_usage"`
-	MemoryUsage        float32   `gorm:"column:memory_usage;default:0" json:"memory_usage"`
+	RoomCount          int       `gorm:"column:room_count;default:0" json:"room_count"`
+	CPUUsage           float32   `gorm:"column:cpu_usage;default:0" json:"cpu_usage"`       // 百分比
+	MemoryUsage        float32   `gorm:"column:memory_usage;default:0" json:"memory_usage"` // MB
 	Status             string    `gorm:"column:status;size:32;default:'online'" json:"status"` // online, offline, unhealthy
 	LastHeartbeat      time.Time `gorm:"column:last_heartbeat" json:"last_heartbeat"`
 	CreatedAt          time.Time `gorm:"column:created_at" json:"created_at"`
@@ -59,6 +76,33 @@ type ConnectNode struct {
 	// 注意：数据库表没有 deleted_at 字段，不使用软删除
 }
 
+// Tenant 租户（房间和用户 ID 以 "<app_id>:" 为前缀隔离）
+type Tenant struct {
+	AppID           string    `gorm:"column:app_id;primaryKey;size:32" json:"app_id"`
+	Name            string    `gorm:"column:name;size:128;not null" json:"name"`
+	Status          string    `gorm:"column:status;size:16;not null;default:'active'" json:"status"` // active, disabled
+	MaxMessageBytes int       `gorm:"column:max_message_bytes;default:0" json:"max_message_bytes"`   // 0 表示不限制
+	PublishRate     int       `gorm:"column:publish_rate;default:0" json:"publish_rate"`             // 每秒推送请求数，0 表示不限制
+	CreatedAt       time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"column:updated_at" json:"updated_at"`
+
+	// 全集群用量限制，0 表示不限制；软限制只告警，硬限制拒绝
+	SoftMaxConnections int   `gorm:"column:soft_max_connections;default:0" json:"soft_max_connections"`
+	MaxConnections     int   `gorm:"column:max_connections;default:0" json:"max_connections"`
+	SoftDailyMessages  int64 `gorm:"column:soft_daily_messages;default:0" json:"soft_daily_messages"`
+	MaxDailyMessages   int64 `gorm:"column:max_daily_messages;default:0" json:"max_daily_messages"`
+}
+
+// APIKey 租户 API key（只保存哈希）
+type APIKey struct {
+	KeyID     string     `gorm:"column:key_id;primaryKey;size:16" json:"key_id"`
+	AppID     string     `gorm:"column:app_id;size:32;not null;index" json:"app_id"`
+	Name      string     `gorm:"column:name;size:128" json:"name"`
+	KeyHash   string     `gorm:"column:key_hash;size:64;not null;uniqueIndex" json:"-"`
+	CreatedAt time.Time  `gorm:"column:created_at" json:"created_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at" json:"revoked_at,omitempty"` // NULL 表示有效
+}
+
 // TableName 指定表名
 func (Room) TableName() string {
 	return "rooms"
@@ -68,10 +112,22 @@ func (RoomUser) TableName() string {
 	return "room_users"
 }
 
+func (RoomInvite) TableName() string {
+	return "room_invites"
+}
+
 func (ConnectNode) TableName() string {
 	return "connect_nodes"
 }
 
+func (Tenant) TableName() string {
+	return "tenants"
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
 // BeforeCreate GORM 钩子
 func (r *Room) BeforeCreate(tx *gorm.DB) error {
 	if r.CreatedAt.IsZero() {
@@ -16,11 +16,42 @@ package database
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"gorm.io/gorm/clause"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg/types"
+)
+
+var (
+	// ErrRoomFull 房间已满
+	ErrRoomFull = errors.New("room is full")
+	// ErrNotInvited invite_only 房间且用户未被邀请
+	ErrNotInvited = errors.New("room is invite only")
+	// ErrWrongPassword 房间密码错误
+	ErrWrongPassword = errors.New("wrong room password")
+	// ErrRoomNotFound 房间不存在
+	ErrRoomNotFound = errors.New("room not found")
+	// ErrMemberNotFound 用户不是房间成员
+	ErrMemberNotFound = errors.New("room member not found")
+	// ErrTenantExists 租户已存在
+	ErrTenantExists = errors.New("tenant already exists")
+	// ErrTenantNotFound 租户不存在
+	ErrTenantNotFound = errors.New("tenant not found")
+	// ErrAPIKeyNotFound API key 不存在
+	ErrAPIKeyNotFound = errors.New("api key not found")
 )
 
+// AllTenants 按租户过滤的查询中表示不过滤（"*" 不是合法的 app_id）
+const AllTenants = "*"
+
 // Repository 数据仓库
 type Repository struct {
 	db *gorm.DB
@@ -38,12 +69,10 @@ func (r *Repository) CreateRoom(ctx context.Context, room *Room) error {
 	return r.db.WithContext(ctx).Create(room).Error
 }
 
-// GetRoom 获取房间（包含用户列表）
+// GetRoom 获取房间
 func (r *Repository) GetRoom(ctx context.Context, roomID string) (*Room, error) {
 	var room Room
-	err := r.db.WithContext(ctx).
-		Preload("RoomUsers", "left_at IS NULL"). // 只加载在线用户
-		First(&room, "id = ?", roomID).Error
+	err := r.db.WithContext(ctx).First(&room, "id = ?", roomID).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -57,19 +86,15 @@ func (r *Repository) GetRoomWithStats(ctx context.Context, roomID string) (*Room
 		return room, 0, err
 	}
 
-	var count int64
-	err = r.db.WithContext(ctx).
-		Model(&RoomUser{}).
-		Where("room_id = ? AND left_at IS NULL", roomID).
-		Count(&count).Error
-
+	count, err := r.GetRoomUserCount(ctx, roomID)
 	return room, count, err
 }
 
-// ListRooms 列出所有房间
-func (r *Repository) ListRooms(ctx context.Context, limit, offset int) ([]*Room, error) {
+// ListRooms 列出租户的房间（appID 为 AllTenants 时列出全部房间）
+func (r *Repository) ListRooms(ctx context.Context, appID string, limit, offset int) ([]*Room, error) {
 	var rooms []*Room
 	err := r.db.WithContext(ctx).
+		Scopes(tenantScope("id", appID)).
 		Limit(limit).
 		Offset(offset).
 		Order("created_at DESC").
@@ -84,17 +109,34 @@ func (r *Repository) DeleteRoom(ctx context.Context, roomID string) error {
 
 // ========== RoomUser 操作 ==========
 
-// UserJoinRoom 用户加入房间（事务）
-func (r *Repository) UserJoinRoom(ctx context.Context, userID, userName, roomID, nodeID string, maxUsers int32) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 1. 检查房间是否存在
-		var room Room
-		if err := tx.First(&room, "id = ?", roomID).Error; err != nil {
+// JoinRoomParams 加入房间参数
+type JoinRoomParams struct {
+	UserID          string
+	UserName        string
+	RoomID          string
+	NodeID          string
+	Password        string
+	DefaultMaxUsers int32 // 房间未设置 max_users 时使用
+}
+
+// UserJoinRoom 用户加入房间（事务），按房间策略校验容量和加入方式，返回房间信息和成员记录
+func (r *Repository) UserJoinRoom(ctx context.Context, p *JoinRoomParams) (*Room, *RoomUser, error) {
+	var (
+		room   Room
+		member RoomUser
+	)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. 检查房间是否存在（加行锁，避免并发加入超出容量）
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&room, "id = ?", p.RoomID).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
-				// 房间不存在，创建
+				// 房间不存在，创建；第一个加入的用户成为房主
 				room = Room{
-					ID:   roomID,
-					Name: roomID,
+					ID:            p.RoomID,
+					Name:          p.RoomID,
+					MaxUsers:      int(p.DefaultMaxUsers),
+					JoinMode:      types.JoinModeOpen,
+					PublishPolicy: types.PublishAnyone,
+					OwnerID:       p.UserID,
 				}
 				if err := tx.Create(&room).Error; err != nil {
 					return err
@@ -104,54 +146,248 @@ func (r *Repository) UserJoinRoom(ctx context.Context, userID, userName, roomID,
 			}
 		}
 
-		// 2. 检查房间是否已满（使用配置的最大用户数）
+		// 2. 成员记录按用户 + 房间 + 节点区分（同一用户可以从多个节点的设备加入）：
+		// 本节点已有记录时只更新信息；用户已通过其他节点在房间中时新增本节点记录，不再校验策略和容量
+		var active []RoomUser
+		if err := tx.Where("user_id = ? AND room_id = ? AND left_at IS NULL", p.UserID, p.RoomID).
+			Order("id ASC").
+			Find(&active).Error; err != nil {
+			return err
+		}
+		for _, m := range active {
+			if m.NodeID == p.NodeID {
+				member = m
+				return tx.Model(&member).Update("user_name", p.UserName).Error
+			}
+		}
+		if len(active) > 0 {
+			member = RoomUser{
+				UserID:   p.UserID,
+				UserName: p.UserName,
+				RoomID:   p.RoomID,
+				NodeID:   p.NodeID,
+				Role:     active[0].Role,
+				JoinedAt: time.Now(),
+			}
+			return tx.Create(&member).Error
+		}
+
+		// 3. 检查房间是否已满（房间未配置时使用默认最大用户数）
+		maxUsers := int64(room.MaxUsers)
+		if maxUsers <= 0 {
+			maxUsers = int64(p.DefaultMaxUsers)
+		}
+
 		var currentCount int64
 		if err := tx.Model(&RoomUser{}).
-			Where("room_id = ? AND left_at IS NULL", roomID).
+			Where("room_id = ? AND left_at IS NULL", p.RoomID).
+			Distinct("user_id").
 			Count(&currentCount).Error; err != nil {
 			return err
 		}
 
-		if maxUsers > 0 && currentCount >= int64(maxUsers) {
-			return gorm.ErrInvalidData // 房间已满
+		if maxUsers > 0 && currentCount >= maxUsers {
+			return ErrRoomFull
 		}
 
-		// 3. 检查用户是否已在房间中
-		var existingUser RoomUser
-		err := tx.Where("user_id = ? AND room_id = ? AND left_at IS NULL", userID, roomID).
-			First(&existingUser).Error
+		// 4. 检查加入方式（房主不受限制）
+		if p.UserID != room.OwnerID {
+			switch room.JoinMode {
+			case types.JoinModeInviteOnly:
+				var invited int64
+				if err := tx.Model(&RoomInvite{}).
+					Where("room_id = ? AND user_id = ?", p.RoomID, p.UserID).
+					Count(&invited).Error; err != nil {
+					return err
+				}
+				if invited == 0 {
+					return ErrNotInvited
+				}
+			case types.JoinModePassword:
+				if room.PasswordHash != HashRoomPassword(p.RoomID, p.Password) {
+					return ErrWrongPassword
+				}
+			}
+		}
 
-		if err == nil {
-			// 用户已在房间中，更新信息
-			return tx.Model(&existingUser).Updates(map[string]interface{}{
-				"user_name": userName,
-				"node_id":   nodeID,
-			}).Error
+		// 5. 创建新的用户-房间关系（角色沿用上一次加入时的角色，房主始终为 owner）
+		role := types.RoleMember
+		var previous RoomUser
+		if err := tx.Where("user_id = ? AND room_id = ?", p.UserID, p.RoomID).
+			Order("id DESC").
+			First(&previous).Error; err == nil && types.ValidRole(previous.Role) {
+			role = previous.Role
+		}
+		if p.UserID == room.OwnerID {
+			role = types.RoleOwner
+		} else if role == types.RoleOwner {
+			role = types.RoleModerator
 		}
 
-		// 4. 创建新的用户-房间关系
-		roomUser := RoomUser{
-			UserID:   userID,
-			UserName: userName,
-			RoomID:   roomID,
-			NodeID:   nodeID,
+		member = RoomUser{
+			UserID:   p.UserID,
+			UserName: p.UserName,
+			RoomID:   p.RoomID,
+			NodeID:   p.NodeID,
+			Role:     role,
 			JoinedAt: time.Now(),
 		}
 
-		return tx.Create(&roomUser).Error
+		return tx.Create(&member).Error
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &room, &member, nil
 }
 
-// UserLeaveRoom 用户离开房间
-func (r *Repository) UserLeaveRoom(ctx context.Context, userID, roomID string) error {
-	now := time.Now()
+// BackfillRoomOwners 为没有房主的旧房间补齐房主（最早加入的用户，当前在线的成员记录角色改为 owner），
+// 返回补齐的房间数；没有任何成员记录的房间保持无房主，只能由管理员管理
+func (r *Repository) BackfillRoomOwners(ctx context.Context) (int, error) {
+	var rooms []Room
+	if err := r.db.WithContext(ctx).Where("owner_id IS NULL OR owner_id = ''").Find(&rooms).Error; err != nil {
+		return 0, err
+	}
+
+	filled := 0
+	for _, room := range rooms {
+		var first RoomUser
+		err := r.db.WithContext(ctx).Where("room_id = ?", room.ID).Order("joined_at ASC, id ASC").First(&first).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return filled, err
+		}
+
+		err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// 条件更新，避免覆盖其他实例刚补齐的房主
+			result := tx.Model(&Room{}).
+				Where("id = ? AND (owner_id IS NULL OR owner_id = '')", room.ID).
+				Update("owner_id", first.UserID)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			filled++
+			return tx.Model(&RoomUser{}).
+				Where("room_id = ? AND user_id = ? AND left_at IS NULL", room.ID, first.UserID).
+				Update("role", types.RoleOwner).Error
+		})
+		if err != nil {
+			return filled, err
+		}
+	}
+	return filled, nil
+}
+
+// GetRoomMember 获取房间当前在线成员
+func (r *Repository) GetRoomMember(ctx context.Context, roomID, userID string) (*RoomUser, error) {
+	var member RoomUser
+	err := r.db.WithContext(ctx).
+		Where("room_id = ? AND user_id = ? AND left_at IS NULL", roomID, userID).
+		First(&member).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &member, err
+}
+
+// SetMemberRole 设置成员角色（更新最近一次加入记录和全部在线记录，离开后重新加入沿用该角色）
+func (r *Repository) SetMemberRole(ctx context.Context, roomID, userID, role string) error {
+	var member RoomUser
+	err := r.db.WithContext(ctx).
+		Where("room_id = ? AND user_id = ?", roomID, userID).
+		Order("id DESC").
+		First(&member).Error
+	if err == gorm.ErrRecordNotFound {
+		return ErrMemberNotFound
+	}
+	if err != nil {
+		return err
+	}
+	// 同一用户在多个节点上的在线记录角色保持一致
 	return r.db.WithContext(ctx).
 		Model(&RoomUser{}).
-		Where("user_id = ? AND room_id = ? AND left_at IS NULL", userID, roomID).
-		Updates(map[string]interface{}{
-			"left_at":   now,
+		Where("id = ? OR (room_id = ? AND user_id = ? AND left_at IS NULL)", member.ID, roomID, userID).
+		Update("role", role).Error
+}
+
+// UpdateRoomPolicy 更新房间策略，updates 的 key 为 rooms 表列名
+func (r *Repository) UpdateRoomPolicy(ctx context.Context, roomID string, updates map[string]interface{}) (*Room, error) {
+	var room Room
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&room, "id = ?", roomID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrRoomNotFound
+			}
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := tx.Model(&room).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.First(&room, "id = ?", roomID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// InviteUsers 将用户加入房间邀请名单（已存在的邀请忽略），返回新增数量
+func (r *Repository) InviteUsers(ctx context.Context, roomID, invitedBy string, userIDs []string) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	invites := make([]*RoomInvite, 0, len(userIDs))
+	for _, userID := range userIDs {
+		invites = append(invites, &RoomInvite{
+			RoomID:    roomID,
+			UserID:    userID,
+			InvitedBy: invitedBy,
+			CreatedAt: time.Now(),
+		})
+	}
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&invites)
+	return result.RowsAffected, result.Error
+}
+
+// HashRoomPassword 计算房间密码哈希（以房间 ID 作为盐）
+func HashRoomPassword(roomID, password string) string {
+	sum := sha256.Sum256([]byte(roomID + ":" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+// UserLeaveRoom 用户离开房间：nodeID 不为空时只标记该节点的成员记录，为空时标记全部节点。
+// left 表示用户在房间中已没有在线记录（其他节点上的会话仍在时为 false）
+func (r *Repository) UserLeaveRoom(ctx context.Context, userID, roomID, nodeID string) (left bool, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&RoomUser{}).
+			Where("user_id = ? AND room_id = ? AND left_at IS NULL", userID, roomID)
+		if nodeID != "" {
+			query = query.Where("node_id = ?", nodeID)
+		}
+		if err := query.Updates(map[string]interface{}{
+			"left_at":   time.Now(),
 			"is_online": false,
-		}).Error
+		}).Error; err != nil {
+			return err
+		}
+
+		var remaining int64
+		if err := tx.Model(&RoomUser{}).
+			Where("user_id = ? AND room_id = ? AND left_at IS NULL", userID, roomID).
+			Count(&remaining).Error; err != nil {
+			return err
+		}
+		left = remaining == 0
+		return nil
+	})
+	return left, err
 }
 
 // UpdateUserOnlineStatus 更新用户在线状态
@@ -162,27 +398,35 @@ func (r *Repository) UpdateUserOnlineStatus(ctx context.Context, userID, roomID
 		Update("is_online", isOnline).Error
 }
 
-// GetRoomUsers 获取房间中的用户列表
+// GetRoomUsers 获取房间中的用户列表（每个用户一条，取最早加入的节点记录）
 func (r *Repository) GetRoomUsers(ctx context.Context, roomID string) ([]*RoomUser, error) {
-	var users []*RoomUser
+	var rows []*RoomUser
 	err := r.db.WithContext(ctx).
 		Where("room_id = ? AND left_at IS NULL", roomID).
-		Order("joined_at ASC").
-		Find(&users).Error
-	return users, err
+		Order("joined_at ASC, id ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(rows))
+	users := rows[:0]
+	for _, u := range rows {
+		if !seen[u.UserID] {
+			seen[u.UserID] = true
+			users = append(users, u)
+		}
+	}
+	return users, nil
 }
 
-// GetUserByID 根据用户ID获取用户信息（查找当前在线的用户）
-func (r *Repository) GetUserByID(ctx context.Context, userID string) (*RoomUser, error) {
-	var user RoomUser
+// GetUserMemberships 获取用户当前在线的全部成员记录（每个房间、每个节点一条），最近加入的在前
+func (r *Repository) GetUserMemberships(ctx context.Context, userID string) ([]*RoomUser, error) {
+	var rows []*RoomUser
 	err := r.db.WithContext(ctx).
 		Where("user_id = ? AND left_at IS NULL", userID).
-		Order("joined_at DESC").
-		First(&user).Error
-	if err == gorm.ErrRecordNotFound {
-		return nil, nil
-	}
-	return &user, err
+		Order("joined_at DESC, id DESC").
+		Find(&rows).Error
+	return rows, err
 }
 
 // GetUserRooms 获取用户加入的房间列表
@@ -215,39 +459,287 @@ func (r *Repository) ListNodes(ctx context.Context) ([]*ConnectNode, error) {
 	return nodes, err
 }
 
-// MarkUnhealthyNodes 标记不健康的节点
-func (r *Repository) MarkUnhealthyNodes(ctx context.Context, timeout time.Duration) error {
-	threshold := time.Now().Add(-timeout)
+// ListAllNodes 列出全部节点（包括 unhealthy / offline）
+func (r *Repository) ListAllNodes(ctx context.Context) ([]*ConnectNode, error) {
+	var nodes []*ConnectNode
+	err := r.db.WithContext(ctx).Order("id ASC").Find(&nodes).Error
+	return nodes, err
+}
+
+// UpsertNodeHeartbeat 写入节点心跳（节点不存在则创建），并将节点状态置为 online
+func (r *Repository) UpsertNodeHeartbeat(ctx context.Context, node *ConnectNode) error {
+	now := time.Now()
+	node.Status = "online"
+	node.LastHeartbeat = now
+	node.UpdatedAt = now
 	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"address", "region", "max_connections", "current_connections", "room_count",
+				"cpu_usage", "memory_usage", "status", "last_heartbeat", "updated_at",
+			}),
+		}).
+		Create(node).Error
+}
+
+// MarkUnhealthyNodes 标记不健康的节点，返回本次标记的节点数
+func (r *Repository) MarkUnhealthyNodes(ctx context.Context, timeout time.Duration) (int64, error) {
+	threshold := time.Now().Add(-timeout)
+	result := r.db.WithContext(ctx).
 		Model(&ConnectNode{}).
 		Where("last_heartbeat < ? AND status = ?", threshold, "online").
-		Update("status", "unhealthy").Error
+		Update("status", "unhealthy")
+	return result.RowsAffected, result.Error
+}
+
+// MarkNodeUnhealthyByAddress 节点 etcd 租约失效时按地址标记为 unhealthy
+func (r *Repository) MarkNodeUnhealthyByAddress(ctx context.Context, address string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&ConnectNode{}).
+		Where("address = ? AND status = ?", address, "online").
+		Update("status", "unhealthy")
+	return result.RowsAffected, result.Error
+}
+
+// ListUnhealthyNodes 列出等待清理的 unhealthy 节点
+func (r *Repository) ListUnhealthyNodes(ctx context.Context) ([]*ConnectNode, error) {
+	var nodes []*ConnectNode
+	err := r.db.WithContext(ctx).
+		Where("status = ?", "unhealthy").
+		Find(&nodes).Error
+	return nodes, err
+}
+
+// ReapDeadNode 在同一个事务中将 unhealthy 节点置为 offline 并把节点上所有在线成员记录标记为离开，
+// 返回已离开房间的记录（用户在其他节点上仍有同一房间的在线记录时不返回）。
+// 条件更新保证只有一个 Controller 副本能认领成功（claimed）；清理失败时事务回滚，节点仍为 unhealthy，下一轮重试
+func (r *Repository) ReapDeadNode(ctx context.Context, nodeID string) (claimed bool, users []*RoomUser, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&ConnectNode{}).
+			Where("id = ? AND status = ?", nodeID, "unhealthy").
+			Update("status", "offline")
+		if result.Error != nil || result.RowsAffected != 1 {
+			return result.Error
+		}
+		claimed = true
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("node_id = ? AND left_at IS NULL", nodeID).
+			Find(&users).Error; err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(users))
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		if err := tx.Model(&RoomUser{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"left_at":   time.Now(),
+				"is_online": false,
+			}).Error; err != nil {
+			return err
+		}
+
+		var remaining []RoomUser
+		if err := tx.Select("user_id", "room_id").
+			Where("left_at IS NULL AND user_id IN ?", memberUserIDs(users)).
+			Find(&remaining).Error; err != nil {
+			return err
+		}
+		stillIn := make(map[[2]string]bool, len(remaining))
+		for _, m := range remaining {
+			stillIn[[2]string{m.UserID, m.RoomID}] = true
+		}
+		left := users[:0]
+		for _, u := range users {
+			if !stillIn[[2]string{u.UserID, u.RoomID}] {
+				left = append(left, u)
+			}
+		}
+		users = left
+		return nil
+	})
+	if err != nil {
+		return false, nil, err
+	}
+	return claimed, users, nil
+}
+
+// memberUserIDs 成员记录中的用户 ID（去重）
+func memberUserIDs(members []*RoomUser) []string {
+	seen := make(map[string]bool, len(members))
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		if !seen[m.UserID] {
+			seen[m.UserID] = true
+			ids = append(ids, m.UserID)
+		}
+	}
+	return ids
 }
 
 // ========== 统计查询 ==========
 
-// GetRoomStats 获取房间统计
-func (r *Repository) GetRoomStats(ctx context.Context) (totalRooms, totalUsers int64, err error) {
+// GetRoomStats 获取租户的房间统计（appID 为 AllTenants 时统计全部房间）
+func (r *Repository) GetRoomStats(ctx context.Context, appID string) (totalRooms, totalUsers int64, err error) {
 	// 房间总数
-	if err = r.db.WithContext(ctx).Model(&Room{}).Count(&totalRooms).Error; err != nil {
+	if err = r.db.WithContext(ctx).Model(&Room{}).Scopes(tenantScope("id", appID)).Count(&totalRooms).Error; err != nil {
 		return
 	}
 
-	// 在线用户总数
+	// 在线用户总数（按用户 + 房间去重）
 	err = r.db.WithContext(ctx).
 		Model(&RoomUser{}).
+		Scopes(tenantScope("room_id", appID)).
 		Where("left_at IS NULL").
+		Distinct("user_id", "room_id").
 		Count(&totalUsers).Error
 
 	return
 }
 
-// GetRoomUserCount 获取房间用户数
+// tenantScope 按租户过滤 ID 列：租户的 ID 带 "appID:" 前缀，默认租户（appID 为空）的 ID 不含分隔符，
+// AllTenants 不过滤
+func tenantScope(column, appID string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch appID {
+		case AllTenants:
+			return db
+		case "":
+			return db.Where(column+" NOT LIKE ?", "%"+pkg.TenantSeparator+"%")
+		}
+		// app_id 只含字母、数字、"-"、"_"，需转义 LIKE 通配符 "_"
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(appID)
+		return db.Where(column+" LIKE ?", escaped+pkg.TenantSeparator+"%")
+	}
+}
+
+// GetRoomUserCount 获取房间用户数（同一用户的多个节点记录只计一次）
 func (r *Repository) GetRoomUserCount(ctx context.Context, roomID string) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
 		Model(&RoomUser{}).
 		Where("room_id = ? AND left_at IS NULL", roomID).
+		Distinct("user_id").
 		Count(&count).Error
 	return count, err
 }
+
+// ========== Tenant 操作 ==========
+
+// CreateTenant 创建租户
+func (r *Repository) CreateTenant(ctx context.Context, tenant *Tenant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&Tenant{}).Where("app_id = ?", tenant.AppID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrTenantExists
+		}
+		return tx.Create(tenant).Error
+	})
+}
+
+// GetTenant 获取租户，不存在时返回 nil
+func (r *Repository) GetTenant(ctx context.Context, appID string) (*Tenant, error) {
+	var tenant Tenant
+	err := r.db.WithContext(ctx).First(&tenant, "app_id = ?", appID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &tenant, err
+}
+
+// ListTenants 列出全部租户
+func (r *Repository) ListTenants(ctx context.Context) ([]*Tenant, error) {
+	var tenants []*Tenant
+	err := r.db.WithContext(ctx).Order("app_id").Find(&tenants).Error
+	return tenants, err
+}
+
+// UpdateTenant 更新租户，updates 的 key 为 tenants 表列名
+func (r *Repository) UpdateTenant(ctx context.Context, appID string, updates map[string]interface{}) (*Tenant, error) {
+	var tenant Tenant
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&tenant, "app_id = ?", appID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrTenantNotFound
+			}
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := tx.Model(&tenant).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.First(&tenant, "app_id = ?", appID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// CreateAPIKey 保存 API key（租户需存在）
+func (r *Repository) CreateAPIKey(ctx context.Context, key *APIKey) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&Tenant{}).Where("app_id = ?", key.AppID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrTenantNotFound
+		}
+		return tx.Create(key).Error
+	})
+}
+
+// ListAPIKeys 列出租户的 API key（包括已吊销的）
+func (r *Repository) ListAPIKeys(ctx context.Context, appID string) ([]*APIKey, error) {
+	var keys []*APIKey
+	err := r.db.WithContext(ctx).
+		Where("app_id = ?", appID).
+		Order("created_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+// RevokeAPIKey 吊销 API key（已吊销的保持原吊销时间）
+func (r *Repository) RevokeAPIKey(ctx context.Context, keyID string) error {
+	result := r.db.WithContext(ctx).
+		Model(&APIKey{}).
+		Where("key_id = ? AND revoked_at IS NULL", keyID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := r.db.WithContext(ctx).Model(&APIKey{}).Where("key_id = ?", keyID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrAPIKeyNotFound
+		}
+	}
+	return nil
+}
+
+// GetAPIKeyByHash 按哈希查询未吊销的 API key，不存在时返回 nil
+func (r *Repository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	var key APIKey
+	err := r.db.WithContext(ctx).
+		First(&key, "key_hash = ? AND revoked_at IS NULL", keyHash).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &key, err
+}
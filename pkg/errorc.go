@@ -6,6 +6,7 @@ var (
 	// server
 	ErrHandshake = errors.New("handshake failed")
 	ErrOperation = errors.New("request operation not valid")
+	ErrDraining  = errors.New("node is draining")
 	// ring
 	ErrRingEmpty = errors.New("ring buffer empty")
 	ErrRingFull  = errors.New("ring buffer full")
@@ -25,6 +26,34 @@ var (
 
 	// room
 	ErrRoomDroped = errors.New("room droped")
+	// publish
+	ErrNotInRoom        = errors.New("not joined the room")
+	ErrPublishForbidden = errors.New("publish forbidden by room policy")
+	ErrMsgTooLarge      = errors.New("message exceeds room size limit")
+	// topic
+	ErrTopicInvalid        = errors.New("invalid topic")
+	ErrTopicPatternInvalid = errors.New("invalid topic pattern")
+	ErrTooManyTopics       = errors.New("too many topic subscriptions")
+	// watch
+	ErrWatchOpNotAllowed = errors.New("op not allowed to watch")
+	// client request
+	ErrClientRequestArg     = errors.New("rpc client request arg error")
+	ErrClientOffline        = errors.New("client not connected")
+	ErrClientRequestTimeout = errors.New("client request timeout")
+	// webhook
+	ErrWebhookSignature = errors.New("invalid webhook signature")
+	ErrWebhookTimestamp = errors.New("webhook timestamp out of tolerance")
+	// tenant
+	ErrAppIDInvalid    = errors.New("invalid app id")
+	ErrTenantIDInvalid = errors.New("id must not contain tenant separator")
+	ErrTenantNotFound  = errors.New("tenant not found")
+	ErrTenantDisabled  = errors.New("tenant disabled")
+	ErrAPIKeyInvalid   = errors.New("invalid api key")
+	ErrTenantQuota     = errors.New("tenant quota exceeded")
+	// client auth
+	ErrClientTokenInvalid = errors.New("invalid client token")
+	ErrClientTokenExpired = errors.New("client token expired")
+	ErrClientCredential   = errors.New("client credential required")
 	// rpc
 	ErrLogic = errors.New("logic rpc is not available")
 )
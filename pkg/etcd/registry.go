@@ -243,6 +243,10 @@ func (sd *ServiceDiscovery) handleEndpointAdd(key string, value string) {
 // handleEndpointDelete 处理节点下线事件
 func (sd *ServiceDiscovery) handleEndpointDelete(key string, addr string) {
 	sd.endpointsMu.Lock()
+	// 删除事件不携带 value，从已知端点中取地址
+	if known, ok := sd.endpoints[key]; ok && addr == "" {
+		addr = known
+	}
 	delete(sd.endpoints, key)
 	sd.endpointsMu.Unlock()
 
@@ -0,0 +1,8 @@
+package pkg
+
+import "time"
+
+// Expired 消息是否已过期，expireAt 为 Unix 毫秒，<= 0 表示不过期
+func Expired(expireAt int64) bool {
+	return expireAt > 0 && time.Now().UnixMilli() >= expireAt
+}
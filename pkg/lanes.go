@@ -0,0 +1,163 @@
+package pkg
+
+import (
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+// DefaultStarvationLimit 有积压的低优先级队列最多被连续跳过的次数
+const DefaultStarvationLimit = 8
+
+// Priorities 按出队顺序（从高到低）排列的全部优先级
+var Priorities = [...]protocol.Priority{
+	protocol.Priority_PRIORITY_HIGH,
+	protocol.Priority_PRIORITY_NORMAL,
+	protocol.Priority_PRIORITY_LOW,
+}
+
+// PriorityName 优先级名称（high / normal / low），未知取值按 normal 处理
+func PriorityName(p protocol.Priority) string {
+	return [...]string{"high", "normal", "low"}[laneOf(p)]
+}
+
+// ParsePriority 解析优先级名称（high / normal / low），空字符串为 normal
+func ParsePriority(name string) (protocol.Priority, bool) {
+	switch name {
+	case "", "normal":
+		return protocol.Priority_PRIORITY_NORMAL, true
+	case "high":
+		return protocol.Priority_PRIORITY_HIGH, true
+	case "low":
+		return protocol.Priority_PRIORITY_LOW, true
+	}
+	return protocol.Priority_PRIORITY_NORMAL, false
+}
+
+// laneOf 优先级对应的队列下标
+func laneOf(p protocol.Priority) int {
+	switch p {
+	case protocol.Priority_PRIORITY_HIGH:
+		return 0
+	case protocol.Priority_PRIORITY_LOW:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Lanes 按优先级分道的有界队列：出队时总是先取高优先级，
+// 低优先级有积压且被连续跳过 starvationLimit 次后优先取一条，避免被高优先级流量饿死。
+// 入队并发安全，出队只允许单个消费者
+type Lanes[T any] struct {
+	lanes  [len(Priorities)]chan T
+	notify chan struct{}
+
+	// 以下只由消费者访问
+	skipped         [len(Priorities)]int
+	starvationLimit int
+}
+
+// NewLanes new a priority queue, size is the capacity of each lane.
+func NewLanes[T any](size, starvationLimit int) *Lanes[T] {
+	if starvationLimit < 1 {
+		starvationLimit = DefaultStarvationLimit
+	}
+	l := &Lanes[T]{
+		notify:          make(chan struct{}, 1),
+		starvationLimit: starvationLimit,
+	}
+	for i := range l.lanes {
+		l.lanes[i] = make(chan T, size)
+	}
+	return l
+}
+
+// TryPush 入队，队列满时返回 false
+func (l *Lanes[T]) TryPush(p protocol.Priority, v T) bool {
+	select {
+	case l.lanes[laneOf(p)] <- v:
+		l.wake()
+		return true
+	default:
+		return false
+	}
+}
+
+// Push 入队，队列满时阻塞
+func (l *Lanes[T]) Push(p protocol.Priority, v T) {
+	l.lanes[laneOf(p)] <- v
+	l.wake()
+}
+
+// PushWait 入队，队列满时阻塞直到 done 关闭（返回 false）
+func (l *Lanes[T]) PushWait(done <-chan struct{}, p protocol.Priority, v T) bool {
+	select {
+	case l.lanes[laneOf(p)] <- v:
+		l.wake()
+		return true
+	case <-done:
+		return false
+	}
+}
+
+func (l *Lanes[T]) wake() {
+	select {
+	case l.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Wait 有新元素入队时可读（可能有多余的唤醒，收到后调用 TryPop）
+func (l *Lanes[T]) Wait() <-chan struct{} {
+	return l.notify
+}
+
+// TryPop 按优先级出队，全部为空时返回 false
+func (l *Lanes[T]) TryPop() (v T, ok bool) {
+	// 饥饿保护：从最低优先级开始检查被跳过次数达到上限的队列
+	for i := len(l.lanes) - 1; i > 0; i-- {
+		if l.skipped[i] >= l.starvationLimit {
+			if v, ok = l.pop(i); ok {
+				return
+			}
+		}
+	}
+	for i := range l.lanes {
+		if v, ok = l.pop(i); ok {
+			return
+		}
+	}
+	return
+}
+
+// Pop 按优先级出队，全部为空时阻塞
+func (l *Lanes[T]) Pop() T {
+	for {
+		if v, ok := l.TryPop(); ok {
+			return v
+		}
+		<-l.notify
+	}
+}
+
+// Len 指定优先级队列的积压数量
+func (l *Lanes[T]) Len(p protocol.Priority) int {
+	return len(l.lanes[laneOf(p)])
+}
+
+func (l *Lanes[T]) pop(i int) (v T, ok bool) {
+	select {
+	case v = <-l.lanes[i]:
+	default:
+		return v, false
+	}
+
+	l.skipped[i] = 0
+	for j := i + 1; j < len(l.lanes); j++ {
+		if len(l.lanes[j]) > 0 {
+			l.skipped[j]++
+		} else {
+			l.skipped[j] = 0
+		}
+	}
+	return v, true
+}
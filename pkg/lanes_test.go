@@ -0,0 +1,103 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		name string
+		want protocol.Priority
+		ok   bool
+	}{
+		{"", protocol.Priority_PRIORITY_NORMAL, true},
+		{"normal", protocol.Priority_PRIORITY_NORMAL, true},
+		{"high", protocol.Priority_PRIORITY_HIGH, true},
+		{"low", protocol.Priority_PRIORITY_LOW, true},
+		{"HIGH", protocol.Priority_PRIORITY_NORMAL, false},
+		{"urgent", protocol.Priority_PRIORITY_NORMAL, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParsePriority(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParsePriority(%q) = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+		if ok && tt.name != "" && PriorityName(got) != tt.name {
+			t.Errorf("PriorityName(%v) = %q, want %q", got, PriorityName(got), tt.name)
+		}
+	}
+}
+
+func TestLanesOrder(t *testing.T) {
+	l := NewLanes[string](4, 0)
+	l.Push(protocol.Priority_PRIORITY_LOW, "low")
+	l.Push(protocol.Priority_PRIORITY_NORMAL, "normal")
+	l.Push(protocol.Priority_PRIORITY_HIGH, "high")
+	// 未知优先级按 normal 处理
+	l.Push(protocol.Priority(99), "unknown")
+
+	for _, want := range []string{"high", "normal", "unknown", "low"} {
+		if got, ok := l.TryPop(); !ok || got != want {
+			t.Fatalf("TryPop() = %q, %v, want %q", got, ok, want)
+		}
+	}
+	if _, ok := l.TryPop(); ok {
+		t.Error("TryPop() on empty lanes = true")
+	}
+}
+
+func TestLanesStarvation(t *testing.T) {
+	l := NewLanes[string](16, 2)
+	for i := 0; i < 6; i++ {
+		l.Push(protocol.Priority_PRIORITY_HIGH, "high")
+	}
+	l.Push(protocol.Priority_PRIORITY_LOW, "low")
+
+	// 低优先级被连续跳过 2 次后优先出队
+	var got []string
+	for i := 0; i < 4; i++ {
+		v, _ := l.TryPop()
+		got = append(got, v)
+	}
+	want := []string{"high", "high", "low", "high"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("TryPop() order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestLanesBounded(t *testing.T) {
+	l := NewLanes[int](1, 0)
+	if !l.TryPush(protocol.Priority_PRIORITY_NORMAL, 1) {
+		t.Fatal("TryPush() on empty lane = false")
+	}
+	if l.TryPush(protocol.Priority_PRIORITY_NORMAL, 2) {
+		t.Error("TryPush() on full lane = true")
+	}
+	// 各优先级容量独立
+	if !l.TryPush(protocol.Priority_PRIORITY_HIGH, 3) {
+		t.Error("TryPush() on another lane = false")
+	}
+	if n := l.Len(protocol.Priority_PRIORITY_NORMAL); n != 1 {
+		t.Errorf("Len(normal) = %d, want 1", n)
+	}
+
+	done := make(chan struct{})
+	close(done)
+	if l.PushWait(done, protocol.Priority_PRIORITY_NORMAL, 4) {
+		t.Error("PushWait() on full lane with closed done = true")
+	}
+}
+
+func TestLanesPopWaits(t *testing.T) {
+	l := NewLanes[int](1, 0)
+	got := make(chan int)
+	go func() { got <- l.Pop() }()
+	l.Push(protocol.Priority_PRIORITY_LOW, 42)
+	if v := <-got; v != 42 {
+		t.Errorf("Pop() = %d, want 42", v)
+	}
+}
@@ -0,0 +1,70 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEviction(t *testing.T) {
+	c := NewLRU(2, 0)
+	c.Set("a", []byte("1"))
+	c.Set("b", []byte("2"))
+	// 访问 a 后 b 成为最久未使用
+	if v, ok := c.Get("a"); !ok || string(v) != "1" {
+		t.Fatalf("Get(a) = %q, %v", v, ok)
+	}
+	c.Set("c", []byte("3"))
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get(b) found an evicted entry")
+	}
+	for key, want := range map[string]string{"a": "1", "c": "3"} {
+		if v, ok := c.Get(key); !ok || string(v) != want {
+			t.Errorf("Get(%s) = %q, %v, want %q", key, v, ok, want)
+		}
+	}
+
+	// 覆盖不增加条目
+	c.Set("a", []byte("4"))
+	if v, _ := c.Get("a"); string(v) != "4" || c.ll.Len() != 2 {
+		t.Errorf("Get(a) = %q with %d entries after overwrite, want 4 with 2", v, c.ll.Len())
+	}
+
+	c.Del("a")
+	c.Del("missing")
+	if _, ok := c.Get("a"); ok || c.ll.Len() != 1 || len(c.items) != 1 {
+		t.Errorf("Del(a) left %d entries, Get ok=%v", c.ll.Len(), ok)
+	}
+
+	if NewLRU(0, 0).size != 1 {
+		t.Error("NewLRU(0) size should be clamped to 1")
+	}
+}
+
+func TestLRUSetNX(t *testing.T) {
+	c := NewLRU(8, 0)
+	if _, ok := c.SetNX("k", []byte("first")); !ok {
+		t.Fatal("SetNX on a new key = false")
+	}
+	if v, ok := c.SetNX("k", []byte("second")); ok || string(v) != "first" {
+		t.Errorf("SetNX on an existing key = %q, %v, want first, false", v, ok)
+	}
+}
+
+func TestLRUExpire(t *testing.T) {
+	c := NewLRU(8, 20*time.Millisecond)
+	c.Set("k", []byte("v"))
+	if _, ok := c.Get("k"); !ok {
+		t.Fatal("Get() before ttl = false")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := c.Get("k"); ok {
+		t.Error("Get() after ttl = true")
+	}
+	if len(c.items) != 0 {
+		t.Errorf("expired entry not removed, %d items left", len(c.items))
+	}
+	// 过期后 SetNX 可以重新写入
+	if _, ok := c.SetNX("k", []byte("v2")); !ok {
+		t.Error("SetNX() after ttl = false")
+	}
+}
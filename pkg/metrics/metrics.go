@@ -42,6 +42,19 @@ type MetricsCollector struct {
 	apiErrorCount   metric.Int64Counter
 	nodeConnections metric.Int64ObservableGauge
 
+	// Push-Manager metrics
+	pushRetries        metric.Int64Counter
+	pushDeadLetters    metric.Int64Counter
+	breakerState       metric.Int64ObservableGauge
+	breakerTransitions metric.Int64Counter
+	pushExpired        metric.Int64Counter
+
+	// Webhook metrics
+	webhookDeliveries metric.Int64Counter
+
+	// 租户用量限制
+	tenantLimits metric.Int64Counter
+
 	// 用于计算当前值
 	mu                 sync.RWMutex
 	currentRooms       int64
@@ -49,6 +62,7 @@ type MetricsCollector struct {
 	currentNodes       int64
 	roomUsers          map[string]int64
 	nodeConnectionsMap map[string]int64
+	breakerStates      map[string]int64
 }
 
 // NewMetricsCollector 创建指标收集器
@@ -61,6 +75,7 @@ func NewMetricsCollector(serviceID, serviceName string) (*MetricsCollector, erro
 		serviceID:          serviceID,
 		roomUsers:          make(map[string]int64),
 		nodeConnectionsMap: make(map[string]int64),
+		breakerStates:      make(map[string]int64),
 	}
 
 	var err error
@@ -112,6 +127,62 @@ func NewMetricsCollector(serviceID, serviceName string) (*MetricsCollector, erro
 		metric.WithUnit("{error}"),
 	)
 
+	// 推送重试次数
+	mc.pushRetries, _ = meter.Int64Counter(
+		"pubsub.push.retries.total",
+		metric.WithDescription("Total number of outbound push retries"),
+		metric.WithUnit("{retry}"),
+	)
+
+	// 进入死信队列的消息数
+	mc.pushDeadLetters, _ = meter.Int64Counter(
+		"pubsub.push.deadletters.total",
+		metric.WithDescription("Total number of outbound messages moved to the dead-letter queue"),
+		metric.WithUnit("{message}"),
+	)
+
+	// 每个 Connect-Node 的熔断器状态（0=closed, 1=open, 2=half-open）
+	mc.breakerState, _ = meter.Int64ObservableGauge(
+		"pubsub.push.breaker.state",
+		metric.WithDescription("Circuit breaker state per connect node (0=closed, 1=open, 2=half-open)"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			mc.mu.RLock()
+			defer mc.mu.RUnlock()
+			for node, state := range mc.breakerStates {
+				o.Observe(state, metric.WithAttributes(attribute.String("node", node)))
+			}
+			return nil
+		}),
+	)
+
+	// 熔断器状态变化次数
+	mc.breakerTransitions, _ = meter.Int64Counter(
+		"pubsub.push.breaker.transitions.total",
+		metric.WithDescription("Total number of circuit breaker state transitions"),
+		metric.WithUnit("{transition}"),
+	)
+
+	// 过期丢弃的消息数（按环节）
+	mc.pushExpired, _ = meter.Int64Counter(
+		"pubsub.push.expired.total",
+		metric.WithDescription("Total number of push messages discarded because they expired"),
+		metric.WithUnit("{message}"),
+	)
+
+	// webhook 投递次数（按结果）
+	mc.webhookDeliveries, _ = meter.Int64Counter(
+		"pubsub.webhook.deliveries.total",
+		metric.WithDescription("Total number of webhook delivery attempts by result"),
+		metric.WithUnit("{delivery}"),
+	)
+
+	// 租户超过用量限制的次数（软限制放行，硬限制拒绝）
+	mc.tenantLimits, _ = meter.Int64Counter(
+		"pubsub.tenant.limit.exceeded.total",
+		metric.WithDescription("Total number of requests exceeding a tenant usage limit"),
+		metric.WithUnit("{request}"),
+	)
+
 	return mc, nil
 }
 
@@ -199,6 +270,71 @@ func (m *MetricsCollector) RecordAPIRequest(ctx context.Context, method string,
 	}
 }
 
+// ========== Push Metrics ==========
+
+// RecordPushRetry 记录一次推送重试
+func (m *MetricsCollector) RecordPushRetry(ctx context.Context, node, kind string) {
+	m.pushRetries.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("node", node),
+		attribute.String("kind", kind),
+	))
+}
+
+// RecordDeadLetter 记录一条消息进入死信队列
+func (m *MetricsCollector) RecordDeadLetter(ctx context.Context, node, kind string) {
+	m.pushDeadLetters.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("node", node),
+		attribute.String("kind", kind),
+	))
+}
+
+// RecordPushExpired 记录一条在 stage 环节因过期被丢弃的消息
+func (m *MetricsCollector) RecordPushExpired(ctx context.Context, stage, kind string) {
+	m.pushExpired.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("stage", stage),
+		attribute.String("kind", kind),
+	))
+}
+
+// RecordWebhookDelivery 记录一次 webhook 投递尝试，result 为 success / retry / failed / dropped
+func (m *MetricsCollector) RecordWebhookDelivery(ctx context.Context, endpoint, eventType, result string) {
+	m.webhookDeliveries.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("endpoint", endpoint),
+		attribute.String("event", eventType),
+		attribute.String("result", result),
+	))
+}
+
+// RecordTenantLimit 记录一次超过租户用量限制，resource 为 connections / daily_messages / publish_rate / message_bytes，
+// limit 为 soft / hard
+func (m *MetricsCollector) RecordTenantLimit(ctx context.Context, appID, resource, limit string) {
+	m.tenantLimits.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("app", appID),
+		attribute.String("resource", resource),
+		attribute.String("limit", limit),
+	))
+}
+
+// SetBreakerState 记录节点熔断器状态变化
+func (m *MetricsCollector) SetBreakerState(ctx context.Context, node string, from, to string, state int64) {
+	m.mu.Lock()
+	m.breakerStates[node] = state
+	m.mu.Unlock()
+
+	m.breakerTransitions.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("node", node),
+		attribute.String("from", from),
+		attribute.String("to", to),
+	))
+}
+
+// RemoveBreakerState 节点移除后清理熔断器指标
+func (m *MetricsCollector) RemoveBreakerState(node string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.breakerStates, node)
+}
+
 // ========== Getters ==========
 
 // GetCurrentRooms 获取当前房间数
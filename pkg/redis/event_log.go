@@ -0,0 +1,188 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// EventLogStream 生命周期事件日志（Controller 写入，WatchEvents 按游标读取）
+const EventLogStream = "lifecycle_events"
+
+// ErrCursorTrimmed 游标之后的事件已被裁剪，无法从该游标无缺口地续读
+var ErrCursorTrimmed = errors.New("event log cursor has been trimmed")
+
+// 生命周期事件类型
+const (
+	EventUserConnected    = "user.connected"    // 连接鉴权成功（上线）
+	EventUserDisconnected = "user.disconnected" // 连接断开（下线）
+	EventUserEvicted      = "user.evicted"      // 会话被同用户的新会话挤下线
+	EventRoomCreated      = "room.created"      // 房间有了第一个在线用户
+	EventRoomDeleted      = "room.deleted"      // 房间最后一个在线用户离开
+	EventRoomJoined       = "room.joined"       // 加入房间
+	EventRoomLeft         = "room.left"         // 离开房间（主动离开、被踢出或节点宕机）
+	EventRoomKicked       = "room.kicked"       // 被踢出房间（在 room.left 之后发出）
+	EventNodeUp           = "node.up"           // Connect-Node 上线（首次心跳或恢复心跳）
+	EventNodeDown         = "node.down"         // Connect-Node 下线（心跳超时或 etcd 租约消失后被清理）
+)
+
+// LifecycleEvent 连接、房间和节点的生命周期事件（webhook 的 JSON 和事件日志的内容）
+type LifecycleEvent struct {
+	Cursor     string `json:"-"`         // 事件日志中的位置（stream entry id），写入或读取日志后才有
+	ID         string `json:"id"`        // 事件 ID
+	Type       string `json:"type"`      // 事件类型
+	Timestamp  int64  `json:"timestamp"` // 事件发生时间（Unix 毫秒）
+	UserID     string `json:"user_id,omitempty"`
+	RoomID     string `json:"room_id,omitempty"`
+	ConnID     string `json:"conn_id,omitempty"`
+	NodeID     string `json:"node_id,omitempty"`
+	DeviceID   string `json:"device_id,omitempty"`
+	DeviceType string `json:"device_type,omitempty"`
+	Role       string `json:"role,omitempty"`
+	OperatorID string `json:"operator_id,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// EventLog 基于 Redis Stream 的生命周期事件日志，游标即 stream entry id
+type EventLog struct {
+	client *redis.Client
+	maxLen int64
+}
+
+// NewEventLog 创建事件日志，maxLen 为保留的近似最大事件数（0 表示不裁剪）
+func NewEventLog(client *redis.Client, maxLen int64) *EventLog {
+	return &EventLog{client: client, maxLen: maxLen}
+}
+
+// Append 追加事件，返回并回填游标
+func (l *EventLog) Append(ctx context.Context, event *LifecycleEvent) (string, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal lifecycle event: %w", err)
+	}
+	id, err := l.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: EventLogStream,
+		MaxLen: l.maxLen,
+		Approx: l.maxLen > 0,
+		Values: map[string]interface{}{"type": event.Type, "data": data},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to append lifecycle event: %w", err)
+	}
+	event.Cursor = id
+	return id, nil
+}
+
+// LastCursor 最新事件的游标，日志为空时返回 "0"
+func (l *EventLog) LastCursor(ctx context.Context) (string, error) {
+	entries, err := l.client.XRevRangeN(ctx, EventLogStream, "+", "-", 1).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to read event log: %w", err)
+	}
+	if len(entries) == 0 {
+		return "0", nil
+	}
+	return entries[0].ID, nil
+}
+
+// CheckCursor 检查游标之后的事件是否仍完整保留，已被裁剪时返回 ErrCursorTrimmed。
+// Redis 7 按 max-deleted-entry-id 精确判断；更早的版本在游标早于最早保留的事件时即视为已裁剪
+func (l *EventLog) CheckCursor(ctx context.Context, cursor string) error {
+	if cursor == "" || cursor == "0" || cursor == "0-0" {
+		return nil
+	}
+	info, err := l.client.XInfoStream(ctx, EventLogStream).Result()
+	if err != nil {
+		if strings.Contains(err.Error(), "no such key") {
+			return nil
+		}
+		return fmt.Errorf("failed to inspect event log: %w", err)
+	}
+
+	trimmedTo := info.MaxDeletedEntryID
+	if trimmedTo == "" {
+		trimmedTo = info.FirstEntry.ID
+		if trimmedTo == "" || compareStreamID(cursor, trimmedTo) >= 0 {
+			return nil
+		}
+		return ErrCursorTrimmed
+	}
+	if compareStreamID(cursor, trimmedTo) < 0 {
+		return ErrCursorTrimmed
+	}
+	return nil
+}
+
+// compareStreamID 比较两个 stream entry id（<ms>-<seq>，省略 seq 时为 0）
+func compareStreamID(a, b string) int {
+	am, as := splitStreamID(a)
+	bm, bs := splitStreamID(b)
+	switch {
+	case am != bm:
+		if am < bm {
+			return -1
+		}
+		return 1
+	case as < bs:
+		return -1
+	case as > bs:
+		return 1
+	}
+	return 0
+}
+
+func splitStreamID(id string) (uint64, uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ := strconv.ParseUint(msPart, 10, 64)
+	seq, _ := strconv.ParseUint(seqPart, 10, 64)
+	return ms, seq
+}
+
+// Read 读取游标之后（不含）的最多 count 条事件，没有新事件时最多阻塞 block；超时返回空
+func (l *EventLog) Read(ctx context.Context, cursor string, count int64, block time.Duration) ([]*LifecycleEvent, error) {
+	streams, err := l.client.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{EventLogStream, cursor},
+		Count:   count,
+		Block:   block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+
+	var events []*LifecycleEvent
+	for _, stream := range streams {
+		for _, m := range stream.Messages {
+			event := &LifecycleEvent{}
+			if err := json.Unmarshal([]byte(valueString(m.Values, "data")), event); err != nil {
+				// 无法解析的事件只保留类型，游标照常推进
+				event.Type = valueString(m.Values, "type")
+			}
+			event.Cursor = m.ID
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
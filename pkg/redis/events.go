@@ -0,0 +1,79 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// RoomEventChannel 房间成员变更通知频道（Controller 发布，Connect-Node 订阅）
+	RoomEventChannel = "room_events"
+
+	// 房间事件类型
+	RoomEventRole   = "role"   // 成员角色变更
+	RoomEventKick   = "kick"   // 成员被踢出
+	RoomEventPolicy = "policy" // 房间策略变更
+	RoomEventLeave  = "leave"  // 成员离开（主动离开、被踢或所在节点宕机）
+	RoomEventEvict  = "evict"  // 会话被挤下线（超出设备数或同类型设备登录），按 ConnID 关闭
+)
+
+// RoomEvent 房间事件
+type RoomEvent struct {
+	Type   string `json:"type"`
+	RoomID string `json:"room_id"`
+	UserID string `json:"user_id,omitempty"`
+	ConnID string `json:"conn_id,omitempty"`
+	Role   string `json:"role,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// PublishRoomEvent 发布房间事件
+func PublishRoomEvent(ctx context.Context, client *redis.Client, event *RoomEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal room event: %w", err)
+	}
+	return client.Publish(ctx, RoomEventChannel, data).Err()
+}
+
+// SubscribeRoomEvents 订阅房间事件，阻塞直到 ctx 结束
+func SubscribeRoomEvents(ctx context.Context, client *redis.Client, handler func(*RoomEvent)) {
+	sub := client.Subscribe(ctx, RoomEventChannel)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var event RoomEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				log.Printf("⚠️  [Redis] 非法房间事件: %v", err)
+				continue
+			}
+			handler(&event)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+const (
+	// RoomHistoryPrefix 房间消息历史（Stream，每个房间一个，房间 ID 为集群内 ID）
+	RoomHistoryPrefix = "room_history:"
+
+	// RoomHistoryMaxLen 每个房间保留的近似最大消息数
+	RoomHistoryMaxLen = 1000
+
+	// RoomHistoryTTL 房间最后一条消息之后的保留时间
+	RoomHistoryTTL = 7 * 24 * time.Hour
+)
+
+// HistoryMessage 房间消息历史中的一条消息
+type HistoryMessage struct {
+	ID          string // stream entry id（分页游标，写入时忽略）
+	RoomID      string // 集群内房间 ID
+	MessageID   string // 推送请求的幂等 key（未携带时为空）
+	Proto       *protocol.Proto
+	PublishedAt int64 // 进入推送队列的时间（Unix 毫秒）
+	ExpireAt    int64 // 消息过期时间（Unix 毫秒），0 表示不过期；过期的消息不再出现在历史中
+}
+
+// HistoryStore 房间消息历史（Push-Manager 在房间消息进入推送队列时写入）
+type HistoryStore struct {
+	client *redis.Client
+}
+
+// NewHistoryStore 创建房间消息历史
+func NewHistoryStore(client *redis.Client) *HistoryStore {
+	return &HistoryStore{client: client}
+}
+
+// Append 批量追加房间消息（一次 pipeline）
+func (h *HistoryStore) Append(ctx context.Context, messages ...*HistoryMessage) error {
+	pipe := h.client.Pipeline()
+	rooms := make(map[string]struct{}, len(messages))
+	for _, m := range messages {
+		data, err := proto.Marshal(m.Proto)
+		if err != nil {
+			return fmt.Errorf("failed to marshal history message: %w", err)
+		}
+		key := RoomHistoryPrefix + m.RoomID
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: key,
+			MaxLen: RoomHistoryMaxLen,
+			Approx: true,
+			Values: map[string]interface{}{
+				"message_id":   m.MessageID,
+				"proto":        data,
+				"published_at": m.PublishedAt,
+				"expire_at":    m.ExpireAt,
+			},
+		})
+		rooms[key] = struct{}{}
+	}
+	for key := range rooms {
+		pipe.Expire(ctx, key, RoomHistoryTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to append history message: %w", err)
+	}
+	return nil
+}
+
+// List 从新到旧列出 before（不含，为空时从最新一条开始）之前的最多 limit 条消息，
+// 返回下一页游标（为空表示没有更多）。已过期的消息被跳过，因此一页可能少于 limit 条
+func (h *HistoryStore) List(ctx context.Context, roomID, before string, limit int64) ([]*HistoryMessage, string, error) {
+	end := "+"
+	if before != "" {
+		end = "(" + before
+	}
+	entries, err := h.client.XRevRangeN(ctx, RoomHistoryPrefix+roomID, end, "-", limit).Result()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list history: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+	messages := make([]*HistoryMessage, 0, len(entries))
+	for _, m := range entries {
+		expireAt := valueInt64(m.Values, "expire_at")
+		if expireAt > 0 && expireAt <= now {
+			continue
+		}
+		p := &protocol.Proto{}
+		if err := proto.Unmarshal([]byte(valueString(m.Values, "proto")), p); err != nil {
+			continue
+		}
+		messages = append(messages, &HistoryMessage{
+			ID:          m.ID,
+			RoomID:      roomID,
+			MessageID:   valueString(m.Values, "message_id"),
+			Proto:       p,
+			PublishedAt: valueInt64(m.Values, "published_at"),
+			ExpireAt:    expireAt,
+		})
+	}
+
+	var next string
+	if int64(len(entries)) == limit && len(entries) > 0 {
+		next = entries[len(entries)-1].ID
+	}
+	return messages, next, nil
+}
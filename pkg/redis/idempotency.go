@@ -0,0 +1,76 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// IdempotencyPrefix 推送请求幂等记录（String: 处理中为空，完成后为序列化的响应）
+	IdempotencyPrefix = "push_idem:"
+)
+
+// IdempotencyStore Redis 幂等记录（多个 Push-Manager 共享去重窗口）
+type IdempotencyStore struct {
+	client *redis.Client
+}
+
+// NewIdempotencyStore 创建幂等记录存储
+func NewIdempotencyStore(client *redis.Client) *IdempotencyStore {
+	return &IdempotencyStore{client: client}
+}
+
+func idempotencyKey(key string) string {
+	return fmt.Sprintf("%s%s", IdempotencyPrefix, key)
+}
+
+// Reserve 占用幂等 key：首次请求返回 reserved=true；重复请求返回已保存的响应（处理中时为空）
+func (s *IdempotencyStore) Reserve(ctx context.Context, key string, window time.Duration) (reply []byte, reserved bool, err error) {
+	ok, err := s.client.SetNX(ctx, idempotencyKey(key), "", window).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if ok {
+		return nil, true, nil
+	}
+
+	data, err := s.client.Get(ctx, idempotencyKey(key)).Bytes()
+	if err == redis.Nil {
+		// 恰好过期，视为首次请求
+		return s.Reserve(ctx, key, window)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get idempotency record: %w", err)
+	}
+	return data, false, nil
+}
+
+// Complete 保存首次请求的响应，去重窗口内的重复请求直接返回该响应
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, reply []byte, window time.Duration) error {
+	if err := s.client.Set(ctx, idempotencyKey(key), reply, window).Err(); err != nil {
+		return fmt.Errorf("failed to save idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Release 首次请求失败时释放 key，允许调用方重试
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, idempotencyKey(key)).Err()
+}
@@ -0,0 +1,325 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+const (
+	// OutboundStreamPrefix Push-Manager -> Connect-Node 出站队列（每个节点每个优先级一个 stream，
+	// normal 为 push_outbound:{node}，其他为 push_outbound:{node}:{high|low}）
+	OutboundStreamPrefix = "push_outbound:"
+
+	// OutboundGroup 出站队列消费组（多个 Push-Manager 共享，每条消息只投递一次）
+	OutboundGroup = "push-manager"
+
+	// DeadLetterStream 重试耗尽的出站消息
+	DeadLetterStream = "push_deadletter"
+)
+
+// OutboundMessage 出站队列中的一条消息
+type OutboundMessage struct {
+	ID         string // stream entry id
+	Node       string // 目标 Connect-Node 地址
+	Priority   protocol.Priority
+	Kind       string // broadcast / room / user
+	Payload    []byte // 序列化后的 Comet 请求
+	EnqueuedAt int64  // 毫秒
+}
+
+// DeadLetter 死信
+type DeadLetter struct {
+	ID         string
+	Node       string
+	Priority   protocol.Priority
+	Kind       string
+	Payload    []byte
+	Attempts   int
+	Error      string
+	EnqueuedAt int64
+	FailedAt   int64
+}
+
+// OutboundQueue 基于 Redis Stream 的持久化出站队列
+type OutboundQueue struct {
+	client *redis.Client
+	maxLen int64
+}
+
+// NewOutboundQueue 创建出站队列，maxLen 为每个 stream 的近似最大长度（0 表示不裁剪）
+func NewOutboundQueue(client *redis.Client, maxLen int64) *OutboundQueue {
+	return &OutboundQueue{client: client, maxLen: maxLen}
+}
+
+func outboundStream(node string, priority protocol.Priority) string {
+	if priority == protocol.Priority_PRIORITY_NORMAL {
+		return fmt.Sprintf("%s%s", OutboundStreamPrefix, node)
+	}
+	return fmt.Sprintf("%s%s:%s", OutboundStreamPrefix, node, pkg.PriorityName(priority))
+}
+
+// EnsureGroup 创建节点各优先级的出站 stream 及消费组（已存在时忽略），并取消节点下线时设置的过期时间
+func (q *OutboundQueue) EnsureGroup(ctx context.Context, node string) error {
+	for _, priority := range pkg.Priorities {
+		stream := outboundStream(node, priority)
+		err := q.client.XGroupCreateMkStream(ctx, stream, OutboundGroup, "0").Err()
+		if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+			return fmt.Errorf("failed to create outbound group: %w", err)
+		}
+		if err := q.client.Persist(ctx, stream).Err(); err != nil {
+			return fmt.Errorf("failed to persist outbound stream: %w", err)
+		}
+	}
+	return nil
+}
+
+// ExpireNode 节点下线：为其各优先级的出站 stream 设置过期时间，节点在 ttl 内恢复时由 EnsureGroup 取消
+func (q *OutboundQueue) ExpireNode(ctx context.Context, node string, ttl time.Duration) error {
+	pipe := q.client.Pipeline()
+	for _, priority := range pkg.Priorities {
+		pipe.Expire(ctx, outboundStream(node, priority), ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to expire outbound streams: %w", err)
+	}
+	return nil
+}
+
+// Enqueue 追加一条出站消息
+func (q *OutboundQueue) Enqueue(ctx context.Context, node string, priority protocol.Priority, kind string, payload []byte) (string, error) {
+	return q.add(ctx, node, priority, kind, payload, time.Now().UnixMilli())
+}
+
+func (q *OutboundQueue) add(ctx context.Context, node string, priority protocol.Priority, kind string, payload []byte, enqueuedAt int64) (string, error) {
+	args := &redis.XAddArgs{
+		Stream: outboundStream(node, priority),
+		Values: map[string]interface{}{
+			"kind":        kind,
+			"payload":     payload,
+			"enqueued_at": enqueuedAt,
+		},
+	}
+	if q.maxLen > 0 {
+		args.MaxLen = q.maxLen
+		args.Approx = true
+	}
+	id, err := q.client.XAdd(ctx, args).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to enqueue outbound message: %w", err)
+	}
+	return id, nil
+}
+
+// Read 以 consumer 身份读取指定优先级的新消息，block 超时返回空
+func (q *OutboundQueue) Read(ctx context.Context, node string, priority protocol.Priority, consumer string, count int64, block time.Duration) ([]*OutboundMessage, error) {
+	streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    OutboundGroup,
+		Consumer: consumer,
+		Streams:  []string{outboundStream(node, priority), ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbound messages: %w", err)
+	}
+
+	var msgs []*OutboundMessage
+	for _, stream := range streams {
+		for _, m := range stream.Messages {
+			msgs = append(msgs, parseOutbound(node, priority, m))
+		}
+	}
+	return msgs, nil
+}
+
+// Claim 认领空闲超过 minIdle 的未确认消息（处理该消息的 Push-Manager 已崩溃或重启）
+func (q *OutboundQueue) Claim(ctx context.Context, node string, priority protocol.Priority, consumer string, minIdle time.Duration, count int64) ([]*OutboundMessage, error) {
+	var msgs []*OutboundMessage
+	start := "0-0"
+	for {
+		claimed, next, err := q.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   outboundStream(node, priority),
+			Group:    OutboundGroup,
+			Consumer: consumer,
+			MinIdle:  minIdle,
+			Start:    start,
+			Count:    count,
+		}).Result()
+		if err != nil {
+			return msgs, fmt.Errorf("failed to claim outbound messages: %w", err)
+		}
+		for _, m := range claimed {
+			msgs = append(msgs, parseOutbound(node, priority, m))
+		}
+		if next == "0-0" || int64(len(msgs)) >= count {
+			return msgs, nil
+		}
+		start = next
+	}
+}
+
+// Ack 确认并删除已投递的消息
+func (q *OutboundQueue) Ack(ctx context.Context, node string, priority protocol.Priority, id string) error {
+	stream := outboundStream(node, priority)
+	pipe := q.client.TxPipeline()
+	pipe.XAck(ctx, stream, OutboundGroup, id)
+	pipe.XDel(ctx, stream, id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to ack outbound message: %w", err)
+	}
+	return nil
+}
+
+// DeadLetter 将重试耗尽的消息移入死信队列；id 不为空时同时确认出站消息
+func (q *OutboundQueue) DeadLetter(ctx context.Context, msg *OutboundMessage, attempts int, reason string) error {
+	pipe := q.client.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: DeadLetterStream,
+		Values: map[string]interface{}{
+			"node":        msg.Node,
+			"priority":    int32(msg.Priority),
+			"kind":        msg.Kind,
+			"payload":     msg.Payload,
+			"attempts":    attempts,
+			"error":       reason,
+			"enqueued_at": msg.EnqueuedAt,
+			"failed_at":   time.Now().UnixMilli(),
+		},
+	})
+	if msg.ID != "" {
+		stream := outboundStream(msg.Node, msg.Priority)
+		pipe.XAck(ctx, stream, OutboundGroup, msg.ID)
+		pipe.XDel(ctx, stream, msg.ID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+	return nil
+}
+
+// ListDeadLetters 按 ID 顺序列出死信，startID 为上一页最后一条（不含），node 为空表示全部节点。
+// 返回的 next 为空表示没有更多
+func (q *OutboundQueue) ListDeadLetters(ctx context.Context, node, startID string, limit int64) ([]*DeadLetter, string, error) {
+	start := "-"
+	if startID != "" {
+		start = "(" + startID
+	}
+
+	var letters []*DeadLetter
+	for int64(len(letters)) < limit {
+		entries, err := q.client.XRangeN(ctx, DeadLetterStream, start, "+", limit).Result()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list dead letters: %w", err)
+		}
+		for _, e := range entries {
+			start = "(" + e.ID
+			dl := parseDeadLetter(e)
+			if node != "" && dl.Node != node {
+				continue
+			}
+			letters = append(letters, dl)
+			if int64(len(letters)) == limit {
+				return letters, e.ID, nil
+			}
+		}
+		if int64(len(entries)) < limit {
+			return letters, "", nil
+		}
+	}
+	return letters, "", nil
+}
+
+// GetDeadLetter 读取一条死信，不存在时返回 nil
+func (q *OutboundQueue) GetDeadLetter(ctx context.Context, id string) (*DeadLetter, error) {
+	entries, err := q.client.XRangeN(ctx, DeadLetterStream, id, id, 1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead letter: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return parseDeadLetter(entries[0]), nil
+}
+
+// Replay 将死信重新加入目标节点的出站队列并从死信队列删除
+func (q *OutboundQueue) Replay(ctx context.Context, dl *DeadLetter) error {
+	if _, err := q.add(ctx, dl.Node, dl.Priority, dl.Kind, dl.Payload, dl.EnqueuedAt); err != nil {
+		return err
+	}
+	if err := q.client.XDel(ctx, DeadLetterStream, dl.ID).Err(); err != nil {
+		return fmt.Errorf("failed to delete dead letter: %w", err)
+	}
+	return nil
+}
+
+// DeleteDeadLetters 删除死信（如已过期、不再重放的消息）
+func (q *OutboundQueue) DeleteDeadLetters(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := q.client.XDel(ctx, DeadLetterStream, ids...).Err(); err != nil {
+		return fmt.Errorf("failed to delete dead letters: %w", err)
+	}
+	return nil
+}
+
+func parseOutbound(node string, priority protocol.Priority, m redis.XMessage) *OutboundMessage {
+	return &OutboundMessage{
+		ID:         m.ID,
+		Node:       node,
+		Priority:   priority,
+		Kind:       valueString(m.Values, "kind"),
+		Payload:    []byte(valueString(m.Values, "payload")),
+		EnqueuedAt: valueInt64(m.Values, "enqueued_at"),
+	}
+}
+
+func parseDeadLetter(m redis.XMessage) *DeadLetter {
+	return &DeadLetter{
+		ID:         m.ID,
+		Node:       valueString(m.Values, "node"),
+		Priority:   protocol.Priority(valueInt64(m.Values, "priority")),
+		Kind:       valueString(m.Values, "kind"),
+		Payload:    []byte(valueString(m.Values, "payload")),
+		Attempts:   int(valueInt64(m.Values, "attempts")),
+		Error:      valueString(m.Values, "error"),
+		EnqueuedAt: valueInt64(m.Values, "enqueued_at"),
+		FailedAt:   valueInt64(m.Values, "failed_at"),
+	}
+}
+
+func valueString(values map[string]interface{}, key string) string {
+	v, _ := values[key].(string)
+	return v
+}
+
+func valueInt64(values map[string]interface{}, key string) int64 {
+	n, _ := strconv.ParseInt(valueString(values, key), 10, 64)
+	return n
+}
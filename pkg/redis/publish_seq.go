@@ -0,0 +1,52 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// PublishSeqPrefix 客户端发言的 Seq 计数（String，每个房间的每个发送者一个，ID 为集群内 ID）
+	PublishSeqPrefix = "publish_seq:"
+
+	// PublishSeqTTL 发送者最后一次发言之后计数的保留时间，过期后从 1 重新开始（接收方视为发送者重启）
+	PublishSeqTTL = 24 * time.Hour
+)
+
+// PublishSeqStore 为客户端发言分配 Seq：同一用户的多个设备共用一个连续递增的消息流，接收方据此检测缺口
+type PublishSeqStore struct {
+	client *redis.Client
+}
+
+// NewPublishSeqStore 创建发言 Seq 计数
+func NewPublishSeqStore(client *redis.Client) *PublishSeqStore {
+	return &PublishSeqStore{client: client}
+}
+
+// Next 分配发送者在房间内的下一个 Seq（一次 pipeline：INCR + 刷新过期时间）
+func (p *PublishSeqStore) Next(ctx context.Context, roomID, userID string) (int32, error) {
+	key := PublishSeqPrefix + roomID + ":" + userID
+	pipe := p.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, PublishSeqTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return int32(incr.Val()), nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// addRoomUserScript 写入房间用户 Hash 并刷新过期时间，返回 {是否新增字段, 写入后的人数}
+var addRoomUserScript = redis.NewScript(`
+local added = redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+redis.call("PEXPIRE", KEYS[1], ARGV[3])
+return {added, redis.call("HLEN", KEYS[1])}
+`)
+
+// removeRoomUsersScript 从房间用户 Hash 删除用户，返回 {删除的字段数, 删除后的人数}
+var removeRoomUsersScript = redis.NewScript(`
+local removed = redis.call("HDEL", KEYS[1], unpack(ARGV))
+return {removed, redis.call("HLEN", KEYS[1])}
+`)
+
+// AddRoomUser 原子地写入房间在线用户（room_users:{roomID}），first 表示房间因本次写入从空变为有人
+// （并发加入时只有一方为 true）
+func AddRoomUser(ctx context.Context, client redis.Scripter, key, userID string, data []byte, ttl time.Duration) (first bool, count int64, err error) {
+	res, err := addRoomUserScript.Run(ctx, client, []string{key}, userID, data, ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to add room user: %w", err)
+	}
+	return res[0] == 1 && res[1] == 1, res[1], nil
+}
+
+// RemoveRoomUsers 原子地删除房间在线用户，emptied 表示房间因本次删除变空（并发离开时只有一方为 true）
+func RemoveRoomUsers(ctx context.Context, client redis.Scripter, key string, userIDs ...string) (emptied bool, count int64, err error) {
+	args := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		args[i] = id
+	}
+	res, err := removeRoomUsersScript.Run(ctx, client, []string{key}, args...).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to remove room users: %w", err)
+	}
+	return res[0] > 0 && res[1] == 0, res[1], nil
+}
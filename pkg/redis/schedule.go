@@ -0,0 +1,193 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// ScheduleDueKey 定时推送到期时间索引（ZSet: schedule_id -> deliver_at 毫秒）
+	ScheduleDueKey = "push_schedule_due"
+
+	// ScheduleDataKey 定时推送内容（Hash: schedule_id -> Schedule JSON）
+	ScheduleDataKey = "push_schedules"
+
+	// ScheduleSeqKey 定时推送 ID 序列
+	ScheduleSeqKey = "push_schedule_seq"
+)
+
+// Schedule 一条定时推送
+type Schedule struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`       // broadcast / room / user
+	Payload   []byte `json:"payload"`    // 序列化后的 PushServer 请求
+	DeliverAt int64  `json:"deliver_at"` // Unix 毫秒
+	CreatedAt int64  `json:"created_at"` // Unix 毫秒
+}
+
+// ScheduleStore Redis 定时推送存储（多个 Push-Manager 共享，到期时通过租约抢占，投递完成后删除）
+type ScheduleStore struct {
+	client *redis.Client
+}
+
+// NewScheduleStore 创建定时推送存储
+func NewScheduleStore(client *redis.Client) *ScheduleStore {
+	return &ScheduleStore{client: client}
+}
+
+// Save 保存定时推送
+func (s *ScheduleStore) Save(ctx context.Context, schedule *Schedule) error {
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, ScheduleDataKey, schedule.ID, data)
+	pipe.ZAdd(ctx, ScheduleDueKey, redis.Z{Score: float64(schedule.DeliverAt), Member: schedule.ID})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save schedule: %w", err)
+	}
+	return nil
+}
+
+// claimScript 原子地抢占到期计划：到期时间不晚于 now 时把到期时间推后到租约到期（其他 Push-Manager 不再抢到），
+// 返回内容；内容已不存在时顺便清理索引
+var claimScript = redis.NewScript(`
+local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if not score or tonumber(score) > tonumber(ARGV[2]) then
+	return false
+end
+local data = redis.call("HGET", KEYS[2], ARGV[1])
+if not data then
+	redis.call("ZREM", KEYS[1], ARGV[1])
+	return false
+end
+redis.call("ZADD", KEYS[1], ARGV[3], ARGV[1])
+return data
+`)
+
+// Claim 到期时抢占定时推送：抢占成功的 Push-Manager 获得 lease 时长的租约并返回内容，其他返回 nil。
+// 投递完成后调用 Complete 删除计划；投递失败或进程退出时计划保留，租约到期后可被再次抢占
+func (s *ScheduleStore) Claim(ctx context.Context, id string, lease time.Duration) (*Schedule, error) {
+	now := time.Now()
+	data, err := claimScript.Run(ctx, s.client, []string{ScheduleDueKey, ScheduleDataKey},
+		id, now.UnixMilli(), now.Add(lease).UnixMilli()).Text()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim schedule: %w", err)
+	}
+
+	var schedule Schedule
+	if err := json.Unmarshal([]byte(data), &schedule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// Complete 删除已投递（或无法投递）的计划
+func (s *ScheduleStore) Complete(ctx context.Context, id string) error {
+	pipe := s.client.TxPipeline()
+	pipe.ZRem(ctx, ScheduleDueKey, id)
+	pipe.HDel(ctx, ScheduleDataKey, id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to complete schedule: %w", err)
+	}
+	return nil
+}
+
+// Cancel 取消定时推送，返回是否取消成功（不存在或已投递时为 false）
+func (s *ScheduleStore) Cancel(ctx context.Context, id string) (bool, error) {
+	removed, err := s.client.ZRem(ctx, ScheduleDueKey, id).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel schedule: %w", err)
+	}
+	if removed == 0 {
+		return false, nil
+	}
+	if err := s.client.HDel(ctx, ScheduleDataKey, id).Err(); err != nil {
+		return true, fmt.Errorf("failed to delete schedule: %w", err)
+	}
+	return true, nil
+}
+
+// List 按投递时间升序列出待投递的定时推送，limit <= 0 表示全部
+func (s *ScheduleStore) List(ctx context.Context, limit int64) ([]*Schedule, error) {
+	stop := int64(-1)
+	if limit > 0 {
+		stop = limit - 1
+	}
+	ids, err := s.client.ZRange(ctx, ScheduleDueKey, 0, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	values, err := s.client.HMGet(ctx, ScheduleDataKey, ids...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedules: %w", err)
+	}
+
+	schedules := make([]*Schedule, 0, len(values))
+	for _, v := range values {
+		data, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var schedule Schedule
+		if json.Unmarshal([]byte(data), &schedule) == nil {
+			schedules = append(schedules, &schedule)
+		}
+	}
+	return schedules, nil
+}
+
+// DueIDs 全部待投递计划的 ID 及投递时间（Push-Manager 启动和周期同步时加载到本地定时器）
+func (s *ScheduleStore) DueIDs(ctx context.Context) (map[string]int64, error) {
+	entries, err := s.client.ZRangeWithScores(ctx, ScheduleDueKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schedules: %w", err)
+	}
+
+	due := make(map[string]int64, len(entries))
+	for _, e := range entries {
+		id, ok := e.Member.(string)
+		if !ok {
+			continue
+		}
+		due[id] = int64(e.Score)
+	}
+	return due, nil
+}
+
+// NextID 生成定时推送 ID
+func (s *ScheduleStore) NextID(ctx context.Context) (string, error) {
+	seq, err := s.client.Incr(ctx, ScheduleSeqKey).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate schedule id: %w", err)
+	}
+	return "sch-" + strconv.FormatInt(seq, 10), nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// UsagePrefix 租户每天的用量（Hash: 计数项 -> 当天合计，计数项:HH -> 该小时合计），按 UTC 日期分 key
+	UsagePrefix = "usage:"
+
+	// UsageConnPrefix 每个 Connect-Node 上报的租户连接数快照（Hash: appID -> 连接数，节点停止上报后过期）
+	UsageConnPrefix = "usage_conns:"
+
+	// UsageConnNodesKey 上报过连接数快照的节点（Set）
+	UsageConnNodesKey = "usage_conn_nodes"
+
+	// UsageRetention 用量保留时间
+	UsageRetention = 90 * 24 * time.Hour
+)
+
+// 用量计数项
+const (
+	UsagePublished           = "published"
+	UsageDelivered           = "delivered"
+	UsageBytesOut            = "bytes_out"
+	UsagePeakConnections     = "peak_connections"
+	UsageRejectedPublishes   = "rejected_publishes"
+	UsageRejectedConnections = "rejected_connections"
+)
+
+// UsageDelta 一段时间内累积的用量增量
+type UsageDelta struct {
+	Delivered           int64
+	BytesOut            int64
+	RejectedConnections int64
+}
+
+// UsageStore Redis 租户用量（Connect-Node 和 Push-Manager 写入，Controller 汇总报表）
+type UsageStore struct {
+	client *redis.Client
+}
+
+// NewUsageStore 创建用量存储
+func NewUsageStore(client *redis.Client) *UsageStore {
+	return &UsageStore{client: client}
+}
+
+// UsageDay 用量 key 的日期（UTC）
+func UsageDay(t time.Time) string {
+	return t.UTC().Format("20060102")
+}
+
+func usageKey(appID string, t time.Time) string {
+	return UsagePrefix + appID + ":" + UsageDay(t)
+}
+
+func hourField(field string, t time.Time) string {
+	return fmt.Sprintf("%s:%02d", field, t.UTC().Hour())
+}
+
+// publishScript 计入一次推送：当天合计超过硬限制（ARGV[2] > 0）时撤回并计为拒绝。
+// 返回 {当天合计, 是否拒绝}
+var publishScript = redis.NewScript(`
+local n = redis.call("HINCRBY", KEYS[1], "published", 1)
+local limit = tonumber(ARGV[2])
+local rejected = 0
+if limit > 0 and n > limit then
+	n = redis.call("HINCRBY", KEYS[1], "published", -1)
+	redis.call("HINCRBY", KEYS[1], "rejected_publishes", 1)
+	redis.call("HINCRBY", KEYS[1], "rejected_publishes:" .. ARGV[1], 1)
+	rejected = 1
+else
+	redis.call("HINCRBY", KEYS[1], "published:" .. ARGV[1], 1)
+end
+redis.call("EXPIRE", KEYS[1], ARGV[3])
+return {n, rejected}
+`)
+
+// IncrPublished 计入一次推送请求，返回当天（UTC）合计；maxDaily > 0 且会超过时不计入并返回 rejected=true
+func (s *UsageStore) IncrPublished(ctx context.Context, appID string, at time.Time, maxDaily int64) (count int64, rejected bool, err error) {
+	res, err := publishScript.Run(ctx, s.client, []string{usageKey(appID, at)},
+		fmt.Sprintf("%02d", at.UTC().Hour()), maxDaily, int64(UsageRetention.Seconds())).Int64Slice()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to count publish: %w", err)
+	}
+	return res[0], res[1] == 1, nil
+}
+
+// AddUsage 批量写入各租户的用量增量（计入 at 所在的天和小时）
+func (s *UsageStore) AddUsage(ctx context.Context, at time.Time, deltas map[string]*UsageDelta) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+	pipe := s.client.Pipeline()
+	for appID, d := range deltas {
+		key := usageKey(appID, at)
+		for field, n := range map[string]int64{
+			UsageDelivered:           d.Delivered,
+			UsageBytesOut:            d.BytesOut,
+			UsageRejectedConnections: d.RejectedConnections,
+		} {
+			if n == 0 {
+				continue
+			}
+			pipe.HIncrBy(ctx, key, field, n)
+			pipe.HIncrBy(ctx, key, hourField(field, at), n)
+		}
+		pipe.Expire(ctx, key, UsageRetention)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to add usage: %w", err)
+	}
+	return nil
+}
+
+// peakScript 连接数峰值取较大值（当天和当前小时）
+var peakScript = redis.NewScript(`
+for i, field in ipairs({"peak_connections", "peak_connections:" .. ARGV[1]}) do
+	local v = tonumber(redis.call("HGET", KEYS[1], field) or "0")
+	if tonumber(ARGV[2]) > v then
+		redis.call("HSET", KEYS[1], field, ARGV[2])
+	end
+end
+redis.call("EXPIRE", KEYS[1], ARGV[3])
+return 0
+`)
+
+// RecordPeakConnections 记录各租户全集群连接数的峰值
+func (s *UsageStore) RecordPeakConnections(ctx context.Context, at time.Time, conns map[string]int64) error {
+	hour := fmt.Sprintf("%02d", at.UTC().Hour())
+	for appID, n := range conns {
+		if n <= 0 {
+			continue
+		}
+		if err := peakScript.Run(ctx, s.client, []string{usageKey(appID, at)}, hour, n, int64(UsageRetention.Seconds())).Err(); err != nil {
+			return fmt.Errorf("failed to record peak connections: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetNodeConnections 上报节点当前的租户连接数快照（ttl 内未再次上报视为节点下线）
+func (s *UsageStore) SetNodeConnections(ctx context.Context, nodeID string, conns map[string]int64, ttl time.Duration) error {
+	key := UsageConnPrefix + nodeID
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, key)
+	if len(conns) > 0 {
+		values := make(map[string]interface{}, len(conns))
+		for appID, n := range conns {
+			values[appID] = n
+		}
+		pipe.HSet(ctx, key, values)
+		pipe.Expire(ctx, key, ttl)
+	}
+	pipe.SAdd(ctx, UsageConnNodesKey, nodeID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set node connections: %w", err)
+	}
+	return nil
+}
+
+// ClusterConnections 汇总全部节点的租户连接数（快照已过期的节点从集合中移除）
+func (s *UsageStore) ClusterConnections(ctx context.Context) (map[string]int64, error) {
+	nodes, err := s.client.SMembers(ctx, UsageConnNodesKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage nodes: %w", err)
+	}
+
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(nodes))
+	for i, nodeID := range nodes {
+		cmds[i] = pipe.HGetAll(ctx, UsageConnPrefix+nodeID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get node connections: %w", err)
+	}
+
+	total := make(map[string]int64)
+	var stale []interface{}
+	for i, cmd := range cmds {
+		values := cmd.Val()
+		if len(values) == 0 {
+			stale = append(stale, nodes[i])
+			continue
+		}
+		for appID, v := range values {
+			n, _ := strconv.ParseInt(v, 10, 64)
+			total[appID] += n
+		}
+	}
+	if len(stale) > 0 {
+		// 节点连接数为 0 时快照也为空，下次上报会重新加入
+		s.client.SRem(ctx, UsageConnNodesKey, stale...)
+	}
+	return total, nil
+}
+
+// DayUsage 租户某天（UTC）的用量：totals 为当天合计，hours 为各小时（0-23）的用量
+func (s *UsageStore) DayUsage(ctx context.Context, appID string, day time.Time) (totals map[string]int64, hours [24]map[string]int64, err error) {
+	values, err := s.client.HGetAll(ctx, usageKey(appID, day)).Result()
+	if err != nil {
+		return nil, hours, fmt.Errorf("failed to get usage: %w", err)
+	}
+
+	totals = make(map[string]int64)
+	for field, v := range values {
+		n, _ := strconv.ParseInt(v, 10, 64)
+		name, hour, hourly := strings.Cut(field, ":")
+		if !hourly {
+			totals[name] = n
+			continue
+		}
+		h, err := strconv.Atoi(hour)
+		if err != nil || h < 0 || h > 23 {
+			continue
+		}
+		if hours[h] == nil {
+			hours[h] = make(map[string]int64)
+		}
+		hours[h][name] = n
+	}
+	return totals, hours, nil
+}
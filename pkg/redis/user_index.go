@@ -0,0 +1,222 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// UserConnPrefix 用户 -> 连接索引（Hash: conn_id -> UserConnection JSON）
+	UserConnPrefix = "user_conns:"
+
+	// UserConnTTL 索引过期时间（每次写入刷新，防止节点异常后残留）
+	UserConnTTL = 24 * time.Hour
+
+	// userConnTxRetries 读改写冲突时的重试次数
+	userConnTxRetries = 10
+)
+
+// UserConnection 用户的一个连接
+type UserConnection struct {
+	ConnID      string   `json:"conn_id"`
+	NodeID      string   `json:"node_id"`
+	NodeAddress string   `json:"node_address"` // Connect-Node gRPC 地址
+	DeviceID    string   `json:"device_id,omitempty"`
+	DeviceType  string   `json:"device_type,omitempty"`
+	Rooms       []string `json:"rooms"`
+	ConnectedAt int64    `json:"connected_at"`
+}
+
+// UserConnStore Redis 用户连接索引
+type UserConnStore struct {
+	client *redis.Client
+}
+
+// NewUserConnStore 创建用户连接索引
+func NewUserConnStore(client *redis.Client) *UserConnStore {
+	return &UserConnStore{client: client}
+}
+
+func userConnKey(userID string) string {
+	return fmt.Sprintf("%s%s", UserConnPrefix, userID)
+}
+
+// SaveConnection 写入（覆盖）用户的一个连接
+func (s *UserConnStore) SaveConnection(ctx context.Context, userID string, conn *UserConnection) error {
+	data, err := json.Marshal(conn)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user connection: %w", err)
+	}
+
+	key := userConnKey(userID)
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, conn.ConnID, data)
+	pipe.Expire(ctx, key, UserConnTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetConnection 获取用户的一个连接，不存在返回 nil
+func (s *UserConnStore) GetConnection(ctx context.Context, userID, connID string) (*UserConnection, error) {
+	return getConnection(ctx, s.client, userConnKey(userID), connID)
+}
+
+// UpdateConnection 原子地读改写用户的一个连接（WATCH/MULTI，并发修改时重试）：
+// fn 收到当前记录（不存在时为 nil），返回要写入的记录，返回 nil 表示不修改。fn 可能被调用多次
+func (s *UserConnStore) UpdateConnection(ctx context.Context, userID, connID string, fn func(conn *UserConnection) *UserConnection) error {
+	key := userConnKey(userID)
+	txf := func(tx *redis.Tx) error {
+		conn, err := getConnection(ctx, tx, key, connID)
+		if err != nil {
+			return err
+		}
+		updated := fn(conn)
+		if updated == nil {
+			return nil
+		}
+		data, err := json.Marshal(updated)
+		if err != nil {
+			return fmt.Errorf("failed to marshal user connection: %w", err)
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, connID, data)
+			pipe.Expire(ctx, key, UserConnTTL)
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < userConnTxRetries; i++ {
+		err := s.client.Watch(ctx, txf, key)
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to update user connection: %w", redis.TxFailedErr)
+}
+
+// getConnection 读取一个连接（可在 WATCH 事务中使用）
+func getConnection(ctx context.Context, c redis.Cmdable, key, connID string) (*UserConnection, error) {
+	data, err := c.HGet(ctx, key, connID).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user connection: %w", err)
+	}
+
+	var conn UserConnection
+	if err := json.Unmarshal([]byte(data), &conn); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user connection: %w", err)
+	}
+	return &conn, nil
+}
+
+// GetConnections 获取用户的全部连接（一次 HGETALL）
+func (s *UserConnStore) GetConnections(ctx context.Context, userID string) ([]*UserConnection, error) {
+	data, err := s.client.HGetAll(ctx, userConnKey(userID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user connections: %w", err)
+	}
+
+	conns := make([]*UserConnection, 0, len(data))
+	for _, v := range data {
+		var conn UserConnection
+		if json.Unmarshal([]byte(v), &conn) == nil {
+			conns = append(conns, &conn)
+		}
+	}
+	return conns, nil
+}
+
+// AddRoom 将房间加入连接的房间列表
+func (s *UserConnStore) AddRoom(ctx context.Context, userID, connID, roomID string) error {
+	return s.updateRooms(ctx, userID, connID, func(rooms []string) []string {
+		for _, r := range rooms {
+			if r == roomID {
+				return rooms
+			}
+		}
+		return append(rooms, roomID)
+	})
+}
+
+// RemoveRoom 从连接的房间列表中移除房间（connID 为空时作用于用户的所有连接）
+func (s *UserConnStore) RemoveRoom(ctx context.Context, userID, connID, roomID string) error {
+	remove := func(rooms []string) []string {
+		out := rooms[:0]
+		for _, r := range rooms {
+			if r != roomID {
+				out = append(out, r)
+			}
+		}
+		return out
+	}
+
+	if connID != "" {
+		return s.updateRooms(ctx, userID, connID, remove)
+	}
+
+	conns, err := s.GetConnections(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, conn := range conns {
+		if err := s.updateRooms(ctx, userID, conn.ConnID, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateRooms 原子地修改连接的房间列表（连接不存在时忽略）
+func (s *UserConnStore) updateRooms(ctx context.Context, userID, connID string, fn func([]string) []string) error {
+	return s.UpdateConnection(ctx, userID, connID, func(conn *UserConnection) *UserConnection {
+		if conn == nil {
+			return nil
+		}
+		conn.Rooms = fn(append([]string(nil), conn.Rooms...))
+		return conn
+	})
+}
+
+// RemoveConnection 删除用户的一个连接
+func (s *UserConnStore) RemoveConnection(ctx context.Context, userID, connID string) error {
+	return s.client.HDel(ctx, userConnKey(userID), connID).Err()
+}
+
+// RemoveNodeConnections 删除用户在指定节点上的全部连接（节点宕机清理）
+func (s *UserConnStore) RemoveNodeConnections(ctx context.Context, userID, nodeID string) error {
+	conns, err := s.GetConnections(ctx, userID)
+	if err != nil {
+		return err
+	}
+	var fields []string
+	for _, conn := range conns {
+		if conn.NodeID == nodeID {
+			fields = append(fields, conn.ConnID)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return s.client.HDel(ctx, userConnKey(userID), fields...).Err()
+}
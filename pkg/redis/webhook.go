@@ -0,0 +1,264 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// WebhookFailureStream 投递次数耗尽的 webhook 事件
+	WebhookFailureStream = "webhook_failed"
+
+	// WebhookQueuePrefix 待投递的 webhook 事件（每个 endpoint 一个 stream: webhook_queue:{endpoint}）
+	WebhookQueuePrefix = "webhook_queue:"
+
+	// WebhookQueueGroup 待投递队列消费组（多个 Controller 副本共享，每个事件只投递一次）
+	WebhookQueueGroup = "controller"
+)
+
+// FailedWebhook 投递失败的 webhook 事件
+type FailedWebhook struct {
+	ID        string // stream entry id
+	Endpoint  string // endpoint 名称
+	EventID   string
+	EventType string
+	Payload   []byte // 事件 JSON（重放时原样投递）
+	Attempts  int
+	Error     string
+	FailedAt  int64 // 毫秒
+}
+
+// WebhookStore 基于 Redis Stream 的 webhook 失败记录（多个 Controller 副本共享）
+type WebhookStore struct {
+	client *redis.Client
+	maxLen int64
+}
+
+// NewWebhookStore 创建 webhook 失败记录，maxLen 为 stream 的近似最大长度（0 表示不裁剪）
+func NewWebhookStore(client *redis.Client, maxLen int64) *WebhookStore {
+	return &WebhookStore{client: client, maxLen: maxLen}
+}
+
+// Fail 记录一条投递失败的事件
+func (s *WebhookStore) Fail(ctx context.Context, f *FailedWebhook) error {
+	err := s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: WebhookFailureStream,
+		MaxLen: s.maxLen,
+		Approx: s.maxLen > 0,
+		Values: map[string]interface{}{
+			"endpoint":   f.Endpoint,
+			"event_id":   f.EventID,
+			"event_type": f.EventType,
+			"payload":    f.Payload,
+			"attempts":   f.Attempts,
+			"error":      f.Error,
+			"failed_at":  time.Now().UnixMilli(),
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to record webhook failure: %w", err)
+	}
+	return nil
+}
+
+// List 按 ID 顺序列出失败记录，startID 为上一页最后一条（不含），endpoint 为空表示全部。
+// 返回的 next 为空表示没有更多
+func (s *WebhookStore) List(ctx context.Context, endpoint, startID string, limit int64) ([]*FailedWebhook, string, error) {
+	start := "-"
+	if startID != "" {
+		start = "(" + startID
+	}
+
+	var failures []*FailedWebhook
+	for int64(len(failures)) < limit {
+		entries, err := s.client.XRangeN(ctx, WebhookFailureStream, start, "+", limit).Result()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list webhook failures: %w", err)
+		}
+		for _, e := range entries {
+			start = "(" + e.ID
+			f := parseFailedWebhook(e)
+			if endpoint != "" && f.Endpoint != endpoint {
+				continue
+			}
+			failures = append(failures, f)
+			if int64(len(failures)) == limit {
+				return failures, e.ID, nil
+			}
+		}
+		if int64(len(entries)) < limit {
+			return failures, "", nil
+		}
+	}
+	return failures, "", nil
+}
+
+// Get 读取一条失败记录，不存在时返回 nil
+func (s *WebhookStore) Get(ctx context.Context, id string) (*FailedWebhook, error) {
+	entries, err := s.client.XRangeN(ctx, WebhookFailureStream, id, id, 1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook failure: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return parseFailedWebhook(entries[0]), nil
+}
+
+// Delete 删除失败记录（重放后调用），返回是否删除了记录（多个副本同时重放时只有一个成功）
+func (s *WebhookStore) Delete(ctx context.Context, id string) (bool, error) {
+	n, err := s.client.XDel(ctx, WebhookFailureStream, id).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete webhook failure: %w", err)
+	}
+	return n > 0, nil
+}
+
+func parseFailedWebhook(m redis.XMessage) *FailedWebhook {
+	return &FailedWebhook{
+		ID:        m.ID,
+		Endpoint:  valueString(m.Values, "endpoint"),
+		EventID:   valueString(m.Values, "event_id"),
+		EventType: valueString(m.Values, "event_type"),
+		Payload:   []byte(valueString(m.Values, "payload")),
+		Attempts:  int(valueInt64(m.Values, "attempts")),
+		Error:     valueString(m.Values, "error"),
+		FailedAt:  valueInt64(m.Values, "failed_at"),
+	}
+}
+
+// QueuedWebhook 待投递队列中的一个事件
+type QueuedWebhook struct {
+	ID        string // stream entry id
+	EventID   string
+	EventType string
+	Payload   []byte // 事件 JSON
+}
+
+// WebhookQueue 基于 Redis Stream 的 webhook 待投递队列：Controller 重启时未投递完的事件保留，
+// 由重启后的实例或其他副本认领后继续投递
+type WebhookQueue struct {
+	client *redis.Client
+	maxLen int64
+}
+
+// NewWebhookQueue 创建 webhook 待投递队列，maxLen 为每个 stream 的近似最大长度（0 表示不裁剪）
+func NewWebhookQueue(client *redis.Client, maxLen int64) *WebhookQueue {
+	return &WebhookQueue{client: client, maxLen: maxLen}
+}
+
+func webhookQueueStream(endpoint string) string {
+	return WebhookQueuePrefix + endpoint
+}
+
+// EnsureGroup 创建 endpoint 的待投递 stream 及消费组（已存在时忽略）
+func (q *WebhookQueue) EnsureGroup(ctx context.Context, endpoint string) error {
+	err := q.client.XGroupCreateMkStream(ctx, webhookQueueStream(endpoint), WebhookQueueGroup, "0").Err()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create webhook queue group: %w", err)
+	}
+	return nil
+}
+
+// Enqueue 追加一个待投递事件
+func (q *WebhookQueue) Enqueue(ctx context.Context, endpoint, eventID, eventType string, payload []byte) error {
+	err := q.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: webhookQueueStream(endpoint),
+		MaxLen: q.maxLen,
+		Approx: q.maxLen > 0,
+		Values: map[string]interface{}{
+			"event_id":   eventID,
+			"event_type": eventType,
+			"payload":    payload,
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to enqueue webhook: %w", err)
+	}
+	return nil
+}
+
+// Read 以 consumer 身份读取新事件，block 超时返回空
+func (q *WebhookQueue) Read(ctx context.Context, endpoint, consumer string, count int64, block time.Duration) ([]*QueuedWebhook, error) {
+	streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    WebhookQueueGroup,
+		Consumer: consumer,
+		Streams:  []string{webhookQueueStream(endpoint), ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook queue: %w", err)
+	}
+
+	var queued []*QueuedWebhook
+	for _, stream := range streams {
+		for _, m := range stream.Messages {
+			queued = append(queued, parseQueuedWebhook(m))
+		}
+	}
+	return queued, nil
+}
+
+// Claim 认领空闲超过 minIdle 的未确认事件（投递该事件的 Controller 已崩溃或重启）
+func (q *WebhookQueue) Claim(ctx context.Context, endpoint, consumer string, minIdle time.Duration, count int64) ([]*QueuedWebhook, error) {
+	claimed, _, err := q.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   webhookQueueStream(endpoint),
+		Group:    WebhookQueueGroup,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    "0-0",
+		Count:    count,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhooks: %w", err)
+	}
+	queued := make([]*QueuedWebhook, 0, len(claimed))
+	for _, m := range claimed {
+		queued = append(queued, parseQueuedWebhook(m))
+	}
+	return queued, nil
+}
+
+// Ack 确认并删除已处理（投递成功或已记入失败记录）的事件
+func (q *WebhookQueue) Ack(ctx context.Context, endpoint, id string) error {
+	stream := webhookQueueStream(endpoint)
+	pipe := q.client.TxPipeline()
+	pipe.XAck(ctx, stream, WebhookQueueGroup, id)
+	pipe.XDel(ctx, stream, id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to ack webhook: %w", err)
+	}
+	return nil
+}
+
+func parseQueuedWebhook(m redis.XMessage) *QueuedWebhook {
+	return &QueuedWebhook{
+		ID:        m.ID,
+		EventID:   valueString(m.Values, "event_id"),
+		EventType: valueString(m.Values, "event_type"),
+		Payload:   []byte(valueString(m.Values, "payload")),
+	}
+}
@@ -0,0 +1,14 @@
+package pkg
+
+import "strings"
+
+// SplitList 拆分逗号分隔的列表（去掉首尾空白，忽略空项）
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,205 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+)
+
+const (
+	// TenantSeparator 租户 ID 与租户内 ID 的分隔符：租户 app1 的房间 room-1 在集群内（MySQL、Redis、bucket）为 app1:room-1
+	TenantSeparator = ":"
+	// MaxAppIDLen 租户 ID 最大长度
+	MaxAppIDLen = 32
+	// TenantActive / TenantDisabled 租户状态
+	TenantActive   = "active"
+	TenantDisabled = "disabled"
+	// APIKeyPrefix API key 明文前缀
+	APIKeyPrefix = "psk_"
+	// TenantCacheSize / TenantCacheTTL 接入层租户和 API key 查询缓存
+	TenantCacheSize = 10000
+	TenantCacheTTL  = 30 * time.Second
+)
+
+var appIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateAppID 校验租户 ID：小写字母、数字、"-"、"_"，不超过 MaxAppIDLen
+func ValidateAppID(appID string) error {
+	if len(appID) > MaxAppIDLen || !appIDPattern.MatchString(appID) {
+		return ErrAppIDInvalid
+	}
+	return nil
+}
+
+// ValidateLocalID 校验租户内的房间/用户 ID：不能包含分隔符，避免拼出其他租户的 ID
+func ValidateLocalID(id string) error {
+	if strings.Contains(id, TenantSeparator) {
+		return ErrTenantIDInvalid
+	}
+	return nil
+}
+
+// ScopeID 租户内 ID 转为集群内 ID，默认租户（appID 为空）不加前缀
+func ScopeID(appID, id string) string {
+	if appID == "" || id == "" {
+		return id
+	}
+	return appID + TenantSeparator + id
+}
+
+// ScopeIDs 批量转换，返回新切片
+func ScopeIDs(appID string, ids []string) []string {
+	scoped := make([]string, len(ids))
+	for i, id := range ids {
+		scoped[i] = ScopeID(appID, id)
+	}
+	return scoped
+}
+
+// UnscopeID 集群内 ID 转回租户内 ID（不属于该租户的 ID 原样返回）
+func UnscopeID(appID, id string) string {
+	if appID == "" {
+		return id
+	}
+	return strings.TrimPrefix(id, appID+TenantSeparator)
+}
+
+// TenantOf 集群内 ID 所属的租户，默认租户返回空字符串
+func TenantOf(id string) string {
+	appID, _, found := strings.Cut(id, TenantSeparator)
+	if !found {
+		return ""
+	}
+	return appID
+}
+
+// GenerateAPIKey 生成 API key：keyID 用于展示和吊销，key 为明文（只在创建时返回）
+func GenerateAPIKey() (keyID, key string, err error) {
+	buf := make([]byte, 36)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	keyID = hex.EncodeToString(buf[:4])
+	return keyID, APIKeyPrefix + keyID + "_" + base64.RawURLEncoding.EncodeToString(buf[4:]), nil
+}
+
+// HashAPIKey API key 的存储和查询形式 hex(SHA-256(key))
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// TenantResolver 经 Controller 查询租户和 API key，结果（包括不存在）在本地缓存 ttl；
+// 租户停用、API key 吊销最迟 ttl 后生效
+type TenantResolver struct {
+	client  controller.ControllerServiceClient
+	tenants *LRU // appID -> Tenant（空值表示不存在）
+	keys    *LRU // key 哈希 -> Tenant（空值表示不存在或已吊销）
+}
+
+// NewTenantResolver 创建租户查询缓存
+func NewTenantResolver(client controller.ControllerServiceClient, size int, ttl time.Duration) *TenantResolver {
+	return &TenantResolver{
+		client:  client,
+		tenants: NewLRU(size, ttl),
+		keys:    NewLRU(size, ttl),
+	}
+}
+
+// Tenant 查询租户：默认租户（appID 为空）返回 nil；不存在返回 ErrTenantNotFound，停用返回 ErrTenantDisabled
+func (r *TenantResolver) Tenant(ctx context.Context, appID string) (*controller.Tenant, error) {
+	if appID == "" {
+		return nil, nil
+	}
+	if ValidateAppID(appID) != nil {
+		return nil, ErrTenantNotFound
+	}
+	if data, ok := r.tenants.Get(appID); ok {
+		return decodeTenant(data)
+	}
+
+	resp, err := r.client.GetTenant(ctx, &controller.GetTenantRequest{AppId: appID})
+	if err != nil {
+		return nil, err
+	}
+	var tenant *controller.Tenant
+	if resp.Found {
+		tenant = resp.Tenant
+	}
+	data := encodeTenant(tenant)
+	r.tenants.Set(appID, data)
+	return decodeTenant(data)
+}
+
+// ResolveAPIKey 查询 API key 所属租户：key 不存在或已吊销返回 ErrAPIKeyInvalid，租户停用返回 ErrTenantDisabled
+func (r *TenantResolver) ResolveAPIKey(ctx context.Context, key string) (*controller.Tenant, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, ErrAPIKeyInvalid
+	}
+	hash := HashAPIKey(key)
+	data, ok := r.keys.Get(hash)
+	if !ok {
+		resp, err := r.client.ResolveAPIKey(ctx, &controller.ResolveAPIKeyRequest{KeyHash: hash})
+		if err != nil {
+			return nil, err
+		}
+		var tenant *controller.Tenant
+		if resp.Found {
+			tenant = resp.Tenant
+		}
+		data = encodeTenant(tenant)
+		r.keys.Set(hash, data)
+	}
+
+	tenant, err := decodeTenant(data)
+	if err == ErrTenantNotFound {
+		return nil, ErrAPIKeyInvalid
+	}
+	return tenant, err
+}
+
+func encodeTenant(tenant *controller.Tenant) []byte {
+	if tenant == nil {
+		return []byte{}
+	}
+	// app_id 必填，序列化结果不会为空
+	data, _ := proto.Marshal(tenant)
+	return data
+}
+
+func decodeTenant(data []byte) (*controller.Tenant, error) {
+	if len(data) == 0 {
+		return nil, ErrTenantNotFound
+	}
+	tenant := &controller.Tenant{}
+	if err := proto.Unmarshal(data, tenant); err != nil {
+		return nil, err
+	}
+	if tenant.Status == TenantDisabled {
+		return nil, ErrTenantDisabled
+	}
+	return tenant, nil
+}
@@ -0,0 +1,74 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScopeID(t *testing.T) {
+	tests := []struct {
+		appID, id, scoped string
+	}{
+		{"app1", "room-1", "app1:room-1"},
+		{"", "room-1", "room-1"},
+		{"app1", "", ""},
+	}
+	for _, tt := range tests {
+		if got := ScopeID(tt.appID, tt.id); got != tt.scoped {
+			t.Errorf("ScopeID(%q, %q) = %q, want %q", tt.appID, tt.id, got, tt.scoped)
+		}
+		if got := UnscopeID(tt.appID, tt.scoped); got != tt.id {
+			t.Errorf("UnscopeID(%q, %q) = %q, want %q", tt.appID, tt.scoped, got, tt.id)
+		}
+		if tt.id != "" {
+			if got := TenantOf(tt.scoped); got != tt.appID {
+				t.Errorf("TenantOf(%q) = %q, want %q", tt.scoped, got, tt.appID)
+			}
+		}
+	}
+
+	// 不属于该租户的 ID 原样返回
+	if got := UnscopeID("app1", "app2:room-1"); got != "app2:room-1" {
+		t.Errorf("UnscopeID() of another tenant = %q", got)
+	}
+	if got := ScopeIDs("app1", []string{"a", "b"}); strings.Join(got, ",") != "app1:a,app1:b" {
+		t.Errorf("ScopeIDs() = %v", got)
+	}
+}
+
+func TestValidateIDs(t *testing.T) {
+	for id, ok := range map[string]bool{"room-1": true, "": true, "app1:room-1": false} {
+		if err := ValidateLocalID(id); (err == nil) != ok {
+			t.Errorf("ValidateLocalID(%q) = %v, want ok=%v", id, err, ok)
+		}
+	}
+	appIDs := map[string]bool{
+		"app1":                             true,
+		"my_app-2":                         true,
+		"":                                 false,
+		"App1":                             false,
+		"-app":                             false,
+		"app:1":                            false,
+		strings.Repeat("a", MaxAppIDLen):   true,
+		strings.Repeat("a", MaxAppIDLen+1): false,
+	}
+	for appID, ok := range appIDs {
+		if err := ValidateAppID(appID); (err == nil) != ok {
+			t.Errorf("ValidateAppID(%q) = %v, want ok=%v", appID, err, ok)
+		}
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	keyID, key, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(key, APIKeyPrefix+keyID+"_") || len(keyID) != 8 {
+		t.Errorf("GenerateAPIKey() = %q, %q", keyID, key)
+	}
+	_, other, _ := GenerateAPIKey()
+	if other == key || HashAPIKey(key) == HashAPIKey(other) {
+		t.Error("GenerateAPIKey() keys or hashes are not distinct")
+	}
+}
@@ -0,0 +1,62 @@
+package pkg
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	// ClientTokenPrefix 客户端连接令牌前缀
+	ClientTokenPrefix = "pct_"
+	// MaxClientTokenTTL 客户端连接令牌最长有效期
+	MaxClientTokenTTL = 24 * time.Hour
+)
+
+// ClientTokenClaims 客户端连接令牌内容：由 Web 按 API key 所属租户签发，Connect-Node 鉴权时校验
+type ClientTokenClaims struct {
+	AppID     string `json:"app_id"`
+	UserID    string `json:"user_id"`           // 租户内用户 ID
+	RoomID    string `json:"room_id,omitempty"` // 为空时可加入任意房间
+	ExpiresAt int64  `json:"exp"`               // Unix 秒
+}
+
+// SignClientToken 签发客户端连接令牌，格式 pct_<base64url(claims)>.<base64url(HMAC-SHA256(secret, claims))>
+func SignClientToken(secret string, claims ClientTokenClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return ClientTokenPrefix + encoded + "." + clientTokenMAC(secret, encoded), nil
+}
+
+// VerifyClientToken 校验签名和有效期，返回令牌内容
+func VerifyClientToken(secret, token string) (*ClientTokenClaims, error) {
+	encoded, sig, ok := strings.Cut(strings.TrimPrefix(token, ClientTokenPrefix), ".")
+	if secret == "" || !ok || !strings.HasPrefix(token, ClientTokenPrefix) ||
+		!hmac.Equal([]byte(sig), []byte(clientTokenMAC(secret, encoded))) {
+		return nil, ErrClientTokenInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrClientTokenInvalid
+	}
+	claims := &ClientTokenClaims{}
+	if json.Unmarshal(payload, claims) != nil || claims.UserID == "" {
+		return nil, ErrClientTokenInvalid
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrClientTokenExpired
+	}
+	return claims, nil
+}
+
+func clientTokenMAC(secret, encoded string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientToken(t *testing.T) {
+	claims := ClientTokenClaims{AppID: "app1", UserID: "alice", RoomID: "room-1", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+	token, err := SignClientToken("s3cret", claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := VerifyClientToken("s3cret", token)
+	if err != nil || *got != claims {
+		t.Fatalf("VerifyClientToken() = %+v, %v, want %+v", got, err, claims)
+	}
+
+	expired, _ := SignClientToken("s3cret", ClientTokenClaims{UserID: "alice", ExpiresAt: time.Now().Unix() - 1})
+	noUser, _ := SignClientToken("s3cret", ClientTokenClaims{AppID: "app1", ExpiresAt: claims.ExpiresAt})
+	payload, sig, _ := strings.Cut(strings.TrimPrefix(token, ClientTokenPrefix), ".")
+	other, _ := SignClientToken("s3cret", ClientTokenClaims{AppID: "app2", UserID: "alice", ExpiresAt: claims.ExpiresAt})
+	otherPayload, _, _ := strings.Cut(strings.TrimPrefix(other, ClientTokenPrefix), ".")
+
+	tests := []struct {
+		name   string
+		secret string
+		token  string
+		want   error
+	}{
+		{"wrong secret", "other", token, ErrClientTokenInvalid},
+		{"empty secret", "", token, ErrClientTokenInvalid},
+		{"expired", "s3cret", expired, ErrClientTokenExpired},
+		{"missing user", "s3cret", noUser, ErrClientTokenInvalid},
+		{"missing prefix", "s3cret", payload + "." + sig, ErrClientTokenInvalid},
+		{"missing signature", "s3cret", ClientTokenPrefix + payload, ErrClientTokenInvalid},
+		{"swapped payload", "s3cret", ClientTokenPrefix + otherPayload + "." + sig, ErrClientTokenInvalid},
+		{"api key", "s3cret", APIKeyPrefix + "abc", ErrClientTokenInvalid},
+	}
+	for _, tt := range tests {
+		if _, err := VerifyClientToken(tt.secret, tt.token); !errors.Is(err, tt.want) {
+			t.Errorf("%s: VerifyClientToken() = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
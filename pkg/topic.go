@@ -0,0 +1,47 @@
+package pkg
+
+import "strings"
+
+const (
+	// TopicSeparator 主题分段分隔符
+	TopicSeparator = "."
+	// TopicWildcardOne 订阅模式中匹配任意一段
+	TopicWildcardOne = "*"
+	// TopicWildcardAll 订阅模式中匹配零或多段（只能是最后一段）
+	TopicWildcardAll = "#"
+	// MaxTopicLen 主题或订阅模式的最大长度
+	MaxTopicLen = 256
+)
+
+// ValidateTopic 校验发布主题：非空分段，不能包含通配符
+func ValidateTopic(topic string) error {
+	if topic == "" || len(topic) > MaxTopicLen {
+		return ErrTopicInvalid
+	}
+	for _, seg := range strings.Split(topic, TopicSeparator) {
+		if seg == "" || seg == TopicWildcardOne || seg == TopicWildcardAll {
+			return ErrTopicInvalid
+		}
+	}
+	return nil
+}
+
+// ValidateTopicPattern 校验订阅模式：通配符必须是完整分段，"#" 只能在末尾
+func ValidateTopicPattern(pattern string) error {
+	if pattern == "" || len(pattern) > MaxTopicLen {
+		return ErrTopicPatternInvalid
+	}
+	segs := strings.Split(pattern, TopicSeparator)
+	for i, seg := range segs {
+		if seg == "" {
+			return ErrTopicPatternInvalid
+		}
+		if seg == TopicWildcardAll && i != len(segs)-1 {
+			return ErrTopicPatternInvalid
+		}
+		if seg != TopicWildcardOne && seg != TopicWildcardAll && strings.ContainsAny(seg, TopicWildcardOne+TopicWildcardAll) {
+			return ErrTopicPatternInvalid
+		}
+	}
+	return nil
+}
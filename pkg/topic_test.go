@@ -0,0 +1,51 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTopic(t *testing.T) {
+	tests := []struct {
+		topic string
+		ok    bool
+	}{
+		{"stock", true},
+		{"stock.AAPL.price", true},
+		{"", false},
+		{"stock..price", false},
+		{".stock", false},
+		{"stock.*", false},
+		{"stock.#", false},
+		{strings.Repeat("a", MaxTopicLen), true},
+		{strings.Repeat("a", MaxTopicLen+1), false},
+	}
+	for _, tt := range tests {
+		if err := ValidateTopic(tt.topic); (err == nil) != tt.ok {
+			t.Errorf("ValidateTopic(%q) = %v, want ok=%v", tt.topic, err, tt.ok)
+		}
+	}
+}
+
+func TestValidateTopicPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		ok      bool
+	}{
+		{"stock.AAPL.price", true},
+		{"stock.*.price", true},
+		{"stock.#", true},
+		{"#", true},
+		{"*", true},
+		{"", false},
+		{"stock..price", false},
+		{"stock.#.price", false},
+		{"stock.AA*", false},
+		{"stock.#x", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateTopicPattern(tt.pattern); (err == nil) != tt.ok {
+			t.Errorf("ValidateTopicPattern(%q) = %v, want ok=%v", tt.pattern, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// 房间加入方式
+const (
+	JoinModeOpen       = "open"        // 任何人可加入
+	JoinModeInviteOnly = "invite_only" // 仅受邀用户可加入
+	JoinModePassword   = "password"    // 需要房间密码
+)
+
+// 房间发言权限
+const (
+	PublishAnyone = "anyone" // 房间内所有成员可发言
+	PublishOwner  = "owner"  // 仅房主可发言
+	PublishNone   = "none"   // 禁止上行发言，只接受服务端推送
+)
+
+// ValidJoinMode 检查加入方式是否合法
+func ValidJoinMode(mode string) bool {
+	switch mode {
+	case JoinModeOpen, JoinModeInviteOnly, JoinModePassword:
+		return true
+	}
+	return false
+}
+
+// ValidPublishPolicy 检查发言权限是否合法
+func ValidPublishPolicy(policy string) bool {
+	switch policy {
+	case PublishAnyone, PublishOwner, PublishNone:
+		return true
+	}
+	return false
+}
+
+// CanPublish 根据房间发言权限和用户角色判断用户是否可以发言
+func CanPublish(policy, role string) bool {
+	if !HasPermission(role, PermissionPublish) {
+		return false
+	}
+	switch policy {
+	case "", PublishAnyone:
+		return true
+	case PublishOwner:
+		return role == RoleOwner
+	default:
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// 房间成员角色
+const (
+	RoleOwner     = "owner"     // 房主
+	RoleModerator = "moderator" // 管理员
+	RoleMember    = "member"    // 普通成员
+	RoleViewer    = "viewer"    // 观众（只读）
+)
+
+// 房间操作权限
+const (
+	PermissionPublish  = "publish"  // 上行发言
+	PermissionInvite   = "invite"   // 邀请用户
+	PermissionKick     = "kick"     // 踢出用户
+	PermissionSettings = "settings" // 修改房间策略和成员角色
+)
+
+// rolePermissions 角色 -> 权限列表
+var rolePermissions = map[string]map[string]bool{
+	RoleOwner: {
+		PermissionPublish:  true,
+		PermissionInvite:   true,
+		PermissionKick:     true,
+		PermissionSettings: true,
+	},
+	RoleModerator: {
+		PermissionPublish: true,
+		PermissionInvite:  true,
+		PermissionKick:    true,
+	},
+	RoleMember: {
+		PermissionPublish: true,
+	},
+	RoleViewer: {},
+}
+
+// roleRank 角色等级，用于判断能否管理其他成员
+var roleRank = map[string]int{
+	RoleOwner:     4,
+	RoleModerator: 3,
+	RoleMember:    2,
+	RoleViewer:    1,
+}
+
+// ValidRole 检查角色是否合法
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// ValidPermission 检查权限名是否合法
+func ValidPermission(permission string) bool {
+	switch permission {
+	case PermissionPublish, PermissionInvite, PermissionKick, PermissionSettings:
+		return true
+	}
+	return false
+}
+
+// HasPermission 判断角色是否拥有权限（未知角色没有任何权限）
+func HasPermission(role, permission string) bool {
+	return rolePermissions[role][permission]
+}
+
+// Outranks 判断 role 的等级是否高于 other（只能管理比自己等级低的成员）
+func Outranks(role, other string) bool {
+	return roleRank[role] > roleRank[other]
+}
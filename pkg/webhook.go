@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// WebhookSignatureHeader 签名头，格式 t=<Unix 秒>,v1=<hex(HMAC-SHA256(secret, "<t>.<body>"))>
+	WebhookSignatureHeader = "X-Pubsub-Signature"
+	// WebhookEventHeader 事件类型头
+	WebhookEventHeader = "X-Pubsub-Event"
+	// WebhookDeliveryHeader 事件 ID 头（重试和重放时不变，接收方可据此去重）
+	WebhookDeliveryHeader = "X-Pubsub-Delivery"
+)
+
+// SignWebhook 计算 webhook 签名头的值
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+	return "t=" + ts + ",v1=" + webhookMAC(secret, ts, body)
+}
+
+// VerifyWebhook 校验 webhook 签名头，tolerance > 0 时拒绝时间戳偏差超过 tolerance 的请求（防重放）
+func VerifyWebhook(secret, header string, body []byte, tolerance time.Duration) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig = v
+		}
+	}
+	t, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return ErrWebhookSignature
+	}
+	if tolerance > 0 {
+		if d := time.Since(time.Unix(t, 0)); d > tolerance || d < -tolerance {
+			return ErrWebhookTimestamp
+		}
+	}
+	if !hmac.Equal([]byte(sig), []byte(webhookMAC(secret, ts, body))) {
+		return ErrWebhookSignature
+	}
+	return nil
+}
+
+// MatchEventType 事件类型是否匹配过滤条件：filters 为空或包含 "*" 时匹配全部，"room.*" 匹配前缀
+func MatchEventType(filters []string, eventType string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, f := range filters {
+		if f == "*" || f == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(f, "*"); ok && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookMAC(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(`{"type":"room.joined","room_id":"room-001"}`)
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name      string
+		secret    string
+		header    string
+		body      []byte
+		tolerance time.Duration
+		want      error
+	}{
+		{"valid", "s3cret", SignWebhook("s3cret", now, body), body, 5 * time.Minute, nil},
+		{"valid without tolerance", "s3cret", SignWebhook("s3cret", now-3600, body), body, 0, nil},
+		{"spaces between parts", "s3cret", "t=" + strconv.FormatInt(now, 10) + ", v1=" + webhookMAC("s3cret", strconv.FormatInt(now, 10), body), body, time.Minute, nil},
+		{"wrong secret", "other", SignWebhook("s3cret", now, body), body, time.Minute, ErrWebhookSignature},
+		{"tampered body", "s3cret", SignWebhook("s3cret", now, body), []byte(`{}`), time.Minute, ErrWebhookSignature},
+		{"stale timestamp", "s3cret", SignWebhook("s3cret", now-3600, body), body, 5 * time.Minute, ErrWebhookTimestamp},
+		{"future timestamp", "s3cret", SignWebhook("s3cret", now+3600, body), body, 5 * time.Minute, ErrWebhookTimestamp},
+		{"missing signature", "s3cret", "t=" + strconv.FormatInt(now, 10), body, time.Minute, ErrWebhookSignature},
+		{"missing timestamp", "s3cret", "v1=" + webhookMAC("s3cret", strconv.FormatInt(now, 10), body), body, time.Minute, ErrWebhookSignature},
+		{"empty header", "s3cret", "", body, time.Minute, ErrWebhookSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyWebhook(tt.secret, tt.header, tt.body, tt.tolerance); !errors.Is(err, tt.want) {
+				t.Fatalf("VerifyWebhook() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchEventType(t *testing.T) {
+	tests := []struct {
+		filters   []string
+		eventType string
+		want      bool
+	}{
+		{nil, "room.joined", true},
+		{[]string{"*"}, "node.down", true},
+		{[]string{"room.joined"}, "room.joined", true},
+		{[]string{"room.joined"}, "room.left", false},
+		{[]string{"room.*"}, "room.left", true},
+		{[]string{"room.*"}, "user.connected", false},
+		{[]string{"user.*", "node.down"}, "node.down", true},
+		{[]string{"user.*", "node.down"}, "node.up", false},
+		{[]string{"room."}, "room.joined", false},
+	}
+	for _, tt := range tests {
+		if got := MatchEventType(tt.filters, tt.eventType); got != tt.want {
+			t.Errorf("MatchEventType(%v, %q) = %v, want %v", tt.filters, tt.eventType, got, tt.want)
+		}
+	}
+}
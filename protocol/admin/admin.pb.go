@@ -0,0 +1,1620 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.0
+// source: admin.proto
+
+package admin
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListNodesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IncludeOffline bool                   `protobuf:"varint,1,opt,name=include_offline,json=includeOffline,proto3" json:"include_offline,omitempty"` // 包含 unhealthy / offline 节点
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListNodesRequest) Reset() {
+	*x = ListNodesRequest{}
+	mi := &file_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNodesRequest) ProtoMessage() {}
+
+func (x *ListNodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNodesRequest.ProtoReflect.Descriptor instead.
+func (*ListNodesRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListNodesRequest) GetIncludeOffline() bool {
+	if x != nil {
+		return x.IncludeOffline
+	}
+	return false
+}
+
+type ListNodesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConnectNodes  []*ConnectNodeInfo     `protobuf:"bytes,1,rep,name=connect_nodes,json=connectNodes,proto3" json:"connect_nodes,omitempty"`
+	PushManagers  []*PushManagerStatus   `protobuf:"bytes,2,rep,name=push_managers,json=pushManagers,proto3" json:"push_managers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNodesResponse) Reset() {
+	*x = ListNodesResponse{}
+	mi := &file_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNodesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNodesResponse) ProtoMessage() {}
+
+func (x *ListNodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNodesResponse.ProtoReflect.Descriptor instead.
+func (*ListNodesResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListNodesResponse) GetConnectNodes() []*ConnectNodeInfo {
+	if x != nil {
+		return x.ConnectNodes
+	}
+	return nil
+}
+
+func (x *ListNodesResponse) GetPushManagers() []*PushManagerStatus {
+	if x != nil {
+		return x.PushManagers
+	}
+	return nil
+}
+
+// ConnectNodeInfo Connect-Node 负载：心跳字段来自 Controller 节点表，status 字段为实时查询结果
+type ConnectNodeInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	NodeId         string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Address        string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Region         string                 `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	Status         string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // online, unhealthy, offline（心跳状态）
+	MaxConnections int32                  `protobuf:"varint,5,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
+	Connections    int32                  `protobuf:"varint,6,opt,name=connections,proto3" json:"connections,omitempty"` // 最近一次心跳上报的连接数
+	Rooms          int32                  `protobuf:"varint,7,opt,name=rooms,proto3" json:"rooms,omitempty"`
+	CpuUsage       float32                `protobuf:"fixed32,8,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`                // 百分比
+	MemoryUsage    float32                `protobuf:"fixed32,9,opt,name=memory_usage,json=memoryUsage,proto3" json:"memory_usage,omitempty"`       // MB
+	LastHeartbeat  int64                  `protobuf:"varint,10,opt,name=last_heartbeat,json=lastHeartbeat,proto3" json:"last_heartbeat,omitempty"` // Unix 秒
+	Reachable      bool                   `protobuf:"varint,11,opt,name=reachable,proto3" json:"reachable,omitempty"`                              // 实时查询是否成功
+	Live           *NodeStatus            `protobuf:"bytes,12,opt,name=live,proto3" json:"live,omitempty"`                                         // 实时状态（reachable=false 时为空）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ConnectNodeInfo) Reset() {
+	*x = ConnectNodeInfo{}
+	mi := &file_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConnectNodeInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectNodeInfo) ProtoMessage() {}
+
+func (x *ConnectNodeInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectNodeInfo.ProtoReflect.Descriptor instead.
+func (*ConnectNodeInfo) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ConnectNodeInfo) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *ConnectNodeInfo) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *ConnectNodeInfo) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *ConnectNodeInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ConnectNodeInfo) GetMaxConnections() int32 {
+	if x != nil {
+		return x.MaxConnections
+	}
+	return 0
+}
+
+func (x *ConnectNodeInfo) GetConnections() int32 {
+	if x != nil {
+		return x.Connections
+	}
+	return 0
+}
+
+func (x *ConnectNodeInfo) GetRooms() int32 {
+	if x != nil {
+		return x.Rooms
+	}
+	return 0
+}
+
+func (x *ConnectNodeInfo) GetCpuUsage() float32 {
+	if x != nil {
+		return x.CpuUsage
+	}
+	return 0
+}
+
+func (x *ConnectNodeInfo) GetMemoryUsage() float32 {
+	if x != nil {
+		return x.MemoryUsage
+	}
+	return 0
+}
+
+func (x *ConnectNodeInfo) GetLastHeartbeat() int64 {
+	if x != nil {
+		return x.LastHeartbeat
+	}
+	return 0
+}
+
+func (x *ConnectNodeInfo) GetReachable() bool {
+	if x != nil {
+		return x.Reachable
+	}
+	return false
+}
+
+func (x *ConnectNodeInfo) GetLive() *NodeStatus {
+	if x != nil {
+		return x.Live
+	}
+	return nil
+}
+
+type GetNodeStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNodeStatusRequest) Reset() {
+	*x = GetNodeStatusRequest{}
+	mi := &file_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNodeStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeStatusRequest) ProtoMessage() {}
+
+func (x *GetNodeStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetNodeStatusRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{3}
+}
+
+// NodeStatus Connect-Node 实时状态
+type NodeStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Draining      bool                   `protobuf:"varint,2,opt,name=draining,proto3" json:"draining,omitempty"` // drain 模式：拒绝新连接，已有会话不受影响
+	Sessions      int32                  `protobuf:"varint,3,opt,name=sessions,proto3" json:"sessions,omitempty"`
+	Rooms         int32                  `protobuf:"varint,4,opt,name=rooms,proto3" json:"rooms,omitempty"`
+	Buckets       int32                  `protobuf:"varint,5,opt,name=buckets,proto3" json:"buckets,omitempty"`
+	QueueDepth    int64                  `protobuf:"varint,6,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"` // 全部会话下行队列中待发送的消息数
+	StartedAt     int64                  `protobuf:"varint,7,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`    // 进程启动时间，Unix 秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeStatus) Reset() {
+	*x = NodeStatus{}
+	mi := &file_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeStatus) ProtoMessage() {}
+
+func (x *NodeStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeStatus.ProtoReflect.Descriptor instead.
+func (*NodeStatus) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *NodeStatus) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *NodeStatus) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+func (x *NodeStatus) GetSessions() int32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetRooms() int32 {
+	if x != nil {
+		return x.Rooms
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetBuckets() int32 {
+	if x != nil {
+		return x.Buckets
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetQueueDepth() int64 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+type GetPushStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPushStatusRequest) Reset() {
+	*x = GetPushStatusRequest{}
+	mi := &file_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPushStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPushStatusRequest) ProtoMessage() {}
+
+func (x *GetPushStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPushStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetPushStatusRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{5}
+}
+
+// PushManagerStatus Push-Manager 实时状态
+type PushManagerStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ManagerId     string                 `protobuf:"bytes,1,opt,name=manager_id,json=managerId,proto3" json:"manager_id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Reachable     bool                   `protobuf:"varint,3,opt,name=reachable,proto3" json:"reachable,omitempty"` // 由 AdminService 填写
+	Targets       []*PushTarget          `protobuf:"bytes,4,rep,name=targets,proto3" json:"targets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushManagerStatus) Reset() {
+	*x = PushManagerStatus{}
+	mi := &file_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushManagerStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushManagerStatus) ProtoMessage() {}
+
+func (x *PushManagerStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushManagerStatus.ProtoReflect.Descriptor instead.
+func (*PushManagerStatus) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PushManagerStatus) GetManagerId() string {
+	if x != nil {
+		return x.ManagerId
+	}
+	return ""
+}
+
+func (x *PushManagerStatus) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *PushManagerStatus) GetReachable() bool {
+	if x != nil {
+		return x.Reachable
+	}
+	return false
+}
+
+func (x *PushManagerStatus) GetTargets() []*PushTarget {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+// PushTarget Push-Manager 到一个 Connect-Node 的出站状态
+type PushTarget struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeAddress   string                 `protobuf:"bytes,1,opt,name=node_address,json=nodeAddress,proto3" json:"node_address,omitempty"`
+	Breaker       string                 `protobuf:"bytes,2,opt,name=breaker,proto3" json:"breaker,omitempty"`                             // closed, open, half-open
+	PendingHigh   int32                  `protobuf:"varint,3,opt,name=pending_high,json=pendingHigh,proto3" json:"pending_high,omitempty"` // 本地出站队列中待发送的消息数（按优先级）
+	PendingNormal int32                  `protobuf:"varint,4,opt,name=pending_normal,json=pendingNormal,proto3" json:"pending_normal,omitempty"`
+	PendingLow    int32                  `protobuf:"varint,5,opt,name=pending_low,json=pendingLow,proto3" json:"pending_low,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushTarget) Reset() {
+	*x = PushTarget{}
+	mi := &file_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushTarget) ProtoMessage() {}
+
+func (x *PushTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushTarget.ProtoReflect.Descriptor instead.
+func (*PushTarget) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PushTarget) GetNodeAddress() string {
+	if x != nil {
+		return x.NodeAddress
+	}
+	return ""
+}
+
+func (x *PushTarget) GetBreaker() string {
+	if x != nil {
+		return x.Breaker
+	}
+	return ""
+}
+
+func (x *PushTarget) GetPendingHigh() int32 {
+	if x != nil {
+		return x.PendingHigh
+	}
+	return 0
+}
+
+func (x *PushTarget) GetPendingNormal() int32 {
+	if x != nil {
+		return x.PendingNormal
+	}
+	return 0
+}
+
+func (x *PushTarget) GetPendingLow() int32 {
+	if x != nil {
+		return x.PendingLow
+	}
+	return 0
+}
+
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	AppId         string                 `protobuf:"bytes,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`    // 按租户过滤
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 按集群内用户 ID 过滤
+	RoomId        string                 `protobuf:"bytes,4,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"` // 按集群内房间 ID 过滤
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                // 每个节点最多返回的会话数，默认 100，最大 1000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListSessionsRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *ListSessionsRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *ListSessionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListSessionsRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ListSessionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Session 一个已鉴权的连接（user_id / room_id 为集群内 ID）
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	AppId         string                 `protobuf:"bytes,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,4,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceType    string                 `protobuf:"bytes,5,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	ConnId        string                 `protobuf:"bytes,6,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	Ip            string                 `protobuf:"bytes,7,opt,name=ip,proto3" json:"ip,omitempty"`
+	RoomId        string                 `protobuf:"bytes,8,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	ConnectedAt   int64                  `protobuf:"varint,9,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"` // Unix 秒
+	QueueDepth    int32                  `protobuf:"varint,10,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`   // 下行队列中待发送的消息数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Session) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *Session) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *Session) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Session) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *Session) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *Session) GetConnId() string {
+	if x != nil {
+		return x.ConnId
+	}
+	return ""
+}
+
+func (x *Session) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Session) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *Session) GetConnectedAt() int64 {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return 0
+}
+
+func (x *Session) GetQueueDepth() int32 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+type ListSessionsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Sessions         []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	Truncated        bool                   `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"` // 有节点的会话数超过 limit
+	UnreachableNodes []string               `protobuf:"bytes,3,rep,name=unreachable_nodes,json=unreachableNodes,proto3" json:"unreachable_nodes,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+func (x *ListSessionsResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *ListSessionsResponse) GetUnreachableNodes() []string {
+	if x != nil {
+		return x.UnreachableNodes
+	}
+	return nil
+}
+
+type DisconnectSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`       // 集群内用户 ID，必填
+	DeviceId      string                 `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"` // 为空时断开用户的全部设备
+	ConnId        string                 `protobuf:"bytes,4,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`       // 只断开指定连接
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisconnectSessionRequest) Reset() {
+	*x = DisconnectSessionRequest{}
+	mi := &file_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisconnectSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisconnectSessionRequest) ProtoMessage() {}
+
+func (x *DisconnectSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisconnectSessionRequest.ProtoReflect.Descriptor instead.
+func (*DisconnectSessionRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DisconnectSessionRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *DisconnectSessionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DisconnectSessionRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DisconnectSessionRequest) GetConnId() string {
+	if x != nil {
+		return x.ConnId
+	}
+	return ""
+}
+
+func (x *DisconnectSessionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DisconnectSessionResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Success          bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message          string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Disconnected     int32                  `protobuf:"varint,3,opt,name=disconnected,proto3" json:"disconnected,omitempty"`
+	UnreachableNodes []string               `protobuf:"bytes,4,rep,name=unreachable_nodes,json=unreachableNodes,proto3" json:"unreachable_nodes,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DisconnectSessionResponse) Reset() {
+	*x = DisconnectSessionResponse{}
+	mi := &file_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisconnectSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisconnectSessionResponse) ProtoMessage() {}
+
+func (x *DisconnectSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisconnectSessionResponse.ProtoReflect.Descriptor instead.
+func (*DisconnectSessionResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DisconnectSessionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DisconnectSessionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DisconnectSessionResponse) GetDisconnected() int32 {
+	if x != nil {
+		return x.Disconnected
+	}
+	return 0
+}
+
+func (x *DisconnectSessionResponse) GetUnreachableNodes() []string {
+	if x != nil {
+		return x.UnreachableNodes
+	}
+	return nil
+}
+
+type GetDistributionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	TopRooms      int32                  `protobuf:"varint,2,opt,name=top_rooms,json=topRooms,proto3" json:"top_rooms,omitempty"` // 输出在线人数最多的房间数，默认 20
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDistributionRequest) Reset() {
+	*x = GetDistributionRequest{}
+	mi := &file_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDistributionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDistributionRequest) ProtoMessage() {}
+
+func (x *GetDistributionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDistributionRequest.ProtoReflect.Descriptor instead.
+func (*GetDistributionRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetDistributionRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *GetDistributionRequest) GetTopRooms() int32 {
+	if x != nil {
+		return x.TopRooms
+	}
+	return 0
+}
+
+type BucketStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Sessions      int32                  `protobuf:"varint,2,opt,name=sessions,proto3" json:"sessions,omitempty"`
+	Users         int32                  `protobuf:"varint,3,opt,name=users,proto3" json:"users,omitempty"`
+	Rooms         int32                  `protobuf:"varint,4,opt,name=rooms,proto3" json:"rooms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BucketStats) Reset() {
+	*x = BucketStats{}
+	mi := &file_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BucketStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BucketStats) ProtoMessage() {}
+
+func (x *BucketStats) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BucketStats.ProtoReflect.Descriptor instead.
+func (*BucketStats) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BucketStats) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BucketStats) GetSessions() int32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+func (x *BucketStats) GetUsers() int32 {
+	if x != nil {
+		return x.Users
+	}
+	return 0
+}
+
+func (x *BucketStats) GetRooms() int32 {
+	if x != nil {
+		return x.Rooms
+	}
+	return 0
+}
+
+type RoomLoad struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"` // 集群内房间 ID
+	Online        int32                  `protobuf:"varint,2,opt,name=online,proto3" json:"online,omitempty"`
+	Buckets       int32                  `protobuf:"varint,3,opt,name=buckets,proto3" json:"buckets,omitempty"` // 房间分布在多少个 bucket 中
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomLoad) Reset() {
+	*x = RoomLoad{}
+	mi := &file_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomLoad) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomLoad) ProtoMessage() {}
+
+func (x *RoomLoad) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomLoad.ProtoReflect.Descriptor instead.
+func (*RoomLoad) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RoomLoad) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *RoomLoad) GetOnline() int32 {
+	if x != nil {
+		return x.Online
+	}
+	return 0
+}
+
+func (x *RoomLoad) GetBuckets() int32 {
+	if x != nil {
+		return x.Buckets
+	}
+	return 0
+}
+
+type NodeDistribution struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Buckets       []*BucketStats         `protobuf:"bytes,2,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	TopRooms      []*RoomLoad            `protobuf:"bytes,3,rep,name=top_rooms,json=topRooms,proto3" json:"top_rooms,omitempty"`
+	TotalRooms    int32                  `protobuf:"varint,4,opt,name=total_rooms,json=totalRooms,proto3" json:"total_rooms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeDistribution) Reset() {
+	*x = NodeDistribution{}
+	mi := &file_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeDistribution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeDistribution) ProtoMessage() {}
+
+func (x *NodeDistribution) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeDistribution.ProtoReflect.Descriptor instead.
+func (*NodeDistribution) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *NodeDistribution) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *NodeDistribution) GetBuckets() []*BucketStats {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *NodeDistribution) GetTopRooms() []*RoomLoad {
+	if x != nil {
+		return x.TopRooms
+	}
+	return nil
+}
+
+func (x *NodeDistribution) GetTotalRooms() int32 {
+	if x != nil {
+		return x.TotalRooms
+	}
+	return 0
+}
+
+type GetDistributionResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Nodes            []*NodeDistribution    `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	UnreachableNodes []string               `protobuf:"bytes,2,rep,name=unreachable_nodes,json=unreachableNodes,proto3" json:"unreachable_nodes,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetDistributionResponse) Reset() {
+	*x = GetDistributionResponse{}
+	mi := &file_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDistributionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDistributionResponse) ProtoMessage() {}
+
+func (x *GetDistributionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDistributionResponse.ProtoReflect.Descriptor instead.
+func (*GetDistributionResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetDistributionResponse) GetNodes() []*NodeDistribution {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *GetDistributionResponse) GetUnreachableNodes() []string {
+	if x != nil {
+		return x.UnreachableNodes
+	}
+	return nil
+}
+
+type SetDrainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"` // AdminService 必填
+	Drain         bool                   `protobuf:"varint,2,opt,name=drain,proto3" json:"drain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDrainRequest) Reset() {
+	*x = SetDrainRequest{}
+	mi := &file_admin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDrainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDrainRequest) ProtoMessage() {}
+
+func (x *SetDrainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDrainRequest.ProtoReflect.Descriptor instead.
+func (*SetDrainRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SetDrainRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *SetDrainRequest) GetDrain() bool {
+	if x != nil {
+		return x.Drain
+	}
+	return false
+}
+
+type SetDrainResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Draining      bool                   `protobuf:"varint,3,opt,name=draining,proto3" json:"draining,omitempty"`
+	Sessions      int32                  `protobuf:"varint,4,opt,name=sessions,proto3" json:"sessions,omitempty"` // 当前会话数（drain 后逐渐减少）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDrainResponse) Reset() {
+	*x = SetDrainResponse{}
+	mi := &file_admin_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDrainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDrainResponse) ProtoMessage() {}
+
+func (x *SetDrainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDrainResponse.ProtoReflect.Descriptor instead.
+func (*SetDrainResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SetDrainResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SetDrainResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SetDrainResponse) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+func (x *SetDrainResponse) GetSessions() int32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+var File_admin_proto protoreflect.FileDescriptor
+
+const file_admin_proto_rawDesc = "" +
+	"\n" +
+	"\vadmin.proto\x12\x06pubsub\";\n" +
+	"\x10ListNodesRequest\x12'\n" +
+	"\x0finclude_offline\x18\x01 \x01(\bR\x0eincludeOffline\"\x91\x01\n" +
+	"\x11ListNodesResponse\x12<\n" +
+	"\rconnect_nodes\x18\x01 \x03(\v2\x17.pubsub.ConnectNodeInfoR\fconnectNodes\x12>\n" +
+	"\rpush_managers\x18\x02 \x03(\v2\x19.pubsub.PushManagerStatusR\fpushManagers\"\x82\x03\n" +
+	"\x0fConnectNodeInfo\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06region\x18\x03 \x01(\tR\x06region\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12'\n" +
+	"\x0fmax_connections\x18\x05 \x01(\x05R\x0emaxConnections\x12 \n" +
+	"\vconnections\x18\x06 \x01(\x05R\vconnections\x12\x14\n" +
+	"\x05rooms\x18\a \x01(\x05R\x05rooms\x12\x1b\n" +
+	"\tcpu_usage\x18\b \x01(\x02R\bcpuUsage\x12!\n" +
+	"\fmemory_usage\x18\t \x01(\x02R\vmemoryUsage\x12%\n" +
+	"\x0elast_heartbeat\x18\n" +
+	" \x01(\x03R\rlastHeartbeat\x12\x1c\n" +
+	"\treachable\x18\v \x01(\bR\treachable\x12&\n" +
+	"\x04live\x18\f \x01(\v2\x12.pubsub.NodeStatusR\x04live\"\x16\n" +
+	"\x14GetNodeStatusRequest\"\xcd\x01\n" +
+	"\n" +
+	"NodeStatus\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1a\n" +
+	"\bdraining\x18\x02 \x01(\bR\bdraining\x12\x1a\n" +
+	"\bsessions\x18\x03 \x01(\x05R\bsessions\x12\x14\n" +
+	"\x05rooms\x18\x04 \x01(\x05R\x05rooms\x12\x18\n" +
+	"\abuckets\x18\x05 \x01(\x05R\abuckets\x12\x1f\n" +
+	"\vqueue_depth\x18\x06 \x01(\x03R\n" +
+	"queueDepth\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\a \x01(\x03R\tstartedAt\"\x16\n" +
+	"\x14GetPushStatusRequest\"\x98\x01\n" +
+	"\x11PushManagerStatus\x12\x1d\n" +
+	"\n" +
+	"manager_id\x18\x01 \x01(\tR\tmanagerId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x1c\n" +
+	"\treachable\x18\x03 \x01(\bR\treachable\x12,\n" +
+	"\atargets\x18\x04 \x03(\v2\x12.pubsub.PushTargetR\atargets\"\xb4\x01\n" +
+	"\n" +
+	"PushTarget\x12!\n" +
+	"\fnode_address\x18\x01 \x01(\tR\vnodeAddress\x12\x18\n" +
+	"\abreaker\x18\x02 \x01(\tR\abreaker\x12!\n" +
+	"\fpending_high\x18\x03 \x01(\x05R\vpendingHigh\x12%\n" +
+	"\x0epending_normal\x18\x04 \x01(\x05R\rpendingNormal\x12\x1f\n" +
+	"\vpending_low\x18\x05 \x01(\x05R\n" +
+	"pendingLow\"\x8d\x01\n" +
+	"\x13ListSessionsRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x15\n" +
+	"\x06app_id\x18\x02 \x01(\tR\x05appId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x17\n" +
+	"\aroom_id\x18\x04 \x01(\tR\x06roomId\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"\x96\x02\n" +
+	"\aSession\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x15\n" +
+	"\x06app_id\x18\x02 \x01(\tR\x05appId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdevice_id\x18\x04 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_type\x18\x05 \x01(\tR\n" +
+	"deviceType\x12\x17\n" +
+	"\aconn_id\x18\x06 \x01(\tR\x06connId\x12\x0e\n" +
+	"\x02ip\x18\a \x01(\tR\x02ip\x12\x17\n" +
+	"\aroom_id\x18\b \x01(\tR\x06roomId\x12!\n" +
+	"\fconnected_at\x18\t \x01(\x03R\vconnectedAt\x12\x1f\n" +
+	"\vqueue_depth\x18\n" +
+	" \x01(\x05R\n" +
+	"queueDepth\"\x8e\x01\n" +
+	"\x14ListSessionsResponse\x12+\n" +
+	"\bsessions\x18\x01 \x03(\v2\x0f.pubsub.SessionR\bsessions\x12\x1c\n" +
+	"\ttruncated\x18\x02 \x01(\bR\ttruncated\x12+\n" +
+	"\x11unreachable_nodes\x18\x03 \x03(\tR\x10unreachableNodes\"\x9a\x01\n" +
+	"\x18DisconnectSessionRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12\x17\n" +
+	"\aconn_id\x18\x04 \x01(\tR\x06connId\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\"\xa0\x01\n" +
+	"\x19DisconnectSessionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\"\n" +
+	"\fdisconnected\x18\x03 \x01(\x05R\fdisconnected\x12+\n" +
+	"\x11unreachable_nodes\x18\x04 \x03(\tR\x10unreachableNodes\"N\n" +
+	"\x16GetDistributionRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1b\n" +
+	"\ttop_rooms\x18\x02 \x01(\x05R\btopRooms\"k\n" +
+	"\vBucketStats\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x1a\n" +
+	"\bsessions\x18\x02 \x01(\x05R\bsessions\x12\x14\n" +
+	"\x05users\x18\x03 \x01(\x05R\x05users\x12\x14\n" +
+	"\x05rooms\x18\x04 \x01(\x05R\x05rooms\"U\n" +
+	"\bRoomLoad\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x16\n" +
+	"\x06online\x18\x02 \x01(\x05R\x06online\x12\x18\n" +
+	"\abuckets\x18\x03 \x01(\x05R\abuckets\"\xaa\x01\n" +
+	"\x10NodeDistribution\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12-\n" +
+	"\abuckets\x18\x02 \x03(\v2\x13.pubsub.BucketStatsR\abuckets\x12-\n" +
+	"\ttop_rooms\x18\x03 \x03(\v2\x10.pubsub.RoomLoadR\btopRooms\x12\x1f\n" +
+	"\vtotal_rooms\x18\x04 \x01(\x05R\n" +
+	"totalRooms\"v\n" +
+	"\x17GetDistributionResponse\x12.\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x18.pubsub.NodeDistributionR\x05nodes\x12+\n" +
+	"\x11unreachable_nodes\x18\x02 \x03(\tR\x10unreachableNodes\"@\n" +
+	"\x0fSetDrainRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x14\n" +
+	"\x05drain\x18\x02 \x01(\bR\x05drain\"~\n" +
+	"\x10SetDrainResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1a\n" +
+	"\bdraining\x18\x03 \x01(\bR\bdraining\x12\x1a\n" +
+	"\bsessions\x18\x04 \x01(\x05R\bsessions2\x88\x03\n" +
+	"\fAdminService\x12@\n" +
+	"\tListNodes\x12\x18.pubsub.ListNodesRequest\x1a\x19.pubsub.ListNodesResponse\x12I\n" +
+	"\fListSessions\x12\x1b.pubsub.ListSessionsRequest\x1a\x1c.pubsub.ListSessionsResponse\x12X\n" +
+	"\x11DisconnectSession\x12 .pubsub.DisconnectSessionRequest\x1a!.pubsub.DisconnectSessionResponse\x12R\n" +
+	"\x0fGetDistribution\x12\x1e.pubsub.GetDistributionRequest\x1a\x1f.pubsub.GetDistributionResponse\x12=\n" +
+	"\bSetDrain\x12\x17.pubsub.SetDrainRequest\x1a\x18.pubsub.SetDrainResponse2\xff\x02\n" +
+	"\tNodeAdmin\x12A\n" +
+	"\rGetNodeStatus\x12\x1c.pubsub.GetNodeStatusRequest\x1a\x12.pubsub.NodeStatus\x12I\n" +
+	"\fListSessions\x12\x1b.pubsub.ListSessionsRequest\x1a\x1c.pubsub.ListSessionsResponse\x12X\n" +
+	"\x11DisconnectSession\x12 .pubsub.DisconnectSessionRequest\x1a!.pubsub.DisconnectSessionResponse\x12K\n" +
+	"\x0fGetDistribution\x12\x1e.pubsub.GetDistributionRequest\x1a\x18.pubsub.NodeDistribution\x12=\n" +
+	"\bSetDrain\x12\x17.pubsub.SetDrainRequest\x1a\x18.pubsub.SetDrainResponse2U\n" +
+	"\tPushAdmin\x12H\n" +
+	"\rGetPushStatus\x12\x1c.pubsub.GetPushStatusRequest\x1a\x19.pubsub.PushManagerStatusB?Z=github.com/livekit/psrpc/examples/pubsub/protocol/admin;adminb\x06proto3"
+
+var (
+	file_admin_proto_rawDescOnce sync.Once
+	file_admin_proto_rawDescData []byte
+)
+
+func file_admin_proto_rawDescGZIP() []byte {
+	file_admin_proto_rawDescOnce.Do(func() {
+		file_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_admin_proto_rawDesc), len(file_admin_proto_rawDesc)))
+	})
+	return file_admin_proto_rawDescData
+}
+
+var file_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_admin_proto_goTypes = []any{
+	(*ListNodesRequest)(nil),          // 0: pubsub.ListNodesRequest
+	(*ListNodesResponse)(nil),         // 1: pubsub.ListNodesResponse
+	(*ConnectNodeInfo)(nil),           // 2: pubsub.ConnectNodeInfo
+	(*GetNodeStatusRequest)(nil),      // 3: pubsub.GetNodeStatusRequest
+	(*NodeStatus)(nil),                // 4: pubsub.NodeStatus
+	(*GetPushStatusRequest)(nil),      // 5: pubsub.GetPushStatusRequest
+	(*PushManagerStatus)(nil),         // 6: pubsub.PushManagerStatus
+	(*PushTarget)(nil),                // 7: pubsub.PushTarget
+	(*ListSessionsRequest)(nil),       // 8: pubsub.ListSessionsRequest
+	(*Session)(nil),                   // 9: pubsub.Session
+	(*ListSessionsResponse)(nil),      // 10: pubsub.ListSessionsResponse
+	(*DisconnectSessionRequest)(nil),  // 11: pubsub.DisconnectSessionRequest
+	(*DisconnectSessionResponse)(nil), // 12: pubsub.DisconnectSessionResponse
+	(*GetDistributionRequest)(nil),    // 13: pubsub.GetDistributionRequest
+	(*BucketStats)(nil),               // 14: pubsub.BucketStats
+	(*RoomLoad)(nil),                  // 15: pubsub.RoomLoad
+	(*NodeDistribution)(nil),          // 16: pubsub.NodeDistribution
+	(*GetDistributionResponse)(nil),   // 17: pubsub.GetDistributionResponse
+	(*SetDrainRequest)(nil),           // 18: pubsub.SetDrainRequest
+	(*SetDrainResponse)(nil),          // 19: pubsub.SetDrainResponse
+}
+var file_admin_proto_depIdxs = []int32{
+	2,  // 0: pubsub.ListNodesResponse.connect_nodes:type_name -> pubsub.ConnectNodeInfo
+	6,  // 1: pubsub.ListNodesResponse.push_managers:type_name -> pubsub.PushManagerStatus
+	4,  // 2: pubsub.ConnectNodeInfo.live:type_name -> pubsub.NodeStatus
+	7,  // 3: pubsub.PushManagerStatus.targets:type_name -> pubsub.PushTarget
+	9,  // 4: pubsub.ListSessionsResponse.sessions:type_name -> pubsub.Session
+	14, // 5: pubsub.NodeDistribution.buckets:type_name -> pubsub.BucketStats
+	15, // 6: pubsub.NodeDistribution.top_rooms:type_name -> pubsub.RoomLoad
+	16, // 7: pubsub.GetDistributionResponse.nodes:type_name -> pubsub.NodeDistribution
+	0,  // 8: pubsub.AdminService.ListNodes:input_type -> pubsub.ListNodesRequest
+	8,  // 9: pubsub.AdminService.ListSessions:input_type -> pubsub.ListSessionsRequest
+	11, // 10: pubsub.AdminService.DisconnectSession:input_type -> pubsub.DisconnectSessionRequest
+	13, // 11: pubsub.AdminService.GetDistribution:input_type -> pubsub.GetDistributionRequest
+	18, // 12: pubsub.AdminService.SetDrain:input_type -> pubsub.SetDrainRequest
+	3,  // 13: pubsub.NodeAdmin.GetNodeStatus:input_type -> pubsub.GetNodeStatusRequest
+	8,  // 14: pubsub.NodeAdmin.ListSessions:input_type -> pubsub.ListSessionsRequest
+	11, // 15: pubsub.NodeAdmin.DisconnectSession:input_type -> pubsub.DisconnectSessionRequest
+	13, // 16: pubsub.NodeAdmin.GetDistribution:input_type -> pubsub.GetDistributionRequest
+	18, // 17: pubsub.NodeAdmin.SetDrain:input_type -> pubsub.SetDrainRequest
+	5,  // 18: pubsub.PushAdmin.GetPushStatus:input_type -> pubsub.GetPushStatusRequest
+	1,  // 19: pubsub.AdminService.ListNodes:output_type -> pubsub.ListNodesResponse
+	10, // 20: pubsub.AdminService.ListSessions:output_type -> pubsub.ListSessionsResponse
+	12, // 21: pubsub.AdminService.DisconnectSession:output_type -> pubsub.DisconnectSessionResponse
+	17, // 22: pubsub.AdminService.GetDistribution:output_type -> pubsub.GetDistributionResponse
+	19, // 23: pubsub.AdminService.SetDrain:output_type -> pubsub.SetDrainResponse
+	4,  // 24: pubsub.NodeAdmin.GetNodeStatus:output_type -> pubsub.NodeStatus
+	10, // 25: pubsub.NodeAdmin.ListSessions:output_type -> pubsub.ListSessionsResponse
+	12, // 26: pubsub.NodeAdmin.DisconnectSession:output_type -> pubsub.DisconnectSessionResponse
+	16, // 27: pubsub.NodeAdmin.GetDistribution:output_type -> pubsub.NodeDistribution
+	19, // 28: pubsub.NodeAdmin.SetDrain:output_type -> pubsub.SetDrainResponse
+	6,  // 29: pubsub.PushAdmin.GetPushStatus:output_type -> pubsub.PushManagerStatus
+	19, // [19:30] is the sub-list for method output_type
+	8,  // [8:19] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_admin_proto_init() }
+func file_admin_proto_init() {
+	if File_admin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_admin_proto_rawDesc), len(file_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   3,
+		},
+		GoTypes:           file_admin_proto_goTypes,
+		DependencyIndexes: file_admin_proto_depIdxs,
+		MessageInfos:      file_admin_proto_msgTypes,
+	}.Build()
+	File_admin_proto = out.File
+	file_admin_proto_goTypes = nil
+	file_admin_proto_depIdxs = nil
+}
@@ -0,0 +1,665 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.0
+// source: admin.proto
+
+package admin
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdminService_ListNodes_FullMethodName         = "/pubsub.AdminService/ListNodes"
+	AdminService_ListSessions_FullMethodName      = "/pubsub.AdminService/ListSessions"
+	AdminService_DisconnectSession_FullMethodName = "/pubsub.AdminService/DisconnectSession"
+	AdminService_GetDistribution_FullMethodName   = "/pubsub.AdminService/GetDistribution"
+	AdminService_SetDrain_FullMethodName          = "/pubsub.AdminService/SetDrain"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminService 集群运维接口（Controller 提供）：汇总 Controller 的节点表、各 Connect-Node 和 Push-Manager 的实时状态
+type AdminServiceClient interface {
+	// 列出 Connect-Node（心跳负载 + 实时状态）和 Push-Manager（出站队列、熔断器）
+	ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error)
+	// 列出节点上的会话（node_id 为空时查询全部在线节点）
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// 强制断开会话（node_id 为空时在全部在线节点上查找）
+	DisconnectSession(ctx context.Context, in *DisconnectSessionRequest, opts ...grpc.CallOption) (*DisconnectSessionResponse, error)
+	// bucket 和房间分布（node_id 为空时输出全部在线节点）
+	GetDistribution(ctx context.Context, in *GetDistributionRequest, opts ...grpc.CallOption) (*GetDistributionResponse, error)
+	// 开启/关闭节点的 drain 模式
+	SetDrain(ctx context.Context, in *SetDrainRequest, opts ...grpc.CallOption) (*SetDrainResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNodesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListNodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DisconnectSession(ctx context.Context, in *DisconnectSessionRequest, opts ...grpc.CallOption) (*DisconnectSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisconnectSessionResponse)
+	err := c.cc.Invoke(ctx, AdminService_DisconnectSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetDistribution(ctx context.Context, in *GetDistributionRequest, opts ...grpc.CallOption) (*GetDistributionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDistributionResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetDistribution_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetDrain(ctx context.Context, in *SetDrainRequest, opts ...grpc.CallOption) (*SetDrainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetDrainResponse)
+	err := c.cc.Invoke(ctx, AdminService_SetDrain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// AdminService 集群运维接口（Controller 提供）：汇总 Controller 的节点表、各 Connect-Node 和 Push-Manager 的实时状态
+type AdminServiceServer interface {
+	// 列出 Connect-Node（心跳负载 + 实时状态）和 Push-Manager（出站队列、熔断器）
+	ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error)
+	// 列出节点上的会话（node_id 为空时查询全部在线节点）
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// 强制断开会话（node_id 为空时在全部在线节点上查找）
+	DisconnectSession(context.Context, *DisconnectSessionRequest) (*DisconnectSessionResponse, error)
+	// bucket 和房间分布（node_id 为空时输出全部在线节点）
+	GetDistribution(context.Context, *GetDistributionRequest) (*GetDistributionResponse, error)
+	// 开启/关闭节点的 drain 模式
+	SetDrain(context.Context, *SetDrainRequest) (*SetDrainResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNodes not implemented")
+}
+func (UnimplementedAdminServiceServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedAdminServiceServer) DisconnectSession(context.Context, *DisconnectSessionRequest) (*DisconnectSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisconnectSession not implemented")
+}
+func (UnimplementedAdminServiceServer) GetDistribution(context.Context, *GetDistributionRequest) (*GetDistributionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDistribution not implemented")
+}
+func (UnimplementedAdminServiceServer) SetDrain(context.Context, *SetDrainRequest) (*SetDrainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDrain not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_ListNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListNodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListNodes(ctx, req.(*ListNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DisconnectSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisconnectSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DisconnectSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DisconnectSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DisconnectSession(ctx, req.(*DisconnectSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetDistribution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDistributionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetDistribution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetDistribution_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetDistribution(ctx, req.(*GetDistributionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetDrain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetDrain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetDrain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetDrain(ctx, req.(*SetDrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pubsub.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListNodes",
+			Handler:    _AdminService_ListNodes_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _AdminService_ListSessions_Handler,
+		},
+		{
+			MethodName: "DisconnectSession",
+			Handler:    _AdminService_DisconnectSession_Handler,
+		},
+		{
+			MethodName: "GetDistribution",
+			Handler:    _AdminService_GetDistribution_Handler,
+		},
+		{
+			MethodName: "SetDrain",
+			Handler:    _AdminService_SetDrain_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
+
+const (
+	NodeAdmin_GetNodeStatus_FullMethodName     = "/pubsub.NodeAdmin/GetNodeStatus"
+	NodeAdmin_ListSessions_FullMethodName      = "/pubsub.NodeAdmin/ListSessions"
+	NodeAdmin_DisconnectSession_FullMethodName = "/pubsub.NodeAdmin/DisconnectSession"
+	NodeAdmin_GetDistribution_FullMethodName   = "/pubsub.NodeAdmin/GetDistribution"
+	NodeAdmin_SetDrain_FullMethodName          = "/pubsub.NodeAdmin/SetDrain"
+)
+
+// NodeAdminClient is the client API for NodeAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NodeAdmin 单个 Connect-Node 的运维接口（由 AdminService 调用）
+type NodeAdminClient interface {
+	GetNodeStatus(ctx context.Context, in *GetNodeStatusRequest, opts ...grpc.CallOption) (*NodeStatus, error)
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	DisconnectSession(ctx context.Context, in *DisconnectSessionRequest, opts ...grpc.CallOption) (*DisconnectSessionResponse, error)
+	GetDistribution(ctx context.Context, in *GetDistributionRequest, opts ...grpc.CallOption) (*NodeDistribution, error)
+	SetDrain(ctx context.Context, in *SetDrainRequest, opts ...grpc.CallOption) (*SetDrainResponse, error)
+}
+
+type nodeAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNodeAdminClient(cc grpc.ClientConnInterface) NodeAdminClient {
+	return &nodeAdminClient{cc}
+}
+
+func (c *nodeAdminClient) GetNodeStatus(ctx context.Context, in *GetNodeStatusRequest, opts ...grpc.CallOption) (*NodeStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeStatus)
+	err := c.cc.Invoke(ctx, NodeAdmin_GetNodeStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeAdminClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, NodeAdmin_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeAdminClient) DisconnectSession(ctx context.Context, in *DisconnectSessionRequest, opts ...grpc.CallOption) (*DisconnectSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisconnectSessionResponse)
+	err := c.cc.Invoke(ctx, NodeAdmin_DisconnectSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeAdminClient) GetDistribution(ctx context.Context, in *GetDistributionRequest, opts ...grpc.CallOption) (*NodeDistribution, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeDistribution)
+	err := c.cc.Invoke(ctx, NodeAdmin_GetDistribution_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeAdminClient) SetDrain(ctx context.Context, in *SetDrainRequest, opts ...grpc.CallOption) (*SetDrainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetDrainResponse)
+	err := c.cc.Invoke(ctx, NodeAdmin_SetDrain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NodeAdminServer is the server API for NodeAdmin service.
+// All implementations must embed UnimplementedNodeAdminServer
+// for forward compatibility.
+//
+// NodeAdmin 单个 Connect-Node 的运维接口（由 AdminService 调用）
+type NodeAdminServer interface {
+	GetNodeStatus(context.Context, *GetNodeStatusRequest) (*NodeStatus, error)
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	DisconnectSession(context.Context, *DisconnectSessionRequest) (*DisconnectSessionResponse, error)
+	GetDistribution(context.Context, *GetDistributionRequest) (*NodeDistribution, error)
+	SetDrain(context.Context, *SetDrainRequest) (*SetDrainResponse, error)
+	mustEmbedUnimplementedNodeAdminServer()
+}
+
+// UnimplementedNodeAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNodeAdminServer struct{}
+
+func (UnimplementedNodeAdminServer) GetNodeStatus(context.Context, *GetNodeStatusRequest) (*NodeStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeStatus not implemented")
+}
+func (UnimplementedNodeAdminServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedNodeAdminServer) DisconnectSession(context.Context, *DisconnectSessionRequest) (*DisconnectSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisconnectSession not implemented")
+}
+func (UnimplementedNodeAdminServer) GetDistribution(context.Context, *GetDistributionRequest) (*NodeDistribution, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDistribution not implemented")
+}
+func (UnimplementedNodeAdminServer) SetDrain(context.Context, *SetDrainRequest) (*SetDrainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDrain not implemented")
+}
+func (UnimplementedNodeAdminServer) mustEmbedUnimplementedNodeAdminServer() {}
+func (UnimplementedNodeAdminServer) testEmbeddedByValue()                   {}
+
+// UnsafeNodeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NodeAdminServer will
+// result in compilation errors.
+type UnsafeNodeAdminServer interface {
+	mustEmbedUnimplementedNodeAdminServer()
+}
+
+func RegisterNodeAdminServer(s grpc.ServiceRegistrar, srv NodeAdminServer) {
+	// If the following call pancis, it indicates UnimplementedNodeAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NodeAdmin_ServiceDesc, srv)
+}
+
+func _NodeAdmin_GetNodeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNodeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeAdminServer).GetNodeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeAdmin_GetNodeStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeAdminServer).GetNodeStatus(ctx, req.(*GetNodeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeAdmin_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeAdminServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeAdmin_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeAdminServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeAdmin_DisconnectSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisconnectSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeAdminServer).DisconnectSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeAdmin_DisconnectSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeAdminServer).DisconnectSession(ctx, req.(*DisconnectSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeAdmin_GetDistribution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDistributionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeAdminServer).GetDistribution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeAdmin_GetDistribution_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeAdminServer).GetDistribution(ctx, req.(*GetDistributionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeAdmin_SetDrain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeAdminServer).SetDrain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeAdmin_SetDrain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeAdminServer).SetDrain(ctx, req.(*SetDrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NodeAdmin_ServiceDesc is the grpc.ServiceDesc for NodeAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NodeAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pubsub.NodeAdmin",
+	HandlerType: (*NodeAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetNodeStatus",
+			Handler:    _NodeAdmin_GetNodeStatus_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _NodeAdmin_ListSessions_Handler,
+		},
+		{
+			MethodName: "DisconnectSession",
+			Handler:    _NodeAdmin_DisconnectSession_Handler,
+		},
+		{
+			MethodName: "GetDistribution",
+			Handler:    _NodeAdmin_GetDistribution_Handler,
+		},
+		{
+			MethodName: "SetDrain",
+			Handler:    _NodeAdmin_SetDrain_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
+
+const (
+	PushAdmin_GetPushStatus_FullMethodName = "/pubsub.PushAdmin/GetPushStatus"
+)
+
+// PushAdminClient is the client API for PushAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PushAdmin 单个 Push-Manager 的运维接口（由 AdminService 调用）
+type PushAdminClient interface {
+	GetPushStatus(ctx context.Context, in *GetPushStatusRequest, opts ...grpc.CallOption) (*PushManagerStatus, error)
+}
+
+type pushAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPushAdminClient(cc grpc.ClientConnInterface) PushAdminClient {
+	return &pushAdminClient{cc}
+}
+
+func (c *pushAdminClient) GetPushStatus(ctx context.Context, in *GetPushStatusRequest, opts ...grpc.CallOption) (*PushManagerStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PushManagerStatus)
+	err := c.cc.Invoke(ctx, PushAdmin_GetPushStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PushAdminServer is the server API for PushAdmin service.
+// All implementations must embed UnimplementedPushAdminServer
+// for forward compatibility.
+//
+// PushAdmin 单个 Push-Manager 的运维接口（由 AdminService 调用）
+type PushAdminServer interface {
+	GetPushStatus(context.Context, *GetPushStatusRequest) (*PushManagerStatus, error)
+	mustEmbedUnimplementedPushAdminServer()
+}
+
+// UnimplementedPushAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPushAdminServer struct{}
+
+func (UnimplementedPushAdminServer) GetPushStatus(context.Context, *GetPushStatusRequest) (*PushManagerStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPushStatus not implemented")
+}
+func (UnimplementedPushAdminServer) mustEmbedUnimplementedPushAdminServer() {}
+func (UnimplementedPushAdminServer) testEmbeddedByValue()                   {}
+
+// UnsafePushAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PushAdminServer will
+// result in compilation errors.
+type UnsafePushAdminServer interface {
+	mustEmbedUnimplementedPushAdminServer()
+}
+
+func RegisterPushAdminServer(s grpc.ServiceRegistrar, srv PushAdminServer) {
+	// If the following call pancis, it indicates UnimplementedPushAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PushAdmin_ServiceDesc, srv)
+}
+
+func _PushAdmin_GetPushStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPushStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushAdminServer).GetPushStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushAdmin_GetPushStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushAdminServer).GetPushStatus(ctx, req.(*GetPushStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PushAdmin_ServiceDesc is the grpc.ServiceDesc for PushAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PushAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pubsub.PushAdmin",
+	HandlerType: (*PushAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPushStatus",
+			Handler:    _PushAdmin_GetPushStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
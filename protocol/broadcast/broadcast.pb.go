@@ -25,6 +25,12 @@ const (
 type BroadCastReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Proto         *protocol.Proto        `protobuf:"bytes,1,opt,name=proto,proto3" json:"proto,omitempty"`
+	DeliverAt     int64                  `protobuf:"varint,2,opt,name=deliver_at,json=deliverAt,proto3" json:"deliver_at,omitempty"`     // 定时投递时间（Unix 毫秒），0 表示立即投递
+	DelayMs       int64                  `protobuf:"varint,3,opt,name=delay_ms,json=delayMs,proto3" json:"delay_ms,omitempty"`           // 延迟投递（毫秒），与 deliver_at 同时指定时以 deliver_at 为准
+	MessageId     string                 `protobuf:"bytes,4,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`      // 幂等 key：去重窗口内相同 message_id 的重复请求返回首次的响应
+	Priority      protocol.Priority      `protobuf:"varint,5,opt,name=priority,proto3,enum=protocol.Priority" json:"priority,omitempty"` // 推送优先级（默认 NORMAL）
+	ExpireAt      int64                  `protobuf:"varint,6,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`        // 过期时间（Unix 毫秒），0 表示不过期；过期的消息在任何环节都直接丢弃
+	AppId         string                 `protobuf:"bytes,7,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`                  // 租户（为空表示默认租户）：只推送给该租户的连接
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,11 +72,54 @@ func (x *BroadCastReq) GetProto() *protocol.Proto {
 	return nil
 }
 
+func (x *BroadCastReq) GetDeliverAt() int64 {
+	if x != nil {
+		return x.DeliverAt
+	}
+	return 0
+}
+
+func (x *BroadCastReq) GetDelayMs() int64 {
+	if x != nil {
+		return x.DelayMs
+	}
+	return 0
+}
+
+func (x *BroadCastReq) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *BroadCastReq) GetPriority() protocol.Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return protocol.Priority(0)
+}
+
+func (x *BroadCastReq) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *BroadCastReq) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
 type BroadCastReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
 	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
 	Desc          string                 `protobuf:"bytes,3,opt,name=desc,proto3" json:"desc,omitempty"`
+	ScheduleId    string                 `protobuf:"bytes,4,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"` // 定时/延迟投递时返回的计划 ID
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -126,20 +175,1742 @@ func (x *BroadCastReply) GetDesc() string {
 	return ""
 }
 
-var File_broadcast_broadcast_proto protoreflect.FileDescriptor
+func (x *BroadCastReply) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
 
-const file_broadcast_broadcast_proto_rawDesc = "" +
-	"\n" +
-	"\x19broadcast/broadcast.proto\x12\bprotocol\x1a\x17protocol/protocol.proto\"5\n" +
-	"\fBroadCastReq\x12%\n" +
-	"\x05proto\x18\x01 \x01(\v2\x0f.protocol.ProtoR\x05proto\"J\n" +
-	"\x0eBroadCastReply\x12\x12\n" +
-	"\x04code\x18\x01 \x01(\tR\x04code\x12\x10\n" +
-	"\x03msg\x18\x02 \x01(\tR\x03msg\x12\x12\n" +
-	"\x04desc\x18\x03 \x01(\tR\x04desc2K\n" +
-	"\n" +
-	"PushServer\x12=\n" +
-	"\tBroadcast\x12\x16.protocol.BroadCastReq\x1a\x18.protocol.BroadCastReplyBGZEgithub.com/livekit/psrpc/examples/pubsub/protocol/broadcast;broadcastb\x06proto3"
+// 房间广播请求
+type BroadCastRoomReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Proto         *protocol.Proto        `protobuf:"bytes,2,opt,name=proto,proto3" json:"proto,omitempty"`
+	DeliverAt     int64                  `protobuf:"varint,3,opt,name=deliver_at,json=deliverAt,proto3" json:"deliver_at,omitempty"` // 定时投递时间（Unix 毫秒），0 表示立即投递
+	DelayMs       int64                  `protobuf:"varint,4,opt,name=delay_ms,json=delayMs,proto3" json:"delay_ms,omitempty"`       // 延迟投递（毫秒）
+	MessageId     string                 `protobuf:"bytes,5,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`  // 幂等 key
+	Priority      protocol.Priority      `protobuf:"varint,6,opt,name=priority,proto3,enum=protocol.Priority" json:"priority,omitempty"`
+	ExpireAt      int64                  `protobuf:"varint,7,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`    // 过期时间（Unix 毫秒），0 表示不过期
+	AppId         string                 `protobuf:"bytes,8,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`              // 租户，room_id 为租户内的房间 ID
+	AssignSeq     bool                   `protobuf:"varint,9,opt,name=assign_seq,json=assignSeq,proto3" json:"assign_seq,omitempty"` // 由 Push-Manager 按 (房间, 发送者) 分配连续的 Seq（客户端发言：同一用户的多个设备共用一个消息流）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadCastRoomReq) Reset() {
+	*x = BroadCastRoomReq{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadCastRoomReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadCastRoomReq) ProtoMessage() {}
+
+func (x *BroadCastRoomReq) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadCastRoomReq.ProtoReflect.Descriptor instead.
+func (*BroadCastRoomReq) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BroadCastRoomReq) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *BroadCastRoomReq) GetProto() *protocol.Proto {
+	if x != nil {
+		return x.Proto
+	}
+	return nil
+}
+
+func (x *BroadCastRoomReq) GetDeliverAt() int64 {
+	if x != nil {
+		return x.DeliverAt
+	}
+	return 0
+}
+
+func (x *BroadCastRoomReq) GetDelayMs() int64 {
+	if x != nil {
+		return x.DelayMs
+	}
+	return 0
+}
+
+func (x *BroadCastRoomReq) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *BroadCastRoomReq) GetPriority() protocol.Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return protocol.Priority(0)
+}
+
+func (x *BroadCastRoomReq) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *BroadCastRoomReq) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *BroadCastRoomReq) GetAssignSeq() bool {
+	if x != nil {
+		return x.AssignSeq
+	}
+	return false
+}
+
+// 房间广播响应
+type BroadCastRoomReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	Desc          string                 `protobuf:"bytes,3,opt,name=desc,proto3" json:"desc,omitempty"`
+	ScheduleId    string                 `protobuf:"bytes,4,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadCastRoomReply) Reset() {
+	*x = BroadCastRoomReply{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadCastRoomReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadCastRoomReply) ProtoMessage() {}
+
+func (x *BroadCastRoomReply) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadCastRoomReply.ProtoReflect.Descriptor instead.
+func (*BroadCastRoomReply) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BroadCastRoomReply) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *BroadCastRoomReply) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *BroadCastRoomReply) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *BroadCastRoomReply) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+// 按用户推送请求
+type PushToUserReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	Proto         *protocol.Proto        `protobuf:"bytes,2,opt,name=proto,proto3" json:"proto,omitempty"`
+	DeviceIds     []string               `protobuf:"bytes,3,rep,name=device_ids,json=deviceIds,proto3" json:"device_ids,omitempty"`       // 只推送到这些设备（为空表示全部设备）
+	DeviceTypes   []string               `protobuf:"bytes,4,rep,name=device_types,json=deviceTypes,proto3" json:"device_types,omitempty"` // 只推送到这些类型的设备（为空表示全部类型）
+	DeliverAt     int64                  `protobuf:"varint,5,opt,name=deliver_at,json=deliverAt,proto3" json:"deliver_at,omitempty"`      // 定时投递时间（Unix 毫秒），0 表示立即投递
+	DelayMs       int64                  `protobuf:"varint,6,opt,name=delay_ms,json=delayMs,proto3" json:"delay_ms,omitempty"`            // 延迟投递（毫秒）
+	MessageId     string                 `protobuf:"bytes,7,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`       // 幂等 key
+	Priority      protocol.Priority      `protobuf:"varint,8,opt,name=priority,proto3,enum=protocol.Priority" json:"priority,omitempty"`
+	ExpireAt      int64                  `protobuf:"varint,9,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"` // 过期时间（Unix 毫秒），0 表示不过期
+	AppId         string                 `protobuf:"bytes,10,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`          // 租户，user_ids 为租户内的用户 ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushToUserReq) Reset() {
+	*x = PushToUserReq{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushToUserReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushToUserReq) ProtoMessage() {}
+
+func (x *PushToUserReq) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushToUserReq.ProtoReflect.Descriptor instead.
+func (*PushToUserReq) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PushToUserReq) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *PushToUserReq) GetProto() *protocol.Proto {
+	if x != nil {
+		return x.Proto
+	}
+	return nil
+}
+
+func (x *PushToUserReq) GetDeviceIds() []string {
+	if x != nil {
+		return x.DeviceIds
+	}
+	return nil
+}
+
+func (x *PushToUserReq) GetDeviceTypes() []string {
+	if x != nil {
+		return x.DeviceTypes
+	}
+	return nil
+}
+
+func (x *PushToUserReq) GetDeliverAt() int64 {
+	if x != nil {
+		return x.DeliverAt
+	}
+	return 0
+}
+
+func (x *PushToUserReq) GetDelayMs() int64 {
+	if x != nil {
+		return x.DelayMs
+	}
+	return 0
+}
+
+func (x *PushToUserReq) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *PushToUserReq) GetPriority() protocol.Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return protocol.Priority(0)
+}
+
+func (x *PushToUserReq) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *PushToUserReq) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+// 按用户推送响应
+type PushToUserReply struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Code           string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Msg            string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	Desc           string                 `protobuf:"bytes,3,opt,name=desc,proto3" json:"desc,omitempty"`
+	OfflineUserIds []string               `protobuf:"bytes,4,rep,name=offline_user_ids,json=offlineUserIds,proto3" json:"offline_user_ids,omitempty"` // 索引中没有连接的用户（定时投递时为空，到期时才路由）
+	ScheduleId     string                 `protobuf:"bytes,5,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PushToUserReply) Reset() {
+	*x = PushToUserReply{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushToUserReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushToUserReply) ProtoMessage() {}
+
+func (x *PushToUserReply) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushToUserReply.ProtoReflect.Descriptor instead.
+func (*PushToUserReply) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PushToUserReply) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *PushToUserReply) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *PushToUserReply) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *PushToUserReply) GetOfflineUserIds() []string {
+	if x != nil {
+		return x.OfflineUserIds
+	}
+	return nil
+}
+
+func (x *PushToUserReply) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+// 主题发布请求：推送给订阅了匹配主题的连接（与房间无关）
+type PublishTopicReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topic         string                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"` // 按 "." 分段的主题，如 stock.AAPL.trade（不能包含通配符）
+	Proto         *protocol.Proto        `protobuf:"bytes,2,opt,name=proto,proto3" json:"proto,omitempty"`
+	DeliverAt     int64                  `protobuf:"varint,3,opt,name=deliver_at,json=deliverAt,proto3" json:"deliver_at,omitempty"` // 定时投递时间（Unix 毫秒），0 表示立即投递
+	DelayMs       int64                  `protobuf:"varint,4,opt,name=delay_ms,json=delayMs,proto3" json:"delay_ms,omitempty"`       // 延迟投递（毫秒）
+	MessageId     string                 `protobuf:"bytes,5,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`  // 幂等 key
+	Priority      protocol.Priority      `protobuf:"varint,6,opt,name=priority,proto3,enum=protocol.Priority" json:"priority,omitempty"`
+	ExpireAt      int64                  `protobuf:"varint,7,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"` // 过期时间（Unix 毫秒），0 表示不过期
+	AppId         string                 `protobuf:"bytes,8,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`           // 租户：只推送给该租户订阅了匹配模式的连接
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishTopicReq) Reset() {
+	*x = PublishTopicReq{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishTopicReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishTopicReq) ProtoMessage() {}
+
+func (x *PublishTopicReq) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishTopicReq.ProtoReflect.Descriptor instead.
+func (*PublishTopicReq) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PublishTopicReq) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *PublishTopicReq) GetProto() *protocol.Proto {
+	if x != nil {
+		return x.Proto
+	}
+	return nil
+}
+
+func (x *PublishTopicReq) GetDeliverAt() int64 {
+	if x != nil {
+		return x.DeliverAt
+	}
+	return 0
+}
+
+func (x *PublishTopicReq) GetDelayMs() int64 {
+	if x != nil {
+		return x.DelayMs
+	}
+	return 0
+}
+
+func (x *PublishTopicReq) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *PublishTopicReq) GetPriority() protocol.Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return protocol.Priority(0)
+}
+
+func (x *PublishTopicReq) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *PublishTopicReq) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+// 主题发布响应
+type PublishTopicReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	Desc          string                 `protobuf:"bytes,3,opt,name=desc,proto3" json:"desc,omitempty"`
+	ScheduleId    string                 `protobuf:"bytes,4,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishTopicReply) Reset() {
+	*x = PublishTopicReply{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishTopicReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishTopicReply) ProtoMessage() {}
+
+func (x *PublishTopicReply) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishTopicReply.ProtoReflect.Descriptor instead.
+func (*PublishTopicReply) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PublishTopicReply) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *PublishTopicReply) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *PublishTopicReply) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *PublishTopicReply) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+// 向用户的客户端发起请求（如登录确认、上报应用状态）并等待响应
+type RequestClientReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`     // 指定设备；为空时发给用户的全部会话，最先响应的生效
+	Body          []byte                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`                             // 请求体
+	TimeoutMs     int64                  `protobuf:"varint,4,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"` // 等待响应的超时，默认 10 秒，最长 60 秒
+	AppId         string                 `protobuf:"bytes,5,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`              // 租户，user_id 为租户内的用户 ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestClientReq) Reset() {
+	*x = RequestClientReq{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestClientReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestClientReq) ProtoMessage() {}
+
+func (x *RequestClientReq) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestClientReq.ProtoReflect.Descriptor instead.
+func (*RequestClientReq) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RequestClientReq) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RequestClientReq) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *RequestClientReq) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *RequestClientReq) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *RequestClientReq) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+// 客户端响应
+type RequestClientReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"` // OK / INVALID_ARGUMENT / OFFLINE / TIMEOUT / UNAVAILABLE
+	Desc          string                 `protobuf:"bytes,3,opt,name=desc,proto3" json:"desc,omitempty"`
+	RequestId     string                 `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ConnId        string                 `protobuf:"bytes,5,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"` // 响应的连接
+	Body          []byte                 `protobuf:"bytes,6,opt,name=body,proto3" json:"body,omitempty"`                   // 客户端响应体
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestClientReply) Reset() {
+	*x = RequestClientReply{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestClientReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestClientReply) ProtoMessage() {}
+
+func (x *RequestClientReply) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestClientReply.ProtoReflect.Descriptor instead.
+func (*RequestClientReply) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RequestClientReply) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *RequestClientReply) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *RequestClientReply) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *RequestClientReply) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *RequestClientReply) GetConnId() string {
+	if x != nil {
+		return x.ConnId
+	}
+	return ""
+}
+
+func (x *RequestClientReply) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+// 死信：重试耗尽仍推送失败的出站消息
+type DeadLetter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                    // 死信 ID（Redis stream entry id）
+	Node          string                 `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`                                // 目标 Connect-Node 地址
+	Kind          string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`                                // broadcast / room / user
+	Attempts      int32                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`                       // 已尝试次数
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`                              // 最后一次失败原因
+	EnqueuedAt    int64                  `protobuf:"varint,6,opt,name=enqueued_at,json=enqueuedAt,proto3" json:"enqueued_at,omitempty"` // 入队时间（毫秒）
+	FailedAt      int64                  `protobuf:"varint,7,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`       // 进入死信时间（毫秒）
+	RoomId        string                 `protobuf:"bytes,8,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`              // kind=room 时的房间
+	Keys          []string               `protobuf:"bytes,9,rep,name=keys,proto3" json:"keys,omitempty"`                                // kind=user 时的会话 key
+	Proto         *protocol.Proto        `protobuf:"bytes,10,opt,name=proto,proto3" json:"proto,omitempty"`
+	Topic         string                 `protobuf:"bytes,11,opt,name=topic,proto3" json:"topic,omitempty"` // kind=topic 时的主题
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetter) ProtoMessage() {}
+
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeadLetter) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetEnqueuedAt() int64 {
+	if x != nil {
+		return x.EnqueuedAt
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetFailedAt() int64 {
+	if x != nil {
+		return x.FailedAt
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *DeadLetter) GetProto() *protocol.Proto {
+	if x != nil {
+		return x.Proto
+	}
+	return nil
+}
+
+func (x *DeadLetter) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+// 列出死信请求
+type ListDeadLettersReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Node          string                 `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`                      // 按节点过滤（为空表示全部）
+	StartId       string                 `protobuf:"bytes,2,opt,name=start_id,json=startId,proto3" json:"start_id,omitempty"` // 分页起点（不含），为空从头开始
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                   // 默认 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersReq) Reset() {
+	*x = ListDeadLettersReq{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersReq) ProtoMessage() {}
+
+func (x *ListDeadLettersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersReq.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersReq) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListDeadLettersReq) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
+func (x *ListDeadLettersReq) GetStartId() string {
+	if x != nil {
+		return x.StartId
+	}
+	return ""
+}
+
+func (x *ListDeadLettersReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// 列出死信响应
+type ListDeadLettersReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Letters       []*DeadLetter          `protobuf:"bytes,1,rep,name=letters,proto3" json:"letters,omitempty"`
+	NextId        string                 `protobuf:"bytes,2,opt,name=next_id,json=nextId,proto3" json:"next_id,omitempty"` // 下一页起点，为空表示没有更多
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersReply) Reset() {
+	*x = ListDeadLettersReply{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersReply) ProtoMessage() {}
+
+func (x *ListDeadLettersReply) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersReply.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersReply) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListDeadLettersReply) GetLetters() []*DeadLetter {
+	if x != nil {
+		return x.Letters
+	}
+	return nil
+}
+
+func (x *ListDeadLettersReply) GetNextId() string {
+	if x != nil {
+		return x.NextId
+	}
+	return ""
+}
+
+// 重放死信请求
+type ReplayDeadLettersReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`  // 指定死信 ID
+	All           bool                   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"` // 重放全部死信（可配合 node 过滤）
+	Node          string                 `protobuf:"bytes,3,opt,name=node,proto3" json:"node,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayDeadLettersReq) Reset() {
+	*x = ReplayDeadLettersReq{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayDeadLettersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayDeadLettersReq) ProtoMessage() {}
+
+func (x *ReplayDeadLettersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayDeadLettersReq.ProtoReflect.Descriptor instead.
+func (*ReplayDeadLettersReq) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReplayDeadLettersReq) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *ReplayDeadLettersReq) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+func (x *ReplayDeadLettersReq) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
+// 重放死信响应
+type ReplayDeadLettersReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Replayed      int32                  `protobuf:"varint,1,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	FailedIds     []string               `protobuf:"bytes,2,rep,name=failed_ids,json=failedIds,proto3" json:"failed_ids,omitempty"`    // 重放失败（如目标节点已下线）的死信，仍保留在死信队列
+	ExpiredIds    []string               `protobuf:"bytes,3,rep,name=expired_ids,json=expiredIds,proto3" json:"expired_ids,omitempty"` // 消息已过期未重放的死信（已从死信队列删除）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayDeadLettersReply) Reset() {
+	*x = ReplayDeadLettersReply{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayDeadLettersReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayDeadLettersReply) ProtoMessage() {}
+
+func (x *ReplayDeadLettersReply) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayDeadLettersReply.ProtoReflect.Descriptor instead.
+func (*ReplayDeadLettersReply) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReplayDeadLettersReply) GetReplayed() int32 {
+	if x != nil {
+		return x.Replayed
+	}
+	return 0
+}
+
+func (x *ReplayDeadLettersReply) GetFailedIds() []string {
+	if x != nil {
+		return x.FailedIds
+	}
+	return nil
+}
+
+func (x *ReplayDeadLettersReply) GetExpiredIds() []string {
+	if x != nil {
+		return x.ExpiredIds
+	}
+	return nil
+}
+
+// 待投递的定时推送
+type ScheduledPush struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`                             // broadcast / room / user
+	DeliverAt     int64                  `protobuf:"varint,3,opt,name=deliver_at,json=deliverAt,proto3" json:"deliver_at,omitempty"` // Unix 毫秒
+	CreatedAt     int64                  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix 毫秒
+	RoomId        string                 `protobuf:"bytes,5,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`           // kind=room
+	UserIds       []string               `protobuf:"bytes,6,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`        // kind=user
+	Proto         *protocol.Proto        `protobuf:"bytes,7,opt,name=proto,proto3" json:"proto,omitempty"`
+	Topic         string                 `protobuf:"bytes,8,opt,name=topic,proto3" json:"topic,omitempty"` // kind=topic
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledPush) Reset() {
+	*x = ScheduledPush{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledPush) ProtoMessage() {}
+
+func (x *ScheduledPush) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledPush.ProtoReflect.Descriptor instead.
+func (*ScheduledPush) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ScheduledPush) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ScheduledPush) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ScheduledPush) GetDeliverAt() int64 {
+	if x != nil {
+		return x.DeliverAt
+	}
+	return 0
+}
+
+func (x *ScheduledPush) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ScheduledPush) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ScheduledPush) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *ScheduledPush) GetProto() *protocol.Proto {
+	if x != nil {
+		return x.Proto
+	}
+	return nil
+}
+
+func (x *ScheduledPush) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+// 取消定时推送请求
+type CancelScheduleReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduleReq) Reset() {
+	*x = CancelScheduleReq{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduleReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduleReq) ProtoMessage() {}
+
+func (x *CancelScheduleReq) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduleReq.ProtoReflect.Descriptor instead.
+func (*CancelScheduleReq) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CancelScheduleReq) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+// 取消定时推送响应
+type CancelScheduleReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cancelled     bool                   `protobuf:"varint,1,opt,name=cancelled,proto3" json:"cancelled,omitempty"` // false 表示计划不存在或已投递
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduleReply) Reset() {
+	*x = CancelScheduleReply{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduleReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduleReply) ProtoMessage() {}
+
+func (x *CancelScheduleReply) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduleReply.ProtoReflect.Descriptor instead.
+func (*CancelScheduleReply) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CancelScheduleReply) GetCancelled() bool {
+	if x != nil {
+		return x.Cancelled
+	}
+	return false
+}
+
+// 列出定时推送请求
+type ListSchedulesReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`    // 按类型过滤（为空表示全部）
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // 默认 100，按投递时间升序
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSchedulesReq) Reset() {
+	*x = ListSchedulesReq{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSchedulesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSchedulesReq) ProtoMessage() {}
+
+func (x *ListSchedulesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSchedulesReq.ProtoReflect.Descriptor instead.
+func (*ListSchedulesReq) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListSchedulesReq) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ListSchedulesReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// 列出定时推送响应
+type ListSchedulesReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedules     []*ScheduledPush       `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSchedulesReply) Reset() {
+	*x = ListSchedulesReply{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSchedulesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSchedulesReply) ProtoMessage() {}
+
+func (x *ListSchedulesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSchedulesReply.ProtoReflect.Descriptor instead.
+func (*ListSchedulesReply) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListSchedulesReply) GetSchedules() []*ScheduledPush {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+// 房间历史消息
+type HistoryMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                // 历史 ID（Redis stream entry id，分页游标）
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // 推送请求的幂等 key（未携带时为空）
+	Proto         *protocol.Proto        `protobuf:"bytes,3,opt,name=proto,proto3" json:"proto,omitempty"`
+	PublishedAt   int64                  `protobuf:"varint,4,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"` // 进入推送队列的时间（毫秒）
+	ExpireAt      int64                  `protobuf:"varint,5,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`          // 过期时间（Unix 毫秒），0 表示不过期；过期的消息不再返回
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryMessage) Reset() {
+	*x = HistoryMessage{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryMessage) ProtoMessage() {}
+
+func (x *HistoryMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryMessage.ProtoReflect.Descriptor instead.
+func (*HistoryMessage) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *HistoryMessage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *HistoryMessage) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *HistoryMessage) GetProto() *protocol.Proto {
+	if x != nil {
+		return x.Proto
+	}
+	return nil
+}
+
+func (x *HistoryMessage) GetPublishedAt() int64 {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return 0
+}
+
+func (x *HistoryMessage) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+// 查询房间历史消息请求
+type ListRoomHistoryReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Before        string                 `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"`            // 分页起点（不含），为空从最新一条开始
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`             // 默认 50
+	AppId         string                 `protobuf:"bytes,4,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"` // 租户 ID（为空表示默认租户）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoomHistoryReq) Reset() {
+	*x = ListRoomHistoryReq{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoomHistoryReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoomHistoryReq) ProtoMessage() {}
+
+func (x *ListRoomHistoryReq) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoomHistoryReq.ProtoReflect.Descriptor instead.
+func (*ListRoomHistoryReq) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListRoomHistoryReq) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ListRoomHistoryReq) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *ListRoomHistoryReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListRoomHistoryReq) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+// 查询房间历史消息响应（从新到旧）
+type ListRoomHistoryReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*HistoryMessage      `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	NextBefore    string                 `protobuf:"bytes,2,opt,name=next_before,json=nextBefore,proto3" json:"next_before,omitempty"` // 下一页起点，为空表示没有更多
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoomHistoryReply) Reset() {
+	*x = ListRoomHistoryReply{}
+	mi := &file_broadcast_broadcast_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoomHistoryReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoomHistoryReply) ProtoMessage() {}
+
+func (x *ListRoomHistoryReply) ProtoReflect() protoreflect.Message {
+	mi := &file_broadcast_broadcast_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoomHistoryReply.ProtoReflect.Descriptor instead.
+func (*ListRoomHistoryReply) Descriptor() ([]byte, []int) {
+	return file_broadcast_broadcast_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListRoomHistoryReply) GetMessages() []*HistoryMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ListRoomHistoryReply) GetNextBefore() string {
+	if x != nil {
+		return x.NextBefore
+	}
+	return ""
+}
+
+var File_broadcast_broadcast_proto protoreflect.FileDescriptor
+
+const file_broadcast_broadcast_proto_rawDesc = "" +
+	"\n" +
+	"\x19broadcast/broadcast.proto\x12\bprotocol\x1a\x17protocol/protocol.proto\"\xf2\x01\n" +
+	"\fBroadCastReq\x12%\n" +
+	"\x05proto\x18\x01 \x01(\v2\x0f.protocol.ProtoR\x05proto\x12\x1d\n" +
+	"\n" +
+	"deliver_at\x18\x02 \x01(\x03R\tdeliverAt\x12\x19\n" +
+	"\bdelay_ms\x18\x03 \x01(\x03R\adelayMs\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x04 \x01(\tR\tmessageId\x12.\n" +
+	"\bpriority\x18\x05 \x01(\x0e2\x12.protocol.PriorityR\bpriority\x12\x1b\n" +
+	"\texpire_at\x18\x06 \x01(\x03R\bexpireAt\x12\x15\n" +
+	"\x06app_id\x18\a \x01(\tR\x05appId\"k\n" +
+	"\x0eBroadCastReply\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x12\x12\n" +
+	"\x04desc\x18\x03 \x01(\tR\x04desc\x12\x1f\n" +
+	"\vschedule_id\x18\x04 \x01(\tR\n" +
+	"scheduleId\"\xae\x02\n" +
+	"\x10BroadCastRoomReq\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12%\n" +
+	"\x05proto\x18\x02 \x01(\v2\x0f.protocol.ProtoR\x05proto\x12\x1d\n" +
+	"\n" +
+	"deliver_at\x18\x03 \x01(\x03R\tdeliverAt\x12\x19\n" +
+	"\bdelay_ms\x18\x04 \x01(\x03R\adelayMs\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x05 \x01(\tR\tmessageId\x12.\n" +
+	"\bpriority\x18\x06 \x01(\x0e2\x12.protocol.PriorityR\bpriority\x12\x1b\n" +
+	"\texpire_at\x18\a \x01(\x03R\bexpireAt\x12\x15\n" +
+	"\x06app_id\x18\b \x01(\tR\x05appId\x12\x1d\n" +
+	"\n" +
+	"assign_seq\x18\t \x01(\bR\tassignSeq\"o\n" +
+	"\x12BroadCastRoomReply\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x12\x12\n" +
+	"\x04desc\x18\x03 \x01(\tR\x04desc\x12\x1f\n" +
+	"\vschedule_id\x18\x04 \x01(\tR\n" +
+	"scheduleId\"\xd0\x02\n" +
+	"\rPushToUserReq\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\x12%\n" +
+	"\x05proto\x18\x02 \x01(\v2\x0f.protocol.ProtoR\x05proto\x12\x1d\n" +
+	"\n" +
+	"device_ids\x18\x03 \x03(\tR\tdeviceIds\x12!\n" +
+	"\fdevice_types\x18\x04 \x03(\tR\vdeviceTypes\x12\x1d\n" +
+	"\n" +
+	"deliver_at\x18\x05 \x01(\x03R\tdeliverAt\x12\x19\n" +
+	"\bdelay_ms\x18\x06 \x01(\x03R\adelayMs\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\a \x01(\tR\tmessageId\x12.\n" +
+	"\bpriority\x18\b \x01(\x0e2\x12.protocol.PriorityR\bpriority\x12\x1b\n" +
+	"\texpire_at\x18\t \x01(\x03R\bexpireAt\x12\x15\n" +
+	"\x06app_id\x18\n" +
+	" \x01(\tR\x05appId\"\x96\x01\n" +
+	"\x0fPushToUserReply\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x12\x12\n" +
+	"\x04desc\x18\x03 \x01(\tR\x04desc\x12(\n" +
+	"\x10offline_user_ids\x18\x04 \x03(\tR\x0eofflineUserIds\x12\x1f\n" +
+	"\vschedule_id\x18\x05 \x01(\tR\n" +
+	"scheduleId\"\x8b\x02\n" +
+	"\x0fPublishTopicReq\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\x12%\n" +
+	"\x05proto\x18\x02 \x01(\v2\x0f.protocol.ProtoR\x05proto\x12\x1d\n" +
+	"\n" +
+	"deliver_at\x18\x03 \x01(\x03R\tdeliverAt\x12\x19\n" +
+	"\bdelay_ms\x18\x04 \x01(\x03R\adelayMs\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x05 \x01(\tR\tmessageId\x12.\n" +
+	"\bpriority\x18\x06 \x01(\x0e2\x12.protocol.PriorityR\bpriority\x12\x1b\n" +
+	"\texpire_at\x18\a \x01(\x03R\bexpireAt\x12\x15\n" +
+	"\x06app_id\x18\b \x01(\tR\x05appId\"n\n" +
+	"\x11PublishTopicReply\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x12\x12\n" +
+	"\x04desc\x18\x03 \x01(\tR\x04desc\x12\x1f\n" +
+	"\vschedule_id\x18\x04 \x01(\tR\n" +
+	"scheduleId\"\x92\x01\n" +
+	"\x10RequestClientReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\fR\x04body\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x04 \x01(\x03R\ttimeoutMs\x12\x15\n" +
+	"\x06app_id\x18\x05 \x01(\tR\x05appId\"\x9a\x01\n" +
+	"\x12RequestClientReply\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x12\x12\n" +
+	"\x04desc\x18\x03 \x01(\tR\x04desc\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x04 \x01(\tR\trequestId\x12\x17\n" +
+	"\aconn_id\x18\x05 \x01(\tR\x06connId\x12\x12\n" +
+	"\x04body\x18\x06 \x01(\fR\x04body\"\x9e\x02\n" +
+	"\n" +
+	"DeadLetter\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04node\x18\x02 \x01(\tR\x04node\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x1a\n" +
+	"\battempts\x18\x04 \x01(\x05R\battempts\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x1f\n" +
+	"\venqueued_at\x18\x06 \x01(\x03R\n" +
+	"enqueuedAt\x12\x1b\n" +
+	"\tfailed_at\x18\a \x01(\x03R\bfailedAt\x12\x17\n" +
+	"\aroom_id\x18\b \x01(\tR\x06roomId\x12\x12\n" +
+	"\x04keys\x18\t \x03(\tR\x04keys\x12%\n" +
+	"\x05proto\x18\n" +
+	" \x01(\v2\x0f.protocol.ProtoR\x05proto\x12\x14\n" +
+	"\x05topic\x18\v \x01(\tR\x05topic\"Y\n" +
+	"\x12ListDeadLettersReq\x12\x12\n" +
+	"\x04node\x18\x01 \x01(\tR\x04node\x12\x19\n" +
+	"\bstart_id\x18\x02 \x01(\tR\astartId\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"_\n" +
+	"\x14ListDeadLettersReply\x12.\n" +
+	"\aletters\x18\x01 \x03(\v2\x14.protocol.DeadLetterR\aletters\x12\x17\n" +
+	"\anext_id\x18\x02 \x01(\tR\x06nextId\"N\n" +
+	"\x14ReplayDeadLettersReq\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\x12\x12\n" +
+	"\x04node\x18\x03 \x01(\tR\x04node\"t\n" +
+	"\x16ReplayDeadLettersReply\x12\x1a\n" +
+	"\breplayed\x18\x01 \x01(\x05R\breplayed\x12\x1d\n" +
+	"\n" +
+	"failed_ids\x18\x02 \x03(\tR\tfailedIds\x12\x1f\n" +
+	"\vexpired_ids\x18\x03 \x03(\tR\n" +
+	"expiredIds\"\xe2\x01\n" +
+	"\rScheduledPush\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x1d\n" +
+	"\n" +
+	"deliver_at\x18\x03 \x01(\x03R\tdeliverAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x12\x17\n" +
+	"\aroom_id\x18\x05 \x01(\tR\x06roomId\x12\x19\n" +
+	"\buser_ids\x18\x06 \x03(\tR\auserIds\x12%\n" +
+	"\x05proto\x18\a \x01(\v2\x0f.protocol.ProtoR\x05proto\x12\x14\n" +
+	"\x05topic\x18\b \x01(\tR\x05topic\"4\n" +
+	"\x11CancelScheduleReq\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\tR\n" +
+	"scheduleId\"3\n" +
+	"\x13CancelScheduleReply\x12\x1c\n" +
+	"\tcancelled\x18\x01 \x01(\bR\tcancelled\"<\n" +
+	"\x10ListSchedulesReq\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"K\n" +
+	"\x12ListSchedulesReply\x125\n" +
+	"\tschedules\x18\x01 \x03(\v2\x17.protocol.ScheduledPushR\tschedules\"\xa6\x01\n" +
+	"\x0eHistoryMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12%\n" +
+	"\x05proto\x18\x03 \x01(\v2\x0f.protocol.ProtoR\x05proto\x12!\n" +
+	"\fpublished_at\x18\x04 \x01(\x03R\vpublishedAt\x12\x1b\n" +
+	"\texpire_at\x18\x05 \x01(\x03R\bexpireAt\"r\n" +
+	"\x12ListRoomHistoryReq\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x16\n" +
+	"\x06before\x18\x02 \x01(\tR\x06before\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x15\n" +
+	"\x06app_id\x18\x04 \x01(\tR\x05appId\"m\n" +
+	"\x14ListRoomHistoryReply\x124\n" +
+	"\bmessages\x18\x01 \x03(\v2\x18.protocol.HistoryMessageR\bmessages\x12\x1f\n" +
+	"\vnext_before\x18\x02 \x01(\tR\n" +
+	"nextBefore2\xff\x05\n" +
+	"\n" +
+	"PushServer\x12=\n" +
+	"\tBroadcast\x12\x16.protocol.BroadCastReq\x1a\x18.protocol.BroadCastReply\x12K\n" +
+	"\x0fBroadcastToRoom\x12\x1a.protocol.BroadCastRoomReq\x1a\x1c.protocol.BroadCastRoomReply\x12@\n" +
+	"\n" +
+	"PushToUser\x12\x17.protocol.PushToUserReq\x1a\x19.protocol.PushToUserReply\x12F\n" +
+	"\fPublishTopic\x12\x19.protocol.PublishTopicReq\x1a\x1b.protocol.PublishTopicReply\x12I\n" +
+	"\rRequestClient\x12\x1a.protocol.RequestClientReq\x1a\x1c.protocol.RequestClientReply\x12O\n" +
+	"\x0fListDeadLetters\x12\x1c.protocol.ListDeadLettersReq\x1a\x1e.protocol.ListDeadLettersReply\x12U\n" +
+	"\x11ReplayDeadLetters\x12\x1e.protocol.ReplayDeadLettersReq\x1a .protocol.ReplayDeadLettersReply\x12L\n" +
+	"\x0eCancelSchedule\x12\x1b.protocol.CancelScheduleReq\x1a\x1d.protocol.CancelScheduleReply\x12I\n" +
+	"\rListSchedules\x12\x1a.protocol.ListSchedulesReq\x1a\x1c.protocol.ListSchedulesReply\x12O\n" +
+	"\x0fListRoomHistory\x12\x1c.protocol.ListRoomHistoryReq\x1a\x1e.protocol.ListRoomHistoryReplyBGZEgithub.com/livekit/psrpc/examples/pubsub/protocol/broadcast;broadcastb\x06proto3"
 
 var (
 	file_broadcast_broadcast_proto_rawDescOnce sync.Once
@@ -153,21 +1924,74 @@ func file_broadcast_broadcast_proto_rawDescGZIP() []byte {
 	return file_broadcast_broadcast_proto_rawDescData
 }
 
-var file_broadcast_broadcast_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_broadcast_broadcast_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_broadcast_broadcast_proto_goTypes = []any{
-	(*BroadCastReq)(nil),   // 0: protocol.BroadCastReq
-	(*BroadCastReply)(nil), // 1: protocol.BroadCastReply
-	(*protocol.Proto)(nil), // 2: protocol.Proto
+	(*BroadCastReq)(nil),           // 0: protocol.BroadCastReq
+	(*BroadCastReply)(nil),         // 1: protocol.BroadCastReply
+	(*BroadCastRoomReq)(nil),       // 2: protocol.BroadCastRoomReq
+	(*BroadCastRoomReply)(nil),     // 3: protocol.BroadCastRoomReply
+	(*PushToUserReq)(nil),          // 4: protocol.PushToUserReq
+	(*PushToUserReply)(nil),        // 5: protocol.PushToUserReply
+	(*PublishTopicReq)(nil),        // 6: protocol.PublishTopicReq
+	(*PublishTopicReply)(nil),      // 7: protocol.PublishTopicReply
+	(*RequestClientReq)(nil),       // 8: protocol.RequestClientReq
+	(*RequestClientReply)(nil),     // 9: protocol.RequestClientReply
+	(*DeadLetter)(nil),             // 10: protocol.DeadLetter
+	(*ListDeadLettersReq)(nil),     // 11: protocol.ListDeadLettersReq
+	(*ListDeadLettersReply)(nil),   // 12: protocol.ListDeadLettersReply
+	(*ReplayDeadLettersReq)(nil),   // 13: protocol.ReplayDeadLettersReq
+	(*ReplayDeadLettersReply)(nil), // 14: protocol.ReplayDeadLettersReply
+	(*ScheduledPush)(nil),          // 15: protocol.ScheduledPush
+	(*CancelScheduleReq)(nil),      // 16: protocol.CancelScheduleReq
+	(*CancelScheduleReply)(nil),    // 17: protocol.CancelScheduleReply
+	(*ListSchedulesReq)(nil),       // 18: protocol.ListSchedulesReq
+	(*ListSchedulesReply)(nil),     // 19: protocol.ListSchedulesReply
+	(*HistoryMessage)(nil),         // 20: protocol.HistoryMessage
+	(*ListRoomHistoryReq)(nil),     // 21: protocol.ListRoomHistoryReq
+	(*ListRoomHistoryReply)(nil),   // 22: protocol.ListRoomHistoryReply
+	(*protocol.Proto)(nil),         // 23: protocol.Proto
+	(protocol.Priority)(0),         // 24: protocol.Priority
 }
 var file_broadcast_broadcast_proto_depIdxs = []int32{
-	2, // 0: protocol.BroadCastReq.proto:type_name -> protocol.Proto
-	0, // 1: protocol.PushServer.Broadcast:input_type -> protocol.BroadCastReq
-	1, // 2: protocol.PushServer.Broadcast:output_type -> protocol.BroadCastReply
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	23, // 0: protocol.BroadCastReq.proto:type_name -> protocol.Proto
+	24, // 1: protocol.BroadCastReq.priority:type_name -> protocol.Priority
+	23, // 2: protocol.BroadCastRoomReq.proto:type_name -> protocol.Proto
+	24, // 3: protocol.BroadCastRoomReq.priority:type_name -> protocol.Priority
+	23, // 4: protocol.PushToUserReq.proto:type_name -> protocol.Proto
+	24, // 5: protocol.PushToUserReq.priority:type_name -> protocol.Priority
+	23, // 6: protocol.PublishTopicReq.proto:type_name -> protocol.Proto
+	24, // 7: protocol.PublishTopicReq.priority:type_name -> protocol.Priority
+	23, // 8: protocol.DeadLetter.proto:type_name -> protocol.Proto
+	10, // 9: protocol.ListDeadLettersReply.letters:type_name -> protocol.DeadLetter
+	23, // 10: protocol.ScheduledPush.proto:type_name -> protocol.Proto
+	15, // 11: protocol.ListSchedulesReply.schedules:type_name -> protocol.ScheduledPush
+	23, // 12: protocol.HistoryMessage.proto:type_name -> protocol.Proto
+	20, // 13: protocol.ListRoomHistoryReply.messages:type_name -> protocol.HistoryMessage
+	0,  // 14: protocol.PushServer.Broadcast:input_type -> protocol.BroadCastReq
+	2,  // 15: protocol.PushServer.BroadcastToRoom:input_type -> protocol.BroadCastRoomReq
+	4,  // 16: protocol.PushServer.PushToUser:input_type -> protocol.PushToUserReq
+	6,  // 17: protocol.PushServer.PublishTopic:input_type -> protocol.PublishTopicReq
+	8,  // 18: protocol.PushServer.RequestClient:input_type -> protocol.RequestClientReq
+	11, // 19: protocol.PushServer.ListDeadLetters:input_type -> protocol.ListDeadLettersReq
+	13, // 20: protocol.PushServer.ReplayDeadLetters:input_type -> protocol.ReplayDeadLettersReq
+	16, // 21: protocol.PushServer.CancelSchedule:input_type -> protocol.CancelScheduleReq
+	18, // 22: protocol.PushServer.ListSchedules:input_type -> protocol.ListSchedulesReq
+	21, // 23: protocol.PushServer.ListRoomHistory:input_type -> protocol.ListRoomHistoryReq
+	1,  // 24: protocol.PushServer.Broadcast:output_type -> protocol.BroadCastReply
+	3,  // 25: protocol.PushServer.BroadcastToRoom:output_type -> protocol.BroadCastRoomReply
+	5,  // 26: protocol.PushServer.PushToUser:output_type -> protocol.PushToUserReply
+	7,  // 27: protocol.PushServer.PublishTopic:output_type -> protocol.PublishTopicReply
+	9,  // 28: protocol.PushServer.RequestClient:output_type -> protocol.RequestClientReply
+	12, // 29: protocol.PushServer.ListDeadLetters:output_type -> protocol.ListDeadLettersReply
+	14, // 30: protocol.PushServer.ReplayDeadLetters:output_type -> protocol.ReplayDeadLettersReply
+	17, // 31: protocol.PushServer.CancelSchedule:output_type -> protocol.CancelScheduleReply
+	19, // 32: protocol.PushServer.ListSchedules:output_type -> protocol.ListSchedulesReply
+	22, // 33: protocol.PushServer.ListRoomHistory:output_type -> protocol.ListRoomHistoryReply
+	24, // [24:34] is the sub-list for method output_type
+	14, // [14:24] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_broadcast_broadcast_proto_init() }
@@ -181,7 +2005,7 @@ func file_broadcast_broadcast_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_broadcast_broadcast_proto_rawDesc), len(file_broadcast_broadcast_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PushServer_Broadcast_FullMethodName = "/protocol.PushServer/Broadcast"
+	PushServer_Broadcast_FullMethodName         = "/protocol.PushServer/Broadcast"
+	PushServer_BroadcastToRoom_FullMethodName   = "/protocol.PushServer/BroadcastToRoom"
+	PushServer_PushToUser_FullMethodName        = "/protocol.PushServer/PushToUser"
+	PushServer_PublishTopic_FullMethodName      = "/protocol.PushServer/PublishTopic"
+	PushServer_RequestClient_FullMethodName     = "/protocol.PushServer/RequestClient"
+	PushServer_ListDeadLetters_FullMethodName   = "/protocol.PushServer/ListDeadLetters"
+	PushServer_ReplayDeadLetters_FullMethodName = "/protocol.PushServer/ReplayDeadLetters"
+	PushServer_CancelSchedule_FullMethodName    = "/protocol.PushServer/CancelSchedule"
+	PushServer_ListSchedules_FullMethodName     = "/protocol.PushServer/ListSchedules"
+	PushServer_ListRoomHistory_FullMethodName   = "/protocol.PushServer/ListRoomHistory"
 )
 
 // PushServerClient is the client API for PushServer service.
@@ -28,6 +37,24 @@ const (
 type PushServerClient interface {
 	// Broadcast send to every entity
 	Broadcast(ctx context.Context, in *BroadCastReq, opts ...grpc.CallOption) (*BroadCastReply, error)
+	// BroadcastToRoom broadcast to specific room
+	BroadcastToRoom(ctx context.Context, in *BroadCastRoomReq, opts ...grpc.CallOption) (*BroadCastRoomReply, error)
+	// PushToUser push to specific users, routed by the Redis user -> connections index
+	PushToUser(ctx context.Context, in *PushToUserReq, opts ...grpc.CallOption) (*PushToUserReply, error)
+	// PublishTopic publish to connections subscribed to a matching topic pattern
+	PublishTopic(ctx context.Context, in *PublishTopicReq, opts ...grpc.CallOption) (*PublishTopicReply, error)
+	// RequestClient send a request to a user's connected client and wait for its reply
+	RequestClient(ctx context.Context, in *RequestClientReq, opts ...grpc.CallOption) (*RequestClientReply, error)
+	// ListDeadLetters list outbound messages that exhausted their retries (admin)
+	ListDeadLetters(ctx context.Context, in *ListDeadLettersReq, opts ...grpc.CallOption) (*ListDeadLettersReply, error)
+	// ReplayDeadLetters re-enqueue dead letters to their Connect-Node outbound queue (admin)
+	ReplayDeadLetters(ctx context.Context, in *ReplayDeadLettersReq, opts ...grpc.CallOption) (*ReplayDeadLettersReply, error)
+	// CancelSchedule cancel a pending scheduled push
+	CancelSchedule(ctx context.Context, in *CancelScheduleReq, opts ...grpc.CallOption) (*CancelScheduleReply, error)
+	// ListSchedules list pending scheduled pushes ordered by delivery time
+	ListSchedules(ctx context.Context, in *ListSchedulesReq, opts ...grpc.CallOption) (*ListSchedulesReply, error)
+	// ListRoomHistory list recent room messages, newest first
+	ListRoomHistory(ctx context.Context, in *ListRoomHistoryReq, opts ...grpc.CallOption) (*ListRoomHistoryReply, error)
 }
 
 type pushServerClient struct {
@@ -48,12 +75,120 @@ func (c *pushServerClient) Broadcast(ctx context.Context, in *BroadCastReq, opts
 	return out, nil
 }
 
+func (c *pushServerClient) BroadcastToRoom(ctx context.Context, in *BroadCastRoomReq, opts ...grpc.CallOption) (*BroadCastRoomReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BroadCastRoomReply)
+	err := c.cc.Invoke(ctx, PushServer_BroadcastToRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pushServerClient) PushToUser(ctx context.Context, in *PushToUserReq, opts ...grpc.CallOption) (*PushToUserReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PushToUserReply)
+	err := c.cc.Invoke(ctx, PushServer_PushToUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pushServerClient) PublishTopic(ctx context.Context, in *PublishTopicReq, opts ...grpc.CallOption) (*PublishTopicReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublishTopicReply)
+	err := c.cc.Invoke(ctx, PushServer_PublishTopic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pushServerClient) RequestClient(ctx context.Context, in *RequestClientReq, opts ...grpc.CallOption) (*RequestClientReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestClientReply)
+	err := c.cc.Invoke(ctx, PushServer_RequestClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pushServerClient) ListDeadLetters(ctx context.Context, in *ListDeadLettersReq, opts ...grpc.CallOption) (*ListDeadLettersReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeadLettersReply)
+	err := c.cc.Invoke(ctx, PushServer_ListDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pushServerClient) ReplayDeadLetters(ctx context.Context, in *ReplayDeadLettersReq, opts ...grpc.CallOption) (*ReplayDeadLettersReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayDeadLettersReply)
+	err := c.cc.Invoke(ctx, PushServer_ReplayDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pushServerClient) CancelSchedule(ctx context.Context, in *CancelScheduleReq, opts ...grpc.CallOption) (*CancelScheduleReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelScheduleReply)
+	err := c.cc.Invoke(ctx, PushServer_CancelSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pushServerClient) ListSchedules(ctx context.Context, in *ListSchedulesReq, opts ...grpc.CallOption) (*ListSchedulesReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSchedulesReply)
+	err := c.cc.Invoke(ctx, PushServer_ListSchedules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pushServerClient) ListRoomHistory(ctx context.Context, in *ListRoomHistoryReq, opts ...grpc.CallOption) (*ListRoomHistoryReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRoomHistoryReply)
+	err := c.cc.Invoke(ctx, PushServer_ListRoomHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PushServerServer is the server API for PushServer service.
 // All implementations must embed UnimplementedPushServerServer
 // for forward compatibility.
 type PushServerServer interface {
 	// Broadcast send to every entity
 	Broadcast(context.Context, *BroadCastReq) (*BroadCastReply, error)
+	// BroadcastToRoom broadcast to specific room
+	BroadcastToRoom(context.Context, *BroadCastRoomReq) (*BroadCastRoomReply, error)
+	// PushToUser push to specific users, routed by the Redis user -> connections index
+	PushToUser(context.Context, *PushToUserReq) (*PushToUserReply, error)
+	// PublishTopic publish to connections subscribed to a matching topic pattern
+	PublishTopic(context.Context, *PublishTopicReq) (*PublishTopicReply, error)
+	// RequestClient send a request to a user's connected client and wait for its reply
+	RequestClient(context.Context, *RequestClientReq) (*RequestClientReply, error)
+	// ListDeadLetters list outbound messages that exhausted their retries (admin)
+	ListDeadLetters(context.Context, *ListDeadLettersReq) (*ListDeadLettersReply, error)
+	// ReplayDeadLetters re-enqueue dead letters to their Connect-Node outbound queue (admin)
+	ReplayDeadLetters(context.Context, *ReplayDeadLettersReq) (*ReplayDeadLettersReply, error)
+	// CancelSchedule cancel a pending scheduled push
+	CancelSchedule(context.Context, *CancelScheduleReq) (*CancelScheduleReply, error)
+	// ListSchedules list pending scheduled pushes ordered by delivery time
+	ListSchedules(context.Context, *ListSchedulesReq) (*ListSchedulesReply, error)
+	// ListRoomHistory list recent room messages, newest first
+	ListRoomHistory(context.Context, *ListRoomHistoryReq) (*ListRoomHistoryReply, error)
 	mustEmbedUnimplementedPushServerServer()
 }
 
@@ -67,6 +202,33 @@ type UnimplementedPushServerServer struct{}
 func (UnimplementedPushServerServer) Broadcast(context.Context, *BroadCastReq) (*BroadCastReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Broadcast not implemented")
 }
+func (UnimplementedPushServerServer) BroadcastToRoom(context.Context, *BroadCastRoomReq) (*BroadCastRoomReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BroadcastToRoom not implemented")
+}
+func (UnimplementedPushServerServer) PushToUser(context.Context, *PushToUserReq) (*PushToUserReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushToUser not implemented")
+}
+func (UnimplementedPushServerServer) PublishTopic(context.Context, *PublishTopicReq) (*PublishTopicReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishTopic not implemented")
+}
+func (UnimplementedPushServerServer) RequestClient(context.Context, *RequestClientReq) (*RequestClientReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestClient not implemented")
+}
+func (UnimplementedPushServerServer) ListDeadLetters(context.Context, *ListDeadLettersReq) (*ListDeadLettersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (UnimplementedPushServerServer) ReplayDeadLetters(context.Context, *ReplayDeadLettersReq) (*ReplayDeadLettersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayDeadLetters not implemented")
+}
+func (UnimplementedPushServerServer) CancelSchedule(context.Context, *CancelScheduleReq) (*CancelScheduleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelSchedule not implemented")
+}
+func (UnimplementedPushServerServer) ListSchedules(context.Context, *ListSchedulesReq) (*ListSchedulesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchedules not implemented")
+}
+func (UnimplementedPushServerServer) ListRoomHistory(context.Context, *ListRoomHistoryReq) (*ListRoomHistoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRoomHistory not implemented")
+}
 func (UnimplementedPushServerServer) mustEmbedUnimplementedPushServerServer() {}
 func (UnimplementedPushServerServer) testEmbeddedByValue()                    {}
 
@@ -106,6 +268,168 @@ func _PushServer_Broadcast_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PushServer_BroadcastToRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadCastRoomReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServerServer).BroadcastToRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushServer_BroadcastToRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServerServer).BroadcastToRoom(ctx, req.(*BroadCastRoomReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PushServer_PushToUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushToUserReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServerServer).PushToUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushServer_PushToUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServerServer).PushToUser(ctx, req.(*PushToUserReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PushServer_PublishTopic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishTopicReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServerServer).PublishTopic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushServer_PublishTopic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServerServer).PublishTopic(ctx, req.(*PublishTopicReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PushServer_RequestClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestClientReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServerServer).RequestClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushServer_RequestClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServerServer).RequestClient(ctx, req.(*RequestClientReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PushServer_ListDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadLettersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServerServer).ListDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushServer_ListDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServerServer).ListDeadLetters(ctx, req.(*ListDeadLettersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PushServer_ReplayDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayDeadLettersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServerServer).ReplayDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushServer_ReplayDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServerServer).ReplayDeadLetters(ctx, req.(*ReplayDeadLettersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PushServer_CancelSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelScheduleReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServerServer).CancelSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushServer_CancelSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServerServer).CancelSchedule(ctx, req.(*CancelScheduleReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PushServer_ListSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSchedulesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServerServer).ListSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushServer_ListSchedules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServerServer).ListSchedules(ctx, req.(*ListSchedulesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PushServer_ListRoomHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRoomHistoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServerServer).ListRoomHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushServer_ListRoomHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServerServer).ListRoomHistory(ctx, req.(*ListRoomHistoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PushServer_ServiceDesc is the grpc.ServiceDesc for PushServer service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -117,6 +441,42 @@ var PushServer_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Broadcast",
 			Handler:    _PushServer_Broadcast_Handler,
 		},
+		{
+			MethodName: "BroadcastToRoom",
+			Handler:    _PushServer_BroadcastToRoom_Handler,
+		},
+		{
+			MethodName: "PushToUser",
+			Handler:    _PushServer_PushToUser_Handler,
+		},
+		{
+			MethodName: "PublishTopic",
+			Handler:    _PushServer_PublishTopic_Handler,
+		},
+		{
+			MethodName: "RequestClient",
+			Handler:    _PushServer_RequestClient_Handler,
+		},
+		{
+			MethodName: "ListDeadLetters",
+			Handler:    _PushServer_ListDeadLetters_Handler,
+		},
+		{
+			MethodName: "ReplayDeadLetters",
+			Handler:    _PushServer_ReplayDeadLetters_Handler,
+		},
+		{
+			MethodName: "CancelSchedule",
+			Handler:    _PushServer_CancelSchedule_Handler,
+		},
+		{
+			MethodName: "ListSchedules",
+			Handler:    _PushServer_ListSchedules_Handler,
+		},
+		{
+			MethodName: "ListRoomHistory",
+			Handler:    _PushServer_ListRoomHistory_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "broadcast/broadcast.proto",
@@ -23,6 +23,7 @@ package controller
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -43,6 +44,8 @@ type JoinRoomRequest struct {
 	UserName      string                 `protobuf:"bytes,3,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
 	NodeId        string                 `protobuf:"bytes,4,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 	Metadata      map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Password      string                 `protobuf:"bytes,6,opt,name=password,proto3" json:"password,omitempty"`           // password 模式房间的密码
+	ConnId        string                 `protobuf:"bytes,7,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"` // 发起加入的连接（用于维护 user -> connections 索引）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -112,11 +115,26 @@ func (x *JoinRoomRequest) GetMetadata() map[string]string {
 	return nil
 }
 
+func (x *JoinRoomRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *JoinRoomRequest) GetConnId() string {
+	if x != nil {
+		return x.ConnId
+	}
+	return ""
+}
+
 type JoinRoomResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	RoomInfo      *RoomInfo              `protobuf:"bytes,3,opt,name=room_info,json=roomInfo,proto3" json:"room_info,omitempty"`
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"` // 用户在房间中的角色
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -172,10 +190,18 @@ func (x *JoinRoomResponse) GetRoomInfo() *RoomInfo {
 	return nil
 }
 
+func (x *JoinRoomResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
 type LeaveRoomRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	ConnId        string                 `protobuf:"bytes,3,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"` // 为空时从用户所有连接的房间列表中移除
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -224,6 +250,13 @@ func (x *LeaveRoomRequest) GetRoomId() string {
 	return ""
 }
 
+func (x *LeaveRoomRequest) GetConnId() string {
+	if x != nil {
+		return x.ConnId
+	}
+	return ""
+}
+
 type LeaveRoomResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -414,6 +447,7 @@ type GetUserNodeResponse struct {
 	NodeAddress   string                 `protobuf:"bytes,2,opt,name=node_address,json=nodeAddress,proto3" json:"node_address,omitempty"`
 	Found         bool                   `protobuf:"varint,3,opt,name=found,proto3" json:"found,omitempty"`
 	RoomId        string                 `protobuf:"bytes,4,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"` // 用户所在房间
+	Connections   []*UserConnection      `protobuf:"bytes,5,rep,name=connections,proto3" json:"connections,omitempty"`     // 用户的全部连接
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -476,26 +510,39 @@ func (x *GetUserNodeResponse) GetRoomId() string {
 	return ""
 }
 
-type GetRoomStatsRequest struct {
+func (x *GetUserNodeResponse) GetConnections() []*UserConnection {
+	if x != nil {
+		return x.Connections
+	}
+	return nil
+}
+
+type NotifyUserOnlineRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ConnId        string                 `protobuf:"bytes,2,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	NodeId        string                 `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	NodeAddress   string                 `protobuf:"bytes,4,opt,name=node_address,json=nodeAddress,proto3" json:"node_address,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,5,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`       // 设备 ID（同一设备重复登录时替换旧会话）
+	DeviceType    string                 `protobuf:"bytes,6,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"` // 设备类型：ios / android / web / desktop 等
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetRoomStatsRequest) Reset() {
-	*x = GetRoomStatsRequest{}
+func (x *NotifyUserOnlineRequest) Reset() {
+	*x = NotifyUserOnlineRequest{}
 	mi := &file_controller_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRoomStatsRequest) String() string {
+func (x *NotifyUserOnlineRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRoomStatsRequest) ProtoMessage() {}
+func (*NotifyUserOnlineRequest) ProtoMessage() {}
 
-func (x *GetRoomStatsRequest) ProtoReflect() protoreflect.Message {
+func (x *NotifyUserOnlineRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_controller_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -507,34 +554,76 @@ func (x *GetRoomStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRoomStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetRoomStatsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use NotifyUserOnlineRequest.ProtoReflect.Descriptor instead.
+func (*NotifyUserOnlineRequest) Descriptor() ([]byte, []int) {
 	return file_controller_proto_rawDescGZIP(), []int{8}
 }
 
-type GetRoomStatsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TotalRooms    int32                  `protobuf:"varint,1,opt,name=total_rooms,json=totalRooms,proto3" json:"total_rooms,omitempty"`
-	TotalUsers    int32                  `protobuf:"varint,2,opt,name=total_users,json=totalUsers,proto3" json:"total_users,omitempty"`
-	Rooms         []*RoomStats           `protobuf:"bytes,3,rep,name=rooms,proto3" json:"rooms,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *NotifyUserOnlineRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
 }
 
-func (x *GetRoomStatsResponse) Reset() {
-	*x = GetRoomStatsResponse{}
+func (x *NotifyUserOnlineRequest) GetConnId() string {
+	if x != nil {
+		return x.ConnId
+	}
+	return ""
+}
+
+func (x *NotifyUserOnlineRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *NotifyUserOnlineRequest) GetNodeAddress() string {
+	if x != nil {
+		return x.NodeAddress
+	}
+	return ""
+}
+
+func (x *NotifyUserOnlineRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *NotifyUserOnlineRequest) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+type NotifyUserOnlineResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message        string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	EvictedConnIds []string               `protobuf:"bytes,3,rep,name=evicted_conn_ids,json=evictedConnIds,proto3" json:"evicted_conn_ids,omitempty"` // 按会话策略被挤下线的连接
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *NotifyUserOnlineResponse) Reset() {
+	*x = NotifyUserOnlineResponse{}
 	mi := &file_controller_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRoomStatsResponse) String() string {
+func (x *NotifyUserOnlineResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRoomStatsResponse) ProtoMessage() {}
+func (*NotifyUserOnlineResponse) ProtoMessage() {}
 
-func (x *GetRoomStatsResponse) ProtoReflect() protoreflect.Message {
+func (x *NotifyUserOnlineResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_controller_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -546,58 +635,55 @@ func (x *GetRoomStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRoomStatsResponse.ProtoReflect.Descriptor instead.
-func (*GetRoomStatsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use NotifyUserOnlineResponse.ProtoReflect.Descriptor instead.
+func (*NotifyUserOnlineResponse) Descriptor() ([]byte, []int) {
 	return file_controller_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *GetRoomStatsResponse) GetTotalRooms() int32 {
+func (x *NotifyUserOnlineResponse) GetSuccess() bool {
 	if x != nil {
-		return x.TotalRooms
+		return x.Success
 	}
-	return 0
+	return false
 }
 
-func (x *GetRoomStatsResponse) GetTotalUsers() int32 {
+func (x *NotifyUserOnlineResponse) GetMessage() string {
 	if x != nil {
-		return x.TotalUsers
+		return x.Message
 	}
-	return 0
+	return ""
 }
 
-func (x *GetRoomStatsResponse) GetRooms() []*RoomStats {
+func (x *NotifyUserOnlineResponse) GetEvictedConnIds() []string {
 	if x != nil {
-		return x.Rooms
+		return x.EvictedConnIds
 	}
 	return nil
 }
 
-// ========== Common Types ==========
-type RoomInfo struct {
+type NotifyUserOfflineRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
-	Users         []*UserInfo            `protobuf:"bytes,2,rep,name=users,proto3" json:"users,omitempty"`
-	Metadata      *RoomMetadata          `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     int64                  `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ConnId        string                 `protobuf:"bytes,2,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	NodeId        string                 `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RoomInfo) Reset() {
-	*x = RoomInfo{}
+func (x *NotifyUserOfflineRequest) Reset() {
+	*x = NotifyUserOfflineRequest{}
 	mi := &file_controller_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RoomInfo) String() string {
+func (x *NotifyUserOfflineRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RoomInfo) ProtoMessage() {}
+func (*NotifyUserOfflineRequest) ProtoMessage() {}
 
-func (x *RoomInfo) ProtoReflect() protoreflect.Message {
+func (x *NotifyUserOfflineRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_controller_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -609,71 +695,54 @@ func (x *RoomInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RoomInfo.ProtoReflect.Descriptor instead.
-func (*RoomInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use NotifyUserOfflineRequest.ProtoReflect.Descriptor instead.
+func (*NotifyUserOfflineRequest) Descriptor() ([]byte, []int) {
 	return file_controller_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *RoomInfo) GetRoomId() string {
+func (x *NotifyUserOfflineRequest) GetUserId() string {
 	if x != nil {
-		return x.RoomId
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *RoomInfo) GetUsers() []*UserInfo {
-	if x != nil {
-		return x.Users
-	}
-	return nil
-}
-
-func (x *RoomInfo) GetMetadata() *RoomMetadata {
-	if x != nil {
-		return x.Metadata
-	}
-	return nil
-}
-
-func (x *RoomInfo) GetCreatedAt() int64 {
+func (x *NotifyUserOfflineRequest) GetConnId() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.ConnId
 	}
-	return 0
+	return ""
 }
 
-func (x *RoomInfo) GetUpdatedAt() int64 {
+func (x *NotifyUserOfflineRequest) GetNodeId() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.NodeId
 	}
-	return 0
+	return ""
 }
 
-type UserInfo struct {
+type NotifyUserOfflineResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	UserName      string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
-	NodeId        string                 `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
-	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	JoinedAt      int64                  `protobuf:"varint,5,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserInfo) Reset() {
-	*x = UserInfo{}
+func (x *NotifyUserOfflineResponse) Reset() {
+	*x = NotifyUserOfflineResponse{}
 	mi := &file_controller_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserInfo) String() string {
+func (x *NotifyUserOfflineResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserInfo) ProtoMessage() {}
+func (*NotifyUserOfflineResponse) ProtoMessage() {}
 
-func (x *UserInfo) ProtoReflect() protoreflect.Message {
+func (x *NotifyUserOfflineResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_controller_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -685,70 +754,119 @@ func (x *UserInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserInfo.ProtoReflect.Descriptor instead.
-func (*UserInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use NotifyUserOfflineResponse.ProtoReflect.Descriptor instead.
+func (*NotifyUserOfflineResponse) Descriptor() ([]byte, []int) {
 	return file_controller_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *UserInfo) GetUserId() string {
+func (x *NotifyUserOfflineResponse) GetSuccess() bool {
 	if x != nil {
-		return x.UserId
+		return x.Success
+	}
+	return false
+}
+
+func (x *NotifyUserOfflineResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
 
-func (x *UserInfo) GetUserName() string {
+type UpdateRoomPolicyRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	RoomId     string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	OperatorId string                 `protobuf:"bytes,2,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"` // 操作人，需为房主
+	Policy     *RoomPolicy            `protobuf:"bytes,3,opt,name=policy,proto3" json:"policy,omitempty"`
+	// 要修改的字段（RoomPolicy 的字段名，如 max_message_bytes），可以设置为零值；
+	// 为空时只修改 policy 中的非零值字段
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRoomPolicyRequest) Reset() {
+	*x = UpdateRoomPolicyRequest{}
+	mi := &file_controller_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRoomPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRoomPolicyRequest) ProtoMessage() {}
+
+func (x *UpdateRoomPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[12]
 	if x != nil {
-		return x.UserName
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRoomPolicyRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRoomPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateRoomPolicyRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
 	}
 	return ""
 }
 
-func (x *UserInfo) GetNodeId() string {
+func (x *UpdateRoomPolicyRequest) GetOperatorId() string {
 	if x != nil {
-		return x.NodeId
+		return x.OperatorId
 	}
 	return ""
 }
 
-func (x *UserInfo) GetMetadata() map[string]string {
+func (x *UpdateRoomPolicyRequest) GetPolicy() *RoomPolicy {
 	if x != nil {
-		return x.Metadata
+		return x.Policy
 	}
 	return nil
 }
 
-func (x *UserInfo) GetJoinedAt() int64 {
+func (x *UpdateRoomPolicyRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
 	if x != nil {
-		return x.JoinedAt
+		return x.UpdateMask
 	}
-	return 0
+	return nil
 }
 
-type RoomMetadata struct {
+type UpdateRoomPolicyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	MaxUsers      int32                  `protobuf:"varint,3,opt,name=max_users,json=maxUsers,proto3" json:"max_users,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Policy        *RoomPolicy            `protobuf:"bytes,3,opt,name=policy,proto3" json:"policy,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RoomMetadata) Reset() {
-	*x = RoomMetadata{}
-	mi := &file_controller_proto_msgTypes[12]
+func (x *UpdateRoomPolicyResponse) Reset() {
+	*x = UpdateRoomPolicyResponse{}
+	mi := &file_controller_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RoomMetadata) String() string {
+func (x *UpdateRoomPolicyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RoomMetadata) ProtoMessage() {}
+func (*UpdateRoomPolicyResponse) ProtoMessage() {}
 
-func (x *RoomMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_controller_proto_msgTypes[12]
+func (x *UpdateRoomPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -759,56 +877,56 @@ func (x *RoomMetadata) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RoomMetadata.ProtoReflect.Descriptor instead.
-func (*RoomMetadata) Descriptor() ([]byte, []int) {
-	return file_controller_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use UpdateRoomPolicyResponse.ProtoReflect.Descriptor instead.
+func (*UpdateRoomPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *RoomMetadata) GetName() string {
+func (x *UpdateRoomPolicyResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Name
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-func (x *RoomMetadata) GetDescription() string {
+func (x *UpdateRoomPolicyResponse) GetMessage() string {
 	if x != nil {
-		return x.Description
+		return x.Message
 	}
 	return ""
 }
 
-func (x *RoomMetadata) GetMaxUsers() int32 {
+func (x *UpdateRoomPolicyResponse) GetPolicy() *RoomPolicy {
 	if x != nil {
-		return x.MaxUsers
+		return x.Policy
 	}
-	return 0
+	return nil
 }
 
-type RoomStats struct {
+type InviteToRoomRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
-	UserCount     int32                  `protobuf:"varint,2,opt,name=user_count,json=userCount,proto3" json:"user_count,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	OperatorId    string                 `protobuf:"bytes,2,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
+	UserIds       []string               `protobuf:"bytes,3,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RoomStats) Reset() {
-	*x = RoomStats{}
-	mi := &file_controller_proto_msgTypes[13]
+func (x *InviteToRoomRequest) Reset() {
+	*x = InviteToRoomRequest{}
+	mi := &file_controller_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RoomStats) String() string {
+func (x *InviteToRoomRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RoomStats) ProtoMessage() {}
+func (*InviteToRoomRequest) ProtoMessage() {}
 
-func (x *RoomStats) ProtoReflect() protoreflect.Message {
-	mi := &file_controller_proto_msgTypes[13]
+func (x *InviteToRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -819,53 +937,3050 @@ func (x *RoomStats) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RoomStats.ProtoReflect.Descriptor instead.
-func (*RoomStats) Descriptor() ([]byte, []int) {
-	return file_controller_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use InviteToRoomRequest.ProtoReflect.Descriptor instead.
+func (*InviteToRoomRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *RoomStats) GetRoomId() string {
+func (x *InviteToRoomRequest) GetRoomId() string {
 	if x != nil {
 		return x.RoomId
 	}
 	return ""
 }
 
-func (x *RoomStats) GetUserCount() int32 {
+func (x *InviteToRoomRequest) GetOperatorId() string {
 	if x != nil {
-		return x.UserCount
+		return x.OperatorId
 	}
-	return 0
+	return ""
 }
 
-func (x *RoomStats) GetCreatedAt() int64 {
+func (x *InviteToRoomRequest) GetUserIds() []string {
 	if x != nil {
-		return x.CreatedAt
+		return x.UserIds
 	}
-	return 0
+	return nil
 }
 
-var File_controller_proto protoreflect.FileDescriptor
-
-const file_controller_proto_rawDesc = "" +
-	"\n" +
-	"\x10controller.proto\x12\x06pubsub\"\xf9\x01\n" +
-	"\x0fJoinRoomRequest\x12\x17\n" +
+type InviteToRoomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Invited       int32                  `protobuf:"varint,3,opt,name=invited,proto3" json:"invited,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteToRoomResponse) Reset() {
+	*x = InviteToRoomResponse{}
+	mi := &file_controller_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteToRoomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteToRoomResponse) ProtoMessage() {}
+
+func (x *InviteToRoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteToRoomResponse.ProtoReflect.Descriptor instead.
+func (*InviteToRoomResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *InviteToRoomResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *InviteToRoomResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *InviteToRoomResponse) GetInvited() int32 {
+	if x != nil {
+		return x.Invited
+	}
+	return 0
+}
+
+// ========== Access Control ==========
+type CheckPermissionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Permission    string                 `protobuf:"bytes,3,opt,name=permission,proto3" json:"permission,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckPermissionRequest) Reset() {
+	*x = CheckPermissionRequest{}
+	mi := &file_controller_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckPermissionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckPermissionRequest) ProtoMessage() {}
+
+func (x *CheckPermissionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckPermissionRequest.ProtoReflect.Descriptor instead.
+func (*CheckPermissionRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CheckPermissionRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *CheckPermissionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CheckPermissionRequest) GetPermission() string {
+	if x != nil {
+		return x.Permission
+	}
+	return ""
+}
+
+type CheckPermissionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Allowed       bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckPermissionResponse) Reset() {
+	*x = CheckPermissionResponse{}
+	mi := &file_controller_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckPermissionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckPermissionResponse) ProtoMessage() {}
+
+func (x *CheckPermissionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckPermissionResponse.ProtoReflect.Descriptor instead.
+func (*CheckPermissionResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CheckPermissionResponse) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *CheckPermissionResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *CheckPermissionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type SetUserRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	OperatorId    string                 `protobuf:"bytes,2,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserRoleRequest) Reset() {
+	*x = SetUserRoleRequest{}
+	mi := &file_controller_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserRoleRequest) ProtoMessage() {}
+
+func (x *SetUserRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserRoleRequest.ProtoReflect.Descriptor instead.
+func (*SetUserRoleRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SetUserRoleRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *SetUserRoleRequest) GetOperatorId() string {
+	if x != nil {
+		return x.OperatorId
+	}
+	return ""
+}
+
+func (x *SetUserRoleRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetUserRoleRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type SetUserRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserRoleResponse) Reset() {
+	*x = SetUserRoleResponse{}
+	mi := &file_controller_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserRoleResponse) ProtoMessage() {}
+
+func (x *SetUserRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserRoleResponse.ProtoReflect.Descriptor instead.
+func (*SetUserRoleResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SetUserRoleResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SetUserRoleResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type KickUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	OperatorId    string                 `protobuf:"bytes,2,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KickUserRequest) Reset() {
+	*x = KickUserRequest{}
+	mi := &file_controller_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KickUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KickUserRequest) ProtoMessage() {}
+
+func (x *KickUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KickUserRequest.ProtoReflect.Descriptor instead.
+func (*KickUserRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *KickUserRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *KickUserRequest) GetOperatorId() string {
+	if x != nil {
+		return x.OperatorId
+	}
+	return ""
+}
+
+func (x *KickUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *KickUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type KickUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KickUserResponse) Reset() {
+	*x = KickUserResponse{}
+	mi := &file_controller_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KickUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KickUserResponse) ProtoMessage() {}
+
+func (x *KickUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KickUserResponse.ProtoReflect.Descriptor instead.
+func (*KickUserResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *KickUserResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *KickUserResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ========== Webhook ==========
+// 投递失败的 webhook 事件
+type FailedWebhook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`             // 失败记录 ID
+	Endpoint      string                 `protobuf:"bytes,2,opt,name=endpoint,proto3" json:"endpoint,omitempty"` // endpoint 名称
+	EventId       string                 `protobuf:"bytes,3,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventType     string                 `protobuf:"bytes,4,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Payload       []byte                 `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"` // 事件 JSON
+	Attempts      int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`                        // 最后一次失败原因
+	FailedAt      int64                  `protobuf:"varint,8,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"` // Unix 毫秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailedWebhook) Reset() {
+	*x = FailedWebhook{}
+	mi := &file_controller_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailedWebhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailedWebhook) ProtoMessage() {}
+
+func (x *FailedWebhook) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailedWebhook.ProtoReflect.Descriptor instead.
+func (*FailedWebhook) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *FailedWebhook) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FailedWebhook) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *FailedWebhook) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *FailedWebhook) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *FailedWebhook) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *FailedWebhook) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *FailedWebhook) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *FailedWebhook) GetFailedAt() int64 {
+	if x != nil {
+		return x.FailedAt
+	}
+	return 0
+}
+
+type ListFailedWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Endpoint      string                 `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`              // 按 endpoint 过滤（为空表示全部）
+	StartId       string                 `protobuf:"bytes,2,opt,name=start_id,json=startId,proto3" json:"start_id,omitempty"` // 分页起点（不含）
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                   // 默认 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFailedWebhooksRequest) Reset() {
+	*x = ListFailedWebhooksRequest{}
+	mi := &file_controller_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFailedWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFailedWebhooksRequest) ProtoMessage() {}
+
+func (x *ListFailedWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFailedWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListFailedWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListFailedWebhooksRequest) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *ListFailedWebhooksRequest) GetStartId() string {
+	if x != nil {
+		return x.StartId
+	}
+	return ""
+}
+
+func (x *ListFailedWebhooksRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListFailedWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Failures      []*FailedWebhook       `protobuf:"bytes,1,rep,name=failures,proto3" json:"failures,omitempty"`
+	NextId        string                 `protobuf:"bytes,2,opt,name=next_id,json=nextId,proto3" json:"next_id,omitempty"` // 下一页起点，为空表示没有更多
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFailedWebhooksResponse) Reset() {
+	*x = ListFailedWebhooksResponse{}
+	mi := &file_controller_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFailedWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFailedWebhooksResponse) ProtoMessage() {}
+
+func (x *ListFailedWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFailedWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListFailedWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListFailedWebhooksResponse) GetFailures() []*FailedWebhook {
+	if x != nil {
+		return x.Failures
+	}
+	return nil
+}
+
+func (x *ListFailedWebhooksResponse) GetNextId() string {
+	if x != nil {
+		return x.NextId
+	}
+	return ""
+}
+
+type ReplayWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`  // 指定失败记录 ID
+	All           bool                   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"` // 重放全部（可配合 endpoint 过滤）
+	Endpoint      string                 `protobuf:"bytes,3,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayWebhooksRequest) Reset() {
+	*x = ReplayWebhooksRequest{}
+	mi := &file_controller_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayWebhooksRequest) ProtoMessage() {}
+
+func (x *ReplayWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ReplayWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ReplayWebhooksRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *ReplayWebhooksRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+func (x *ReplayWebhooksRequest) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+type ReplayWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Replayed      int32                  `protobuf:"varint,1,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	FailedIds     []string               `protobuf:"bytes,2,rep,name=failed_ids,json=failedIds,proto3" json:"failed_ids,omitempty"` // 未能重放（如 endpoint 已移除或队列已满）的记录，仍保留
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayWebhooksResponse) Reset() {
+	*x = ReplayWebhooksResponse{}
+	mi := &file_controller_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayWebhooksResponse) ProtoMessage() {}
+
+func (x *ReplayWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ReplayWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ReplayWebhooksResponse) GetReplayed() int32 {
+	if x != nil {
+		return x.Replayed
+	}
+	return 0
+}
+
+func (x *ReplayWebhooksResponse) GetFailedIds() []string {
+	if x != nil {
+		return x.FailedIds
+	}
+	return nil
+}
+
+// ========== Lifecycle Events ==========
+type WatchEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cursor        string                 `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`                            // 从该游标之后（不含）开始读取；为空时只接收新事件
+	FromOldest    bool                   `protobuf:"varint,2,opt,name=from_oldest,json=fromOldest,proto3" json:"from_oldest,omitempty"` // cursor 为空时从事件日志中最早的事件开始
+	Types         []string               `protobuf:"bytes,3,rep,name=types,proto3" json:"types,omitempty"`                              // 事件类型过滤，支持 "room.*" 前缀匹配，为空表示全部
+	RoomPrefix    string                 `protobuf:"bytes,4,opt,name=room_prefix,json=roomPrefix,proto3" json:"room_prefix,omitempty"`  // 只接收 room_id 以此为前缀的事件
+	UserPrefix    string                 `protobuf:"bytes,5,opt,name=user_prefix,json=userPrefix,proto3" json:"user_prefix,omitempty"`  // 只接收 user_id 以此为前缀的事件
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEventsRequest) Reset() {
+	*x = WatchEventsRequest{}
+	mi := &file_controller_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsRequest) ProtoMessage() {}
+
+func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *WatchEventsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *WatchEventsRequest) GetFromOldest() bool {
+	if x != nil {
+		return x.FromOldest
+	}
+	return false
+}
+
+func (x *WatchEventsRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *WatchEventsRequest) GetRoomPrefix() string {
+	if x != nil {
+		return x.RoomPrefix
+	}
+	return ""
+}
+
+func (x *WatchEventsRequest) GetUserPrefix() string {
+	if x != nil {
+		return x.UserPrefix
+	}
+	return ""
+}
+
+// 生命周期事件
+type LifecycleEvent struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Cursor string                 `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"` // 事件日志中的位置，断线后以此续读
+	Id     string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Type   string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"` // user.connected / user.disconnected / user.evicted / room.created / room.deleted /
+	// room.joined / room.left / room.kicked / node.up / node.down
+	Timestamp     int64  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix 毫秒
+	UserId        string `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RoomId        string `protobuf:"bytes,6,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	ConnId        string `protobuf:"bytes,7,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	NodeId        string `protobuf:"bytes,8,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	DeviceId      string `protobuf:"bytes,9,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceType    string `protobuf:"bytes,10,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	Role          string `protobuf:"bytes,11,opt,name=role,proto3" json:"role,omitempty"`
+	OperatorId    string `protobuf:"bytes,12,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
+	Reason        string `protobuf:"bytes,13,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LifecycleEvent) Reset() {
+	*x = LifecycleEvent{}
+	mi := &file_controller_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LifecycleEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LifecycleEvent) ProtoMessage() {}
+
+func (x *LifecycleEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LifecycleEvent.ProtoReflect.Descriptor instead.
+func (*LifecycleEvent) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *LifecycleEvent) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *LifecycleEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetConnId() string {
+	if x != nil {
+		return x.ConnId
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetOperatorId() string {
+	if x != nil {
+		return x.OperatorId
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// ========== Tenants ==========
+// 租户：房间和用户 ID 在集群内以 "<app_id>:" 为前缀隔离
+type Tenant struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AppId           string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Status          string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                                             // active, disabled
+	MaxMessageBytes int32                  `protobuf:"varint,4,opt,name=max_message_bytes,json=maxMessageBytes,proto3" json:"max_message_bytes,omitempty"` // 单条推送消息体大小上限，0 表示不限制
+	PublishRate     int32                  `protobuf:"varint,5,opt,name=publish_rate,json=publishRate,proto3" json:"publish_rate,omitempty"`               // 每秒推送请求数上限（每个 Push-Manager 实例），0 表示不限制
+	CreatedAt       int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                     // Unix 秒
+	UpdatedAt       int64                  `protobuf:"varint,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// 用量限制（全集群，0 表示不限制）：超过软限制只告警，超过硬限制拒绝
+	SoftMaxConnections int32 `protobuf:"varint,8,opt,name=soft_max_connections,json=softMaxConnections,proto3" json:"soft_max_connections,omitempty"` // 同时在线连接数
+	MaxConnections     int32 `protobuf:"varint,9,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
+	SoftDailyMessages  int64 `protobuf:"varint,10,opt,name=soft_daily_messages,json=softDailyMessages,proto3" json:"soft_daily_messages,omitempty"` // 每天（UTC）推送请求数
+	MaxDailyMessages   int64 `protobuf:"varint,11,opt,name=max_daily_messages,json=maxDailyMessages,proto3" json:"max_daily_messages,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Tenant) Reset() {
+	*x = Tenant{}
+	mi := &file_controller_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tenant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tenant) ProtoMessage() {}
+
+func (x *Tenant) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tenant.ProtoReflect.Descriptor instead.
+func (*Tenant) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Tenant) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *Tenant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Tenant) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Tenant) GetMaxMessageBytes() int32 {
+	if x != nil {
+		return x.MaxMessageBytes
+	}
+	return 0
+}
+
+func (x *Tenant) GetPublishRate() int32 {
+	if x != nil {
+		return x.PublishRate
+	}
+	return 0
+}
+
+func (x *Tenant) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Tenant) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *Tenant) GetSoftMaxConnections() int32 {
+	if x != nil {
+		return x.SoftMaxConnections
+	}
+	return 0
+}
+
+func (x *Tenant) GetMaxConnections() int32 {
+	if x != nil {
+		return x.MaxConnections
+	}
+	return 0
+}
+
+func (x *Tenant) GetSoftDailyMessages() int64 {
+	if x != nil {
+		return x.SoftDailyMessages
+	}
+	return 0
+}
+
+func (x *Tenant) GetMaxDailyMessages() int64 {
+	if x != nil {
+		return x.MaxDailyMessages
+	}
+	return 0
+}
+
+type CreateTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenant        *Tenant                `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"` // app_id 必填；status 为空时为 active
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTenantRequest) Reset() {
+	*x = CreateTenantRequest{}
+	mi := &file_controller_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTenantRequest) ProtoMessage() {}
+
+func (x *CreateTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTenantRequest.ProtoReflect.Descriptor instead.
+func (*CreateTenantRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CreateTenantRequest) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+type CreateTenantResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Tenant        *Tenant                `protobuf:"bytes,3,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTenantResponse) Reset() {
+	*x = CreateTenantResponse{}
+	mi := &file_controller_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTenantResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTenantResponse) ProtoMessage() {}
+
+func (x *CreateTenantResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTenantResponse.ProtoReflect.Descriptor instead.
+func (*CreateTenantResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CreateTenantResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CreateTenantResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CreateTenantResponse) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+type UpdateTenantRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Tenant *Tenant                `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"` // 按 app_id 更新
+	// 要修改的字段（Tenant 的字段名，如 max_connections），可以设置为零值（不限制）；
+	// 为空时只修改 tenant 中的非零值字段
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTenantRequest) Reset() {
+	*x = UpdateTenantRequest{}
+	mi := &file_controller_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTenantRequest) ProtoMessage() {}
+
+func (x *UpdateTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTenantRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTenantRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UpdateTenantRequest) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+func (x *UpdateTenantRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type UpdateTenantResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Tenant        *Tenant                `protobuf:"bytes,3,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTenantResponse) Reset() {
+	*x = UpdateTenantResponse{}
+	mi := &file_controller_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTenantResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTenantResponse) ProtoMessage() {}
+
+func (x *UpdateTenantResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTenantResponse.ProtoReflect.Descriptor instead.
+func (*UpdateTenantResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *UpdateTenantResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateTenantResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UpdateTenantResponse) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+type GetTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppId         string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTenantRequest) Reset() {
+	*x = GetTenantRequest{}
+	mi := &file_controller_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTenantRequest) ProtoMessage() {}
+
+func (x *GetTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTenantRequest.ProtoReflect.Descriptor instead.
+func (*GetTenantRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetTenantRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+type GetTenantResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Tenant        *Tenant                `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTenantResponse) Reset() {
+	*x = GetTenantResponse{}
+	mi := &file_controller_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTenantResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTenantResponse) ProtoMessage() {}
+
+func (x *GetTenantResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTenantResponse.ProtoReflect.Descriptor instead.
+func (*GetTenantResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetTenantResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetTenantResponse) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+type ListTenantsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsRequest) Reset() {
+	*x = ListTenantsRequest{}
+	mi := &file_controller_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsRequest) ProtoMessage() {}
+
+func (x *ListTenantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsRequest.ProtoReflect.Descriptor instead.
+func (*ListTenantsRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{36}
+}
+
+type ListTenantsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenants       []*Tenant              `protobuf:"bytes,1,rep,name=tenants,proto3" json:"tenants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsResponse) Reset() {
+	*x = ListTenantsResponse{}
+	mi := &file_controller_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsResponse) ProtoMessage() {}
+
+func (x *ListTenantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsResponse.ProtoReflect.Descriptor instead.
+func (*ListTenantsResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListTenantsResponse) GetTenants() []*Tenant {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+// API key（明文只在创建时返回）
+type APIKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	AppId         string                 `protobuf:"bytes,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix 秒
+	RevokedAt     int64                  `protobuf:"varint,5,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"` // 0 表示未吊销
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_controller_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKey) ProtoMessage() {}
+
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *APIKey) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *APIKey) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *APIKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *APIKey) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *APIKey) GetRevokedAt() int64 {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return 0
+}
+
+type CreateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppId         string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // 备注
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_controller_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *CreateAPIKeyRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Key           *APIKey                `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Secret        string                 `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"` // API key 明文，只返回这一次
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyResponse) Reset() {
+	*x = CreateAPIKeyResponse{}
+	mi := &file_controller_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyResponse) ProtoMessage() {}
+
+func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *CreateAPIKeyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CreateAPIKeyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyResponse) GetKey() *APIKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListAPIKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppId         string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysRequest) Reset() {
+	*x = ListAPIKeysRequest{}
+	mi := &file_controller_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysRequest) ProtoMessage() {}
+
+func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListAPIKeysRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+type ListAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*APIKey              `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysResponse) Reset() {
+	*x = ListAPIKeysResponse{}
+	mi := &file_controller_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysResponse) ProtoMessage() {}
+
+func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListAPIKeysResponse) GetKeys() []*APIKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type RevokeAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyRequest) Reset() {
+	*x = RevokeAPIKeyRequest{}
+	mi := &file_controller_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyRequest) ProtoMessage() {}
+
+func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *RevokeAPIKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+type RevokeAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyResponse) Reset() {
+	*x = RevokeAPIKeyResponse{}
+	mi := &file_controller_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyResponse) ProtoMessage() {}
+
+func (x *RevokeAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *RevokeAPIKeyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RevokeAPIKeyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ResolveAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyHash       string                 `protobuf:"bytes,1,opt,name=key_hash,json=keyHash,proto3" json:"key_hash,omitempty"` // hex(SHA-256(API key))，明文不离开接入层
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveAPIKeyRequest) Reset() {
+	*x = ResolveAPIKeyRequest{}
+	mi := &file_controller_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveAPIKeyRequest) ProtoMessage() {}
+
+func (x *ResolveAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*ResolveAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ResolveAPIKeyRequest) GetKeyHash() string {
+	if x != nil {
+		return x.KeyHash
+	}
+	return ""
+}
+
+type ResolveAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"` // key 不存在或已吊销时为 false
+	KeyId         string                 `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Tenant        *Tenant                `protobuf:"bytes,3,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveAPIKeyResponse) Reset() {
+	*x = ResolveAPIKeyResponse{}
+	mi := &file_controller_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveAPIKeyResponse) ProtoMessage() {}
+
+func (x *ResolveAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*ResolveAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ResolveAPIKeyResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *ResolveAPIKeyResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *ResolveAPIKeyResponse) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+type GetUsageReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppId         string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`       // 为空时报告全部租户
+	FromDay       string                 `protobuf:"bytes,2,opt,name=from_day,json=fromDay,proto3" json:"from_day,omitempty"` // 起始日期 YYYY-MM-DD（UTC），为空时为今天
+	ToDay         string                 `protobuf:"bytes,3,opt,name=to_day,json=toDay,proto3" json:"to_day,omitempty"`       // 结束日期（含），为空时与 from_day 相同，最多 31 天
+	Hourly        bool                   `protobuf:"varint,4,opt,name=hourly,proto3" json:"hourly,omitempty"`                 // 按小时输出窗口（默认按天）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageReportRequest) Reset() {
+	*x = GetUsageReportRequest{}
+	mi := &file_controller_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageReportRequest) ProtoMessage() {}
+
+func (x *GetUsageReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageReportRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageReportRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *GetUsageReportRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *GetUsageReportRequest) GetFromDay() string {
+	if x != nil {
+		return x.FromDay
+	}
+	return ""
+}
+
+func (x *GetUsageReportRequest) GetToDay() string {
+	if x != nil {
+		return x.ToDay
+	}
+	return ""
+}
+
+func (x *GetUsageReportRequest) GetHourly() bool {
+	if x != nil {
+		return x.Hourly
+	}
+	return false
+}
+
+// UsageWindow 一个时间窗口的用量（推送数由 Push-Manager 计数，投递数和出站字节数由 Connect-Node 计数）
+type UsageWindow struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Start               int64                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`                                                        // 窗口开始，Unix 秒
+	End                 int64                  `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`                                                            // 窗口结束（不含）
+	Published           int64                  `protobuf:"varint,3,opt,name=published,proto3" json:"published,omitempty"`                                                // 推送请求数
+	Delivered           int64                  `protobuf:"varint,4,opt,name=delivered,proto3" json:"delivered,omitempty"`                                                // 投递到连接的消息数
+	BytesOut            int64                  `protobuf:"varint,5,opt,name=bytes_out,json=bytesOut,proto3" json:"bytes_out,omitempty"`                                  // 投递的消息体字节数
+	PeakConnections     int64                  `protobuf:"varint,6,opt,name=peak_connections,json=peakConnections,proto3" json:"peak_connections,omitempty"`             // 窗口内的全集群连接数峰值（按 Connect-Node 汇总周期采样）
+	RejectedPublishes   int64                  `protobuf:"varint,7,opt,name=rejected_publishes,json=rejectedPublishes,proto3" json:"rejected_publishes,omitempty"`       // 超过每日硬限制被拒绝的推送请求数
+	RejectedConnections int64                  `protobuf:"varint,8,opt,name=rejected_connections,json=rejectedConnections,proto3" json:"rejected_connections,omitempty"` // 超过连接数硬限制被拒绝的连接数
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *UsageWindow) Reset() {
+	*x = UsageWindow{}
+	mi := &file_controller_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageWindow) ProtoMessage() {}
+
+func (x *UsageWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageWindow.ProtoReflect.Descriptor instead.
+func (*UsageWindow) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *UsageWindow) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *UsageWindow) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *UsageWindow) GetPublished() int64 {
+	if x != nil {
+		return x.Published
+	}
+	return 0
+}
+
+func (x *UsageWindow) GetDelivered() int64 {
+	if x != nil {
+		return x.Delivered
+	}
+	return 0
+}
+
+func (x *UsageWindow) GetBytesOut() int64 {
+	if x != nil {
+		return x.BytesOut
+	}
+	return 0
+}
+
+func (x *UsageWindow) GetPeakConnections() int64 {
+	if x != nil {
+		return x.PeakConnections
+	}
+	return 0
+}
+
+func (x *UsageWindow) GetRejectedPublishes() int64 {
+	if x != nil {
+		return x.RejectedPublishes
+	}
+	return 0
+}
+
+func (x *UsageWindow) GetRejectedConnections() int64 {
+	if x != nil {
+		return x.RejectedConnections
+	}
+	return 0
+}
+
+type TenantUsage struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Tenant             *Tenant                `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`            // 租户及其用量限制
+	Connections        int64                  `protobuf:"varint,2,opt,name=connections,proto3" json:"connections,omitempty"` // 当前全集群连接数
+	Windows            []*UsageWindow         `protobuf:"bytes,3,rep,name=windows,proto3" json:"windows,omitempty"`
+	SoftLimitsExceeded []string               `protobuf:"bytes,4,rep,name=soft_limits_exceeded,json=softLimitsExceeded,proto3" json:"soft_limits_exceeded,omitempty"` // 当前已超过的软限制：connections / daily_messages
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TenantUsage) Reset() {
+	*x = TenantUsage{}
+	mi := &file_controller_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TenantUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TenantUsage) ProtoMessage() {}
+
+func (x *TenantUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TenantUsage.ProtoReflect.Descriptor instead.
+func (*TenantUsage) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *TenantUsage) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+func (x *TenantUsage) GetConnections() int64 {
+	if x != nil {
+		return x.Connections
+	}
+	return 0
+}
+
+func (x *TenantUsage) GetWindows() []*UsageWindow {
+	if x != nil {
+		return x.Windows
+	}
+	return nil
+}
+
+func (x *TenantUsage) GetSoftLimitsExceeded() []string {
+	if x != nil {
+		return x.SoftLimitsExceeded
+	}
+	return nil
+}
+
+type GetUsageReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reports       []*TenantUsage         `protobuf:"bytes,1,rep,name=reports,proto3" json:"reports,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageReportResponse) Reset() {
+	*x = GetUsageReportResponse{}
+	mi := &file_controller_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageReportResponse) ProtoMessage() {}
+
+func (x *GetUsageReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageReportResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageReportResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *GetUsageReportResponse) GetReports() []*TenantUsage {
+	if x != nil {
+		return x.Reports
+	}
+	return nil
+}
+
+// ========== Node Management ==========
+type NodeHeartbeatRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	NodeId             string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Address            string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"` // Connect-Node gRPC 地址
+	Region             string                 `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	MaxConnections     int32                  `protobuf:"varint,4,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
+	CurrentConnections int32                  `protobuf:"varint,5,opt,name=current_connections,json=currentConnections,proto3" json:"current_connections,omitempty"`
+	RoomCount          int32                  `protobuf:"varint,6,opt,name=room_count,json=roomCount,proto3" json:"room_count,omitempty"`
+	CpuUsage           float32                `protobuf:"fixed32,7,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`          // CPU 使用率（百分比）
+	MemoryUsage        float32                `protobuf:"fixed32,8,opt,name=memory_usage,json=memoryUsage,proto3" json:"memory_usage,omitempty"` // 进程内存占用（MB）
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *NodeHeartbeatRequest) Reset() {
+	*x = NodeHeartbeatRequest{}
+	mi := &file_controller_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeHeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeHeartbeatRequest) ProtoMessage() {}
+
+func (x *NodeHeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeHeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*NodeHeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *NodeHeartbeatRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *NodeHeartbeatRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *NodeHeartbeatRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *NodeHeartbeatRequest) GetMaxConnections() int32 {
+	if x != nil {
+		return x.MaxConnections
+	}
+	return 0
+}
+
+func (x *NodeHeartbeatRequest) GetCurrentConnections() int32 {
+	if x != nil {
+		return x.CurrentConnections
+	}
+	return 0
+}
+
+func (x *NodeHeartbeatRequest) GetRoomCount() int32 {
+	if x != nil {
+		return x.RoomCount
+	}
+	return 0
+}
+
+func (x *NodeHeartbeatRequest) GetCpuUsage() float32 {
+	if x != nil {
+		return x.CpuUsage
+	}
+	return 0
+}
+
+func (x *NodeHeartbeatRequest) GetMemoryUsage() float32 {
+	if x != nil {
+		return x.MemoryUsage
+	}
+	return 0
+}
+
+type NodeHeartbeatResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Success             bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message             string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	HeartbeatIntervalMs int64                  `protobuf:"varint,3,opt,name=heartbeat_interval_ms,json=heartbeatIntervalMs,proto3" json:"heartbeat_interval_ms,omitempty"` // Controller 期望的心跳间隔
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *NodeHeartbeatResponse) Reset() {
+	*x = NodeHeartbeatResponse{}
+	mi := &file_controller_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeHeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeHeartbeatResponse) ProtoMessage() {}
+
+func (x *NodeHeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeHeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*NodeHeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *NodeHeartbeatResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *NodeHeartbeatResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *NodeHeartbeatResponse) GetHeartbeatIntervalMs() int64 {
+	if x != nil {
+		return x.HeartbeatIntervalMs
+	}
+	return 0
+}
+
+type GetRoomStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppId         string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`                 // 只统计该租户的房间（为空表示默认租户）
+	AllTenants    bool                   `protobuf:"varint,2,opt,name=all_tenants,json=allTenants,proto3" json:"all_tenants,omitempty"` // 统计全部租户的房间（管理视图，忽略 app_id）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomStatsRequest) Reset() {
+	*x = GetRoomStatsRequest{}
+	mi := &file_controller_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomStatsRequest) ProtoMessage() {}
+
+func (x *GetRoomStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetRoomStatsRequest) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *GetRoomStatsRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *GetRoomStatsRequest) GetAllTenants() bool {
+	if x != nil {
+		return x.AllTenants
+	}
+	return false
+}
+
+type GetRoomStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalRooms    int32                  `protobuf:"varint,1,opt,name=total_rooms,json=totalRooms,proto3" json:"total_rooms,omitempty"`
+	TotalUsers    int32                  `protobuf:"varint,2,opt,name=total_users,json=totalUsers,proto3" json:"total_users,omitempty"`
+	Rooms         []*RoomStats           `protobuf:"bytes,3,rep,name=rooms,proto3" json:"rooms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomStatsResponse) Reset() {
+	*x = GetRoomStatsResponse{}
+	mi := &file_controller_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomStatsResponse) ProtoMessage() {}
+
+func (x *GetRoomStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetRoomStatsResponse) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *GetRoomStatsResponse) GetTotalRooms() int32 {
+	if x != nil {
+		return x.TotalRooms
+	}
+	return 0
+}
+
+func (x *GetRoomStatsResponse) GetTotalUsers() int32 {
+	if x != nil {
+		return x.TotalUsers
+	}
+	return 0
+}
+
+func (x *GetRoomStatsResponse) GetRooms() []*RoomStats {
+	if x != nil {
+		return x.Rooms
+	}
+	return nil
+}
+
+// ========== Common Types ==========
+type RoomInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Users         []*UserInfo            `protobuf:"bytes,2,rep,name=users,proto3" json:"users,omitempty"`
+	Metadata      *RoomMetadata          `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Policy        *RoomPolicy            `protobuf:"bytes,6,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomInfo) Reset() {
+	*x = RoomInfo{}
+	mi := &file_controller_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomInfo) ProtoMessage() {}
+
+func (x *RoomInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomInfo.ProtoReflect.Descriptor instead.
+func (*RoomInfo) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *RoomInfo) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *RoomInfo) GetUsers() []*UserInfo {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *RoomInfo) GetMetadata() *RoomMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RoomInfo) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *RoomInfo) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *RoomInfo) GetPolicy() *RoomPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type UserInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserName      string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	NodeId        string                 `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	JoinedAt      int64                  `protobuf:"varint,5,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
+	Role          string                 `protobuf:"bytes,6,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserInfo) Reset() {
+	*x = UserInfo{}
+	mi := &file_controller_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserInfo) ProtoMessage() {}
+
+func (x *UserInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserInfo.ProtoReflect.Descriptor instead.
+func (*UserInfo) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *UserInfo) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserInfo) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *UserInfo) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *UserInfo) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UserInfo) GetJoinedAt() int64 {
+	if x != nil {
+		return x.JoinedAt
+	}
+	return 0
+}
+
+func (x *UserInfo) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type RoomMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	MaxUsers      int32                  `protobuf:"varint,3,opt,name=max_users,json=maxUsers,proto3" json:"max_users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomMetadata) Reset() {
+	*x = RoomMetadata{}
+	mi := &file_controller_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomMetadata) ProtoMessage() {}
+
+func (x *RoomMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomMetadata.ProtoReflect.Descriptor instead.
+func (*RoomMetadata) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *RoomMetadata) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RoomMetadata) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *RoomMetadata) GetMaxUsers() int32 {
+	if x != nil {
+		return x.MaxUsers
+	}
+	return 0
+}
+
+// 房间策略（存储在 MySQL rooms 表）
+type RoomPolicy struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MaxUsers        int32                  `protobuf:"varint,1,opt,name=max_users,json=maxUsers,proto3" json:"max_users,omitempty"`                        // 最大用户数，0 表示使用全局默认值
+	JoinMode        string                 `protobuf:"bytes,2,opt,name=join_mode,json=joinMode,proto3" json:"join_mode,omitempty"`                         // open, invite_only, password
+	Password        string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`                                         // 仅用于设置，查询时不返回
+	PublishPolicy   string                 `protobuf:"bytes,4,opt,name=publish_policy,json=publishPolicy,proto3" json:"publish_policy,omitempty"`          // anyone, owner, none
+	MaxMessageBytes int32                  `protobuf:"varint,5,opt,name=max_message_bytes,json=maxMessageBytes,proto3" json:"max_message_bytes,omitempty"` // 上行消息大小上限，0 表示不限制
+	OwnerId         string                 `protobuf:"bytes,6,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RoomPolicy) Reset() {
+	*x = RoomPolicy{}
+	mi := &file_controller_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomPolicy) ProtoMessage() {}
+
+func (x *RoomPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomPolicy.ProtoReflect.Descriptor instead.
+func (*RoomPolicy) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *RoomPolicy) GetMaxUsers() int32 {
+	if x != nil {
+		return x.MaxUsers
+	}
+	return 0
+}
+
+func (x *RoomPolicy) GetJoinMode() string {
+	if x != nil {
+		return x.JoinMode
+	}
+	return ""
+}
+
+func (x *RoomPolicy) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *RoomPolicy) GetPublishPolicy() string {
+	if x != nil {
+		return x.PublishPolicy
+	}
+	return ""
+}
+
+func (x *RoomPolicy) GetMaxMessageBytes() int32 {
+	if x != nil {
+		return x.MaxMessageBytes
+	}
+	return 0
+}
+
+func (x *RoomPolicy) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+// 用户连接（Redis user_conns 索引）
+type UserConnection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConnId        string                 `protobuf:"bytes,1,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	NodeAddress   string                 `protobuf:"bytes,3,opt,name=node_address,json=nodeAddress,proto3" json:"node_address,omitempty"` // Connect-Node gRPC 地址
+	Rooms         []string               `protobuf:"bytes,4,rep,name=rooms,proto3" json:"rooms,omitempty"`
+	ConnectedAt   int64                  `protobuf:"varint,5,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,6,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceType    string                 `protobuf:"bytes,7,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserConnection) Reset() {
+	*x = UserConnection{}
+	mi := &file_controller_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserConnection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserConnection) ProtoMessage() {}
+
+func (x *UserConnection) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserConnection.ProtoReflect.Descriptor instead.
+func (*UserConnection) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *UserConnection) GetConnId() string {
+	if x != nil {
+		return x.ConnId
+	}
+	return ""
+}
+
+func (x *UserConnection) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *UserConnection) GetNodeAddress() string {
+	if x != nil {
+		return x.NodeAddress
+	}
+	return ""
+}
+
+func (x *UserConnection) GetRooms() []string {
+	if x != nil {
+		return x.Rooms
+	}
+	return nil
+}
+
+func (x *UserConnection) GetConnectedAt() int64 {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return 0
+}
+
+func (x *UserConnection) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *UserConnection) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+type RoomStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserCount     int32                  `protobuf:"varint,2,opt,name=user_count,json=userCount,proto3" json:"user_count,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomStats) Reset() {
+	*x = RoomStats{}
+	mi := &file_controller_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomStats) ProtoMessage() {}
+
+func (x *RoomStats) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomStats.ProtoReflect.Descriptor instead.
+func (*RoomStats) Descriptor() ([]byte, []int) {
+	return file_controller_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *RoomStats) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *RoomStats) GetUserCount() int32 {
+	if x != nil {
+		return x.UserCount
+	}
+	return 0
+}
+
+func (x *RoomStats) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+var File_controller_proto protoreflect.FileDescriptor
+
+const file_controller_proto_rawDesc = "" +
+	"\n" +
+	"\x10controller.proto\x12\x06pubsub\x1a google/protobuf/field_mask.proto\"\xae\x02\n" +
+	"\x0fJoinRoomRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
 	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x1b\n" +
 	"\tuser_name\x18\x03 \x01(\tR\buserName\x12\x17\n" +
 	"\anode_id\x18\x04 \x01(\tR\x06nodeId\x12A\n" +
-	"\bmetadata\x18\x05 \x03(\v2%.pubsub.JoinRoomRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\bmetadata\x18\x05 \x03(\v2%.pubsub.JoinRoomRequest.MetadataEntryR\bmetadata\x12\x1a\n" +
+	"\bpassword\x18\x06 \x01(\tR\bpassword\x12\x17\n" +
+	"\aconn_id\x18\a \x01(\tR\x06connId\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"u\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x89\x01\n" +
 	"\x10JoinRoomResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
-	"\troom_info\x18\x03 \x01(\v2\x10.pubsub.RoomInfoR\broomInfo\"D\n" +
+	"\troom_info\x18\x03 \x01(\v2\x10.pubsub.RoomInfoR\broomInfo\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\"]\n" +
 	"\x10LeaveRoomRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
-	"\aroom_id\x18\x02 \x01(\tR\x06roomId\"G\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x17\n" +
+	"\aconn_id\x18\x03 \x01(\tR\x06connId\"G\n" +
 	"\x11LeaveRoomResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\"-\n" +
@@ -874,19 +3989,244 @@ const file_controller_proto_rawDesc = "" +
 	"\x13GetRoomInfoResponse\x12-\n" +
 	"\troom_info\x18\x01 \x01(\v2\x10.pubsub.RoomInfoR\broomInfo\"-\n" +
 	"\x12GetUserNodeRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x80\x01\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xba\x01\n" +
 	"\x13GetUserNodeResponse\x12\x17\n" +
 	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12!\n" +
 	"\fnode_address\x18\x02 \x01(\tR\vnodeAddress\x12\x14\n" +
 	"\x05found\x18\x03 \x01(\bR\x05found\x12\x17\n" +
-	"\aroom_id\x18\x04 \x01(\tR\x06roomId\"\x15\n" +
-	"\x13GetRoomStatsRequest\"\x81\x01\n" +
+	"\aroom_id\x18\x04 \x01(\tR\x06roomId\x128\n" +
+	"\vconnections\x18\x05 \x03(\v2\x16.pubsub.UserConnectionR\vconnections\"\xc5\x01\n" +
+	"\x17NotifyUserOnlineRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\aconn_id\x18\x02 \x01(\tR\x06connId\x12\x17\n" +
+	"\anode_id\x18\x03 \x01(\tR\x06nodeId\x12!\n" +
+	"\fnode_address\x18\x04 \x01(\tR\vnodeAddress\x12\x1b\n" +
+	"\tdevice_id\x18\x05 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_type\x18\x06 \x01(\tR\n" +
+	"deviceType\"x\n" +
+	"\x18NotifyUserOnlineResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12(\n" +
+	"\x10evicted_conn_ids\x18\x03 \x03(\tR\x0eevictedConnIds\"e\n" +
+	"\x18NotifyUserOfflineRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\aconn_id\x18\x02 \x01(\tR\x06connId\x12\x17\n" +
+	"\anode_id\x18\x03 \x01(\tR\x06nodeId\"O\n" +
+	"\x19NotifyUserOfflineResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xbc\x01\n" +
+	"\x17UpdateRoomPolicyRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1f\n" +
+	"\voperator_id\x18\x02 \x01(\tR\n" +
+	"operatorId\x12*\n" +
+	"\x06policy\x18\x03 \x01(\v2\x12.pubsub.RoomPolicyR\x06policy\x12;\n" +
+	"\vupdate_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"z\n" +
+	"\x18UpdateRoomPolicyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12*\n" +
+	"\x06policy\x18\x03 \x01(\v2\x12.pubsub.RoomPolicyR\x06policy\"j\n" +
+	"\x13InviteToRoomRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1f\n" +
+	"\voperator_id\x18\x02 \x01(\tR\n" +
+	"operatorId\x12\x19\n" +
+	"\buser_ids\x18\x03 \x03(\tR\auserIds\"d\n" +
+	"\x14InviteToRoomResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x18\n" +
+	"\ainvited\x18\x03 \x01(\x05R\ainvited\"j\n" +
+	"\x16CheckPermissionRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1e\n" +
+	"\n" +
+	"permission\x18\x03 \x01(\tR\n" +
+	"permission\"a\n" +
+	"\x17CheckPermissionResponse\x12\x18\n" +
+	"\aallowed\x18\x01 \x01(\bR\aallowed\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"{\n" +
+	"\x12SetUserRoleRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1f\n" +
+	"\voperator_id\x18\x02 \x01(\tR\n" +
+	"operatorId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\"I\n" +
+	"\x13SetUserRoleResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"|\n" +
+	"\x0fKickUserRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1f\n" +
+	"\voperator_id\x18\x02 \x01(\tR\n" +
+	"operatorId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"F\n" +
+	"\x10KickUserResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xde\x01\n" +
+	"\rFailedWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bendpoint\x18\x02 \x01(\tR\bendpoint\x12\x19\n" +
+	"\bevent_id\x18\x03 \x01(\tR\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x04 \x01(\tR\teventType\x12\x18\n" +
+	"\apayload\x18\x05 \x01(\fR\apayload\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x1b\n" +
+	"\tfailed_at\x18\b \x01(\x03R\bfailedAt\"h\n" +
+	"\x19ListFailedWebhooksRequest\x12\x1a\n" +
+	"\bendpoint\x18\x01 \x01(\tR\bendpoint\x12\x19\n" +
+	"\bstart_id\x18\x02 \x01(\tR\astartId\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"h\n" +
+	"\x1aListFailedWebhooksResponse\x121\n" +
+	"\bfailures\x18\x01 \x03(\v2\x15.pubsub.FailedWebhookR\bfailures\x12\x17\n" +
+	"\anext_id\x18\x02 \x01(\tR\x06nextId\"W\n" +
+	"\x15ReplayWebhooksRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\x12\x1a\n" +
+	"\bendpoint\x18\x03 \x01(\tR\bendpoint\"S\n" +
+	"\x16ReplayWebhooksResponse\x12\x1a\n" +
+	"\breplayed\x18\x01 \x01(\x05R\breplayed\x12\x1d\n" +
+	"\n" +
+	"failed_ids\x18\x02 \x03(\tR\tfailedIds\"\xa5\x01\n" +
+	"\x12WatchEventsRequest\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\tR\x06cursor\x12\x1f\n" +
+	"\vfrom_oldest\x18\x02 \x01(\bR\n" +
+	"fromOldest\x12\x14\n" +
+	"\x05types\x18\x03 \x03(\tR\x05types\x12\x1f\n" +
+	"\vroom_prefix\x18\x04 \x01(\tR\n" +
+	"roomPrefix\x12\x1f\n" +
+	"\vuser_prefix\x18\x05 \x01(\tR\n" +
+	"userPrefix\"\xd9\x02\n" +
+	"\x0eLifecycleEvent\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\tR\x06cursor\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\tR\x06userId\x12\x17\n" +
+	"\aroom_id\x18\x06 \x01(\tR\x06roomId\x12\x17\n" +
+	"\aconn_id\x18\a \x01(\tR\x06connId\x12\x17\n" +
+	"\anode_id\x18\b \x01(\tR\x06nodeId\x12\x1b\n" +
+	"\tdevice_id\x18\t \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_type\x18\n" +
+	" \x01(\tR\n" +
+	"deviceType\x12\x12\n" +
+	"\x04role\x18\v \x01(\tR\x04role\x12\x1f\n" +
+	"\voperator_id\x18\f \x01(\tR\n" +
+	"operatorId\x12\x16\n" +
+	"\x06reason\x18\r \x01(\tR\x06reason\"\x91\x03\n" +
+	"\x06Tenant\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12*\n" +
+	"\x11max_message_bytes\x18\x04 \x01(\x05R\x0fmaxMessageBytes\x12!\n" +
+	"\fpublish_rate\x18\x05 \x01(\x05R\vpublishRate\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\x03R\tupdatedAt\x120\n" +
+	"\x14soft_max_connections\x18\b \x01(\x05R\x12softMaxConnections\x12'\n" +
+	"\x0fmax_connections\x18\t \x01(\x05R\x0emaxConnections\x12.\n" +
+	"\x13soft_daily_messages\x18\n" +
+	" \x01(\x03R\x11softDailyMessages\x12,\n" +
+	"\x12max_daily_messages\x18\v \x01(\x03R\x10maxDailyMessages\"=\n" +
+	"\x13CreateTenantRequest\x12&\n" +
+	"\x06tenant\x18\x01 \x01(\v2\x0e.pubsub.TenantR\x06tenant\"r\n" +
+	"\x14CreateTenantResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12&\n" +
+	"\x06tenant\x18\x03 \x01(\v2\x0e.pubsub.TenantR\x06tenant\"z\n" +
+	"\x13UpdateTenantRequest\x12&\n" +
+	"\x06tenant\x18\x01 \x01(\v2\x0e.pubsub.TenantR\x06tenant\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"r\n" +
+	"\x14UpdateTenantResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12&\n" +
+	"\x06tenant\x18\x03 \x01(\v2\x0e.pubsub.TenantR\x06tenant\")\n" +
+	"\x10GetTenantRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\"Q\n" +
+	"\x11GetTenantResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12&\n" +
+	"\x06tenant\x18\x02 \x01(\v2\x0e.pubsub.TenantR\x06tenant\"\x14\n" +
+	"\x12ListTenantsRequest\"?\n" +
+	"\x13ListTenantsResponse\x12(\n" +
+	"\atenants\x18\x01 \x03(\v2\x0e.pubsub.TenantR\atenants\"\x88\x01\n" +
+	"\x06APIKey\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x15\n" +
+	"\x06app_id\x18\x02 \x01(\tR\x05appId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"revoked_at\x18\x05 \x01(\x03R\trevokedAt\"@\n" +
+	"\x13CreateAPIKeyRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x84\x01\n" +
+	"\x14CreateAPIKeyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12 \n" +
+	"\x03key\x18\x03 \x01(\v2\x0e.pubsub.APIKeyR\x03key\x12\x16\n" +
+	"\x06secret\x18\x04 \x01(\tR\x06secret\"+\n" +
+	"\x12ListAPIKeysRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\"9\n" +
+	"\x13ListAPIKeysResponse\x12\"\n" +
+	"\x04keys\x18\x01 \x03(\v2\x0e.pubsub.APIKeyR\x04keys\",\n" +
+	"\x13RevokeAPIKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\"J\n" +
+	"\x14RevokeAPIKeyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"1\n" +
+	"\x14ResolveAPIKeyRequest\x12\x19\n" +
+	"\bkey_hash\x18\x01 \x01(\tR\akeyHash\"l\n" +
+	"\x15ResolveAPIKeyResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\x12&\n" +
+	"\x06tenant\x18\x03 \x01(\v2\x0e.pubsub.TenantR\x06tenant\"x\n" +
+	"\x15GetUsageReportRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12\x19\n" +
+	"\bfrom_day\x18\x02 \x01(\tR\afromDay\x12\x15\n" +
+	"\x06to_day\x18\x03 \x01(\tR\x05toDay\x12\x16\n" +
+	"\x06hourly\x18\x04 \x01(\bR\x06hourly\"\x9b\x02\n" +
+	"\vUsageWindow\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\x03R\x03end\x12\x1c\n" +
+	"\tpublished\x18\x03 \x01(\x03R\tpublished\x12\x1c\n" +
+	"\tdelivered\x18\x04 \x01(\x03R\tdelivered\x12\x1b\n" +
+	"\tbytes_out\x18\x05 \x01(\x03R\bbytesOut\x12)\n" +
+	"\x10peak_connections\x18\x06 \x01(\x03R\x0fpeakConnections\x12-\n" +
+	"\x12rejected_publishes\x18\a \x01(\x03R\x11rejectedPublishes\x121\n" +
+	"\x14rejected_connections\x18\b \x01(\x03R\x13rejectedConnections\"\xb8\x01\n" +
+	"\vTenantUsage\x12&\n" +
+	"\x06tenant\x18\x01 \x01(\v2\x0e.pubsub.TenantR\x06tenant\x12 \n" +
+	"\vconnections\x18\x02 \x01(\x03R\vconnections\x12-\n" +
+	"\awindows\x18\x03 \x03(\v2\x13.pubsub.UsageWindowR\awindows\x120\n" +
+	"\x14soft_limits_exceeded\x18\x04 \x03(\tR\x12softLimitsExceeded\"G\n" +
+	"\x16GetUsageReportResponse\x12-\n" +
+	"\areports\x18\x01 \x03(\v2\x13.pubsub.TenantUsageR\areports\"\x9a\x02\n" +
+	"\x14NodeHeartbeatRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06region\x18\x03 \x01(\tR\x06region\x12'\n" +
+	"\x0fmax_connections\x18\x04 \x01(\x05R\x0emaxConnections\x12/\n" +
+	"\x13current_connections\x18\x05 \x01(\x05R\x12currentConnections\x12\x1d\n" +
+	"\n" +
+	"room_count\x18\x06 \x01(\x05R\troomCount\x12\x1b\n" +
+	"\tcpu_usage\x18\a \x01(\x02R\bcpuUsage\x12!\n" +
+	"\fmemory_usage\x18\b \x01(\x02R\vmemoryUsage\"\x7f\n" +
+	"\x15NodeHeartbeatResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x122\n" +
+	"\x15heartbeat_interval_ms\x18\x03 \x01(\x03R\x13heartbeatIntervalMs\"M\n" +
+	"\x13GetRoomStatsRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12\x1f\n" +
+	"\vall_tenants\x18\x02 \x01(\bR\n" +
+	"allTenants\"\x81\x01\n" +
 	"\x14GetRoomStatsResponse\x12\x1f\n" +
 	"\vtotal_rooms\x18\x01 \x01(\x05R\n" +
 	"totalRooms\x12\x1f\n" +
 	"\vtotal_users\x18\x02 \x01(\x05R\n" +
 	"totalUsers\x12'\n" +
-	"\x05rooms\x18\x03 \x03(\v2\x11.pubsub.RoomStatsR\x05rooms\"\xbb\x01\n" +
+	"\x05rooms\x18\x03 \x03(\v2\x11.pubsub.RoomStatsR\x05rooms\"\xe7\x01\n" +
 	"\bRoomInfo\x12\x17\n" +
 	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12&\n" +
 	"\x05users\x18\x02 \x03(\v2\x10.pubsub.UserInfoR\x05users\x120\n" +
@@ -894,32 +4234,71 @@ const file_controller_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\x05 \x01(\x03R\tupdatedAt\"\xef\x01\n" +
+	"updated_at\x18\x05 \x01(\x03R\tupdatedAt\x12*\n" +
+	"\x06policy\x18\x06 \x01(\v2\x12.pubsub.RoomPolicyR\x06policy\"\x83\x02\n" +
 	"\bUserInfo\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
 	"\tuser_name\x18\x02 \x01(\tR\buserName\x12\x17\n" +
 	"\anode_id\x18\x03 \x01(\tR\x06nodeId\x12:\n" +
 	"\bmetadata\x18\x04 \x03(\v2\x1e.pubsub.UserInfo.MetadataEntryR\bmetadata\x12\x1b\n" +
-	"\tjoined_at\x18\x05 \x01(\x03R\bjoinedAt\x1a;\n" +
+	"\tjoined_at\x18\x05 \x01(\x03R\bjoinedAt\x12\x12\n" +
+	"\x04role\x18\x06 \x01(\tR\x04role\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"a\n" +
 	"\fRoomMetadata\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1b\n" +
-	"\tmax_users\x18\x03 \x01(\x05R\bmaxUsers\"b\n" +
+	"\tmax_users\x18\x03 \x01(\x05R\bmaxUsers\"\xd0\x01\n" +
+	"\n" +
+	"RoomPolicy\x12\x1b\n" +
+	"\tmax_users\x18\x01 \x01(\x05R\bmaxUsers\x12\x1b\n" +
+	"\tjoin_mode\x18\x02 \x01(\tR\bjoinMode\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12%\n" +
+	"\x0epublish_policy\x18\x04 \x01(\tR\rpublishPolicy\x12*\n" +
+	"\x11max_message_bytes\x18\x05 \x01(\x05R\x0fmaxMessageBytes\x12\x19\n" +
+	"\bowner_id\x18\x06 \x01(\tR\aownerId\"\xdc\x01\n" +
+	"\x0eUserConnection\x12\x17\n" +
+	"\aconn_id\x18\x01 \x01(\tR\x06connId\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\x12!\n" +
+	"\fnode_address\x18\x03 \x01(\tR\vnodeAddress\x12\x14\n" +
+	"\x05rooms\x18\x04 \x03(\tR\x05rooms\x12!\n" +
+	"\fconnected_at\x18\x05 \x01(\x03R\vconnectedAt\x12\x1b\n" +
+	"\tdevice_id\x18\x06 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_type\x18\a \x01(\tR\n" +
+	"deviceType\"b\n" +
 	"\tRoomStats\x12\x17\n" +
 	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1d\n" +
 	"\n" +
 	"user_count\x18\x02 \x01(\x05R\tuserCount\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x03 \x01(\x03R\tcreatedAt2\xef\x02\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt2\xfb\x0e\n" +
 	"\x11ControllerService\x12=\n" +
 	"\bJoinRoom\x12\x17.pubsub.JoinRoomRequest\x1a\x18.pubsub.JoinRoomResponse\x12@\n" +
 	"\tLeaveRoom\x12\x18.pubsub.LeaveRoomRequest\x1a\x19.pubsub.LeaveRoomResponse\x12F\n" +
 	"\vGetRoomInfo\x12\x1a.pubsub.GetRoomInfoRequest\x1a\x1b.pubsub.GetRoomInfoResponse\x12F\n" +
 	"\vGetUserNode\x12\x1a.pubsub.GetUserNodeRequest\x1a\x1b.pubsub.GetUserNodeResponse\x12I\n" +
-	"\fGetRoomStats\x12\x1b.pubsub.GetRoomStatsRequest\x1a\x1c.pubsub.GetRoomStatsResponseBIZGgithub.com/livekit/psrpc/examples/pubsub/protocol/controller;controllerb\x06proto3"
+	"\fGetRoomStats\x12\x1b.pubsub.GetRoomStatsRequest\x1a\x1c.pubsub.GetRoomStatsResponse\x12U\n" +
+	"\x10UpdateRoomPolicy\x12\x1f.pubsub.UpdateRoomPolicyRequest\x1a .pubsub.UpdateRoomPolicyResponse\x12I\n" +
+	"\fInviteToRoom\x12\x1b.pubsub.InviteToRoomRequest\x1a\x1c.pubsub.InviteToRoomResponse\x12R\n" +
+	"\x0fCheckPermission\x12\x1e.pubsub.CheckPermissionRequest\x1a\x1f.pubsub.CheckPermissionResponse\x12F\n" +
+	"\vSetUserRole\x12\x1a.pubsub.SetUserRoleRequest\x1a\x1b.pubsub.SetUserRoleResponse\x12=\n" +
+	"\bKickUser\x12\x17.pubsub.KickUserRequest\x1a\x18.pubsub.KickUserResponse\x12L\n" +
+	"\rNodeHeartbeat\x12\x1c.pubsub.NodeHeartbeatRequest\x1a\x1d.pubsub.NodeHeartbeatResponse\x12U\n" +
+	"\x10NotifyUserOnline\x12\x1f.pubsub.NotifyUserOnlineRequest\x1a .pubsub.NotifyUserOnlineResponse\x12X\n" +
+	"\x11NotifyUserOffline\x12 .pubsub.NotifyUserOfflineRequest\x1a!.pubsub.NotifyUserOfflineResponse\x12[\n" +
+	"\x12ListFailedWebhooks\x12!.pubsub.ListFailedWebhooksRequest\x1a\".pubsub.ListFailedWebhooksResponse\x12O\n" +
+	"\x0eReplayWebhooks\x12\x1d.pubsub.ReplayWebhooksRequest\x1a\x1e.pubsub.ReplayWebhooksResponse\x12C\n" +
+	"\vWatchEvents\x12\x1a.pubsub.WatchEventsRequest\x1a\x16.pubsub.LifecycleEvent0\x01\x12I\n" +
+	"\fCreateTenant\x12\x1b.pubsub.CreateTenantRequest\x1a\x1c.pubsub.CreateTenantResponse\x12I\n" +
+	"\fUpdateTenant\x12\x1b.pubsub.UpdateTenantRequest\x1a\x1c.pubsub.UpdateTenantResponse\x12@\n" +
+	"\tGetTenant\x12\x18.pubsub.GetTenantRequest\x1a\x19.pubsub.GetTenantResponse\x12F\n" +
+	"\vListTenants\x12\x1a.pubsub.ListTenantsRequest\x1a\x1b.pubsub.ListTenantsResponse\x12I\n" +
+	"\fCreateAPIKey\x12\x1b.pubsub.CreateAPIKeyRequest\x1a\x1c.pubsub.CreateAPIKeyResponse\x12F\n" +
+	"\vListAPIKeys\x12\x1a.pubsub.ListAPIKeysRequest\x1a\x1b.pubsub.ListAPIKeysResponse\x12I\n" +
+	"\fRevokeAPIKey\x12\x1b.pubsub.RevokeAPIKeyRequest\x1a\x1c.pubsub.RevokeAPIKeyResponse\x12L\n" +
+	"\rResolveAPIKey\x12\x1c.pubsub.ResolveAPIKeyRequest\x1a\x1d.pubsub.ResolveAPIKeyResponse\x12O\n" +
+	"\x0eGetUsageReport\x12\x1d.pubsub.GetUsageReportRequest\x1a\x1e.pubsub.GetUsageReportResponseBIZGgithub.com/livekit/psrpc/examples/pubsub/protocol/controller;controllerb\x06proto3"
 
 var (
 	file_controller_proto_rawDescOnce sync.Once
@@ -933,48 +4312,155 @@ func file_controller_proto_rawDescGZIP() []byte {
 	return file_controller_proto_rawDescData
 }
 
-var file_controller_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_controller_proto_msgTypes = make([]protoimpl.MessageInfo, 63)
 var file_controller_proto_goTypes = []any{
-	(*JoinRoomRequest)(nil),      // 0: pubsub.JoinRoomRequest
-	(*JoinRoomResponse)(nil),     // 1: pubsub.JoinRoomResponse
-	(*LeaveRoomRequest)(nil),     // 2: pubsub.LeaveRoomRequest
-	(*LeaveRoomResponse)(nil),    // 3: pubsub.LeaveRoomResponse
-	(*GetRoomInfoRequest)(nil),   // 4: pubsub.GetRoomInfoRequest
-	(*GetRoomInfoResponse)(nil),  // 5: pubsub.GetRoomInfoResponse
-	(*GetUserNodeRequest)(nil),   // 6: pubsub.GetUserNodeRequest
-	(*GetUserNodeResponse)(nil),  // 7: pubsub.GetUserNodeResponse
-	(*GetRoomStatsRequest)(nil),  // 8: pubsub.GetRoomStatsRequest
-	(*GetRoomStatsResponse)(nil), // 9: pubsub.GetRoomStatsResponse
-	(*RoomInfo)(nil),             // 10: pubsub.RoomInfo
-	(*UserInfo)(nil),             // 11: pubsub.UserInfo
-	(*RoomMetadata)(nil),         // 12: pubsub.RoomMetadata
-	(*RoomStats)(nil),            // 13: pubsub.RoomStats
-	nil,                          // 14: pubsub.JoinRoomRequest.MetadataEntry
-	nil,                          // 15: pubsub.UserInfo.MetadataEntry
+	(*JoinRoomRequest)(nil),            // 0: pubsub.JoinRoomRequest
+	(*JoinRoomResponse)(nil),           // 1: pubsub.JoinRoomResponse
+	(*LeaveRoomRequest)(nil),           // 2: pubsub.LeaveRoomRequest
+	(*LeaveRoomResponse)(nil),          // 3: pubsub.LeaveRoomResponse
+	(*GetRoomInfoRequest)(nil),         // 4: pubsub.GetRoomInfoRequest
+	(*GetRoomInfoResponse)(nil),        // 5: pubsub.GetRoomInfoResponse
+	(*GetUserNodeRequest)(nil),         // 6: pubsub.GetUserNodeRequest
+	(*GetUserNodeResponse)(nil),        // 7: pubsub.GetUserNodeResponse
+	(*NotifyUserOnlineRequest)(nil),    // 8: pubsub.NotifyUserOnlineRequest
+	(*NotifyUserOnlineResponse)(nil),   // 9: pubsub.NotifyUserOnlineResponse
+	(*NotifyUserOfflineRequest)(nil),   // 10: pubsub.NotifyUserOfflineRequest
+	(*NotifyUserOfflineResponse)(nil),  // 11: pubsub.NotifyUserOfflineResponse
+	(*UpdateRoomPolicyRequest)(nil),    // 12: pubsub.UpdateRoomPolicyRequest
+	(*UpdateRoomPolicyResponse)(nil),   // 13: pubsub.UpdateRoomPolicyResponse
+	(*InviteToRoomRequest)(nil),        // 14: pubsub.InviteToRoomRequest
+	(*InviteToRoomResponse)(nil),       // 15: pubsub.InviteToRoomResponse
+	(*CheckPermissionRequest)(nil),     // 16: pubsub.CheckPermissionRequest
+	(*CheckPermissionResponse)(nil),    // 17: pubsub.CheckPermissionResponse
+	(*SetUserRoleRequest)(nil),         // 18: pubsub.SetUserRoleRequest
+	(*SetUserRoleResponse)(nil),        // 19: pubsub.SetUserRoleResponse
+	(*KickUserRequest)(nil),            // 20: pubsub.KickUserRequest
+	(*KickUserResponse)(nil),           // 21: pubsub.KickUserResponse
+	(*FailedWebhook)(nil),              // 22: pubsub.FailedWebhook
+	(*ListFailedWebhooksRequest)(nil),  // 23: pubsub.ListFailedWebhooksRequest
+	(*ListFailedWebhooksResponse)(nil), // 24: pubsub.ListFailedWebhooksResponse
+	(*ReplayWebhooksRequest)(nil),      // 25: pubsub.ReplayWebhooksRequest
+	(*ReplayWebhooksResponse)(nil),     // 26: pubsub.ReplayWebhooksResponse
+	(*WatchEventsRequest)(nil),         // 27: pubsub.WatchEventsRequest
+	(*LifecycleEvent)(nil),             // 28: pubsub.LifecycleEvent
+	(*Tenant)(nil),                     // 29: pubsub.Tenant
+	(*CreateTenantRequest)(nil),        // 30: pubsub.CreateTenantRequest
+	(*CreateTenantResponse)(nil),       // 31: pubsub.CreateTenantResponse
+	(*UpdateTenantRequest)(nil),        // 32: pubsub.UpdateTenantRequest
+	(*UpdateTenantResponse)(nil),       // 33: pubsub.UpdateTenantResponse
+	(*GetTenantRequest)(nil),           // 34: pubsub.GetTenantRequest
+	(*GetTenantResponse)(nil),          // 35: pubsub.GetTenantResponse
+	(*ListTenantsRequest)(nil),         // 36: pubsub.ListTenantsRequest
+	(*ListTenantsResponse)(nil),        // 37: pubsub.ListTenantsResponse
+	(*APIKey)(nil),                     // 38: pubsub.APIKey
+	(*CreateAPIKeyRequest)(nil),        // 39: pubsub.CreateAPIKeyRequest
+	(*CreateAPIKeyResponse)(nil),       // 40: pubsub.CreateAPIKeyResponse
+	(*ListAPIKeysRequest)(nil),         // 41: pubsub.ListAPIKeysRequest
+	(*ListAPIKeysResponse)(nil),        // 42: pubsub.ListAPIKeysResponse
+	(*RevokeAPIKeyRequest)(nil),        // 43: pubsub.RevokeAPIKeyRequest
+	(*RevokeAPIKeyResponse)(nil),       // 44: pubsub.RevokeAPIKeyResponse
+	(*ResolveAPIKeyRequest)(nil),       // 45: pubsub.ResolveAPIKeyRequest
+	(*ResolveAPIKeyResponse)(nil),      // 46: pubsub.ResolveAPIKeyResponse
+	(*GetUsageReportRequest)(nil),      // 47: pubsub.GetUsageReportRequest
+	(*UsageWindow)(nil),                // 48: pubsub.UsageWindow
+	(*TenantUsage)(nil),                // 49: pubsub.TenantUsage
+	(*GetUsageReportResponse)(nil),     // 50: pubsub.GetUsageReportResponse
+	(*NodeHeartbeatRequest)(nil),       // 51: pubsub.NodeHeartbeatRequest
+	(*NodeHeartbeatResponse)(nil),      // 52: pubsub.NodeHeartbeatResponse
+	(*GetRoomStatsRequest)(nil),        // 53: pubsub.GetRoomStatsRequest
+	(*GetRoomStatsResponse)(nil),       // 54: pubsub.GetRoomStatsResponse
+	(*RoomInfo)(nil),                   // 55: pubsub.RoomInfo
+	(*UserInfo)(nil),                   // 56: pubsub.UserInfo
+	(*RoomMetadata)(nil),               // 57: pubsub.RoomMetadata
+	(*RoomPolicy)(nil),                 // 58: pubsub.RoomPolicy
+	(*UserConnection)(nil),             // 59: pubsub.UserConnection
+	(*RoomStats)(nil),                  // 60: pubsub.RoomStats
+	nil,                                // 61: pubsub.JoinRoomRequest.MetadataEntry
+	nil,                                // 62: pubsub.UserInfo.MetadataEntry
+	(*fieldmaskpb.FieldMask)(nil),      // 63: google.protobuf.FieldMask
 }
 var file_controller_proto_depIdxs = []int32{
-	14, // 0: pubsub.JoinRoomRequest.metadata:type_name -> pubsub.JoinRoomRequest.MetadataEntry
-	10, // 1: pubsub.JoinRoomResponse.room_info:type_name -> pubsub.RoomInfo
-	10, // 2: pubsub.GetRoomInfoResponse.room_info:type_name -> pubsub.RoomInfo
-	13, // 3: pubsub.GetRoomStatsResponse.rooms:type_name -> pubsub.RoomStats
-	11, // 4: pubsub.RoomInfo.users:type_name -> pubsub.UserInfo
-	12, // 5: pubsub.RoomInfo.metadata:type_name -> pubsub.RoomMetadata
-	15, // 6: pubsub.UserInfo.metadata:type_name -> pubsub.UserInfo.MetadataEntry
-	0,  // 7: pubsub.ControllerService.JoinRoom:input_type -> pubsub.JoinRoomRequest
-	2,  // 8: pubsub.ControllerService.LeaveRoom:input_type -> pubsub.LeaveRoomRequest
-	4,  // 9: pubsub.ControllerService.GetRoomInfo:input_type -> pubsub.GetRoomInfoRequest
-	6,  // 10: pubsub.ControllerService.GetUserNode:input_type -> pubsub.GetUserNodeRequest
-	8,  // 11: pubsub.ControllerService.GetRoomStats:input_type -> pubsub.GetRoomStatsRequest
-	1,  // 12: pubsub.ControllerService.JoinRoom:output_type -> pubsub.JoinRoomResponse
-	3,  // 13: pubsub.ControllerService.LeaveRoom:output_type -> pubsub.LeaveRoomResponse
-	5,  // 14: pubsub.ControllerService.GetRoomInfo:output_type -> pubsub.GetRoomInfoResponse
-	7,  // 15: pubsub.ControllerService.GetUserNode:output_type -> pubsub.GetUserNodeResponse
-	9,  // 16: pubsub.ControllerService.GetRoomStats:output_type -> pubsub.GetRoomStatsResponse
-	12, // [12:17] is the sub-list for method output_type
-	7,  // [7:12] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	61, // 0: pubsub.JoinRoomRequest.metadata:type_name -> pubsub.JoinRoomRequest.MetadataEntry
+	55, // 1: pubsub.JoinRoomResponse.room_info:type_name -> pubsub.RoomInfo
+	55, // 2: pubsub.GetRoomInfoResponse.room_info:type_name -> pubsub.RoomInfo
+	59, // 3: pubsub.GetUserNodeResponse.connections:type_name -> pubsub.UserConnection
+	58, // 4: pubsub.UpdateRoomPolicyRequest.policy:type_name -> pubsub.RoomPolicy
+	63, // 5: pubsub.UpdateRoomPolicyRequest.update_mask:type_name -> google.protobuf.FieldMask
+	58, // 6: pubsub.UpdateRoomPolicyResponse.policy:type_name -> pubsub.RoomPolicy
+	22, // 7: pubsub.ListFailedWebhooksResponse.failures:type_name -> pubsub.FailedWebhook
+	29, // 8: pubsub.CreateTenantRequest.tenant:type_name -> pubsub.Tenant
+	29, // 9: pubsub.CreateTenantResponse.tenant:type_name -> pubsub.Tenant
+	29, // 10: pubsub.UpdateTenantRequest.tenant:type_name -> pubsub.Tenant
+	63, // 11: pubsub.UpdateTenantRequest.update_mask:type_name -> google.protobuf.FieldMask
+	29, // 12: pubsub.UpdateTenantResponse.tenant:type_name -> pubsub.Tenant
+	29, // 13: pubsub.GetTenantResponse.tenant:type_name -> pubsub.Tenant
+	29, // 14: pubsub.ListTenantsResponse.tenants:type_name -> pubsub.Tenant
+	38, // 15: pubsub.CreateAPIKeyResponse.key:type_name -> pubsub.APIKey
+	38, // 16: pubsub.ListAPIKeysResponse.keys:type_name -> pubsub.APIKey
+	29, // 17: pubsub.ResolveAPIKeyResponse.tenant:type_name -> pubsub.Tenant
+	29, // 18: pubsub.TenantUsage.tenant:type_name -> pubsub.Tenant
+	48, // 19: pubsub.TenantUsage.windows:type_name -> pubsub.UsageWindow
+	49, // 20: pubsub.GetUsageReportResponse.reports:type_name -> pubsub.TenantUsage
+	60, // 21: pubsub.GetRoomStatsResponse.rooms:type_name -> pubsub.RoomStats
+	56, // 22: pubsub.RoomInfo.users:type_name -> pubsub.UserInfo
+	57, // 23: pubsub.RoomInfo.metadata:type_name -> pubsub.RoomMetadata
+	58, // 24: pubsub.RoomInfo.policy:type_name -> pubsub.RoomPolicy
+	62, // 25: pubsub.UserInfo.metadata:type_name -> pubsub.UserInfo.MetadataEntry
+	0,  // 26: pubsub.ControllerService.JoinRoom:input_type -> pubsub.JoinRoomRequest
+	2,  // 27: pubsub.ControllerService.LeaveRoom:input_type -> pubsub.LeaveRoomRequest
+	4,  // 28: pubsub.ControllerService.GetRoomInfo:input_type -> pubsub.GetRoomInfoRequest
+	6,  // 29: pubsub.ControllerService.GetUserNode:input_type -> pubsub.GetUserNodeRequest
+	53, // 30: pubsub.ControllerService.GetRoomStats:input_type -> pubsub.GetRoomStatsRequest
+	12, // 31: pubsub.ControllerService.UpdateRoomPolicy:input_type -> pubsub.UpdateRoomPolicyRequest
+	14, // 32: pubsub.ControllerService.InviteToRoom:input_type -> pubsub.InviteToRoomRequest
+	16, // 33: pubsub.ControllerService.CheckPermission:input_type -> pubsub.CheckPermissionRequest
+	18, // 34: pubsub.ControllerService.SetUserRole:input_type -> pubsub.SetUserRoleRequest
+	20, // 35: pubsub.ControllerService.KickUser:input_type -> pubsub.KickUserRequest
+	51, // 36: pubsub.ControllerService.NodeHeartbeat:input_type -> pubsub.NodeHeartbeatRequest
+	8,  // 37: pubsub.ControllerService.NotifyUserOnline:input_type -> pubsub.NotifyUserOnlineRequest
+	10, // 38: pubsub.ControllerService.NotifyUserOffline:input_type -> pubsub.NotifyUserOfflineRequest
+	23, // 39: pubsub.ControllerService.ListFailedWebhooks:input_type -> pubsub.ListFailedWebhooksRequest
+	25, // 40: pubsub.ControllerService.ReplayWebhooks:input_type -> pubsub.ReplayWebhooksRequest
+	27, // 41: pubsub.ControllerService.WatchEvents:input_type -> pubsub.WatchEventsRequest
+	30, // 42: pubsub.ControllerService.CreateTenant:input_type -> pubsub.CreateTenantRequest
+	32, // 43: pubsub.ControllerService.UpdateTenant:input_type -> pubsub.UpdateTenantRequest
+	34, // 44: pubsub.ControllerService.GetTenant:input_type -> pubsub.GetTenantRequest
+	36, // 45: pubsub.ControllerService.ListTenants:input_type -> pubsub.ListTenantsRequest
+	39, // 46: pubsub.ControllerService.CreateAPIKey:input_type -> pubsub.CreateAPIKeyRequest
+	41, // 47: pubsub.ControllerService.ListAPIKeys:input_type -> pubsub.ListAPIKeysRequest
+	43, // 48: pubsub.ControllerService.RevokeAPIKey:input_type -> pubsub.RevokeAPIKeyRequest
+	45, // 49: pubsub.ControllerService.ResolveAPIKey:input_type -> pubsub.ResolveAPIKeyRequest
+	47, // 50: pubsub.ControllerService.GetUsageReport:input_type -> pubsub.GetUsageReportRequest
+	1,  // 51: pubsub.ControllerService.JoinRoom:output_type -> pubsub.JoinRoomResponse
+	3,  // 52: pubsub.ControllerService.LeaveRoom:output_type -> pubsub.LeaveRoomResponse
+	5,  // 53: pubsub.ControllerService.GetRoomInfo:output_type -> pubsub.GetRoomInfoResponse
+	7,  // 54: pubsub.ControllerService.GetUserNode:output_type -> pubsub.GetUserNodeResponse
+	54, // 55: pubsub.ControllerService.GetRoomStats:output_type -> pubsub.GetRoomStatsResponse
+	13, // 56: pubsub.ControllerService.UpdateRoomPolicy:output_type -> pubsub.UpdateRoomPolicyResponse
+	15, // 57: pubsub.ControllerService.InviteToRoom:output_type -> pubsub.InviteToRoomResponse
+	17, // 58: pubsub.ControllerService.CheckPermission:output_type -> pubsub.CheckPermissionResponse
+	19, // 59: pubsub.ControllerService.SetUserRole:output_type -> pubsub.SetUserRoleResponse
+	21, // 60: pubsub.ControllerService.KickUser:output_type -> pubsub.KickUserResponse
+	52, // 61: pubsub.ControllerService.NodeHeartbeat:output_type -> pubsub.NodeHeartbeatResponse
+	9,  // 62: pubsub.ControllerService.NotifyUserOnline:output_type -> pubsub.NotifyUserOnlineResponse
+	11, // 63: pubsub.ControllerService.NotifyUserOffline:output_type -> pubsub.NotifyUserOfflineResponse
+	24, // 64: pubsub.ControllerService.ListFailedWebhooks:output_type -> pubsub.ListFailedWebhooksResponse
+	26, // 65: pubsub.ControllerService.ReplayWebhooks:output_type -> pubsub.ReplayWebhooksResponse
+	28, // 66: pubsub.ControllerService.WatchEvents:output_type -> pubsub.LifecycleEvent
+	31, // 67: pubsub.ControllerService.CreateTenant:output_type -> pubsub.CreateTenantResponse
+	33, // 68: pubsub.ControllerService.UpdateTenant:output_type -> pubsub.UpdateTenantResponse
+	35, // 69: pubsub.ControllerService.GetTenant:output_type -> pubsub.GetTenantResponse
+	37, // 70: pubsub.ControllerService.ListTenants:output_type -> pubsub.ListTenantsResponse
+	40, // 71: pubsub.ControllerService.CreateAPIKey:output_type -> pubsub.CreateAPIKeyResponse
+	42, // 72: pubsub.ControllerService.ListAPIKeys:output_type -> pubsub.ListAPIKeysResponse
+	44, // 73: pubsub.ControllerService.RevokeAPIKey:output_type -> pubsub.RevokeAPIKeyResponse
+	46, // 74: pubsub.ControllerService.ResolveAPIKey:output_type -> pubsub.ResolveAPIKeyResponse
+	50, // 75: pubsub.ControllerService.GetUsageReport:output_type -> pubsub.GetUsageReportResponse
+	51, // [51:76] is the sub-list for method output_type
+	26, // [26:51] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_controller_proto_init() }
@@ -988,7 +4474,7 @@ func file_controller_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_controller_proto_rawDesc), len(file_controller_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   16,
+			NumMessages:   63,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -33,11 +33,31 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ControllerService_JoinRoom_FullMethodName     = "/pubsub.ControllerService/JoinRoom"
-	ControllerService_LeaveRoom_FullMethodName    = "/pubsub.ControllerService/LeaveRoom"
-	ControllerService_GetRoomInfo_FullMethodName  = "/pubsub.ControllerService/GetRoomInfo"
-	ControllerService_GetUserNode_FullMethodName  = "/pubsub.ControllerService/GetUserNode"
-	ControllerService_GetRoomStats_FullMethodName = "/pubsub.ControllerService/GetRoomStats"
+	ControllerService_JoinRoom_FullMethodName           = "/pubsub.ControllerService/JoinRoom"
+	ControllerService_LeaveRoom_FullMethodName          = "/pubsub.ControllerService/LeaveRoom"
+	ControllerService_GetRoomInfo_FullMethodName        = "/pubsub.ControllerService/GetRoomInfo"
+	ControllerService_GetUserNode_FullMethodName        = "/pubsub.ControllerService/GetUserNode"
+	ControllerService_GetRoomStats_FullMethodName       = "/pubsub.ControllerService/GetRoomStats"
+	ControllerService_UpdateRoomPolicy_FullMethodName   = "/pubsub.ControllerService/UpdateRoomPolicy"
+	ControllerService_InviteToRoom_FullMethodName       = "/pubsub.ControllerService/InviteToRoom"
+	ControllerService_CheckPermission_FullMethodName    = "/pubsub.ControllerService/CheckPermission"
+	ControllerService_SetUserRole_FullMethodName        = "/pubsub.ControllerService/SetUserRole"
+	ControllerService_KickUser_FullMethodName           = "/pubsub.ControllerService/KickUser"
+	ControllerService_NodeHeartbeat_FullMethodName      = "/pubsub.ControllerService/NodeHeartbeat"
+	ControllerService_NotifyUserOnline_FullMethodName   = "/pubsub.ControllerService/NotifyUserOnline"
+	ControllerService_NotifyUserOffline_FullMethodName  = "/pubsub.ControllerService/NotifyUserOffline"
+	ControllerService_ListFailedWebhooks_FullMethodName = "/pubsub.ControllerService/ListFailedWebhooks"
+	ControllerService_ReplayWebhooks_FullMethodName     = "/pubsub.ControllerService/ReplayWebhooks"
+	ControllerService_WatchEvents_FullMethodName        = "/pubsub.ControllerService/WatchEvents"
+	ControllerService_CreateTenant_FullMethodName       = "/pubsub.ControllerService/CreateTenant"
+	ControllerService_UpdateTenant_FullMethodName       = "/pubsub.ControllerService/UpdateTenant"
+	ControllerService_GetTenant_FullMethodName          = "/pubsub.ControllerService/GetTenant"
+	ControllerService_ListTenants_FullMethodName        = "/pubsub.ControllerService/ListTenants"
+	ControllerService_CreateAPIKey_FullMethodName       = "/pubsub.ControllerService/CreateAPIKey"
+	ControllerService_ListAPIKeys_FullMethodName        = "/pubsub.ControllerService/ListAPIKeys"
+	ControllerService_RevokeAPIKey_FullMethodName       = "/pubsub.ControllerService/RevokeAPIKey"
+	ControllerService_ResolveAPIKey_FullMethodName      = "/pubsub.ControllerService/ResolveAPIKey"
+	ControllerService_GetUsageReport_FullMethodName     = "/pubsub.ControllerService/GetUsageReport"
 )
 
 // ControllerServiceClient is the client API for ControllerService service.
@@ -56,6 +76,46 @@ type ControllerServiceClient interface {
 	GetUserNode(ctx context.Context, in *GetUserNodeRequest, opts ...grpc.CallOption) (*GetUserNodeResponse, error)
 	// 获取房间统计
 	GetRoomStats(ctx context.Context, in *GetRoomStatsRequest, opts ...grpc.CallOption) (*GetRoomStatsResponse, error)
+	// 更新房间策略（容量、加入方式、发言权限、消息大小）
+	UpdateRoomPolicy(ctx context.Context, in *UpdateRoomPolicyRequest, opts ...grpc.CallOption) (*UpdateRoomPolicyResponse, error)
+	// 邀请用户加入房间（invite_only 模式）
+	InviteToRoom(ctx context.Context, in *InviteToRoomRequest, opts ...grpc.CallOption) (*InviteToRoomResponse, error)
+	// 检查用户在房间中是否拥有某项权限（publish, invite, kick, settings）
+	CheckPermission(ctx context.Context, in *CheckPermissionRequest, opts ...grpc.CallOption) (*CheckPermissionResponse, error)
+	// 设置房间成员角色（owner, moderator, member, viewer）
+	SetUserRole(ctx context.Context, in *SetUserRoleRequest, opts ...grpc.CallOption) (*SetUserRoleResponse, error)
+	// 将用户踢出房间
+	KickUser(ctx context.Context, in *KickUserRequest, opts ...grpc.CallOption) (*KickUserResponse, error)
+	// Connect-Node 定期上报心跳（连接数、房间数、CPU、内存）
+	NodeHeartbeat(ctx context.Context, in *NodeHeartbeatRequest, opts ...grpc.CallOption) (*NodeHeartbeatResponse, error)
+	// Connect-Node 通知用户连接鉴权成功（写入 user -> connections 索引）
+	NotifyUserOnline(ctx context.Context, in *NotifyUserOnlineRequest, opts ...grpc.CallOption) (*NotifyUserOnlineResponse, error)
+	// Connect-Node 通知用户连接断开（删除索引）
+	NotifyUserOffline(ctx context.Context, in *NotifyUserOfflineRequest, opts ...grpc.CallOption) (*NotifyUserOfflineResponse, error)
+	// 列出投递次数耗尽的 webhook 事件（管理接口）
+	ListFailedWebhooks(ctx context.Context, in *ListFailedWebhooksRequest, opts ...grpc.CallOption) (*ListFailedWebhooksResponse, error)
+	// 重新投递失败的 webhook 事件（管理接口）
+	ReplayWebhooks(ctx context.Context, in *ReplayWebhooksRequest, opts ...grpc.CallOption) (*ReplayWebhooksResponse, error)
+	// 订阅生命周期事件（上下线、房间创建/删除、加入/离开、节点上下线），可按游标断点续读
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LifecycleEvent], error)
+	// 创建租户（管理接口）
+	CreateTenant(ctx context.Context, in *CreateTenantRequest, opts ...grpc.CallOption) (*CreateTenantResponse, error)
+	// 更新租户状态和配额（管理接口）
+	UpdateTenant(ctx context.Context, in *UpdateTenantRequest, opts ...grpc.CallOption) (*UpdateTenantResponse, error)
+	// 查询租户（供 Connect-Node / Push-Manager / Web 校验租户和配额）
+	GetTenant(ctx context.Context, in *GetTenantRequest, opts ...grpc.CallOption) (*GetTenantResponse, error)
+	// 列出租户（管理接口）
+	ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error)
+	// 为租户创建 API key，明文只在创建时返回一次（管理接口）
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error)
+	// 列出租户的 API key（不含明文，管理接口）
+	ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error)
+	// 吊销 API key（管理接口）
+	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*RevokeAPIKeyResponse, error)
+	// 按 API key 哈希查询所属租户（供 Web 鉴权）
+	ResolveAPIKey(ctx context.Context, in *ResolveAPIKeyRequest, opts ...grpc.CallOption) (*ResolveAPIKeyResponse, error)
+	// 租户用量报表：当前连接数和按天/小时汇总的推送、投递、出站字节数（管理接口）
+	GetUsageReport(ctx context.Context, in *GetUsageReportRequest, opts ...grpc.CallOption) (*GetUsageReportResponse, error)
 }
 
 type controllerServiceClient struct {
@@ -116,6 +176,215 @@ func (c *controllerServiceClient) GetRoomStats(ctx context.Context, in *GetRoomS
 	return out, nil
 }
 
+func (c *controllerServiceClient) UpdateRoomPolicy(ctx context.Context, in *UpdateRoomPolicyRequest, opts ...grpc.CallOption) (*UpdateRoomPolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateRoomPolicyResponse)
+	err := c.cc.Invoke(ctx, ControllerService_UpdateRoomPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) InviteToRoom(ctx context.Context, in *InviteToRoomRequest, opts ...grpc.CallOption) (*InviteToRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InviteToRoomResponse)
+	err := c.cc.Invoke(ctx, ControllerService_InviteToRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) CheckPermission(ctx context.Context, in *CheckPermissionRequest, opts ...grpc.CallOption) (*CheckPermissionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckPermissionResponse)
+	err := c.cc.Invoke(ctx, ControllerService_CheckPermission_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) SetUserRole(ctx context.Context, in *SetUserRoleRequest, opts ...grpc.CallOption) (*SetUserRoleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetUserRoleResponse)
+	err := c.cc.Invoke(ctx, ControllerService_SetUserRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) KickUser(ctx context.Context, in *KickUserRequest, opts ...grpc.CallOption) (*KickUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KickUserResponse)
+	err := c.cc.Invoke(ctx, ControllerService_KickUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) NodeHeartbeat(ctx context.Context, in *NodeHeartbeatRequest, opts ...grpc.CallOption) (*NodeHeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeHeartbeatResponse)
+	err := c.cc.Invoke(ctx, ControllerService_NodeHeartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) NotifyUserOnline(ctx context.Context, in *NotifyUserOnlineRequest, opts ...grpc.CallOption) (*NotifyUserOnlineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotifyUserOnlineResponse)
+	err := c.cc.Invoke(ctx, ControllerService_NotifyUserOnline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) NotifyUserOffline(ctx context.Context, in *NotifyUserOfflineRequest, opts ...grpc.CallOption) (*NotifyUserOfflineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotifyUserOfflineResponse)
+	err := c.cc.Invoke(ctx, ControllerService_NotifyUserOffline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) ListFailedWebhooks(ctx context.Context, in *ListFailedWebhooksRequest, opts ...grpc.CallOption) (*ListFailedWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFailedWebhooksResponse)
+	err := c.cc.Invoke(ctx, ControllerService_ListFailedWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) ReplayWebhooks(ctx context.Context, in *ReplayWebhooksRequest, opts ...grpc.CallOption) (*ReplayWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayWebhooksResponse)
+	err := c.cc.Invoke(ctx, ControllerService_ReplayWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LifecycleEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ControllerService_ServiceDesc.Streams[0], ControllerService_WatchEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEventsRequest, LifecycleEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ControllerService_WatchEventsClient = grpc.ServerStreamingClient[LifecycleEvent]
+
+func (c *controllerServiceClient) CreateTenant(ctx context.Context, in *CreateTenantRequest, opts ...grpc.CallOption) (*CreateTenantResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateTenantResponse)
+	err := c.cc.Invoke(ctx, ControllerService_CreateTenant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) UpdateTenant(ctx context.Context, in *UpdateTenantRequest, opts ...grpc.CallOption) (*UpdateTenantResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateTenantResponse)
+	err := c.cc.Invoke(ctx, ControllerService_UpdateTenant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) GetTenant(ctx context.Context, in *GetTenantRequest, opts ...grpc.CallOption) (*GetTenantResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTenantResponse)
+	err := c.cc.Invoke(ctx, ControllerService_GetTenant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTenantsResponse)
+	err := c.cc.Invoke(ctx, ControllerService_ListTenants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, ControllerService_CreateAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAPIKeysResponse)
+	err := c.cc.Invoke(ctx, ControllerService_ListAPIKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*RevokeAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAPIKeyResponse)
+	err := c.cc.Invoke(ctx, ControllerService_RevokeAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) ResolveAPIKey(ctx context.Context, in *ResolveAPIKeyRequest, opts ...grpc.CallOption) (*ResolveAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveAPIKeyResponse)
+	err := c.cc.Invoke(ctx, ControllerService_ResolveAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerServiceClient) GetUsageReport(ctx context.Context, in *GetUsageReportRequest, opts ...grpc.CallOption) (*GetUsageReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsageReportResponse)
+	err := c.cc.Invoke(ctx, ControllerService_GetUsageReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ControllerServiceServer is the server API for ControllerService service.
 // All implementations must embed UnimplementedControllerServiceServer
 // for forward compatibility.
@@ -132,6 +401,46 @@ type ControllerServiceServer interface {
 	GetUserNode(context.Context, *GetUserNodeRequest) (*GetUserNodeResponse, error)
 	// 获取房间统计
 	GetRoomStats(context.Context, *GetRoomStatsRequest) (*GetRoomStatsResponse, error)
+	// 更新房间策略（容量、加入方式、发言权限、消息大小）
+	UpdateRoomPolicy(context.Context, *UpdateRoomPolicyRequest) (*UpdateRoomPolicyResponse, error)
+	// 邀请用户加入房间（invite_only 模式）
+	InviteToRoom(context.Context, *InviteToRoomRequest) (*InviteToRoomResponse, error)
+	// 检查用户在房间中是否拥有某项权限（publish, invite, kick, settings）
+	CheckPermission(context.Context, *CheckPermissionRequest) (*CheckPermissionResponse, error)
+	// 设置房间成员角色（owner, moderator, member, viewer）
+	SetUserRole(context.Context, *SetUserRoleRequest) (*SetUserRoleResponse, error)
+	// 将用户踢出房间
+	KickUser(context.Context, *KickUserRequest) (*KickUserResponse, error)
+	// Connect-Node 定期上报心跳（连接数、房间数、CPU、内存）
+	NodeHeartbeat(context.Context, *NodeHeartbeatRequest) (*NodeHeartbeatResponse, error)
+	// Connect-Node 通知用户连接鉴权成功（写入 user -> connections 索引）
+	NotifyUserOnline(context.Context, *NotifyUserOnlineRequest) (*NotifyUserOnlineResponse, error)
+	// Connect-Node 通知用户连接断开（删除索引）
+	NotifyUserOffline(context.Context, *NotifyUserOfflineRequest) (*NotifyUserOfflineResponse, error)
+	// 列出投递次数耗尽的 webhook 事件（管理接口）
+	ListFailedWebhooks(context.Context, *ListFailedWebhooksRequest) (*ListFailedWebhooksResponse, error)
+	// 重新投递失败的 webhook 事件（管理接口）
+	ReplayWebhooks(context.Context, *ReplayWebhooksRequest) (*ReplayWebhooksResponse, error)
+	// 订阅生命周期事件（上下线、房间创建/删除、加入/离开、节点上下线），可按游标断点续读
+	WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[LifecycleEvent]) error
+	// 创建租户（管理接口）
+	CreateTenant(context.Context, *CreateTenantRequest) (*CreateTenantResponse, error)
+	// 更新租户状态和配额（管理接口）
+	UpdateTenant(context.Context, *UpdateTenantRequest) (*UpdateTenantResponse, error)
+	// 查询租户（供 Connect-Node / Push-Manager / Web 校验租户和配额）
+	GetTenant(context.Context, *GetTenantRequest) (*GetTenantResponse, error)
+	// 列出租户（管理接口）
+	ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error)
+	// 为租户创建 API key，明文只在创建时返回一次（管理接口）
+	CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	// 列出租户的 API key（不含明文，管理接口）
+	ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error)
+	// 吊销 API key（管理接口）
+	RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error)
+	// 按 API key 哈希查询所属租户（供 Web 鉴权）
+	ResolveAPIKey(context.Context, *ResolveAPIKeyRequest) (*ResolveAPIKeyResponse, error)
+	// 租户用量报表：当前连接数和按天/小时汇总的推送、投递、出站字节数（管理接口）
+	GetUsageReport(context.Context, *GetUsageReportRequest) (*GetUsageReportResponse, error)
 	mustEmbedUnimplementedControllerServiceServer()
 }
 
@@ -157,6 +466,66 @@ func (UnimplementedControllerServiceServer) GetUserNode(context.Context, *GetUse
 func (UnimplementedControllerServiceServer) GetRoomStats(context.Context, *GetRoomStatsRequest) (*GetRoomStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRoomStats not implemented")
 }
+func (UnimplementedControllerServiceServer) UpdateRoomPolicy(context.Context, *UpdateRoomPolicyRequest) (*UpdateRoomPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRoomPolicy not implemented")
+}
+func (UnimplementedControllerServiceServer) InviteToRoom(context.Context, *InviteToRoomRequest) (*InviteToRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteToRoom not implemented")
+}
+func (UnimplementedControllerServiceServer) CheckPermission(context.Context, *CheckPermissionRequest) (*CheckPermissionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckPermission not implemented")
+}
+func (UnimplementedControllerServiceServer) SetUserRole(context.Context, *SetUserRoleRequest) (*SetUserRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserRole not implemented")
+}
+func (UnimplementedControllerServiceServer) KickUser(context.Context, *KickUserRequest) (*KickUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickUser not implemented")
+}
+func (UnimplementedControllerServiceServer) NodeHeartbeat(context.Context, *NodeHeartbeatRequest) (*NodeHeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NodeHeartbeat not implemented")
+}
+func (UnimplementedControllerServiceServer) NotifyUserOnline(context.Context, *NotifyUserOnlineRequest) (*NotifyUserOnlineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NotifyUserOnline not implemented")
+}
+func (UnimplementedControllerServiceServer) NotifyUserOffline(context.Context, *NotifyUserOfflineRequest) (*NotifyUserOfflineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NotifyUserOffline not implemented")
+}
+func (UnimplementedControllerServiceServer) ListFailedWebhooks(context.Context, *ListFailedWebhooksRequest) (*ListFailedWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFailedWebhooks not implemented")
+}
+func (UnimplementedControllerServiceServer) ReplayWebhooks(context.Context, *ReplayWebhooksRequest) (*ReplayWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayWebhooks not implemented")
+}
+func (UnimplementedControllerServiceServer) WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[LifecycleEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedControllerServiceServer) CreateTenant(context.Context, *CreateTenantRequest) (*CreateTenantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTenant not implemented")
+}
+func (UnimplementedControllerServiceServer) UpdateTenant(context.Context, *UpdateTenantRequest) (*UpdateTenantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTenant not implemented")
+}
+func (UnimplementedControllerServiceServer) GetTenant(context.Context, *GetTenantRequest) (*GetTenantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTenant not implemented")
+}
+func (UnimplementedControllerServiceServer) ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTenants not implemented")
+}
+func (UnimplementedControllerServiceServer) CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAPIKey not implemented")
+}
+func (UnimplementedControllerServiceServer) ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAPIKeys not implemented")
+}
+func (UnimplementedControllerServiceServer) RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAPIKey not implemented")
+}
+func (UnimplementedControllerServiceServer) ResolveAPIKey(context.Context, *ResolveAPIKeyRequest) (*ResolveAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveAPIKey not implemented")
+}
+func (UnimplementedControllerServiceServer) GetUsageReport(context.Context, *GetUsageReportRequest) (*GetUsageReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsageReport not implemented")
+}
 func (UnimplementedControllerServiceServer) mustEmbedUnimplementedControllerServiceServer() {}
 func (UnimplementedControllerServiceServer) testEmbeddedByValue()                           {}
 
@@ -268,6 +637,359 @@ func _ControllerService_GetRoomStats_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ControllerService_UpdateRoomPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRoomPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).UpdateRoomPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_UpdateRoomPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).UpdateRoomPolicy(ctx, req.(*UpdateRoomPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_InviteToRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteToRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).InviteToRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_InviteToRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).InviteToRoom(ctx, req.(*InviteToRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_CheckPermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPermissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).CheckPermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_CheckPermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).CheckPermission(ctx, req.(*CheckPermissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_SetUserRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).SetUserRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_SetUserRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).SetUserRole(ctx, req.(*SetUserRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_KickUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).KickUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_KickUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).KickUser(ctx, req.(*KickUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_NodeHeartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeHeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).NodeHeartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_NodeHeartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).NodeHeartbeat(ctx, req.(*NodeHeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_NotifyUserOnline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotifyUserOnlineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).NotifyUserOnline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_NotifyUserOnline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).NotifyUserOnline(ctx, req.(*NotifyUserOnlineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_NotifyUserOffline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotifyUserOfflineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).NotifyUserOffline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_NotifyUserOffline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).NotifyUserOffline(ctx, req.(*NotifyUserOfflineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_ListFailedWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFailedWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).ListFailedWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_ListFailedWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).ListFailedWebhooks(ctx, req.(*ListFailedWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_ReplayWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).ReplayWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_ReplayWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).ReplayWebhooks(ctx, req.(*ReplayWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControllerServiceServer).WatchEvents(m, &grpc.GenericServerStream[WatchEventsRequest, LifecycleEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ControllerService_WatchEventsServer = grpc.ServerStreamingServer[LifecycleEvent]
+
+func _ControllerService_CreateTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).CreateTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_CreateTenant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).CreateTenant(ctx, req.(*CreateTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_UpdateTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).UpdateTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_UpdateTenant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).UpdateTenant(ctx, req.(*UpdateTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_GetTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).GetTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_GetTenant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).GetTenant(ctx, req.(*GetTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_ListTenants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTenantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).ListTenants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_ListTenants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).ListTenants(ctx, req.(*ListTenantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).CreateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_CreateAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).CreateAPIKey(ctx, req.(*CreateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_ListAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAPIKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).ListAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_ListAPIKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).ListAPIKeys(ctx, req.(*ListAPIKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).RevokeAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_RevokeAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).RevokeAPIKey(ctx, req.(*RevokeAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_ResolveAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).ResolveAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_ResolveAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).ResolveAPIKey(ctx, req.(*ResolveAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerService_GetUsageReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServiceServer).GetUsageReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerService_GetUsageReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServiceServer).GetUsageReport(ctx, req.(*GetUsageReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ControllerService_ServiceDesc is the grpc.ServiceDesc for ControllerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -295,7 +1017,89 @@ var ControllerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRoomStats",
 			Handler:    _ControllerService_GetRoomStats_Handler,
 		},
+		{
+			MethodName: "UpdateRoomPolicy",
+			Handler:    _ControllerService_UpdateRoomPolicy_Handler,
+		},
+		{
+			MethodName: "InviteToRoom",
+			Handler:    _ControllerService_InviteToRoom_Handler,
+		},
+		{
+			MethodName: "CheckPermission",
+			Handler:    _ControllerService_CheckPermission_Handler,
+		},
+		{
+			MethodName: "SetUserRole",
+			Handler:    _ControllerService_SetUserRole_Handler,
+		},
+		{
+			MethodName: "KickUser",
+			Handler:    _ControllerService_KickUser_Handler,
+		},
+		{
+			MethodName: "NodeHeartbeat",
+			Handler:    _ControllerService_NodeHeartbeat_Handler,
+		},
+		{
+			MethodName: "NotifyUserOnline",
+			Handler:    _ControllerService_NotifyUserOnline_Handler,
+		},
+		{
+			MethodName: "NotifyUserOffline",
+			Handler:    _ControllerService_NotifyUserOffline_Handler,
+		},
+		{
+			MethodName: "ListFailedWebhooks",
+			Handler:    _ControllerService_ListFailedWebhooks_Handler,
+		},
+		{
+			MethodName: "ReplayWebhooks",
+			Handler:    _ControllerService_ReplayWebhooks_Handler,
+		},
+		{
+			MethodName: "CreateTenant",
+			Handler:    _ControllerService_CreateTenant_Handler,
+		},
+		{
+			MethodName: "UpdateTenant",
+			Handler:    _ControllerService_UpdateTenant_Handler,
+		},
+		{
+			MethodName: "GetTenant",
+			Handler:    _ControllerService_GetTenant_Handler,
+		},
+		{
+			MethodName: "ListTenants",
+			Handler:    _ControllerService_ListTenants_Handler,
+		},
+		{
+			MethodName: "CreateAPIKey",
+			Handler:    _ControllerService_CreateAPIKey_Handler,
+		},
+		{
+			MethodName: "ListAPIKeys",
+			Handler:    _ControllerService_ListAPIKeys_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKey",
+			Handler:    _ControllerService_RevokeAPIKey_Handler,
+		},
+		{
+			MethodName: "ResolveAPIKey",
+			Handler:    _ControllerService_ResolveAPIKey_Handler,
+		},
+		{
+			MethodName: "GetUsageReport",
+			Handler:    _ControllerService_GetUsageReport_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _ControllerService_WatchEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller.proto",
 }
@@ -2,12 +2,51 @@ package protocol
 
 import (
 	"errors"
+	"strings"
 )
 
 const (
+	// OpJoinRoom join room
+	OpJoinRoom = int32(1)
+	// OpJoinRoomReply join room reply
+	OpJoinRoomReply = int32(2)
+	// OpSendMsg server push msg
+	OpSendMsg = int32(2)
+
 	// OpAuth auth connnect
 	OpAuth = int32(7)
 
+	// OpPublish client publish msg to room
+	OpPublish = int32(8)
+	// OpPublishReply publish reply
+	OpPublishReply = int32(9)
+
+	// OpPresence room presence change (join/leave), pushed by server
+	OpPresence = int32(12)
+
+	// OpSubscribe subscribe topic patterns, body: {"topics": ["stock.AAPL.*"]}
+	OpSubscribe = int32(13)
+	// OpSubscribeReply subscribe reply
+	OpSubscribeReply = int32(14)
+	// OpUnsubscribe unsubscribe topic patterns, body: {"topics": [...]}
+	OpUnsubscribe = int32(15)
+	// OpUnsubscribeReply unsubscribe reply
+	OpUnsubscribeReply = int32(16)
+
+	// OpWatch watch push op codes, body: {"ops": [2, 1001]}
+	OpWatch = int32(17)
+	// OpWatchReply watch reply
+	OpWatchReply = int32(18)
+	// OpUnWatch unwatch push op codes, body: {"ops": [...]}
+	OpUnWatch = int32(19)
+	// OpUnWatchReply unwatch reply
+	OpUnWatchReply = int32(20)
+
+	// OpServerRequest server-initiated request, the client must reply with OpServerResponse and the same seq
+	OpServerRequest = int32(21)
+	// OpServerResponse client reply to OpServerRequest
+	OpServerResponse = int32(22)
+
 	OpProtoReady = int32(10)
 
 	MaxBodySize = int32(1 << 12)
@@ -16,6 +55,13 @@ const (
 	OpProtoFinish = int32(11)
 )
 
+const (
+	// JoinRoomSuccess join room reply body on success
+	JoinRoomSuccess = "join room success"
+	// JoinRoomFailed join room reply body prefix on failure, followed by the reason
+	JoinRoomFailed = "join room failed: "
+)
+
 var (
 	// ProtoReady proto ready
 	ProtoReady = &Proto{Op: OpProtoReady}
@@ -26,3 +72,20 @@ var (
 	// ErrProtoHeaderLen proto header len error
 	ErrProtoHeaderLen = errors.New("default server codec header length error")
 )
+
+// JoinRoomReply reports whether p is a join room reply. The reply shares op 2 with
+// pushed messages (OpSendMsg) and is told apart by its body; when the join was
+// rejected, rejected is true and reason holds the message from the Controller.
+func JoinRoomReply(p *Proto) (isReply, rejected bool, reason string) {
+	if p.Op != OpJoinRoomReply {
+		return false, false, ""
+	}
+	body := string(p.Body)
+	if body == JoinRoomSuccess {
+		return true, false, ""
+	}
+	if reason, ok := strings.CutPrefix(body, JoinRoomFailed); ok {
+		return true, true, reason
+	}
+	return false, false, ""
+}
@@ -21,6 +21,56 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Priority 推送优先级：高优先级（强制下线、审核通知等系统消息）总是先于普通和低优先级（批量聊天）投递
+type Priority int32
+
+const (
+	Priority_PRIORITY_NORMAL Priority = 0
+	Priority_PRIORITY_HIGH   Priority = 1
+	Priority_PRIORITY_LOW    Priority = 2
+)
+
+// Enum value maps for Priority.
+var (
+	Priority_name = map[int32]string{
+		0: "PRIORITY_NORMAL",
+		1: "PRIORITY_HIGH",
+		2: "PRIORITY_LOW",
+	}
+	Priority_value = map[string]int32{
+		"PRIORITY_NORMAL": 0,
+		"PRIORITY_HIGH":   1,
+		"PRIORITY_LOW":    2,
+	}
+)
+
+func (x Priority) Enum() *Priority {
+	p := new(Priority)
+	*p = x
+	return p
+}
+
+func (x Priority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Priority) Descriptor() protoreflect.EnumDescriptor {
+	return file_protocol_proto_enumTypes[0].Descriptor()
+}
+
+func (Priority) Type() protoreflect.EnumType {
+	return &file_protocol_proto_enumTypes[0]
+}
+
+func (x Priority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Priority.Descriptor instead.
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{0}
+}
+
 type Proto struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ver           int32                  `protobuf:"varint,1,opt,name=ver,proto3" json:"ver,omitempty"`
@@ -116,7 +166,11 @@ const file_protocol_proto_rawDesc = "" +
 	"\x03seq\x18\x03 \x01(\x05R\x03seq\x12\x16\n" +
 	"\x06roomid\x18\x04 \x01(\tR\x06roomid\x12\x16\n" +
 	"\x06userid\x18\x05 \x01(\tR\x06userid\x12\x12\n" +
-	"\x04body\x18\x06 \x01(\fR\x04bodyBEZCgithub.com/livekit/psrpc/examples/pubsub/protocol/protocol;protocolb\x06proto3"
+	"\x04body\x18\x06 \x01(\fR\x04body*D\n" +
+	"\bPriority\x12\x13\n" +
+	"\x0fPRIORITY_NORMAL\x10\x00\x12\x11\n" +
+	"\rPRIORITY_HIGH\x10\x01\x12\x10\n" +
+	"\fPRIORITY_LOW\x10\x02BEZCgithub.com/livekit/psrpc/examples/pubsub/protocol/protocol;protocolb\x06proto3"
 
 var (
 	file_protocol_proto_rawDescOnce sync.Once
@@ -130,9 +184,11 @@ func file_protocol_proto_rawDescGZIP() []byte {
 	return file_protocol_proto_rawDescData
 }
 
+var file_protocol_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
 var file_protocol_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
 var file_protocol_proto_goTypes = []any{
-	(*Proto)(nil), // 0: protocol.Proto
+	(Priority)(0), // 0: protocol.Priority
+	(*Proto)(nil), // 1: protocol.Proto
 }
 var file_protocol_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -152,13 +208,14 @@ func file_protocol_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protocol_proto_rawDesc), len(file_protocol_proto_rawDesc)),
-			NumEnums:      0,
+			NumEnums:      1,
 			NumMessages:   1,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_protocol_proto_goTypes,
 		DependencyIndexes: file_protocol_proto_depIdxs,
+		EnumInfos:         file_protocol_proto_enumTypes,
 		MessageInfos:      file_protocol_proto_msgTypes,
 	}.Build()
 	File_protocol_proto = out.File
@@ -27,6 +27,9 @@ type PushMsgReq struct {
 	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
 	Proto         *protocol.Proto        `protobuf:"bytes,2,opt,name=proto,proto3" json:"proto,omitempty"`
 	ProtoOp       int32                  `protobuf:"varint,3,opt,name=protoOp,proto3" json:"protoOp,omitempty"`
+	MsgID         string                 `protobuf:"bytes,4,opt,name=msgID,proto3" json:"msgID,omitempty"`                               // 消息 ID（Connect-Node 据此丢弃重投的重复消息）
+	Priority      protocol.Priority      `protobuf:"varint,5,opt,name=priority,proto3,enum=protocol.Priority" json:"priority,omitempty"` // 推送优先级（Connect-Node 按优先级分道写入连接）
+	ExpireAt      int64                  `protobuf:"varint,6,opt,name=expireAt,proto3" json:"expireAt,omitempty"`                        // 过期时间（Unix 毫秒），0 表示不过期
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -82,6 +85,27 @@ func (x *PushMsgReq) GetProtoOp() int32 {
 	return 0
 }
 
+func (x *PushMsgReq) GetMsgID() string {
+	if x != nil {
+		return x.MsgID
+	}
+	return ""
+}
+
+func (x *PushMsgReq) GetPriority() protocol.Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return protocol.Priority(0)
+}
+
+func (x *PushMsgReq) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
 type PushMsgReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -123,6 +147,10 @@ type BroadcastReq struct {
 	ProtoOp       int32                  `protobuf:"varint,1,opt,name=protoOp,proto3" json:"protoOp,omitempty"`
 	Proto         *protocol.Proto        `protobuf:"bytes,2,opt,name=proto,proto3" json:"proto,omitempty"`
 	Speed         int32                  `protobuf:"varint,3,opt,name=speed,proto3" json:"speed,omitempty"`
+	MsgID         string                 `protobuf:"bytes,4,opt,name=msgID,proto3" json:"msgID,omitempty"`
+	Priority      protocol.Priority      `protobuf:"varint,5,opt,name=priority,proto3,enum=protocol.Priority" json:"priority,omitempty"`
+	ExpireAt      int64                  `protobuf:"varint,6,opt,name=expireAt,proto3" json:"expireAt,omitempty"`
+	AppID         string                 `protobuf:"bytes,7,opt,name=appID,proto3" json:"appID,omitempty"` // 租户：只推送给该租户的连接（为空表示默认租户）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -178,6 +206,34 @@ func (x *BroadcastReq) GetSpeed() int32 {
 	return 0
 }
 
+func (x *BroadcastReq) GetMsgID() string {
+	if x != nil {
+		return x.MsgID
+	}
+	return ""
+}
+
+func (x *BroadcastReq) GetPriority() protocol.Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return protocol.Priority(0)
+}
+
+func (x *BroadcastReq) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *BroadcastReq) GetAppID() string {
+	if x != nil {
+		return x.AppID
+	}
+	return ""
+}
+
 type BroadcastReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -218,6 +274,9 @@ type BroadcastRoomReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RoomID        string                 `protobuf:"bytes,1,opt,name=roomID,proto3" json:"roomID,omitempty"`
 	Proto         *protocol.Proto        `protobuf:"bytes,2,opt,name=proto,proto3" json:"proto,omitempty"`
+	MsgID         string                 `protobuf:"bytes,3,opt,name=msgID,proto3" json:"msgID,omitempty"`
+	Priority      protocol.Priority      `protobuf:"varint,4,opt,name=priority,proto3,enum=protocol.Priority" json:"priority,omitempty"`
+	ExpireAt      int64                  `protobuf:"varint,5,opt,name=expireAt,proto3" json:"expireAt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -266,6 +325,27 @@ func (x *BroadcastRoomReq) GetProto() *protocol.Proto {
 	return nil
 }
 
+func (x *BroadcastRoomReq) GetMsgID() string {
+	if x != nil {
+		return x.MsgID
+	}
+	return ""
+}
+
+func (x *BroadcastRoomReq) GetPriority() protocol.Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return protocol.Priority(0)
+}
+
+func (x *BroadcastRoomReq) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
 type BroadcastRoomReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -302,6 +382,256 @@ func (*BroadcastRoomReply) Descriptor() ([]byte, []int) {
 	return file_push_push_proto_rawDescGZIP(), []int{5}
 }
 
+// 主题广播：推送给订阅模式匹配 topic 的连接
+type BroadcastTopicReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topic         string                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Proto         *protocol.Proto        `protobuf:"bytes,2,opt,name=proto,proto3" json:"proto,omitempty"`
+	MsgID         string                 `protobuf:"bytes,3,opt,name=msgID,proto3" json:"msgID,omitempty"`
+	Priority      protocol.Priority      `protobuf:"varint,4,opt,name=priority,proto3,enum=protocol.Priority" json:"priority,omitempty"`
+	ExpireAt      int64                  `protobuf:"varint,5,opt,name=expireAt,proto3" json:"expireAt,omitempty"`
+	AppID         string                 `protobuf:"bytes,6,opt,name=appID,proto3" json:"appID,omitempty"` // 租户：只推送给该租户的连接
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadcastTopicReq) Reset() {
+	*x = BroadcastTopicReq{}
+	mi := &file_push_push_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadcastTopicReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastTopicReq) ProtoMessage() {}
+
+func (x *BroadcastTopicReq) ProtoReflect() protoreflect.Message {
+	mi := &file_push_push_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastTopicReq.ProtoReflect.Descriptor instead.
+func (*BroadcastTopicReq) Descriptor() ([]byte, []int) {
+	return file_push_push_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BroadcastTopicReq) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *BroadcastTopicReq) GetProto() *protocol.Proto {
+	if x != nil {
+		return x.Proto
+	}
+	return nil
+}
+
+func (x *BroadcastTopicReq) GetMsgID() string {
+	if x != nil {
+		return x.MsgID
+	}
+	return ""
+}
+
+func (x *BroadcastTopicReq) GetPriority() protocol.Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return protocol.Priority(0)
+}
+
+func (x *BroadcastTopicReq) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *BroadcastTopicReq) GetAppID() string {
+	if x != nil {
+		return x.AppID
+	}
+	return ""
+}
+
+type BroadcastTopicReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadcastTopicReply) Reset() {
+	*x = BroadcastTopicReply{}
+	mi := &file_push_push_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadcastTopicReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastTopicReply) ProtoMessage() {}
+
+func (x *BroadcastTopicReply) ProtoReflect() protoreflect.Message {
+	mi := &file_push_push_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastTopicReply.ProtoReflect.Descriptor instead.
+func (*BroadcastTopicReply) Descriptor() ([]byte, []int) {
+	return file_push_push_proto_rawDescGZIP(), []int{7}
+}
+
+// 向客户端发起请求并等待其响应（服务端发起的 RPC）
+type ClientRequestReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`              // 会话 key：userId（全部会话，最先响应的生效）或 userId/deviceId
+	Body          []byte                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`            // 请求体，以 op=OpServerRequest 下发，Seq 为关联序号
+	TimeoutMs     int64                  `protobuf:"varint,3,opt,name=timeoutMs,proto3" json:"timeoutMs,omitempty"` // 等待客户端响应的超时
+	RequestID     string                 `protobuf:"bytes,4,opt,name=requestID,proto3" json:"requestID,omitempty"`  // 请求 ID（日志关联）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientRequestReq) Reset() {
+	*x = ClientRequestReq{}
+	mi := &file_push_push_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientRequestReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientRequestReq) ProtoMessage() {}
+
+func (x *ClientRequestReq) ProtoReflect() protoreflect.Message {
+	mi := &file_push_push_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientRequestReq.ProtoReflect.Descriptor instead.
+func (*ClientRequestReq) Descriptor() ([]byte, []int) {
+	return file_push_push_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ClientRequestReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ClientRequestReq) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *ClientRequestReq) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *ClientRequestReq) GetRequestID() string {
+	if x != nil {
+		return x.RequestID
+	}
+	return ""
+}
+
+type ClientRequestReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestID     string                 `protobuf:"bytes,1,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	ConnID        string                 `protobuf:"bytes,2,opt,name=connID,proto3" json:"connID,omitempty"` // 响应的连接
+	Body          []byte                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`     // 客户端响应体
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientRequestReply) Reset() {
+	*x = ClientRequestReply{}
+	mi := &file_push_push_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientRequestReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientRequestReply) ProtoMessage() {}
+
+func (x *ClientRequestReply) ProtoReflect() protoreflect.Message {
+	mi := &file_push_push_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientRequestReply.ProtoReflect.Descriptor instead.
+func (*ClientRequestReply) Descriptor() ([]byte, []int) {
+	return file_push_push_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ClientRequestReply) GetRequestID() string {
+	if x != nil {
+		return x.RequestID
+	}
+	return ""
+}
+
+func (x *ClientRequestReply) GetConnID() string {
+	if x != nil {
+		return x.ConnID
+	}
+	return ""
+}
+
+func (x *ClientRequestReply) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
 type RoomsReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -310,7 +640,7 @@ type RoomsReq struct {
 
 func (x *RoomsReq) Reset() {
 	*x = RoomsReq{}
-	mi := &file_push_push_proto_msgTypes[6]
+	mi := &file_push_push_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -322,7 +652,7 @@ func (x *RoomsReq) String() string {
 func (*RoomsReq) ProtoMessage() {}
 
 func (x *RoomsReq) ProtoReflect() protoreflect.Message {
-	mi := &file_push_push_proto_msgTypes[6]
+	mi := &file_push_push_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -335,7 +665,7 @@ func (x *RoomsReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoomsReq.ProtoReflect.Descriptor instead.
 func (*RoomsReq) Descriptor() ([]byte, []int) {
-	return file_push_push_proto_rawDescGZIP(), []int{6}
+	return file_push_push_proto_rawDescGZIP(), []int{10}
 }
 
 type RoomsReply struct {
@@ -347,7 +677,7 @@ type RoomsReply struct {
 
 func (x *RoomsReply) Reset() {
 	*x = RoomsReply{}
-	mi := &file_push_push_proto_msgTypes[7]
+	mi := &file_push_push_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -359,7 +689,7 @@ func (x *RoomsReply) String() string {
 func (*RoomsReply) ProtoMessage() {}
 
 func (x *RoomsReply) ProtoReflect() protoreflect.Message {
-	mi := &file_push_push_proto_msgTypes[7]
+	mi := &file_push_push_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -372,7 +702,7 @@ func (x *RoomsReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoomsReply.ProtoReflect.Descriptor instead.
 func (*RoomsReply) Descriptor() ([]byte, []int) {
-	return file_push_push_proto_rawDescGZIP(), []int{7}
+	return file_push_push_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *RoomsReply) GetRooms() map[string]bool {
@@ -382,26 +712,290 @@ func (x *RoomsReply) GetRooms() map[string]bool {
 	return nil
 }
 
+// 流式通道中的一条推送命令
+type PushCommand struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Command:
+	//
+	//	*PushCommand_PushMsg
+	//	*PushCommand_Broadcast
+	//	*PushCommand_BroadcastRoom
+	//	*PushCommand_BroadcastTopic
+	Command       isPushCommand_Command `protobuf_oneof:"command"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushCommand) Reset() {
+	*x = PushCommand{}
+	mi := &file_push_push_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushCommand) ProtoMessage() {}
+
+func (x *PushCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_push_push_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushCommand.ProtoReflect.Descriptor instead.
+func (*PushCommand) Descriptor() ([]byte, []int) {
+	return file_push_push_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PushCommand) GetCommand() isPushCommand_Command {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *PushCommand) GetPushMsg() *PushMsgReq {
+	if x != nil {
+		if x, ok := x.Command.(*PushCommand_PushMsg); ok {
+			return x.PushMsg
+		}
+	}
+	return nil
+}
+
+func (x *PushCommand) GetBroadcast() *BroadcastReq {
+	if x != nil {
+		if x, ok := x.Command.(*PushCommand_Broadcast); ok {
+			return x.Broadcast
+		}
+	}
+	return nil
+}
+
+func (x *PushCommand) GetBroadcastRoom() *BroadcastRoomReq {
+	if x != nil {
+		if x, ok := x.Command.(*PushCommand_BroadcastRoom); ok {
+			return x.BroadcastRoom
+		}
+	}
+	return nil
+}
+
+func (x *PushCommand) GetBroadcastTopic() *BroadcastTopicReq {
+	if x != nil {
+		if x, ok := x.Command.(*PushCommand_BroadcastTopic); ok {
+			return x.BroadcastTopic
+		}
+	}
+	return nil
+}
+
+type isPushCommand_Command interface {
+	isPushCommand_Command()
+}
+
+type PushCommand_PushMsg struct {
+	PushMsg *PushMsgReq `protobuf:"bytes,1,opt,name=push_msg,json=pushMsg,proto3,oneof"`
+}
+
+type PushCommand_Broadcast struct {
+	Broadcast *BroadcastReq `protobuf:"bytes,2,opt,name=broadcast,proto3,oneof"`
+}
+
+type PushCommand_BroadcastRoom struct {
+	BroadcastRoom *BroadcastRoomReq `protobuf:"bytes,3,opt,name=broadcast_room,json=broadcastRoom,proto3,oneof"`
+}
+
+type PushCommand_BroadcastTopic struct {
+	BroadcastTopic *BroadcastTopicReq `protobuf:"bytes,4,opt,name=broadcast_topic,json=broadcastTopic,proto3,oneof"`
+}
+
+func (*PushCommand_PushMsg) isPushCommand_Command() {}
+
+func (*PushCommand_Broadcast) isPushCommand_Command() {}
+
+func (*PushCommand_BroadcastRoom) isPushCommand_Command() {}
+
+func (*PushCommand_BroadcastTopic) isPushCommand_Command() {}
+
+// 一批推送命令（Push-Manager -> Connect-Node），每批消耗一个 credit
+type PushBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchId       uint64                 `protobuf:"varint,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	Commands      []*PushCommand         `protobuf:"bytes,2,rep,name=commands,proto3" json:"commands,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushBatch) Reset() {
+	*x = PushBatch{}
+	mi := &file_push_push_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushBatch) ProtoMessage() {}
+
+func (x *PushBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_push_push_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushBatch.ProtoReflect.Descriptor instead.
+func (*PushBatch) Descriptor() ([]byte, []int) {
+	return file_push_push_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PushBatch) GetBatchId() uint64 {
+	if x != nil {
+		return x.BatchId
+	}
+	return 0
+}
+
+func (x *PushBatch) GetCommands() []*PushCommand {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+// 批次确认与流控（Connect-Node -> Push-Manager）
+type PushBatchAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchId       uint64                 `protobuf:"varint,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"` // 0 表示仅发放 credit（如建流时的初始 credit）
+	Failed        []int32                `protobuf:"varint,2,rep,packed,name=failed,proto3" json:"failed,omitempty"`           // 处理失败的命令在批次中的下标
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`                     // 第一个失败命令的错误
+	Credits       int32                  `protobuf:"varint,4,opt,name=credits,proto3" json:"credits,omitempty"`                // 新发放的 credit（允许再发送的批次数）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushBatchAck) Reset() {
+	*x = PushBatchAck{}
+	mi := &file_push_push_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushBatchAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushBatchAck) ProtoMessage() {}
+
+func (x *PushBatchAck) ProtoReflect() protoreflect.Message {
+	mi := &file_push_push_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushBatchAck.ProtoReflect.Descriptor instead.
+func (*PushBatchAck) Descriptor() ([]byte, []int) {
+	return file_push_push_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PushBatchAck) GetBatchId() uint64 {
+	if x != nil {
+		return x.BatchId
+	}
+	return 0
+}
+
+func (x *PushBatchAck) GetFailed() []int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return nil
+}
+
+func (x *PushBatchAck) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PushBatchAck) GetCredits() int32 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
 var File_push_push_proto protoreflect.FileDescriptor
 
 const file_push_push_proto_rawDesc = "" +
 	"\n" +
-	"\x0fpush/push.proto\x12\bprotocol\x1a\x17protocol/protocol.proto\"a\n" +
+	"\x0fpush/push.proto\x12\bprotocol\x1a\x17protocol/protocol.proto\"\xc3\x01\n" +
 	"\n" +
 	"PushMsgReq\x12\x12\n" +
 	"\x04keys\x18\x01 \x03(\tR\x04keys\x12%\n" +
 	"\x05proto\x18\x02 \x01(\v2\x0f.protocol.ProtoR\x05proto\x12\x18\n" +
-	"\aprotoOp\x18\x03 \x01(\x05R\aprotoOp\"\x0e\n" +
-	"\fPushMsgReply\"e\n" +
+	"\aprotoOp\x18\x03 \x01(\x05R\aprotoOp\x12\x14\n" +
+	"\x05msgID\x18\x04 \x01(\tR\x05msgID\x12.\n" +
+	"\bpriority\x18\x05 \x01(\x0e2\x12.protocol.PriorityR\bpriority\x12\x1a\n" +
+	"\bexpireAt\x18\x06 \x01(\x03R\bexpireAt\"\x0e\n" +
+	"\fPushMsgReply\"\xdd\x01\n" +
 	"\fBroadcastReq\x12\x18\n" +
 	"\aprotoOp\x18\x01 \x01(\x05R\aprotoOp\x12%\n" +
 	"\x05proto\x18\x02 \x01(\v2\x0f.protocol.ProtoR\x05proto\x12\x14\n" +
-	"\x05speed\x18\x03 \x01(\x05R\x05speed\"\x10\n" +
-	"\x0eBroadcastReply\"Q\n" +
+	"\x05speed\x18\x03 \x01(\x05R\x05speed\x12\x14\n" +
+	"\x05msgID\x18\x04 \x01(\tR\x05msgID\x12.\n" +
+	"\bpriority\x18\x05 \x01(\x0e2\x12.protocol.PriorityR\bpriority\x12\x1a\n" +
+	"\bexpireAt\x18\x06 \x01(\x03R\bexpireAt\x12\x14\n" +
+	"\x05appID\x18\a \x01(\tR\x05appID\"\x10\n" +
+	"\x0eBroadcastReply\"\xb3\x01\n" +
 	"\x10BroadcastRoomReq\x12\x16\n" +
 	"\x06roomID\x18\x01 \x01(\tR\x06roomID\x12%\n" +
-	"\x05proto\x18\x02 \x01(\v2\x0f.protocol.ProtoR\x05proto\"\x14\n" +
-	"\x12BroadcastRoomReply\"\n" +
+	"\x05proto\x18\x02 \x01(\v2\x0f.protocol.ProtoR\x05proto\x12\x14\n" +
+	"\x05msgID\x18\x03 \x01(\tR\x05msgID\x12.\n" +
+	"\bpriority\x18\x04 \x01(\x0e2\x12.protocol.PriorityR\bpriority\x12\x1a\n" +
+	"\bexpireAt\x18\x05 \x01(\x03R\bexpireAt\"\x14\n" +
+	"\x12BroadcastRoomReply\"\xc8\x01\n" +
+	"\x11BroadcastTopicReq\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\x12%\n" +
+	"\x05proto\x18\x02 \x01(\v2\x0f.protocol.ProtoR\x05proto\x12\x14\n" +
+	"\x05msgID\x18\x03 \x01(\tR\x05msgID\x12.\n" +
+	"\bpriority\x18\x04 \x01(\x0e2\x12.protocol.PriorityR\bpriority\x12\x1a\n" +
+	"\bexpireAt\x18\x05 \x01(\x03R\bexpireAt\x12\x14\n" +
+	"\x05appID\x18\x06 \x01(\tR\x05appID\"\x15\n" +
+	"\x13BroadcastTopicReply\"t\n" +
+	"\x10ClientRequestReq\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\fR\x04body\x12\x1c\n" +
+	"\ttimeoutMs\x18\x03 \x01(\x03R\ttimeoutMs\x12\x1c\n" +
+	"\trequestID\x18\x04 \x01(\tR\trequestID\"^\n" +
+	"\x12ClientRequestReply\x12\x1c\n" +
+	"\trequestID\x18\x01 \x01(\tR\trequestID\x12\x16\n" +
+	"\x06connID\x18\x02 \x01(\tR\x06connID\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\fR\x04body\"\n" +
 	"\n" +
 	"\bRoomsReq\"}\n" +
 	"\n" +
@@ -410,12 +1004,30 @@ const file_push_push_proto_rawDesc = "" +
 	"\n" +
 	"RoomsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\bR\x05value:\x028\x012\xfd\x01\n" +
+	"\x05value\x18\x02 \x01(\bR\x05value:\x028\x01\"\x90\x02\n" +
+	"\vPushCommand\x121\n" +
+	"\bpush_msg\x18\x01 \x01(\v2\x14.protocol.PushMsgReqH\x00R\apushMsg\x126\n" +
+	"\tbroadcast\x18\x02 \x01(\v2\x16.protocol.BroadcastReqH\x00R\tbroadcast\x12C\n" +
+	"\x0ebroadcast_room\x18\x03 \x01(\v2\x1a.protocol.BroadcastRoomReqH\x00R\rbroadcastRoom\x12F\n" +
+	"\x0fbroadcast_topic\x18\x04 \x01(\v2\x1b.protocol.BroadcastTopicReqH\x00R\x0ebroadcastTopicB\t\n" +
+	"\acommand\"Y\n" +
+	"\tPushBatch\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\x04R\abatchId\x121\n" +
+	"\bcommands\x18\x02 \x03(\v2\x15.protocol.PushCommandR\bcommands\"q\n" +
+	"\fPushBatchAck\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\x04R\abatchId\x12\x16\n" +
+	"\x06failed\x18\x02 \x03(\x05R\x06failed\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x18\n" +
+	"\acredits\x18\x04 \x01(\x05R\acredits2\xd5\x03\n" +
 	"\x05Comet\x127\n" +
 	"\aPushMsg\x12\x14.protocol.PushMsgReq\x1a\x16.protocol.PushMsgReply\x12=\n" +
 	"\tBroadcast\x12\x16.protocol.BroadcastReq\x1a\x18.protocol.BroadcastReply\x12I\n" +
-	"\rBroadcastRoom\x12\x1a.protocol.BroadcastRoomReq\x1a\x1c.protocol.BroadcastRoomReply\x121\n" +
-	"\x05Rooms\x12\x12.protocol.RoomsReq\x1a\x14.protocol.RoomsReplyB\rZ\v./push;pushb\x06proto3"
+	"\rBroadcastRoom\x12\x1a.protocol.BroadcastRoomReq\x1a\x1c.protocol.BroadcastRoomReply\x12L\n" +
+	"\x0eBroadcastTopic\x12\x1b.protocol.BroadcastTopicReq\x1a\x1d.protocol.BroadcastTopicReply\x121\n" +
+	"\x05Rooms\x12\x12.protocol.RoomsReq\x1a\x14.protocol.RoomsReply\x12I\n" +
+	"\rClientRequest\x12\x1a.protocol.ClientRequestReq\x1a\x1c.protocol.ClientRequestReply\x12=\n" +
+	"\n" +
+	"PushStream\x12\x13.protocol.PushBatch\x1a\x16.protocol.PushBatchAck(\x010\x01B=Z;github.com/livekit/psrpc/examples/pubsub/protocol/push;pushb\x06proto3"
 
 var (
 	file_push_push_proto_rawDescOnce sync.Once
@@ -429,37 +1041,61 @@ func file_push_push_proto_rawDescGZIP() []byte {
 	return file_push_push_proto_rawDescData
 }
 
-var file_push_push_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_push_push_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_push_push_proto_goTypes = []any{
-	(*PushMsgReq)(nil),         // 0: protocol.PushMsgReq
-	(*PushMsgReply)(nil),       // 1: protocol.PushMsgReply
-	(*BroadcastReq)(nil),       // 2: protocol.BroadcastReq
-	(*BroadcastReply)(nil),     // 3: protocol.BroadcastReply
-	(*BroadcastRoomReq)(nil),   // 4: protocol.BroadcastRoomReq
-	(*BroadcastRoomReply)(nil), // 5: protocol.BroadcastRoomReply
-	(*RoomsReq)(nil),           // 6: protocol.RoomsReq
-	(*RoomsReply)(nil),         // 7: protocol.RoomsReply
-	nil,                        // 8: protocol.RoomsReply.RoomsEntry
-	(*protocol.Proto)(nil),     // 9: protocol.Proto
+	(*PushMsgReq)(nil),          // 0: protocol.PushMsgReq
+	(*PushMsgReply)(nil),        // 1: protocol.PushMsgReply
+	(*BroadcastReq)(nil),        // 2: protocol.BroadcastReq
+	(*BroadcastReply)(nil),      // 3: protocol.BroadcastReply
+	(*BroadcastRoomReq)(nil),    // 4: protocol.BroadcastRoomReq
+	(*BroadcastRoomReply)(nil),  // 5: protocol.BroadcastRoomReply
+	(*BroadcastTopicReq)(nil),   // 6: protocol.BroadcastTopicReq
+	(*BroadcastTopicReply)(nil), // 7: protocol.BroadcastTopicReply
+	(*ClientRequestReq)(nil),    // 8: protocol.ClientRequestReq
+	(*ClientRequestReply)(nil),  // 9: protocol.ClientRequestReply
+	(*RoomsReq)(nil),            // 10: protocol.RoomsReq
+	(*RoomsReply)(nil),          // 11: protocol.RoomsReply
+	(*PushCommand)(nil),         // 12: protocol.PushCommand
+	(*PushBatch)(nil),           // 13: protocol.PushBatch
+	(*PushBatchAck)(nil),        // 14: protocol.PushBatchAck
+	nil,                         // 15: protocol.RoomsReply.RoomsEntry
+	(*protocol.Proto)(nil),      // 16: protocol.Proto
+	(protocol.Priority)(0),      // 17: protocol.Priority
 }
 var file_push_push_proto_depIdxs = []int32{
-	9, // 0: protocol.PushMsgReq.proto:type_name -> protocol.Proto
-	9, // 1: protocol.BroadcastReq.proto:type_name -> protocol.Proto
-	9, // 2: protocol.BroadcastRoomReq.proto:type_name -> protocol.Proto
-	8, // 3: protocol.RoomsReply.rooms:type_name -> protocol.RoomsReply.RoomsEntry
-	0, // 4: protocol.Comet.PushMsg:input_type -> protocol.PushMsgReq
-	2, // 5: protocol.Comet.Broadcast:input_type -> protocol.BroadcastReq
-	4, // 6: protocol.Comet.BroadcastRoom:input_type -> protocol.BroadcastRoomReq
-	6, // 7: protocol.Comet.Rooms:input_type -> protocol.RoomsReq
-	1, // 8: protocol.Comet.PushMsg:output_type -> protocol.PushMsgReply
-	3, // 9: protocol.Comet.Broadcast:output_type -> protocol.BroadcastReply
-	5, // 10: protocol.Comet.BroadcastRoom:output_type -> protocol.BroadcastRoomReply
-	7, // 11: protocol.Comet.Rooms:output_type -> protocol.RoomsReply
-	8, // [8:12] is the sub-list for method output_type
-	4, // [4:8] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	16, // 0: protocol.PushMsgReq.proto:type_name -> protocol.Proto
+	17, // 1: protocol.PushMsgReq.priority:type_name -> protocol.Priority
+	16, // 2: protocol.BroadcastReq.proto:type_name -> protocol.Proto
+	17, // 3: protocol.BroadcastReq.priority:type_name -> protocol.Priority
+	16, // 4: protocol.BroadcastRoomReq.proto:type_name -> protocol.Proto
+	17, // 5: protocol.BroadcastRoomReq.priority:type_name -> protocol.Priority
+	16, // 6: protocol.BroadcastTopicReq.proto:type_name -> protocol.Proto
+	17, // 7: protocol.BroadcastTopicReq.priority:type_name -> protocol.Priority
+	15, // 8: protocol.RoomsReply.rooms:type_name -> protocol.RoomsReply.RoomsEntry
+	0,  // 9: protocol.PushCommand.push_msg:type_name -> protocol.PushMsgReq
+	2,  // 10: protocol.PushCommand.broadcast:type_name -> protocol.BroadcastReq
+	4,  // 11: protocol.PushCommand.broadcast_room:type_name -> protocol.BroadcastRoomReq
+	6,  // 12: protocol.PushCommand.broadcast_topic:type_name -> protocol.BroadcastTopicReq
+	12, // 13: protocol.PushBatch.commands:type_name -> protocol.PushCommand
+	0,  // 14: protocol.Comet.PushMsg:input_type -> protocol.PushMsgReq
+	2,  // 15: protocol.Comet.Broadcast:input_type -> protocol.BroadcastReq
+	4,  // 16: protocol.Comet.BroadcastRoom:input_type -> protocol.BroadcastRoomReq
+	6,  // 17: protocol.Comet.BroadcastTopic:input_type -> protocol.BroadcastTopicReq
+	10, // 18: protocol.Comet.Rooms:input_type -> protocol.RoomsReq
+	8,  // 19: protocol.Comet.ClientRequest:input_type -> protocol.ClientRequestReq
+	13, // 20: protocol.Comet.PushStream:input_type -> protocol.PushBatch
+	1,  // 21: protocol.Comet.PushMsg:output_type -> protocol.PushMsgReply
+	3,  // 22: protocol.Comet.Broadcast:output_type -> protocol.BroadcastReply
+	5,  // 23: protocol.Comet.BroadcastRoom:output_type -> protocol.BroadcastRoomReply
+	7,  // 24: protocol.Comet.BroadcastTopic:output_type -> protocol.BroadcastTopicReply
+	11, // 25: protocol.Comet.Rooms:output_type -> protocol.RoomsReply
+	9,  // 26: protocol.Comet.ClientRequest:output_type -> protocol.ClientRequestReply
+	14, // 27: protocol.Comet.PushStream:output_type -> protocol.PushBatchAck
+	21, // [21:28] is the sub-list for method output_type
+	14, // [14:21] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_push_push_proto_init() }
@@ -467,13 +1103,19 @@ func file_push_push_proto_init() {
 	if File_push_push_proto != nil {
 		return
 	}
+	file_push_push_proto_msgTypes[12].OneofWrappers = []any{
+		(*PushCommand_PushMsg)(nil),
+		(*PushCommand_Broadcast)(nil),
+		(*PushCommand_BroadcastRoom)(nil),
+		(*PushCommand_BroadcastTopic)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_push_push_proto_rawDesc), len(file_push_push_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
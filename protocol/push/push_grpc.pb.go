@@ -19,10 +19,13 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Comet_PushMsg_FullMethodName       = "/protocol.Comet/PushMsg"
-	Comet_Broadcast_FullMethodName     = "/protocol.Comet/Broadcast"
-	Comet_BroadcastRoom_FullMethodName = "/protocol.Comet/BroadcastRoom"
-	Comet_Rooms_FullMethodName         = "/protocol.Comet/Rooms"
+	Comet_PushMsg_FullMethodName        = "/protocol.Comet/PushMsg"
+	Comet_Broadcast_FullMethodName      = "/protocol.Comet/Broadcast"
+	Comet_BroadcastRoom_FullMethodName  = "/protocol.Comet/BroadcastRoom"
+	Comet_BroadcastTopic_FullMethodName = "/protocol.Comet/BroadcastTopic"
+	Comet_Rooms_FullMethodName          = "/protocol.Comet/Rooms"
+	Comet_ClientRequest_FullMethodName  = "/protocol.Comet/ClientRequest"
+	Comet_PushStream_FullMethodName     = "/protocol.Comet/PushStream"
 )
 
 // CometClient is the client API for Comet service.
@@ -35,8 +38,14 @@ type CometClient interface {
 	Broadcast(ctx context.Context, in *BroadcastReq, opts ...grpc.CallOption) (*BroadcastReply, error)
 	// BroadcastRoom broadcast to one room
 	BroadcastRoom(ctx context.Context, in *BroadcastRoomReq, opts ...grpc.CallOption) (*BroadcastRoomReply, error)
+	// BroadcastTopic broadcast to connections subscribed to a matching topic pattern
+	BroadcastTopic(ctx context.Context, in *BroadcastTopicReq, opts ...grpc.CallOption) (*BroadcastTopicReply, error)
 	// Rooms get all rooms
 	Rooms(ctx context.Context, in *RoomsReq, opts ...grpc.CallOption) (*RoomsReply, error)
+	// ClientRequest send a request to a connected client and block until it replies or times out
+	ClientRequest(ctx context.Context, in *ClientRequestReq, opts ...grpc.CallOption) (*ClientRequestReply, error)
+	// PushStream long-lived stream carrying batches of push commands, acked per batch with flow-control credits
+	PushStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PushBatch, PushBatchAck], error)
 }
 
 type cometClient struct {
@@ -77,6 +86,16 @@ func (c *cometClient) BroadcastRoom(ctx context.Context, in *BroadcastRoomReq, o
 	return out, nil
 }
 
+func (c *cometClient) BroadcastTopic(ctx context.Context, in *BroadcastTopicReq, opts ...grpc.CallOption) (*BroadcastTopicReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BroadcastTopicReply)
+	err := c.cc.Invoke(ctx, Comet_BroadcastTopic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cometClient) Rooms(ctx context.Context, in *RoomsReq, opts ...grpc.CallOption) (*RoomsReply, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RoomsReply)
@@ -87,6 +106,29 @@ func (c *cometClient) Rooms(ctx context.Context, in *RoomsReq, opts ...grpc.Call
 	return out, nil
 }
 
+func (c *cometClient) ClientRequest(ctx context.Context, in *ClientRequestReq, opts ...grpc.CallOption) (*ClientRequestReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClientRequestReply)
+	err := c.cc.Invoke(ctx, Comet_ClientRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cometClient) PushStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PushBatch, PushBatchAck], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Comet_ServiceDesc.Streams[0], Comet_PushStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PushBatch, PushBatchAck]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Comet_PushStreamClient = grpc.BidiStreamingClient[PushBatch, PushBatchAck]
+
 // CometServer is the server API for Comet service.
 // All implementations must embed UnimplementedCometServer
 // for forward compatibility.
@@ -97,8 +139,14 @@ type CometServer interface {
 	Broadcast(context.Context, *BroadcastReq) (*BroadcastReply, error)
 	// BroadcastRoom broadcast to one room
 	BroadcastRoom(context.Context, *BroadcastRoomReq) (*BroadcastRoomReply, error)
+	// BroadcastTopic broadcast to connections subscribed to a matching topic pattern
+	BroadcastTopic(context.Context, *BroadcastTopicReq) (*BroadcastTopicReply, error)
 	// Rooms get all rooms
 	Rooms(context.Context, *RoomsReq) (*RoomsReply, error)
+	// ClientRequest send a request to a connected client and block until it replies or times out
+	ClientRequest(context.Context, *ClientRequestReq) (*ClientRequestReply, error)
+	// PushStream long-lived stream carrying batches of push commands, acked per batch with flow-control credits
+	PushStream(grpc.BidiStreamingServer[PushBatch, PushBatchAck]) error
 	mustEmbedUnimplementedCometServer()
 }
 
@@ -118,9 +166,18 @@ func (UnimplementedCometServer) Broadcast(context.Context, *BroadcastReq) (*Broa
 func (UnimplementedCometServer) BroadcastRoom(context.Context, *BroadcastRoomReq) (*BroadcastRoomReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BroadcastRoom not implemented")
 }
+func (UnimplementedCometServer) BroadcastTopic(context.Context, *BroadcastTopicReq) (*BroadcastTopicReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BroadcastTopic not implemented")
+}
 func (UnimplementedCometServer) Rooms(context.Context, *RoomsReq) (*RoomsReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Rooms not implemented")
 }
+func (UnimplementedCometServer) ClientRequest(context.Context, *ClientRequestReq) (*ClientRequestReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClientRequest not implemented")
+}
+func (UnimplementedCometServer) PushStream(grpc.BidiStreamingServer[PushBatch, PushBatchAck]) error {
+	return status.Errorf(codes.Unimplemented, "method PushStream not implemented")
+}
 func (UnimplementedCometServer) mustEmbedUnimplementedCometServer() {}
 func (UnimplementedCometServer) testEmbeddedByValue()               {}
 
@@ -196,6 +253,24 @@ func _Comet_BroadcastRoom_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Comet_BroadcastTopic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastTopicReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CometServer).BroadcastTopic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Comet_BroadcastTopic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CometServer).BroadcastTopic(ctx, req.(*BroadcastTopicReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Comet_Rooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RoomsReq)
 	if err := dec(in); err != nil {
@@ -214,6 +289,31 @@ func _Comet_Rooms_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Comet_ClientRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientRequestReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CometServer).ClientRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Comet_ClientRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CometServer).ClientRequest(ctx, req.(*ClientRequestReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Comet_PushStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CometServer).PushStream(&grpc.GenericServerStream[PushBatch, PushBatchAck]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Comet_PushStreamServer = grpc.BidiStreamingServer[PushBatch, PushBatchAck]
+
 // Comet_ServiceDesc is the grpc.ServiceDesc for Comet service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -233,11 +333,26 @@ var Comet_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BroadcastRoom",
 			Handler:    _Comet_BroadcastRoom_Handler,
 		},
+		{
+			MethodName: "BroadcastTopic",
+			Handler:    _Comet_BroadcastTopic_Handler,
+		},
 		{
 			MethodName: "Rooms",
 			Handler:    _Comet_Rooms_Handler,
 		},
+		{
+			MethodName: "ClientRequest",
+			Handler:    _Comet_ClientRequest_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PushStream",
+			Handler:       _Comet_PushStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "push/push.proto",
 }
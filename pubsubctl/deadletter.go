@@ -0,0 +1,147 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
+)
+
+// runDeadLetters 列出推送死信
+func runDeadLetters(c *cli, fs *flag.FlagSet, args []string) error {
+	node := fs.String("node", "", "按目标 Connect-Node 地址过滤")
+	start := fs.String("start", "", "分页起点（不含）")
+	limit := fs.Int("limit", 100, "条数")
+	parseArgs(fs, args)
+
+	client, err := c.pushClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := client.ListDeadLetters(ctx, &broadcast.ListDeadLettersReq{Node: *node, StartId: *start, Limit: int32(*limit)})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printProto(resp)
+	}
+
+	w := newTable("ID", "NODE", "KIND", "TARGET", "ATTEMPTS", "FAILED", "ERROR")
+	for _, dl := range resp.Letters {
+		target := "-"
+		switch dl.Kind {
+		case "room":
+			target = dl.RoomId
+		case "user":
+			target = strings.Join(dl.Keys, ",")
+		case "topic":
+			target = dl.Topic
+		}
+		row(w, dl.Id, dl.Node, dl.Kind, target, dl.Attempts, formatMillis(dl.FailedAt), dl.Error)
+	}
+	w.Flush()
+	if resp.NextId != "" {
+		fmt.Printf("\n下一页: pubsubctl deadletters -start %s\n", resp.NextId)
+	}
+	return nil
+}
+
+// runReplay 重放推送死信
+func runReplay(c *cli, fs *flag.FlagSet, args []string) error {
+	ids := fs.String("id", "", "死信 ID（逗号分隔）")
+	all := fs.Bool("all", false, "重放全部死信")
+	node := fs.String("node", "", "配合 -all 只重放该节点的死信")
+	parseArgs(fs, args)
+	if (*ids == "") == !*all {
+		return fmt.Errorf("-id 和 -all 必须且只能指定一个")
+	}
+
+	client, err := c.pushClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := client.ReplayDeadLetters(ctx, &broadcast.ReplayDeadLettersReq{Ids: pkg.SplitList(*ids), All: *all, Node: *node})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printProto(resp)
+	}
+
+	fmt.Printf("✅ 已重放 %d 条死信\n", resp.Replayed)
+	if len(resp.FailedIds) > 0 {
+		fmt.Printf("⚠️  重放失败（仍保留在死信队列）: %s\n", strings.Join(resp.FailedIds, ", "))
+	}
+	if len(resp.ExpiredIds) > 0 {
+		fmt.Printf("⏰ 已过期未重放: %s\n", strings.Join(resp.ExpiredIds, ", "))
+	}
+	return nil
+}
+
+// runHistory 查看房间历史消息（从新到旧）
+func runHistory(c *cli, fs *flag.FlagSet, args []string) error {
+	limit := fs.Int("limit", 50, "条数")
+	before := fs.String("before", "", "分页起点（不含），为空从最新一条开始")
+	positional := parseArgs(fs, args)
+	if len(positional) != 1 {
+		fs.Usage()
+		return fmt.Errorf("需要指定一个房间 ID")
+	}
+
+	client, err := c.pushClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := client.ListRoomHistory(ctx, &broadcast.ListRoomHistoryReq{
+		RoomId: positional[0],
+		Before: *before,
+		Limit:  int32(*limit),
+		AppId:  c.appID,
+	})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printProto(resp)
+	}
+
+	w := newTable("ID", "PUBLISHED", "OP", "MESSAGE_ID", "BODY")
+	for _, m := range resp.Messages {
+		var op int32
+		var body string
+		if m.Proto != nil {
+			op, body = m.Proto.Op, string(m.Proto.Body)
+		}
+		row(w, m.Id, formatMillis(m.PublishedAt), op, orDash(m.MessageId), body)
+	}
+	w.Flush()
+	if resp.NextBefore != "" {
+		fmt.Printf("\n下一页: pubsubctl history %s -before %s\n", positional[0], resp.NextBefore)
+	}
+	return nil
+}
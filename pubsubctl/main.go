@@ -0,0 +1,261 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/admin"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+)
+
+// command 子命令
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(c *cli, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []*command{
+	{"send", "send (-room <room> | -user <u1,u2> | -all) -body <text> [-op 2] [-priority high|normal|low] [-message-id <id>] [-delay <dur>]", "推送消息到房间、用户或全部连接", runSend},
+	{"tail", "tail -room <room> [-user <id>] [-ops 2,1001] [-api-key psk_...]", "以订阅者身份加入房间并持续输出收到的消息", runTail},
+	{"rooms", "rooms [-all-tenants]", "列出房间和在线人数", runRooms},
+	{"members", "members <room>", "列出房间在线成员", runMembers},
+	{"nodes", "nodes [-include-offline]", "列出 Connect-Node 和 Push-Manager", runNodes},
+	{"kick", "kick -user <id> [-room <room> -operator <id>] [-node <id>] [-device <id>] [-reason <text>]", "踢出房间成员，或断开用户的连接", runKick},
+	{"deadletters", "deadletters [-node <addr>] [-start <id>] [-limit 100]", "列出推送死信", runDeadLetters},
+	{"replay", "replay (-id <id1,id2> | -all) [-node <addr>]", "重放推送死信", runReplay},
+	{"history", "history <room> [-limit 50] [-before <id>]", "查看房间历史消息（从新到旧）", runHistory},
+}
+
+// cli 全局参数和按需建立的 gRPC 连接
+type cli struct {
+	controllerAddr  string
+	pushManagerAddr string
+	connectNodeAddr string
+	appID           string
+	jsonOutput      bool
+	timeout         time.Duration
+
+	conns map[string]*grpc.ClientConn
+}
+
+func main() {
+	c := &cli{conns: make(map[string]*grpc.ClientConn)}
+
+	fs := flag.NewFlagSet("pubsubctl", flag.ExitOnError)
+	fs.StringVar(&c.controllerAddr, "controller", getEnv("CONTROLLER_ADDR", "localhost:50051"), "Controller gRPC 地址")
+	fs.StringVar(&c.pushManagerAddr, "push-manager", getEnv("PUSH_MANAGER_ADDR", "localhost:50053"), "Push-Manager gRPC 地址")
+	fs.StringVar(&c.connectNodeAddr, "connect-node", getEnv("CONNECT_NODE_ADDR", "localhost:8083"), "Connect-Node WebSocket 地址")
+	fs.StringVar(&c.appID, "app", getEnv("PUBSUB_APP_ID", ""), "租户 ID（为空表示默认租户）")
+	fs.BoolVar(&c.jsonOutput, "json", false, "以 JSON 输出")
+	fs.DurationVar(&c.timeout, "timeout", 5*time.Second, "gRPC 调用超时")
+	fs.Usage = func() { printUsage(fs) }
+	fs.Parse(os.Args[1:])
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	name, args := fs.Arg(0), fs.Args()[1:]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		err := cmd.run(c, cmd.flagSet(), args)
+		c.close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "❌ 未知命令: %s\n\n", name)
+	fs.Usage()
+	os.Exit(2)
+}
+
+// printUsage 输出全局参数和子命令列表
+func printUsage(fs *flag.FlagSet) {
+	out := fs.Output()
+	fmt.Fprintf(out, "用法: pubsubctl [全局参数] <命令> [参数]\n\n命令:\n")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", cmd.name, cmd.summary)
+	}
+	w.Flush()
+	fmt.Fprintf(out, "\n全局参数:\n")
+	fs.PrintDefaults()
+	fmt.Fprintf(out, "\n子命令参数: pubsubctl <命令> -h\n")
+}
+
+// flagSet 创建子命令的参数集，-h 时输出子命令用法
+func (cmd *command) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "用法: pubsubctl %s\n\n%s\n\n", cmd.usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs 解析子命令参数，允许位置参数出现在参数之前（如 history room-001 -limit 10）；
+// 全局参数（如 -json）需放在子命令之前
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// dial 建立（或复用）到 addr 的 gRPC 连接
+func (c *cli) dial(addr string) (*grpc.ClientConn, error) {
+	if conn, ok := c.conns[addr]; ok {
+		return conn, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("连接 %s 失败: %w", addr, err)
+	}
+	c.conns[addr] = conn
+	return conn, nil
+}
+
+func (c *cli) close() {
+	for _, conn := range c.conns {
+		conn.Close()
+	}
+}
+
+func (c *cli) pushClient() (broadcast.PushServerClient, error) {
+	conn, err := c.dial(c.pushManagerAddr)
+	if err != nil {
+		return nil, err
+	}
+	return broadcast.NewPushServerClient(conn), nil
+}
+
+func (c *cli) controllerClient() (controller.ControllerServiceClient, error) {
+	conn, err := c.dial(c.controllerAddr)
+	if err != nil {
+		return nil, err
+	}
+	return controller.NewControllerServiceClient(conn), nil
+}
+
+func (c *cli) adminClient() (admin.AdminServiceClient, error) {
+	conn, err := c.dial(c.controllerAddr)
+	if err != nil {
+		return nil, err
+	}
+	return admin.NewAdminServiceClient(conn), nil
+}
+
+// context 单次 gRPC 调用的超时上下文
+func (c *cli) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.timeout)
+}
+
+// scope 租户内 ID 转为集群内 ID（Controller 和 AdminService 使用集群内 ID）
+func (c *cli) scope(id string) string {
+	return pkg.ScopeID(c.appID, id)
+}
+
+// unscope 集群内 ID 转为租户内 ID（输出时使用）
+func (c *cli) unscope(id string) string {
+	return pkg.UnscopeID(c.appID, id)
+}
+
+// printProto 以缩进的 JSON 输出 protobuf 消息
+func printProto(m proto.Message) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// printJSON 以 JSON 输出普通结构
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// newTable 表格输出，第一行为表头
+func newTable(header ...string) *tabwriter.Writer {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	return w
+}
+
+// row 输出表格的一行
+func row(w *tabwriter.Writer, cols ...interface{}) {
+	parts := make([]string, len(cols))
+	for i, col := range cols {
+		parts[i] = fmt.Sprint(col)
+	}
+	fmt.Fprintln(w, strings.Join(parts, "\t"))
+}
+
+// formatMillis Unix 毫秒转为本地时间（0 时为 -）
+func formatMillis(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return time.UnixMilli(ms).Format("2006-01-02 15:04:05")
+}
+
+// formatUnix Unix 秒转为本地时间（0 时为 -）
+func formatUnix(sec int64) string {
+	if sec <= 0 {
+		return "-"
+	}
+	return time.Unix(sec, 0).Format("2006-01-02 15:04:05")
+}
+
+// getEnv 获取环境变量（带默认值）
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,222 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/admin"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+)
+
+// runRooms 列出房间和在线人数（-all-tenants 时列出全部租户，房间 ID 为集群内 ID）
+func runRooms(c *cli, fs *flag.FlagSet, args []string) error {
+	allTenants := fs.Bool("all-tenants", false, "列出全部租户的房间")
+	parseArgs(fs, args)
+
+	client, err := c.controllerClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := client.GetRoomStats(ctx, &controller.GetRoomStatsRequest{AppId: c.appID, AllTenants: *allTenants})
+	if err != nil {
+		return err
+	}
+	if !*allTenants {
+		for _, room := range resp.Rooms {
+			room.RoomId = c.unscope(room.RoomId)
+		}
+	}
+	if c.jsonOutput {
+		return printProto(resp)
+	}
+
+	w := newTable("ROOM", "USERS", "CREATED")
+	for _, room := range resp.Rooms {
+		row(w, room.RoomId, room.UserCount, formatUnix(room.CreatedAt))
+	}
+	w.Flush()
+	fmt.Printf("\n共 %d 个房间，%d 个在线用户\n", resp.TotalRooms, resp.TotalUsers)
+	return nil
+}
+
+// runMembers 列出房间在线成员
+func runMembers(c *cli, fs *flag.FlagSet, args []string) error {
+	positional := parseArgs(fs, args)
+	if len(positional) != 1 {
+		fs.Usage()
+		return fmt.Errorf("需要指定一个房间 ID")
+	}
+
+	client, err := c.controllerClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := client.GetRoomInfo(ctx, &controller.GetRoomInfoRequest{RoomId: c.scope(positional[0])})
+	if err != nil {
+		return err
+	}
+	if resp.RoomInfo == nil {
+		return fmt.Errorf("房间不存在: %s", positional[0])
+	}
+	info := resp.RoomInfo
+	info.RoomId = c.unscope(info.RoomId)
+	for _, user := range info.Users {
+		user.UserId = c.unscope(user.UserId)
+	}
+	if c.jsonOutput {
+		return printProto(info)
+	}
+
+	w := newTable("USER", "NAME", "ROLE", "NODE", "JOINED")
+	for _, user := range info.Users {
+		row(w, user.UserId, user.UserName, orDash(user.Role), user.NodeId, formatUnix(user.JoinedAt))
+	}
+	w.Flush()
+	fmt.Printf("\n房间 %s: %d 个在线成员\n", info.RoomId, len(info.Users))
+	return nil
+}
+
+// runNodes 列出 Connect-Node（心跳和实时状态）和 Push-Manager（到各节点的熔断器和出站队列）
+func runNodes(c *cli, fs *flag.FlagSet, args []string) error {
+	includeOffline := fs.Bool("include-offline", false, "包含 unhealthy / offline 节点")
+	parseArgs(fs, args)
+
+	client, err := c.adminClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := client.ListNodes(ctx, &admin.ListNodesRequest{IncludeOffline: *includeOffline})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printProto(resp)
+	}
+
+	fmt.Println("Connect-Node:")
+	w := newTable("NODE", "ADDRESS", "STATUS", "SESSIONS", "QUEUE", "ROOMS", "DRAINING", "CPU", "MEM(MB)", "HEARTBEAT")
+	for _, node := range resp.ConnectNodes {
+		sessions, queue, draining := "-", "-", "-"
+		if node.Reachable && node.Live != nil {
+			sessions = fmt.Sprint(node.Live.Sessions)
+			queue = fmt.Sprint(node.Live.QueueDepth)
+			draining = fmt.Sprint(node.Live.Draining)
+		}
+		row(w, node.NodeId, node.Address, node.Status, sessions, queue, node.Rooms, draining,
+			fmt.Sprintf("%.1f%%", node.CpuUsage), fmt.Sprintf("%.0f", node.MemoryUsage), formatUnix(node.LastHeartbeat))
+	}
+	w.Flush()
+
+	fmt.Println("\nPush-Manager:")
+	w = newTable("MANAGER", "ADDRESS", "REACHABLE", "TARGETS")
+	for _, pm := range resp.PushManagers {
+		targets := make([]string, 0, len(pm.Targets))
+		for _, t := range pm.Targets {
+			targets = append(targets, fmt.Sprintf("%s(%s, %d/%d/%d)", t.NodeAddress, t.Breaker, t.PendingHigh, t.PendingNormal, t.PendingLow))
+		}
+		row(w, orDash(pm.ManagerId), pm.Address, pm.Reachable, orDash(strings.Join(targets, " ")))
+	}
+	w.Flush()
+	return nil
+}
+
+// runKick 指定 -room 时按房间权限踢出成员（需要 -operator），否则通过 AdminService 断开用户的连接
+func runKick(c *cli, fs *flag.FlagSet, args []string) error {
+	user := fs.String("user", "", "用户 ID")
+	room := fs.String("room", "", "房间 ID（按房间踢人）")
+	operator := fs.String("operator", "", "执行踢人的成员（需要踢人权限）")
+	node := fs.String("node", "", "只断开该节点上的连接（断开连接时）")
+	device := fs.String("device", "", "只断开该设备（断开连接时）")
+	reason := fs.String("reason", "kicked by pubsubctl", "原因")
+	parseArgs(fs, args)
+	if *user == "" {
+		return fmt.Errorf("-user 不能为空")
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	if *room != "" {
+		if *operator == "" {
+			return fmt.Errorf("按房间踢人需要 -operator")
+		}
+		client, err := c.controllerClient()
+		if err != nil {
+			return err
+		}
+		resp, err := client.KickUser(ctx, &controller.KickUserRequest{
+			RoomId:     c.scope(*room),
+			OperatorId: c.scope(*operator),
+			UserId:     c.scope(*user),
+			Reason:     *reason,
+		})
+		if err != nil {
+			return err
+		}
+		if c.jsonOutput {
+			return printProto(resp)
+		}
+		if !resp.Success {
+			return fmt.Errorf("%s", resp.Message)
+		}
+		fmt.Printf("✅ 已将 %s 踢出房间 %s\n", *user, *room)
+		return nil
+	}
+
+	client, err := c.adminClient()
+	if err != nil {
+		return err
+	}
+	resp, err := client.DisconnectSession(ctx, &admin.DisconnectSessionRequest{
+		NodeId:   *node,
+		UserId:   c.scope(*user),
+		DeviceId: *device,
+		Reason:   *reason,
+	})
+	if err != nil {
+		return err
+	}
+	if c.jsonOutput {
+		return printProto(resp)
+	}
+	if !resp.Success {
+		return fmt.Errorf("%s", resp.Message)
+	}
+	fmt.Printf("✅ 已断开 %s 的 %d 个连接\n", *user, resp.Disconnected)
+	if len(resp.UnreachableNodes) > 0 {
+		fmt.Printf("⚠️  无法访问的节点: %s\n", strings.Join(resp.UnreachableNodes, ", "))
+	}
+	return nil
+}
+
+// orDash 空字符串输出为 -
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+// sendResult send 命令的结果（JSON 输出）
+type sendResult struct {
+	Code           string   `json:"code"`
+	Msg            string   `json:"msg"`
+	Desc           string   `json:"desc"`
+	ScheduleID     string   `json:"schedule_id,omitempty"`
+	OfflineUserIDs []string `json:"offline_user_ids,omitempty"`
+}
+
+// runSend 推送消息：-room 推送到房间，-user 推送给用户，-all 推送给全部连接
+func runSend(c *cli, fs *flag.FlagSet, args []string) error {
+	room := fs.String("room", "", "房间 ID")
+	users := fs.String("user", "", "用户 ID（逗号分隔）")
+	all := fs.Bool("all", false, "推送给全部连接")
+	body := fs.String("body", "", "消息内容")
+	op := fs.Int("op", int(protocol.OpSendMsg), "消息 op")
+	priority := fs.String("priority", "normal", "推送优先级: high / normal / low")
+	messageID := fs.String("message-id", "", "幂等 key（相同 key 的重复请求只推送一次）")
+	delay := fs.Duration("delay", 0, "延迟投递（如 30s、5m）")
+	parseArgs(fs, args)
+
+	targets := 0
+	for _, set := range []bool{*room != "", *users != "", *all} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return fmt.Errorf("-room、-user 和 -all 必须且只能指定一个")
+	}
+	if *body == "" {
+		return fmt.Errorf("-body 不能为空")
+	}
+	prio, ok := pkg.ParsePriority(*priority)
+	if !ok {
+		return fmt.Errorf("-priority 只能是 high / normal / low")
+	}
+
+	client, err := c.pushClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+
+	p := &protocol.Proto{Ver: 1, Op: int32(*op), Roomid: *room, Body: []byte(*body)}
+	delayMs := delay.Milliseconds()
+
+	var result sendResult
+	switch {
+	case *room != "":
+		reply, err := client.BroadcastToRoom(ctx, &broadcast.BroadCastRoomReq{
+			RoomId:    *room,
+			Proto:     p,
+			DelayMs:   delayMs,
+			MessageId: *messageID,
+			Priority:  prio,
+			AppId:     c.appID,
+		})
+		if err != nil {
+			return err
+		}
+		result = sendResult{Code: reply.Code, Msg: reply.Msg, Desc: reply.Desc, ScheduleID: reply.ScheduleId}
+	case *users != "":
+		reply, err := client.PushToUser(ctx, &broadcast.PushToUserReq{
+			UserIds:   pkg.SplitList(*users),
+			Proto:     p,
+			DelayMs:   delayMs,
+			MessageId: *messageID,
+			Priority:  prio,
+			AppId:     c.appID,
+		})
+		if err != nil {
+			return err
+		}
+		result = sendResult{Code: reply.Code, Msg: reply.Msg, Desc: reply.Desc, ScheduleID: reply.ScheduleId, OfflineUserIDs: reply.OfflineUserIds}
+	default:
+		reply, err := client.Broadcast(ctx, &broadcast.BroadCastReq{
+			Proto:     p,
+			DelayMs:   delayMs,
+			MessageId: *messageID,
+			Priority:  prio,
+			AppId:     c.appID,
+		})
+		if err != nil {
+			return err
+		}
+		result = sendResult{Code: reply.Code, Msg: reply.Msg, Desc: reply.Desc, ScheduleID: reply.ScheduleId}
+	}
+
+	if c.jsonOutput {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	} else if result.Code == "0" {
+		fmt.Printf("✅ %s\n", result.Desc)
+		if result.ScheduleID != "" {
+			fmt.Printf("   定时推送: %s（%s 后投递）\n", result.ScheduleID, delay.Round(time.Millisecond))
+		}
+		if len(result.OfflineUserIDs) > 0 {
+			fmt.Printf("   离线用户: %s\n", strings.Join(result.OfflineUserIDs, ", "))
+		}
+	}
+	if result.Code != "0" {
+		return fmt.Errorf("%s: %s", result.Msg, result.Desc)
+	}
+	return nil
+}
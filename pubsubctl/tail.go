@@ -0,0 +1,206 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	getty "github.com/AlexStocks/getty/transport"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	gettypkg "github.com/livekit/psrpc/examples/pubsub/pkg/getty"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+const (
+	// tailHeartbeatPeriod tail 的心跳间隔（小于 Connect-Node 的 session_timeout）
+	tailHeartbeatPeriod = 20 * time.Second
+	// opHeartbeat 心跳请求，opHeartbeatReply 心跳响应
+	opHeartbeat      = int32(5)
+	opHeartbeatReply = int32(6)
+)
+
+// tailMessage tail 输出的一条消息（JSON 输出时每行一条）
+type tailMessage struct {
+	Time   string `json:"time"`
+	Op     int32  `json:"op"`
+	Seq    int32  `json:"seq"`
+	RoomID string `json:"room_id,omitempty"`
+	UserID string `json:"user_id,omitempty"`
+	Body   string `json:"body"`
+}
+
+// tailSubscriber 以订阅者身份加入房间，输出收到的推送；断线后由 Getty 客户端重连并重新加入房间
+type tailSubscriber struct {
+	roomID     string
+	userID     string
+	joinBody   []byte
+	jsonOutput bool
+
+	mu sync.Mutex // 串行输出
+}
+
+// runTail 加入房间并持续输出消息，直到 Ctrl+C
+func runTail(c *cli, fs *flag.FlagSet, args []string) error {
+	room := fs.String("room", "", "房间 ID")
+	user := fs.String("user", "pubsubctl-"+strconv.Itoa(os.Getpid()), "订阅者用户 ID")
+	ops := fs.String("ops", "", "订阅的推送 op（逗号分隔，默认只订阅 op=2）")
+	apiKey := fs.String("api-key", getEnv("PUBSUB_API_KEY", ""), "租户 API key（连接凭证，为空时需节点允许匿名连接默认租户）")
+	parseArgs(fs, args)
+	if *room == "" {
+		return fmt.Errorf("-room 不能为空")
+	}
+
+	watchOps := make([]int32, 0)
+	for _, s := range pkg.SplitList(*ops) {
+		op, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("-ops 格式错误: %s", s)
+		}
+		watchOps = append(watchOps, int32(op))
+	}
+	joinBody, _ := json.Marshal(map[string]interface{}{
+		"api_key":     *apiKey,
+		"app_id":      c.appID,
+		"user_name":   *user,
+		"device_id":   "pubsubctl",
+		"device_type": "cli",
+		"watch_ops":   watchOps,
+	})
+	sub := &tailSubscriber{roomID: *room, userID: *user, joinBody: joinBody, jsonOutput: c.jsonOutput}
+
+	addr := c.connectNodeAddr
+	if !hasWSScheme(addr) {
+		addr = "ws://" + addr + "/connect"
+	}
+	if !c.jsonOutput {
+		fmt.Fprintf(os.Stderr, "🔌 连接 %s，房间 %s，用户 %s（Ctrl+C 退出）\n", addr, *room, *user)
+	}
+
+	wsClient := getty.NewWSClient(
+		getty.WithServerAddress(addr),
+		getty.WithConnectionNumber(1),
+		getty.WithReconnectInterval(int(time.Second)),
+	)
+	wsClient.RunEventLoop(func(session getty.Session) error {
+		var readerPool, writePool pkg.Pool
+		readerPool.Init(4, 1024)
+		writePool.Init(4, 1024)
+
+		session.SetName("pubsubctl-tail")
+		session.SetMaxMsgLen(1024 * 1024)
+		session.SetPkgHandler(gettypkg.NewProtoPackageHandler(&readerPool, &writePool))
+		session.SetEventListener(sub)
+		session.SetReadTimeout(60 * time.Second)
+		session.SetWriteTimeout(10 * time.Second)
+		session.SetCronPeriod(int(tailHeartbeatPeriod / time.Millisecond))
+		session.SetWaitTime(10 * time.Second)
+		return nil
+	})
+	defer wsClient.Close()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	return nil
+}
+
+// hasWSScheme 地址是否已带 ws:// 或 wss://
+func hasWSScheme(addr string) bool {
+	return len(addr) >= 5 && addr[:5] == "ws://" || len(addr) >= 6 && addr[:6] == "wss://"
+}
+
+// OnOpen 连接建立（含重连）后加入房间
+func (t *tailSubscriber) OnOpen(session getty.Session) error {
+	join := &protocol.Proto{
+		Ver:    1,
+		Op:     protocol.OpJoinRoom,
+		Seq:    1,
+		Roomid: t.roomID,
+		Userid: t.userID,
+		Body:   t.joinBody,
+	}
+	if _, _, err := session.WritePkg(join, 5*time.Second); err != nil {
+		return fmt.Errorf("加入房间失败: %w", err)
+	}
+	t.status("✅ 已连接: %s", session.RemoteAddr())
+	return nil
+}
+
+func (t *tailSubscriber) OnError(session getty.Session, err error) {
+	t.status("❌ 连接错误: %v", err)
+}
+
+func (t *tailSubscriber) OnClose(session getty.Session) {
+	t.status("👋 连接关闭，等待重连...")
+}
+
+// OnMessage 输出推送消息（心跳响应和加入房间响应不输出）
+func (t *tailSubscriber) OnMessage(session getty.Session, pkg interface{}) {
+	p, ok := pkg.(*protocol.Proto)
+	if !ok || p.Op == opHeartbeatReply {
+		return
+	}
+	if isReply, rejected, reason := protocol.JoinRoomReply(p); isReply {
+		if rejected {
+			t.status("🚫 加入房间失败: %s", reason)
+		} else {
+			t.status("🚪 已加入房间: %s", t.roomID)
+		}
+		return
+	}
+
+	msg := tailMessage{
+		Time:   time.Now().Format(time.RFC3339Nano),
+		Op:     p.Op,
+		Seq:    p.Seq,
+		RoomID: p.Roomid,
+		UserID: p.Userid,
+		Body:   string(p.Body),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.jsonOutput {
+		data, _ := json.Marshal(msg)
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Printf("%s op=%d seq=%d room=%s user=%s %s\n",
+		time.Now().Format("15:04:05.000"), msg.Op, msg.Seq, msg.RoomID, msg.UserID, msg.Body)
+}
+
+// OnCron 心跳
+func (t *tailSubscriber) OnCron(session getty.Session) {
+	heartbeat := &protocol.Proto{Ver: 1, Op: opHeartbeat, Seq: int32(time.Now().Unix()), Roomid: t.roomID, Userid: t.userID}
+	if _, _, err := session.WritePkg(heartbeat, 5*time.Second); err != nil {
+		t.status("❌ 心跳发送失败: %v", err)
+	}
+}
+
+// status 连接状态输出到 stderr，不混入 stdout 的消息流
+func (t *tailSubscriber) status(format string, args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"sort"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/admin"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+// pushAdminServer 本实例的运维接口（由 Controller 的 AdminService 调用）
+type pushAdminServer struct {
+	admin.UnimplementedPushAdminServer
+
+	server *PushManagerServer
+}
+
+func newPushAdminServer(server *PushManagerServer) *pushAdminServer {
+	return &pushAdminServer{server: server}
+}
+
+// GetPushStatus 到各 Connect-Node 的熔断器状态和本地出站队列长度
+func (a *pushAdminServer) GetPushStatus(ctx context.Context, req *admin.GetPushStatusRequest) (*admin.PushManagerStatus, error) {
+	status := &admin.PushManagerStatus{ManagerId: a.server.managerID}
+	for _, client := range a.server.clients() {
+		status.Targets = append(status.Targets, &admin.PushTarget{
+			NodeAddress:   client.node,
+			Breaker:       client.breaker.State().String(),
+			PendingHigh:   int32(client.outbound.Len(protocol.Priority_PRIORITY_HIGH)),
+			PendingNormal: int32(client.outbound.Len(protocol.Priority_PRIORITY_NORMAL)),
+			PendingLow:    int32(client.outbound.Len(protocol.Priority_PRIORITY_LOW)),
+		})
+	}
+	sort.Slice(status.Targets, func(i, j int) bool {
+		return status.Targets[i].NodeAddress < status.Targets[j].NodeAddress
+	})
+	return status, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// halfOpenPoll half-open 状态下探测在途时，其他发送方的等待间隔
+const halfOpenPoll = 200 * time.Millisecond
+
+// breakerState 熔断器状态
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // 正常放行
+	breakerOpen                         // 熔断：暂停向节点发送
+	breakerHalfOpen                     // 熔断超时后放行一次探测，成功则恢复，失败则重新熔断
+)
+
+func (st breakerState) String() string {
+	switch st {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// circuitBreaker 单个 Connect-Node 的熔断器
+type circuitBreaker struct {
+	mu        sync.Mutex
+	state     breakerState
+	failures  int       // 连续失败次数
+	openedAt  time.Time // 最近一次熔断时间
+	probing   bool      // half-open 状态下是否已有探测在途
+	threshold int
+	timeout   time.Duration
+
+	// onChange 状态变化回调（记录日志和指标），在锁外调用
+	onChange func(from, to breakerState)
+}
+
+func newCircuitBreaker(threshold int, timeout time.Duration, onChange func(from, to breakerState)) *circuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &circuitBreaker{threshold: threshold, timeout: timeout, onChange: onChange}
+}
+
+// Allow 是否允许发送；open 状态下返回还需等待的时间
+func (b *circuitBreaker) Allow() (bool, time.Duration) {
+	b.mu.Lock()
+	from := b.state
+	allowed, wait := true, time.Duration(0)
+
+	switch b.state {
+	case breakerOpen:
+		if elapsed := time.Since(b.openedAt); elapsed < b.timeout {
+			allowed, wait = false, b.timeout-elapsed
+			break
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+	case breakerHalfOpen:
+		if b.probing {
+			allowed, wait = false, halfOpenPoll
+		} else {
+			b.probing = true
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return allowed, wait
+}
+
+// Success 记录一次成功：恢复为 closed
+func (b *circuitBreaker) Success() {
+	b.mu.Lock()
+	from := b.state
+	b.state = breakerClosed
+	b.failures = 0
+	b.probing = false
+	b.mu.Unlock()
+
+	b.notify(from, breakerClosed)
+}
+
+// Failure 记录一次失败：连续失败达到阈值或探测失败时熔断
+func (b *circuitBreaker) Failure() {
+	b.mu.Lock()
+	from := b.state
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+		b.probing = false
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// State 当前状态（open 超时后仍报告 open，直到下一次 Allow 转为 half-open）
+func (b *circuitBreaker) State() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *circuitBreaker) notify(from, to breakerState) {
+	if from != to && b.onChange != nil {
+		b.onChange(from, to)
+	}
+}
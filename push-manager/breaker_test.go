@@ -0,0 +1,76 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var changes []string
+	b := newCircuitBreaker(2, 30*time.Millisecond, func(from, to breakerState) {
+		changes = append(changes, from.String()+"->"+to.String())
+	})
+
+	if ok, _ := b.Allow(); !ok {
+		t.Fatal("Allow() on a new breaker = false")
+	}
+	// 连续失败未达阈值时保持 closed，成功清零失败次数
+	b.Failure()
+	b.Success()
+	b.Failure()
+	if st := b.State(); st != breakerClosed {
+		t.Fatalf("State() after non-consecutive failures = %v, want closed", st)
+	}
+	b.Failure()
+	if st := b.State(); st != breakerOpen {
+		t.Fatalf("State() after %d consecutive failures = %v, want open", 2, st)
+	}
+	if ok, wait := b.Allow(); ok || wait <= 0 || wait > 30*time.Millisecond {
+		t.Fatalf("Allow() while open = %v, %v, want false with a wait up to the timeout", ok, wait)
+	}
+
+	// 超时后只放行一次探测
+	time.Sleep(35 * time.Millisecond)
+	if ok, _ := b.Allow(); !ok {
+		t.Fatal("Allow() after timeout = false, want a half-open probe")
+	}
+	if ok, wait := b.Allow(); ok || wait != halfOpenPoll {
+		t.Fatalf("Allow() while probing = %v, %v, want false, %v", ok, wait, halfOpenPoll)
+	}
+	// 探测失败立即重新熔断
+	b.Failure()
+	if st := b.State(); st != breakerOpen {
+		t.Fatalf("State() after failed probe = %v, want open", st)
+	}
+
+	time.Sleep(35 * time.Millisecond)
+	b.Allow()
+	b.Success()
+	if ok, _ := b.Allow(); !ok || b.State() != breakerClosed {
+		t.Fatalf("breaker not closed after a successful probe: %v", b.State())
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("onChange calls = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("onChange calls = %v, want %v", changes, want)
+		}
+	}
+}
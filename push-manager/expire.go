@@ -0,0 +1,91 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+)
+
+// 过期丢弃的环节（metrics 的 stage 标签）
+const (
+	stageAccept   = "accept"   // PushServer 收到请求时
+	stageSchedule = "schedule" // 定时推送到期时
+	stageEnqueue  = "enqueue"  // 写入节点出站队列时
+	stageQueue    = "queue"    // 从出站队列取出发送时
+	stageRetry    = "retry"    // 失败重试时
+	stageReplay   = "replay"   // 重放死信时
+)
+
+// expiring 携带过期时间的推送请求（PushServer 和 Comet 的推送请求都有 expire_at）
+type expiring interface {
+	GetExpireAt() int64
+}
+
+// scheduledRequest 可定时投递的 PushServer 推送请求
+type scheduledRequest interface {
+	expiring
+	GetDeliverAt() int64
+	GetDelayMs() int64
+}
+
+// rejectExpired 请求已过期，或定时投递时会在投递前过期时返回原因
+func (s *PushManagerServer) rejectExpired(ctx context.Context, kind string, req scheduledRequest) (string, bool) {
+	expireAt := req.GetExpireAt()
+	if expireAt <= 0 {
+		return "", false
+	}
+	if pkg.Expired(expireAt) {
+		s.metrics.RecordPushExpired(ctx, stageAccept, kind)
+		return "消息已过期", true
+	}
+	if at := resolveDeliverAt(req.GetDeliverAt(), req.GetDelayMs()); at >= expireAt {
+		s.metrics.RecordPushExpired(ctx, stageAccept, kind)
+		return "消息会在定时投递前过期", true
+	}
+	return "", false
+}
+
+// expired 出站消息是否已过期
+func (m *outboundMsg) expired() bool {
+	e, ok := m.req.(expiring)
+	return ok && pkg.Expired(e.GetExpireAt())
+}
+
+// dropExpired 丢弃已过期的出站消息（Redis 模式下同时从 stream 确认删除），返回是否已丢弃
+func (bc *BroadcastClient) dropExpired(msg *outboundMsg, stage string) bool {
+	if !msg.expired() {
+		return false
+	}
+	bc.ack(msg)
+	bc.metrics.RecordPushExpired(bc.ctx, stage, msg.kind)
+	log.Printf("⌛ [Push-Manager] 消息已过期，丢弃: node=%s, kind=%s, stage=%s\n", bc.node, msg.kind, stage)
+	return true
+}
+
+// popLive 按优先级取出下一条未过期的消息
+func (bc *BroadcastClient) popLive() (*outboundMsg, bool) {
+	for {
+		msg, ok := bc.outbound.TryPop()
+		if !ok {
+			return nil, false
+		}
+		if !bc.dropExpired(msg, stageQueue) {
+			return msg, true
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+const (
+	// defaultHistoryLimit 查询房间历史消息的默认条数
+	defaultHistoryLimit = 50
+	// maxHistoryLimit 单次查询房间历史消息的最大条数
+	maxHistoryLimit = 500
+	// historyWriteTimeout 写入一批房间历史的超时
+	historyWriteTimeout = time.Second
+	// historyQueueSize 待写入房间历史的队列容量，满时丢弃（不阻塞推送）
+	historyQueueSize = 10000
+	// historyBatchSize 一次 pipeline 写入的最大条数
+	historyBatchSize = 100
+)
+
+// errHistoryUnavailable 未连接 Redis 时不记录房间历史
+var errHistoryUnavailable = errors.New("room history unavailable: redis not connected")
+
+// recordRoomHistory 记录进入推送队列的房间消息（立即投递和定时推送到期投递都会记录）。
+// 由 RunHistoryWriter 异步批量写入，队列满时丢弃；在线状态变化不记录
+func (s *PushManagerServer) recordRoomHistory(req *broadcast.BroadCastRoomReq) {
+	if s.historyCh == nil || req.Proto.Op == protocol.OpPresence {
+		return
+	}
+	m := &redisstore.HistoryMessage{
+		RoomID:      req.RoomId,
+		MessageID:   req.MessageId,
+		Proto:       req.Proto,
+		PublishedAt: time.Now().UnixMilli(),
+		ExpireAt:    req.ExpireAt,
+	}
+	select {
+	case s.historyCh <- m:
+	default:
+		log.Printf("⚠️  [Push-Manager] 房间历史队列已满，丢弃: room=%s\n", req.RoomId)
+	}
+}
+
+// RunHistoryWriter 批量写入房间历史，直到 ctx 取消
+func (s *PushManagerServer) RunHistoryWriter(ctx context.Context) {
+	if s.historyCh == nil {
+		return
+	}
+	batch := make([]*redisstore.HistoryMessage, 0, historyBatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m := <-s.historyCh:
+			batch = append(batch[:0], m)
+		}
+	drain:
+		for len(batch) < historyBatchSize {
+			select {
+			case m := <-s.historyCh:
+				batch = append(batch, m)
+			default:
+				break drain
+			}
+		}
+
+		wctx, cancel := context.WithTimeout(ctx, historyWriteTimeout)
+		if err := s.history.Append(wctx, batch...); err != nil {
+			log.Printf("⚠️  [Push-Manager] 记录房间历史失败: count=%d, err=%v\n", len(batch), err)
+		}
+		cancel()
+	}
+}
+
+// ListRoomHistory 从新到旧列出房间的历史消息
+func (s *PushManagerServer) ListRoomHistory(ctx context.Context, req *broadcast.ListRoomHistoryReq) (*broadcast.ListRoomHistoryReply, error) {
+	if s.history == nil {
+		return nil, errHistoryUnavailable
+	}
+	if req.RoomId == "" {
+		return nil, fmt.Errorf("room_id is required")
+	}
+	if !validateLocalIDs(req.RoomId) {
+		return nil, pkg.ErrTenantIDInvalid
+	}
+	if req.AppId != "" {
+		if _, err := s.tenants.resolver.Tenant(ctx, req.AppId); err != nil {
+			return nil, err
+		}
+	}
+	limit := int64(req.Limit)
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	messages, next, err := s.history.List(ctx, pkg.ScopeID(req.AppId, req.RoomId), req.Before, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &broadcast.ListRoomHistoryReply{NextBefore: next}
+	for _, m := range messages {
+		m.Proto.Roomid = req.RoomId
+		reply.Messages = append(reply.Messages, &broadcast.HistoryMessage{
+			Id:          m.ID,
+			MessageId:   pkg.UnscopeID(req.AppId, m.MessageID),
+			Proto:       m.Proto,
+			PublishedAt: m.PublishedAt,
+			ExpireAt:    m.ExpireAt,
+		})
+	}
+	return reply, nil
+}
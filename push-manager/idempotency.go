@@ -0,0 +1,151 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
+)
+
+const (
+	// duplicateWait 重复请求到达时首次请求仍在处理中，等待其完成的最长时间
+	duplicateWait = 5 * time.Second
+	// duplicatePoll 等待首次请求完成的轮询间隔
+	duplicatePoll = 50 * time.Millisecond
+)
+
+// errDuplicateInProgress 相同 message_id 的首次请求仍在处理中
+var errDuplicateInProgress = errors.New("duplicate request in progress")
+
+// idempotency 推送请求去重：优先使用 Redis（多实例共享），未连接 Redis 时退化为本地 LRU（仅单实例有效）
+type idempotency struct {
+	store  *redisstore.IdempotencyStore
+	lru    *pkg.LRU
+	window time.Duration
+}
+
+func newIdempotency(store *redisstore.IdempotencyStore, window time.Duration, lruSize int) *idempotency {
+	idem := &idempotency{store: store, window: window}
+	if store == nil {
+		idem.lru = pkg.NewLRU(lruSize, window)
+	}
+	return idem
+}
+
+// reserve 占用幂等 key，重复请求返回首次的响应（首次请求处理中时为空）
+func (i *idempotency) reserve(ctx context.Context, key string) ([]byte, bool, error) {
+	if i.store != nil {
+		return i.store.Reserve(ctx, key, i.window)
+	}
+	reply, reserved := i.lru.SetNX(key, nil)
+	return reply, reserved, nil
+}
+
+// complete 保存首次请求的响应
+func (i *idempotency) complete(ctx context.Context, key string, reply []byte) error {
+	if i.store != nil {
+		return i.store.Complete(ctx, key, reply, i.window)
+	}
+	i.lru.Set(key, reply)
+	return nil
+}
+
+// release 首次请求失败时释放 key
+func (i *idempotency) release(ctx context.Context, key string) {
+	if i.store != nil {
+		if err := i.store.Release(ctx, key); err != nil {
+			log.Printf("⚠️  [Push-Manager] 释放幂等 key 失败: key=%s, err=%v\n", key, err)
+		}
+		return
+	}
+	i.lru.Del(key)
+}
+
+// codeReply PushServer 响应都带业务码，"0" 表示成功
+type codeReply interface {
+	proto.Message
+	GetCode() string
+}
+
+// idempotent 按 message_id 去重执行 handle：窗口内的重复请求返回首次请求的响应，首次请求失败时允许重试。
+// 未携带 message_id 时直接执行
+func idempotent[T codeReply](ctx context.Context, s *PushManagerServer, kind, messageID string, newReply func() T, handle func() (T, error)) (T, error) {
+	if messageID == "" || s.idempotency == nil {
+		return handle()
+	}
+	key := kind + ":" + messageID
+
+	deadline := time.Now().Add(duplicateWait)
+	for {
+		data, reserved, err := s.idempotency.reserve(ctx, key)
+		if err != nil {
+			// 去重存储不可用时不阻塞推送
+			log.Printf("⚠️  [Push-Manager] 幂等检查失败，按首次请求处理: key=%s, err=%v\n", key, err)
+			return handle()
+		}
+		if reserved {
+			break
+		}
+		if len(data) > 0 {
+			reply := newReply()
+			if err := proto.Unmarshal(data, reply); err != nil {
+				return reply, fmt.Errorf("failed to decode original reply: %w", err)
+			}
+			log.Printf("♻️  [Push-Manager] 重复请求，返回首次响应: key=%s\n", key)
+			return reply, nil
+		}
+		// 首次请求处理中，等待其完成
+		if time.Now().After(deadline) || !sleepCtx(ctx, duplicatePoll) {
+			var zero T
+			return zero, errDuplicateInProgress
+		}
+	}
+
+	reply, err := handle()
+	if err != nil || reply.GetCode() != "0" {
+		s.idempotency.release(ctx, key)
+		return reply, err
+	}
+
+	data, merr := proto.Marshal(reply)
+	if merr == nil {
+		merr = s.idempotency.complete(ctx, key, data)
+	}
+	if merr != nil {
+		log.Printf("⚠️  [Push-Manager] 保存幂等响应失败: key=%s, err=%v\n", key, merr)
+	}
+	return reply, nil
+}
+
+// msgSeq 本实例生成的消息 ID 序列
+var msgSeq atomic.Uint64
+
+// deliveryID Comet 请求的消息 ID：优先使用调用方的 message_id，否则生成唯一 ID。
+// Connect-Node 据此丢弃出站队列重投造成的重复消息
+func (s *PushManagerServer) deliveryID(messageID string) string {
+	if messageID != "" {
+		return messageID
+	}
+	return fmt.Sprintf("%s-%d-%d", s.managerID, s.bootTime, msgSeq.Add(1))
+}
@@ -17,6 +17,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/admin"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
 	"log"
 	"net"
@@ -27,11 +28,16 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/config"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/etcd"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/metrics"
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/tracing"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func main() {
@@ -83,12 +89,51 @@ func main() {
 	defer etcdDiscovery.Close()
 	log.Printf("✅ ETCD 连接成功\n")
 
+	// 连接 Redis（读取用户连接索引，用于按用户推送）
+	var (
+		userConns *redisstore.UserConnStore
+		outbound  *redisstore.OutboundQueue
+		schedules *redisstore.ScheduleStore
+		idem      *redisstore.IdempotencyStore
+		usage     *redisstore.UsageStore
+		history   *redisstore.HistoryStore
+		pubSeqs   *redisstore.PublishSeqStore
+	)
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.config.Redis.Addr,
+		Password: cfg.config.Redis.Password,
+		DB:       cfg.config.Redis.DB,
+	})
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		log.Printf("⚠️  Redis 连接失败（按用户推送、持久化出站队列、定时推送、租户用量计量、房间历史和发言 Seq 分配不可用）: %v\n", err)
+	} else {
+		userConns = redisstore.NewUserConnStore(redisClient)
+		outbound = redisstore.NewOutboundQueue(redisClient, int64(cfg.config.Push.QueueMaxLen))
+		schedules = redisstore.NewScheduleStore(redisClient)
+		idem = redisstore.NewIdempotencyStore(redisClient)
+		usage = redisstore.NewUsageStore(redisClient)
+		history = redisstore.NewHistoryStore(redisClient)
+		pubSeqs = redisstore.NewPublishSeqStore(redisClient)
+		log.Printf("✅ Redis 连接成功\n")
+	}
+
+	// 连接 Controller（查询租户和配额）
+	tenantResolver := pkg.NewTenantResolver(newControllerClient(cfg.config.ETCD.Endpoints), pkg.TenantCacheSize, pkg.TenantCacheTTL)
+
 	// 5️⃣ 创建 Push-Manager 服务器
 	log.Println("🏗️  创建 Push-Manager 服务器...")
 	pushManager := NewPushManagerServer(
 		cfg.managerID,
 		cfg.config,
 		etcdDiscovery,
+		userConns,
+		outbound,
+		schedules,
+		idem,
+		usage,
+		history,
+		pubSeqs,
+		tenantResolver,
 		metricsCollector,
 	)
 	log.Printf("✅ Push-Manager 服务器创建成功\n")
@@ -97,6 +142,12 @@ func main() {
 	log.Println("👀 启动 Connect-Node 发现...")
 	pushManager.WatchConnectNodes(ctx)
 
+	// 加载并调度定时推送
+	go pushManager.RunScheduler(ctx)
+
+	// 异步批量写入房间历史
+	go pushManager.RunHistoryWriter(ctx)
+
 	// 等待发现节点
 	time.Sleep(1 * time.Second)
 
@@ -104,6 +155,7 @@ func main() {
 	grpcServer := grpc.NewServer()
 
 	broadcast.RegisterPushServerServer(grpcServer, pushManager)
+	admin.RegisterPushAdminServer(grpcServer, newPushAdminServer(pushManager))
 
 	// 8️⃣ 启动 gRPC Server
 	listen, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.grpcPort))
@@ -138,6 +190,10 @@ func main() {
 	log.Println("  - PushToRoom: 推送消息到房间")
 	log.Println("  - PushToUser: 推送消息给指定用户")
 	log.Println("  - BroadcastMessage: 广播消息")
+	log.Println("  - PublishTopic: 发布到主题（客户端以 op=13/15 订阅/取消订阅主题模式，支持 * 和 #）")
+	log.Println("  - ListDeadLetters / ReplayDeadLetters: 死信查看与重放")
+	log.Println("  - ListSchedules / CancelSchedule: 定时推送查看与取消（推送请求携带 deliver_at / delay_ms）")
+	log.Println("  - PushAdmin.GetPushStatus: 出站队列与熔断器状态（由 AdminService 汇总）")
 	log.Println()
 	log.Println("💡 使用示例:")
 	log.Println("  grpcurl -plaintext localhost:50053 list")
@@ -177,6 +233,26 @@ func main() {
 	log.Println("✅ Push-Manager 已关闭")
 }
 
+// newControllerClient 非阻塞创建 Controller 客户端
+func newControllerClient(etcdEndpoints []string) controller.ControllerServiceClient {
+	resolverBuilder, err := etcd.GetETCDResolverBuilder(etcdEndpoints)
+	if err != nil {
+		log.Fatalf("❌ 获取 ETCD Resolver 失败: %v\n", err)
+	}
+
+	target := fmt.Sprintf("%s:///services/controller-manager", resolverBuilder.Scheme())
+	log.Printf("🔗 通过 ETCD 连接 Controller-Manager（非阻塞模式）: %s\n", target)
+
+	conn, err := grpc.Dial(target,
+		grpc.WithResolvers(resolverBuilder),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		log.Fatalf("❌ 创建 gRPC 连接失败: %v\n", err)
+	}
+	return controller.NewControllerServiceClient(conn)
+}
+
 // PushManagerConfig 配置
 type PushManagerConfig struct {
 	managerID   string
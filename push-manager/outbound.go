@@ -0,0 +1,425 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/push"
+)
+
+// 出站消息类型（对应 Comet 的推送 RPC）
+const (
+	kindBroadcast = "broadcast"
+	kindRoom      = "room"
+	kindUser      = "user"
+	kindTopic     = "topic"
+)
+
+const (
+	// outboundReadCount 每次从 stream 读取的消息数
+	outboundReadCount = 100
+	// outboundReadBlock 读取阻塞时间（同时决定 ctx 取消后的退出延迟）
+	outboundReadBlock = 2 * time.Second
+	// outboundEnqueueTimeout 写入 Redis 出站队列的超时
+	outboundEnqueueTimeout = 2 * time.Second
+	// defaultDeadLetterLimit 列出死信的默认条数
+	defaultDeadLetterLimit = 100
+	// outboundLaneSize 每个节点每个优先级的内存队列容量
+	outboundLaneSize = 1000
+	// outboundRetryLimit 每个节点同时等待退避重试的消息数上限
+	outboundRetryLimit = 1000
+)
+
+// outboundMsg 待投递到 Connect-Node 的消息
+type outboundMsg struct {
+	id         string // Redis stream entry id，内存队列模式为空
+	priority   protocol.Priority
+	kind       string
+	req        proto.Message // *push.BroadcastReq / *push.BroadcastRoomReq / *push.PushMsgReq / *push.BroadcastTopicReq
+	enqueuedAt int64         // 毫秒
+	attempts   int           // 已失败次数
+}
+
+// newCometReq 按消息类型创建空的 Comet 请求
+func newCometReq(kind string) (proto.Message, error) {
+	switch kind {
+	case kindBroadcast:
+		return &push.BroadcastReq{}, nil
+	case kindRoom:
+		return &push.BroadcastRoomReq{}, nil
+	case kindUser:
+		return &push.PushMsgReq{}, nil
+	case kindTopic:
+		return &push.BroadcastTopicReq{}, nil
+	}
+	return nil, fmt.Errorf("unknown outbound kind: %s", kind)
+}
+
+// decodeOutbound 反序列化 stream 中的消息
+func decodeOutbound(m *redisstore.OutboundMessage) (*outboundMsg, error) {
+	req, err := newCometReq(m.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(m.Payload, req); err != nil {
+		return nil, fmt.Errorf("failed to decode outbound message: %w", err)
+	}
+	return &outboundMsg{id: m.ID, priority: m.Priority, kind: m.Kind, req: req, enqueuedAt: m.EnqueuedAt}, nil
+}
+
+// enqueue 将消息加入节点对应优先级的出站队列：优先写入 Redis Stream（持久化），失败或未配置 Redis 时退化为内存队列
+func (bc *BroadcastClient) enqueue(kind string, priority protocol.Priority, req proto.Message) {
+	if e, ok := req.(expiring); ok && pkg.Expired(e.GetExpireAt()) {
+		bc.metrics.RecordPushExpired(bc.ctx, stageEnqueue, kind)
+		return
+	}
+	if bc.queue != nil {
+		payload, err := proto.Marshal(req)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(bc.ctx, outboundEnqueueTimeout)
+			_, err = bc.queue.Enqueue(ctx, bc.node, priority, kind, payload)
+			cancel()
+		}
+		if err == nil {
+			return
+		}
+		log.Printf("⚠️  [Push-Manager] 写入出站队列失败，改用内存队列: node=%s, err=%v\n", bc.node, err)
+	}
+
+	// 内存模式下不为熔断中的节点积压消息
+	if bc.breaker.State() == breakerOpen {
+		log.Printf("⚡ [Push-Manager] 节点 %s 熔断中，跳过消息: kind=%s\n", bc.serverID, kind)
+		return
+	}
+
+	msg := &outboundMsg{priority: priority, kind: kind, req: req, enqueuedAt: time.Now().UnixMilli()}
+	if !bc.outbound.TryPush(priority, msg) {
+		log.Printf("⚠️  [Push-Manager] 节点 %s 的内存队列已满，丢弃消息: kind=%s, priority=%s\n", bc.serverID, kind, pkg.PriorityName(priority))
+	}
+}
+
+// runStreamReaders 每个优先级一个读取协程，避免低优先级积压阻塞高优先级消息的读取
+func (bc *BroadcastClient) runStreamReaders() {
+	if err := bc.queue.EnsureGroup(bc.ctx, bc.node); err != nil {
+		log.Printf("❌ [Push-Manager] 初始化出站队列失败: node=%s, err=%v\n", bc.node, err)
+	}
+	for _, priority := range pkg.Priorities {
+		go bc.runStreamReader(priority)
+	}
+}
+
+// runStreamReader 从 Redis 出站 stream 读取消息交给推送流；启动时及周期性认领其他 Push-Manager 遗留的未确认消息
+func (bc *BroadcastClient) runStreamReader(priority protocol.Priority) {
+	lastClaim := time.Time{}
+	for bc.ctx.Err() == nil {
+		var (
+			msgs []*redisstore.OutboundMessage
+			err  error
+		)
+		if time.Since(lastClaim) >= bc.pushCfg.ClaimIdle {
+			lastClaim = time.Now()
+			msgs, err = bc.queue.Claim(bc.ctx, bc.node, priority, bc.consumer, bc.pushCfg.ClaimIdle, outboundReadCount)
+			if len(msgs) > 0 {
+				log.Printf("♻️  [Push-Manager] 认领未确认的出站消息: node=%s, priority=%s, count=%d\n", bc.node, pkg.PriorityName(priority), len(msgs))
+			}
+		}
+		if len(msgs) == 0 && err == nil {
+			msgs, err = bc.queue.Read(bc.ctx, bc.node, priority, bc.consumer, outboundReadCount, outboundReadBlock)
+		}
+		if err != nil {
+			if bc.ctx.Err() != nil {
+				return
+			}
+			log.Printf("⚠️  [Push-Manager] 读取出站队列失败: node=%s, err=%v\n", bc.node, err)
+			sleepCtx(bc.ctx, time.Second)
+			continue
+		}
+
+		for _, m := range msgs {
+			msg, err := decodeOutbound(m)
+			if err != nil {
+				// 无法解析的消息不会成功，直接进入死信
+				bc.deadLetter(m, 0, err)
+				continue
+			}
+			if !bc.outbound.PushWait(bc.ctx.Done(), priority, msg) {
+				return
+			}
+		}
+	}
+}
+
+// retry 投递失败：按指数退避重新放回队列，重试耗尽后进入死信队列。
+// Push-Manager 关闭时等待中的消息保留在 stream 中，等待重新认领
+func (bc *BroadcastClient) retry(msg *outboundMsg, cause error) {
+	// 过期的消息不再重试，也不进入死信
+	if bc.dropExpired(msg, stageRetry) {
+		return
+	}
+	msg.attempts++
+	maxAttempts := bc.pushCfg.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	log.Printf("❌ [Push-Manager] 推送失败: node=%s, kind=%s, attempt=%d/%d, err=%v\n", bc.node, msg.kind, msg.attempts, maxAttempts, cause)
+
+	if msg.attempts < maxAttempts {
+		bc.metrics.RecordPushRetry(bc.ctx, bc.node, msg.kind)
+		delay := retryBackoff(bc.pushCfg.RetryBaseDelay, bc.pushCfg.RetryMaxDelay, msg.attempts)
+		bc.scheduleRetry(msg, delay)
+		return
+	}
+
+	if bc.queue == nil {
+		log.Printf("🗑️  [Push-Manager] 重试耗尽，丢弃消息（未配置 Redis 死信队列）: node=%s, kind=%s\n", bc.node, msg.kind)
+		return
+	}
+	payload, err := proto.Marshal(msg.req)
+	if err != nil {
+		log.Printf("❌ [Push-Manager] 序列化死信失败: %v\n", err)
+		return
+	}
+	bc.deadLetter(&redisstore.OutboundMessage{
+		ID:         msg.id,
+		Node:       bc.node,
+		Priority:   msg.priority,
+		Kind:       msg.kind,
+		Payload:    payload,
+		EnqueuedAt: msg.enqueuedAt,
+	}, msg.attempts, cause)
+}
+
+// scheduleRetry 将消息加入退避重试定时器；等待重试的消息已达上限时不再排队：
+// Redis 模式下消息仍未确认，超过 ClaimIdle 后由 stream 读取协程重新认领，内存模式下丢弃
+func (bc *BroadcastClient) scheduleRetry(msg *outboundMsg, delay time.Duration) {
+	bc.retryMu.Lock()
+	defer bc.retryMu.Unlock()
+
+	if bc.ctx.Err() != nil {
+		return
+	}
+	if len(bc.retrying) >= outboundRetryLimit {
+		log.Printf("⚠️  [Push-Manager] 节点 %s 等待重试的消息已满，%s: kind=%s\n", bc.serverID, bc.retryOverflowAction(), msg.kind)
+		return
+	}
+	bc.retrying[msg] = bc.retryTimer.Add(delay, func() {
+		bc.fireRetry(msg)
+	})
+}
+
+// fireRetry 重试到期（在定时器协程中回调，不能阻塞）：放回出站队列
+func (bc *BroadcastClient) fireRetry(msg *outboundMsg) {
+	bc.retryMu.Lock()
+	td, ok := bc.retrying[msg]
+	delete(bc.retrying, msg)
+	bc.retryMu.Unlock()
+	if !ok {
+		return
+	}
+	bc.retryTimer.Del(td)
+
+	if !bc.outbound.TryPush(msg.priority, msg) {
+		log.Printf("⚠️  [Push-Manager] 节点 %s 的内存队列已满，%s: kind=%s\n", bc.serverID, bc.retryOverflowAction(), msg.kind)
+	}
+}
+
+// cancelRetries 客户端关闭时移除全部等待中的重试
+func (bc *BroadcastClient) cancelRetries() {
+	bc.retryMu.Lock()
+	retrying := bc.retrying
+	bc.retrying = make(map[*outboundMsg]*pkg.TimerData)
+	bc.retryMu.Unlock()
+
+	for _, td := range retrying {
+		bc.retryTimer.Del(td)
+	}
+}
+
+// retryOverflowAction 重试无法排队时的处理方式（用于日志）
+func (bc *BroadcastClient) retryOverflowAction() string {
+	if bc.queue != nil {
+		return "消息留在出站队列等待重新认领"
+	}
+	return "丢弃消息"
+}
+
+// ack 确认 stream 中已投递的消息
+func (bc *BroadcastClient) ack(msg *outboundMsg) {
+	if bc.queue == nil || msg.id == "" {
+		return
+	}
+	if err := bc.queue.Ack(bc.ctx, bc.node, msg.priority, msg.id); err != nil {
+		log.Printf("⚠️  [Push-Manager] 确认出站消息失败: node=%s, id=%s, err=%v\n", bc.node, msg.id, err)
+	}
+}
+
+// deadLetter 写入死信队列
+func (bc *BroadcastClient) deadLetter(m *redisstore.OutboundMessage, attempts int, cause error) {
+	if err := bc.queue.DeadLetter(bc.ctx, m, attempts, cause.Error()); err != nil {
+		log.Printf("❌ [Push-Manager] 写入死信失败: node=%s, id=%s, err=%v\n", bc.node, m.ID, err)
+		return
+	}
+	bc.metrics.RecordDeadLetter(bc.ctx, bc.node, m.Kind)
+	log.Printf("☠️  [Push-Manager] 消息进入死信队列: node=%s, kind=%s, attempts=%d, err=%v\n", bc.node, m.Kind, attempts, cause)
+}
+
+// retryBackoff 第 attempt 次失败后的等待时间：base * 2^(attempt-1)，不超过 max
+func retryBackoff(base, max time.Duration, attempt int) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// sleepCtx 等待 d，ctx 取消时提前返回 false
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// ========== 死信管理 RPC ==========
+
+// ListDeadLetters 列出死信
+func (s *PushManagerServer) ListDeadLetters(ctx context.Context, req *broadcast.ListDeadLettersReq) (*broadcast.ListDeadLettersReply, error) {
+	if s.outbound == nil {
+		return nil, fmt.Errorf("dead-letter queue unavailable: redis not connected")
+	}
+	limit := int64(req.Limit)
+	if limit <= 0 {
+		limit = defaultDeadLetterLimit
+	}
+
+	letters, next, err := s.outbound.ListDeadLetters(ctx, req.Node, req.StartId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &broadcast.ListDeadLettersReply{NextId: next}
+	for _, dl := range letters {
+		reply.Letters = append(reply.Letters, toDeadLetterPB(dl))
+	}
+	return reply, nil
+}
+
+// ReplayDeadLetters 将死信重新加入目标节点的出站队列；目标节点已不在客户端池中时保留死信
+func (s *PushManagerServer) ReplayDeadLetters(ctx context.Context, req *broadcast.ReplayDeadLettersReq) (*broadcast.ReplayDeadLettersReply, error) {
+	if s.outbound == nil {
+		return nil, fmt.Errorf("dead-letter queue unavailable: redis not connected")
+	}
+
+	var letters []*redisstore.DeadLetter
+	if req.All {
+		start := ""
+		for {
+			page, next, err := s.outbound.ListDeadLetters(ctx, req.Node, start, defaultDeadLetterLimit)
+			if err != nil {
+				return nil, err
+			}
+			letters = append(letters, page...)
+			if next == "" {
+				break
+			}
+			start = next
+		}
+	} else {
+		for _, id := range req.Ids {
+			dl, err := s.outbound.GetDeadLetter(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if dl == nil {
+				continue
+			}
+			letters = append(letters, dl)
+		}
+	}
+
+	reply := &broadcast.ReplayDeadLettersReply{}
+	for _, dl := range letters {
+		if msg, err := decodeOutbound(&redisstore.OutboundMessage{Kind: dl.Kind, Payload: dl.Payload}); err == nil && msg.expired() {
+			s.metrics.RecordPushExpired(ctx, stageReplay, dl.Kind)
+			reply.ExpiredIds = append(reply.ExpiredIds, dl.ID)
+			continue
+		}
+		if _, ok := s.clientFor(dl.Node); !ok {
+			log.Printf("⚠️  [Push-Manager] 死信目标节点不在线，跳过重放: id=%s, node=%s\n", dl.ID, dl.Node)
+			reply.FailedIds = append(reply.FailedIds, dl.ID)
+			continue
+		}
+		if err := s.outbound.Replay(ctx, dl); err != nil {
+			log.Printf("❌ [Push-Manager] 重放死信失败: id=%s, err=%v\n", dl.ID, err)
+			reply.FailedIds = append(reply.FailedIds, dl.ID)
+			continue
+		}
+		reply.Replayed++
+	}
+	// 过期的死信不会再被重放，从死信队列删除
+	if err := s.outbound.DeleteDeadLetters(ctx, reply.ExpiredIds...); err != nil {
+		log.Printf("⚠️  [Push-Manager] 删除过期死信失败: %v\n", err)
+	}
+	log.Printf("🔁 [Push-Manager] 重放死信: replayed=%d, failed=%d, expired=%d\n", reply.Replayed, len(reply.FailedIds), len(reply.ExpiredIds))
+	return reply, nil
+}
+
+// toDeadLetterPB 转换为 API 结构，解析出房间、会话 key 和消息体便于排查
+func toDeadLetterPB(dl *redisstore.DeadLetter) *broadcast.DeadLetter {
+	pb := &broadcast.DeadLetter{
+		Id:         dl.ID,
+		Node:       dl.Node,
+		Kind:       dl.Kind,
+		Attempts:   int32(dl.Attempts),
+		Error:      dl.Error,
+		EnqueuedAt: dl.EnqueuedAt,
+		FailedAt:   dl.FailedAt,
+	}
+	msg, err := decodeOutbound(&redisstore.OutboundMessage{Kind: dl.Kind, Payload: dl.Payload})
+	if err != nil {
+		return pb
+	}
+	switch req := msg.req.(type) {
+	case *push.BroadcastReq:
+		pb.Proto = req.Proto
+	case *push.BroadcastRoomReq:
+		pb.RoomId = req.RoomID
+		pb.Proto = req.Proto
+	case *push.PushMsgReq:
+		pb.Keys = req.Keys
+		pb.Proto = req.Proto
+	case *push.BroadcastTopicReq:
+		pb.Topic = req.Topic
+		pb.Proto = req.Proto
+	}
+	return pb
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/push"
+)
+
+const (
+	// defaultRequestClientTimeout 未指定超时时等待客户端响应的时间
+	defaultRequestClientTimeout = 10 * time.Second
+	// maxRequestClientTimeout 等待客户端响应的最长时间
+	maxRequestClientTimeout = time.Minute
+)
+
+// errRequestNoNode 用户的连接所在节点都不可用（未发现或已熔断）
+var errRequestNoNode = errors.New("no available connect-node for user")
+
+// RequestClient 实现 PushServer 的 RequestClient 方法：按用户连接索引找到会话所在节点，
+// 直接调用 Comet.ClientRequest（不经过出站队列），多个节点并发请求，最先响应的生效
+func (s *PushManagerServer) RequestClient(ctx context.Context, req *broadcast.RequestClientReq) (*broadcast.RequestClientReply, error) {
+	if req.UserId == "" {
+		return &broadcast.RequestClientReply{Code: "1", Msg: "INVALID_ARGUMENT", Desc: "user_id 不能为空"}, nil
+	}
+	if s.userConns == nil {
+		return &broadcast.RequestClientReply{Code: "1", Msg: "UNAVAILABLE", Desc: "用户连接索引不可用（Redis 未连接）"}, nil
+	}
+	if !validateLocalIDs(req.UserId) {
+		return &broadcast.RequestClientReply{Code: "1", Msg: "INVALID_ARGUMENT", Desc: pkg.ErrTenantIDInvalid.Error()}, nil
+	}
+	if msg, desc, ok := s.tenants.admit(ctx, req.AppId, len(req.Body)); !ok {
+		return &broadcast.RequestClientReply{Code: "1", Msg: msg, Desc: desc}, nil
+	}
+	userID := pkg.ScopeID(req.AppId, req.UserId)
+	timeout := defaultRequestClientTimeout
+	if req.TimeoutMs > 0 {
+		timeout = min(time.Duration(req.TimeoutMs)*time.Millisecond, maxRequestClientTimeout)
+	}
+	requestID := s.deliveryID("")
+	log.Printf("📨 [Push-Manager] 收到客户端请求: userId=%s, deviceId=%s, requestId=%s, timeout=%v\n", userID, req.DeviceId, requestID, timeout)
+
+	nodes, err := s.requestNodes(ctx, userID, req.DeviceId)
+	if err != nil {
+		log.Printf("❌ [Push-Manager] 查询用户连接索引失败: %v\n", err)
+		return &broadcast.RequestClientReply{Code: "1", Msg: "INTERNAL", Desc: err.Error(), RequestId: requestID}, nil
+	}
+	if len(nodes) == 0 {
+		return &broadcast.RequestClientReply{Code: "1", Msg: "OFFLINE", Desc: "用户没有（匹配的）在线连接", RequestId: requestID}, nil
+	}
+	if msg, desc, ok := s.tenants.countPublish(ctx, req.AppId); !ok {
+		return &broadcast.RequestClientReply{Code: "1", Msg: msg, Desc: desc, RequestId: requestID}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		reply *push.ClientRequestReply
+		err   error
+	}
+	results := make(chan result, len(nodes))
+	for address, key := range nodes {
+		go func() {
+			reply, err := s.requestNode(ctx, address, &push.ClientRequestReq{
+				Key:       key,
+				Body:      req.Body,
+				TimeoutMs: timeout.Milliseconds(),
+				RequestID: requestID,
+			})
+			results <- result{reply: reply, err: err}
+		}()
+	}
+
+	// 最先成功的响应生效，返回后取消其他节点上的请求；全部失败时以最具体的错误为准
+	var lastErr error
+	for range nodes {
+		r := <-results
+		if r.err == nil {
+			log.Printf("✅ [Push-Manager] 收到客户端响应: requestId=%s, connId=%s\n", requestID, r.reply.ConnID)
+			return &broadcast.RequestClientReply{
+				Code:      "0",
+				Msg:       "OK",
+				RequestId: requestID,
+				ConnId:    r.reply.ConnID,
+				Body:      r.reply.Body,
+			}, nil
+		}
+		if lastErr == nil || !errors.Is(lastErr, pkg.ErrClientRequestTimeout) {
+			lastErr = r.err
+		}
+	}
+
+	reply := &broadcast.RequestClientReply{Code: "1", Desc: lastErr.Error(), RequestId: requestID}
+	switch {
+	case errors.Is(lastErr, pkg.ErrClientRequestTimeout) || ctx.Err() != nil:
+		reply.Msg = "TIMEOUT"
+	case errors.Is(lastErr, pkg.ErrClientOffline):
+		reply.Msg = "OFFLINE"
+	default:
+		reply.Msg = "UNAVAILABLE"
+	}
+	log.Printf("⚠️  [Push-Manager] 客户端请求失败: requestId=%s, msg=%s, err=%v\n", requestID, reply.Msg, lastErr)
+	return reply, nil
+}
+
+// requestNodes 用户（指定设备时只取该设备）的连接所在节点 -> 会话 key
+func (s *PushManagerServer) requestNodes(ctx context.Context, userID, deviceID string) (map[string]string, error) {
+	conns, err := s.userConns.GetConnections(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	key := userID
+	if deviceID != "" {
+		key = userID + "/" + deviceID
+	}
+	nodes := make(map[string]string)
+	for _, conn := range conns {
+		if conn.NodeAddress == "" || (deviceID != "" && conn.DeviceID != deviceID) {
+			continue
+		}
+		nodes[conn.NodeAddress] = key
+	}
+	return nodes, nil
+}
+
+// requestNode 调用节点的 Comet.ClientRequest；已熔断的节点直接跳过。
+// 按 Connect-Node 返回的 gRPC 状态码还原为 pkg 中的错误
+func (s *PushManagerServer) requestNode(ctx context.Context, address string, req *push.ClientRequestReq) (*push.ClientRequestReply, error) {
+	client, ok := s.clientFor(address)
+	if !ok || client.breaker.State() == breakerOpen {
+		return nil, errRequestNoNode
+	}
+	reply, err := client.client.ClientRequest(ctx, req)
+	if err == nil {
+		return reply, nil
+	}
+	switch status.Code(err) {
+	case codes.DeadlineExceeded:
+		return nil, pkg.ErrClientRequestTimeout
+	case codes.NotFound:
+		return nil, pkg.ErrClientOffline
+	}
+	return nil, err
+}
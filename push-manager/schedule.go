@@ -0,0 +1,354 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
+)
+
+const (
+	// scheduleSyncInterval 从 Redis 同步其他 Push-Manager 创建或取消的计划的间隔
+	scheduleSyncInterval = 30 * time.Second
+	// scheduleTimerSize 本地定时器初始容量
+	scheduleTimerSize = 1024
+	// defaultScheduleLimit 列出定时推送的默认条数
+	defaultScheduleLimit = 100
+	// scheduleDeliverTimeout 到期计划的抢占和投递超时
+	scheduleDeliverTimeout = 10 * time.Second
+	// scheduleClaimLease 抢占租约时长，需大于投递超时，投递失败时租约到期后重试
+	scheduleClaimLease = time.Minute
+)
+
+// errScheduleUnavailable 未连接 Redis 时不支持定时推送（计划需要持久化）
+var errScheduleUnavailable = errors.New("scheduled delivery unavailable: redis not connected")
+
+// scheduler 定时推送调度：计划持久化在 Redis，本地用 pkg.Timer（最小堆）到期触发
+type scheduler struct {
+	store *redisstore.ScheduleStore
+	timer *pkg.Timer
+
+	mu      sync.Mutex
+	pending map[string]*pkg.TimerData // 本地已加载的计划
+}
+
+func newScheduler(store *redisstore.ScheduleStore) *scheduler {
+	return &scheduler{
+		store:   store,
+		timer:   pkg.NewTimer(scheduleTimerSize),
+		pending: make(map[string]*pkg.TimerData),
+	}
+}
+
+// resolveDeliverAt 计算投递时间（毫秒），返回 0 表示立即投递
+func resolveDeliverAt(deliverAt, delayMs int64) int64 {
+	now := time.Now().UnixMilli()
+	if deliverAt <= 0 && delayMs > 0 {
+		deliverAt = now + delayMs
+	}
+	if deliverAt <= now {
+		return 0
+	}
+	return deliverAt
+}
+
+// scheduleLater 需要定时/延迟投递时保存计划并返回计划 ID；立即投递时返回空 ID
+func (s *PushManagerServer) scheduleLater(ctx context.Context, kind string, req proto.Message, deliverAt, delayMs int64) (string, error) {
+	at := resolveDeliverAt(deliverAt, delayMs)
+	if at == 0 {
+		return "", nil
+	}
+	if s.scheduler == nil {
+		return "", errScheduleUnavailable
+	}
+
+	// 到期投递时走立即投递路径，清除定时字段
+	req = proto.Clone(req)
+	switch r := req.(type) {
+	case *broadcast.BroadCastReq:
+		r.DeliverAt, r.DelayMs = 0, 0
+	case *broadcast.BroadCastRoomReq:
+		r.DeliverAt, r.DelayMs = 0, 0
+	case *broadcast.PushToUserReq:
+		r.DeliverAt, r.DelayMs = 0, 0
+	case *broadcast.PublishTopicReq:
+		r.DeliverAt, r.DelayMs = 0, 0
+	}
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := s.scheduler.store.NextID(ctx)
+	if err != nil {
+		return "", err
+	}
+	schedule := &redisstore.Schedule{
+		ID:        id,
+		Kind:      kind,
+		Payload:   payload,
+		DeliverAt: at,
+		CreatedAt: time.Now().UnixMilli(),
+	}
+	if err := s.scheduler.store.Save(ctx, schedule); err != nil {
+		return "", err
+	}
+	s.armSchedule(id, at)
+
+	log.Printf("⏰ [Push-Manager] 已创建定时推送: id=%s, kind=%s, deliver_at=%s\n", id, kind, time.UnixMilli(at).Format(time.DateTime))
+	return id, nil
+}
+
+// armSchedule 将计划加入本地定时器（已加载的忽略）
+func (s *PushManagerServer) armSchedule(id string, deliverAt int64) {
+	sc := s.scheduler
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if _, ok := sc.pending[id]; ok {
+		return
+	}
+	td := sc.timer.Add(time.Until(time.UnixMilli(deliverAt)), func() {
+		s.fireSchedule(id)
+	})
+	td.Key = id
+	sc.pending[id] = td
+}
+
+// disarmSchedule 从本地定时器移除计划
+func (s *PushManagerServer) disarmSchedule(id string) {
+	sc := s.scheduler
+	sc.mu.Lock()
+	td, ok := sc.pending[id]
+	delete(sc.pending, id)
+	sc.mu.Unlock()
+
+	if ok {
+		sc.timer.Del(td)
+	}
+}
+
+// fireSchedule 计划到期：抢占成功后按类型投递（已被取消或其他 Push-Manager 抢先投递时跳过）。
+// 投递成功后才删除计划，失败时保留，租约到期后由周期同步重新加载并再次抢占
+func (s *PushManagerServer) fireSchedule(id string) {
+	// 在定时器协程中回调，投递放到独立协程，避免阻塞其他计划
+	s.disarmSchedule(id)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(s.ctx, scheduleDeliverTimeout)
+		defer cancel()
+
+		schedule, err := s.scheduler.store.Claim(ctx, id, scheduleClaimLease)
+		if err != nil {
+			log.Printf("❌ [Push-Manager] 抢占定时推送失败: id=%s, err=%v\n", id, err)
+			return
+		}
+		if schedule == nil {
+			return
+		}
+
+		if err := s.deliverSchedule(ctx, schedule); err != nil {
+			log.Printf("❌ [Push-Manager] 定时推送投递失败，租约到期后重试: id=%s, err=%v\n", id, err)
+			return
+		}
+		if err := s.scheduler.store.Complete(ctx, id); err != nil {
+			log.Printf("⚠️  [Push-Manager] 删除已投递的定时推送失败: id=%s, err=%v\n", id, err)
+		}
+	}()
+}
+
+// deliverSchedule 投递计划；返回错误表示可以重试，无法投递的计划（解析失败、已过期）返回 nil 以便删除
+func (s *PushManagerServer) deliverSchedule(ctx context.Context, schedule *redisstore.Schedule) error {
+	req, err := decodeSchedule(schedule)
+	if err != nil {
+		log.Printf("❌ [Push-Manager] 解析定时推送失败，丢弃: id=%s, err=%v\n", schedule.ID, err)
+		return nil
+	}
+	if e, ok := req.(expiring); ok && pkg.Expired(e.GetExpireAt()) {
+		s.metrics.RecordPushExpired(ctx, stageSchedule, schedule.Kind)
+		log.Printf("⌛ [Push-Manager] 定时推送已过期，丢弃: id=%s\n", schedule.ID)
+		return nil
+	}
+
+	switch r := req.(type) {
+	case *broadcast.BroadCastReq:
+		s.EnqueueBroadcastMsg(r)
+	case *broadcast.BroadCastRoomReq:
+		s.EnqueueRoomMsg(r)
+	case *broadcast.PushToUserReq:
+		if s.userConns == nil {
+			return errors.New("user connection index unavailable")
+		}
+		if _, err := s.EnqueueUserMsg(ctx, r); err != nil {
+			return err
+		}
+	case *broadcast.PublishTopicReq:
+		s.EnqueueTopicMsg(r)
+	}
+	log.Printf("🔔 [Push-Manager] 定时推送已投递: id=%s, kind=%s\n", schedule.ID, schedule.Kind)
+	return nil
+}
+
+// RunScheduler 加载已持久化的计划，并周期同步其他 Push-Manager 创建或取消的计划
+func (s *PushManagerServer) RunScheduler(ctx context.Context) {
+	if s.scheduler == nil {
+		log.Printf("⚠️  [Push-Manager] 未连接 Redis，定时推送不可用\n")
+		return
+	}
+
+	ticker := time.NewTicker(scheduleSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		s.syncSchedules(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncSchedules 以 Redis 为准对齐本地定时器
+func (s *PushManagerServer) syncSchedules(ctx context.Context) {
+	due, err := s.scheduler.store.DueIDs(ctx)
+	if err != nil {
+		log.Printf("⚠️  [Push-Manager] 同步定时推送失败: %v\n", err)
+		return
+	}
+
+	for id, at := range due {
+		s.armSchedule(id, at)
+	}
+
+	s.scheduler.mu.Lock()
+	var stale []string
+	for id := range s.scheduler.pending {
+		if _, ok := due[id]; !ok {
+			stale = append(stale, id)
+		}
+	}
+	s.scheduler.mu.Unlock()
+
+	for _, id := range stale {
+		s.disarmSchedule(id)
+	}
+}
+
+// decodeSchedule 反序列化计划中的 PushServer 请求
+func decodeSchedule(schedule *redisstore.Schedule) (proto.Message, error) {
+	var req proto.Message
+	switch schedule.Kind {
+	case kindBroadcast:
+		req = &broadcast.BroadCastReq{}
+	case kindRoom:
+		req = &broadcast.BroadCastRoomReq{}
+	case kindUser:
+		req = &broadcast.PushToUserReq{}
+	case kindTopic:
+		req = &broadcast.PublishTopicReq{}
+	default:
+		return nil, fmt.Errorf("unknown schedule kind: %s", schedule.Kind)
+	}
+	if err := proto.Unmarshal(schedule.Payload, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// ========== 定时推送管理 RPC ==========
+
+// CancelSchedule 取消定时推送
+func (s *PushManagerServer) CancelSchedule(ctx context.Context, req *broadcast.CancelScheduleReq) (*broadcast.CancelScheduleReply, error) {
+	if s.scheduler == nil {
+		return nil, errScheduleUnavailable
+	}
+
+	cancelled, err := s.scheduler.store.Cancel(ctx, req.ScheduleId)
+	if err != nil {
+		return nil, err
+	}
+	s.disarmSchedule(req.ScheduleId)
+
+	if cancelled {
+		log.Printf("🚫 [Push-Manager] 已取消定时推送: id=%s\n", req.ScheduleId)
+	}
+	return &broadcast.CancelScheduleReply{Cancelled: cancelled}, nil
+}
+
+// ListSchedules 按投递时间升序列出待投递的定时推送
+func (s *PushManagerServer) ListSchedules(ctx context.Context, req *broadcast.ListSchedulesReq) (*broadcast.ListSchedulesReply, error) {
+	if s.scheduler == nil {
+		return nil, errScheduleUnavailable
+	}
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultScheduleLimit
+	}
+
+	// 按类型过滤时需要扫描全部计划
+	fetch := int64(limit)
+	if req.Kind != "" {
+		fetch = 0
+	}
+	schedules, err := s.scheduler.store.List(ctx, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &broadcast.ListSchedulesReply{}
+	for _, schedule := range schedules {
+		if req.Kind != "" && schedule.Kind != req.Kind {
+			continue
+		}
+		item := &broadcast.ScheduledPush{
+			Id:        schedule.ID,
+			Kind:      schedule.Kind,
+			DeliverAt: schedule.DeliverAt,
+			CreatedAt: schedule.CreatedAt,
+		}
+		if msg, err := decodeSchedule(schedule); err == nil {
+			switch r := msg.(type) {
+			case *broadcast.BroadCastReq:
+				item.Proto = r.Proto
+			case *broadcast.BroadCastRoomReq:
+				item.RoomId = r.RoomId
+				item.Proto = r.Proto
+			case *broadcast.PushToUserReq:
+				item.UserIds = r.UserIds
+				item.Proto = r.Proto
+			case *broadcast.PublishTopicReq:
+				item.Topic = r.Topic
+				item.Proto = r.Proto
+			}
+		}
+		reply.Schedules = append(reply.Schedules, item)
+		if len(reply.Schedules) == limit {
+			break
+		}
+	}
+	return reply, nil
+}
@@ -19,24 +19,42 @@ import (
 	"fmt"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/push"
 	"log"
+	"slices"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/config"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/etcd"
 	"github.com/livekit/psrpc/examples/pubsub/pkg/metrics"
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
 )
 
 // BroadcastClient 广播客户端包装
 type BroadcastClient struct {
-	serverID      string
-	client        push.CometClient
-	broadcastChan chan *push.BroadcastReq
-	routineSize   uint64
-	conn          *grpc.ClientConn
+	serverID string
+	node     string // Connect-Node gRPC 地址（出站队列按地址区分）
+	client   push.CometClient
+	outbound *pkg.Lanes[*outboundMsg] // 按优先级分道的待发送队列（Redis 模式下由 stream 读取协程填充），由推送流合批发送
+	conn     *grpc.ClientConn
+
+	// 持久化出站队列（Redis 不可用时为 nil，退化为内存队列）
+	queue    *redisstore.OutboundQueue
+	consumer string
+	pushCfg  *config.PushConfig
+	metrics  *metrics.MetricsCollector
+
+	// 节点熔断器：连续失败后暂停发送（Redis 模式下消息留在出站队列延迟投递，内存模式下跳过该节点）
+	breaker *circuitBreaker
+
+	// 等待退避重试的消息（共享的 pkg.Timer 到期后放回 outbound），数量上限 outboundRetryLimit
+	retryTimer *pkg.Timer
+	retryMu    sync.Mutex
+	retrying   map[*outboundMsg]*pkg.TimerData
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -53,9 +71,36 @@ type PushManagerServer struct {
 	// ETCD 服务发现
 	discovery *etcd.ServiceDiscovery
 
-	// Connect-Node 客户端池（nodeID -> *BroadcastClient）
+	// Connect-Node 客户端池（nodeID -> *BroadcastClient），服务发现协程写、RPC 协程读
+	clientsMu          sync.RWMutex
 	broadCastClientMap map[string]*BroadcastClient
 
+	// 用户 -> 连接索引（按用户推送时定位节点，Redis 不可用时为 nil）
+	userConns *redisstore.UserConnStore
+
+	// 持久化出站队列与死信队列（Redis 不可用时为 nil）
+	outbound *redisstore.OutboundQueue
+
+	// 定时推送调度（Redis 不可用时为 nil）
+	scheduler *scheduler
+
+	// 出站消息退避重试定时器（全部节点共享一个协程）
+	retryTimer *pkg.Timer
+
+	// 房间消息历史（Redis 不可用时为 nil），historyCh 为待批量写入的消息
+	history   *redisstore.HistoryStore
+	historyCh chan *redisstore.HistoryMessage
+
+	// 客户端发言的 Seq 分配（Redis 不可用时为 nil，沿用客户端的 Seq）
+	publishSeqs *redisstore.PublishSeqStore
+
+	// 推送请求幂等（按 message_id 去重），bootTime 用于生成本实例唯一的消息 ID
+	idempotency *idempotency
+	bootTime    int64
+
+	// 租户准入（租户状态、消息大小、发布速率、每日推送数）
+	tenants *tenantGate
+
 	// Metrics
 	metrics *metrics.MetricsCollector
 
@@ -69,6 +114,14 @@ func NewPushManagerServer(
 	managerID string,
 	cfg *config.Config,
 	discovery *etcd.ServiceDiscovery,
+	userConns *redisstore.UserConnStore,
+	outbound *redisstore.OutboundQueue,
+	schedules *redisstore.ScheduleStore,
+	idem *redisstore.IdempotencyStore,
+	usage *redisstore.UsageStore,
+	history *redisstore.HistoryStore,
+	publishSeqs *redisstore.PublishSeqStore,
+	tenantResolver *pkg.TenantResolver,
 	metricsCollector *metrics.MetricsCollector,
 ) *PushManagerServer {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -78,10 +131,24 @@ func NewPushManagerServer(
 		config:             cfg,
 		discovery:          discovery,
 		broadCastClientMap: make(map[string]*BroadcastClient),
+		userConns:          userConns,
+		outbound:           outbound,
+		history:            history,
+		publishSeqs:        publishSeqs,
+		idempotency:        newIdempotency(idem, cfg.Push.DedupeWindow, cfg.Push.DedupeLRUSize),
+		bootTime:           time.Now().UnixNano(),
+		tenants:            newTenantGate(tenantResolver, usage, metricsCollector),
+		retryTimer:         pkg.NewTimer(outboundRetryLimit),
 		metrics:            metricsCollector,
 		ctx:                ctx,
 		cancel:             cancel,
 	}
+	if schedules != nil {
+		pms.scheduler = newScheduler(schedules)
+	}
+	if history != nil {
+		pms.historyCh = make(chan *redisstore.HistoryMessage, historyQueueSize)
+	}
 
 	return pms
 }
@@ -111,7 +178,12 @@ func (s *PushManagerServer) WatchConnectNodes(ctx context.Context) {
 					// 事件通道已关闭
 					return
 				}
-				log.Printf("etcd discovery clients %s", event)
+				log.Printf("etcd discovery clients %+v", event)
+
+				if event.Type == etcd.EventDelete {
+					s.removeBroadcastClient(event.Addr)
+					continue
+				}
 
 				endpoints, err := s.discovery.GetEndpoints()
 
@@ -130,6 +202,9 @@ func (s *PushManagerServer) WatchConnectNodes(ctx context.Context) {
 
 // createBroadcastClient 为指定的 Connect-Node 创建广播客户端
 func (s *PushManagerServer) createBroadcastClient(instances []string) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+
 	// 保留已存在的客户端，只创建新的
 	comets := make(map[string]*BroadcastClient)
 	
@@ -140,7 +215,7 @@ func (s *PushManagerServer) createBroadcastClient(instances []string) {
 	
 	// 处理所有实例
 	for _, instance := range instances {
-		nodeID := fmt.Sprintf("connect-node-%s", instance)
+		nodeID := nodeClientID(instance)
 		
 		// 如果已存在，跳过
 		if _, exists := comets[nodeID]; exists {
@@ -166,21 +241,34 @@ func (s *PushManagerServer) createBroadcastClient(instances []string) {
 		}
 
 		client := push.NewCometClient(conn)
-		routineSize := uint64(10) // 工作协程数量
 
 		broadcastClient := &BroadcastClient{
-			serverID:      nodeID,
-			client:        client,
-			broadcastChan: make(chan *push.BroadcastReq, 1000), // 缓冲队列
-			routineSize:   routineSize,
-			conn:          conn,
-			ctx:           ctx,
-			cancel:        cancel,
+			serverID: nodeID,
+			node:     instance,
+			client:   client,
+			outbound: pkg.NewLanes[*outboundMsg](outboundLaneSize, pkg.DefaultStarvationLimit),
+			conn:     conn,
+			queue:    s.outbound,
+			consumer: s.managerID,
+			pushCfg:  s.config.Push,
+			metrics:  s.metrics,
+
+			retryTimer: s.retryTimer,
+			retrying:   make(map[*outboundMsg]*pkg.TimerData),
+
+			ctx:    ctx,
+			cancel: cancel,
 		}
+		broadcastClient.breaker = newCircuitBreaker(
+			s.config.Push.BreakerFailureThreshold,
+			s.config.Push.BreakerOpenTimeout,
+			broadcastClient.onBreakerChange,
+		)
 
-		// 启动工作协程处理消息
-		for i := uint64(0); i < routineSize; i++ {
-			go broadcastClient.runWorker(i)
+		// 每个节点一条长连接推送流，批量发送并按确认重试
+		go broadcastClient.runPushStream()
+		if broadcastClient.queue != nil {
+			go broadcastClient.runStreamReaders()
 		}
 
 		comets[nodeID] = broadcastClient
@@ -192,60 +280,189 @@ func (s *PushManagerServer) createBroadcastClient(instances []string) {
 	log.Printf("✅ [Push-Manager] Connect-Node 客户端创建成功，共 %d 个节点\n", len(comets))
 }
 
-// runWorker 工作协程：从队列中取出消息并发送
-func (bc *BroadcastClient) runWorker(workerID uint64) {
-	log.Printf("👷 [Worker-%s-%d] 已启动\n", bc.serverID, workerID)
-
-	defer func() {
-		log.Printf("👷 [Worker-%s-%d] 已停止\n", bc.serverID, workerID)
-	}()
+// removeBroadcastClient 节点从 etcd 下线后关闭并移除其客户端
+func (s *PushManagerServer) removeBroadcastClient(address string) {
+	if address == "" {
+		return
+	}
+	nodeID := nodeClientID(address)
 
-	for {
-		select {
-		case <-bc.ctx.Done():
-			return
-		case req, ok := <-bc.broadcastChan:
-			if !ok {
-				// 通道已关闭
-				return
-			}
-			// 发送消息到 Connect-Node
-			ctx, cancel := context.WithTimeout(bc.ctx, 5*time.Second)
-			_, err := bc.client.Broadcast(ctx, req)
-			cancel()
+	s.clientsMu.Lock()
+	client, ok := s.broadCastClientMap[nodeID]
+	if ok {
+		delete(s.broadCastClientMap, nodeID)
+	}
+	s.clientsMu.Unlock()
 
-			if err != nil {
-				log.Printf("❌ [Worker-%s-%d] 推送消息失败: %v\n", bc.serverID, workerID, err)
-			} else {
-				log.Printf("✅ [Worker-%s-%d] 消息推送成功\n", bc.serverID, workerID)
-			}
+	if !ok {
+		return
+	}
+	log.Printf("📴 [Push-Manager] Connect-Node 下线，移除客户端: %s\n", nodeID)
+	client.Close()
+	s.metrics.RemoveBreakerState(address)
+
+	// 节点不再恢复时，其出站 stream 在保留时间后由 Redis 删除
+	if s.outbound != nil && s.config.Push.OrphanStreamTTL > 0 {
+		ctx, cancel := context.WithTimeout(s.ctx, outboundEnqueueTimeout)
+		defer cancel()
+		if err := s.outbound.ExpireNode(ctx, address, s.config.Push.OrphanStreamTTL); err != nil {
+			log.Printf("⚠️  [Push-Manager] 设置下线节点出站队列过期失败: node=%s, err=%v\n", address, err)
 		}
 	}
 }
 
+// clients 当前全部 Connect-Node 客户端
+func (s *PushManagerServer) clients() []*BroadcastClient {
+	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+
+	clients := make([]*BroadcastClient, 0, len(s.broadCastClientMap))
+	for _, client := range s.broadCastClientMap {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// clientFor 按 Connect-Node 地址查找客户端
+func (s *PushManagerServer) clientFor(address string) (*BroadcastClient, bool) {
+	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+
+	client, ok := s.broadCastClientMap[nodeClientID(address)]
+	return client, ok
+}
+
 // EnqueueBroadcastMsg 将消息加入到所有 Connect-Node 的队列中
 func (s *PushManagerServer) EnqueueBroadcastMsg(req *broadcast.BroadCastReq) {
 
 	var args = push.BroadcastReq{
-		Proto:   req.Proto,
-		ProtoOp: req.Proto.Op, // 设置 ProtoOp，用于客户端的 NeedPush 检查
+		Proto:    req.Proto,
+		ProtoOp:  req.Proto.Op, // 设置 ProtoOp，用于客户端的 NeedPush 检查
+		MsgID:    s.deliveryID(req.MessageId),
+		Priority: req.Priority,
+		ExpireAt: req.ExpireAt,
+		AppID:    req.AppId,
 	}
 
-	for nodeID, client := range s.broadCastClientMap {
-		select {
-		case client.broadcastChan <- &args:
-			log.Printf("📤 [Push-Manager] 消息已加入队列: %s, op=%d\n", nodeID, args.ProtoOp)
-		default:
-			log.Printf("⚠️  [Push-Manager] 节点 %s 的队列已满，丢弃消息\n", nodeID)
+	for _, client := range s.clients() {
+		client.enqueue(kindBroadcast, req.Priority, &args)
+		log.Printf("📤 [Push-Manager] 消息已加入队列: %s, op=%d\n", client.serverID, args.ProtoOp)
+	}
+}
+
+// EnqueueRoomMsg 将房间消息加入到所有 Connect-Node 的队列中（由各节点按房间过滤，房间 ID 已带租户前缀）
+func (s *PushManagerServer) EnqueueRoomMsg(req *broadcast.BroadCastRoomReq) {
+	args := push.BroadcastRoomReq{
+		RoomID:   req.RoomId,
+		Proto:    req.Proto,
+		MsgID:    s.deliveryID(req.MessageId),
+		Priority: req.Priority,
+		ExpireAt: req.ExpireAt,
+	}
+
+	for _, client := range s.clients() {
+		client.enqueue(kindRoom, req.Priority, &args)
+	}
+
+	s.recordRoomHistory(req)
+}
+
+// EnqueueTopicMsg 将主题消息加入到所有 Connect-Node 的队列中（由各节点按订阅前缀树匹配）
+func (s *PushManagerServer) EnqueueTopicMsg(req *broadcast.PublishTopicReq) {
+	args := push.BroadcastTopicReq{
+		Topic:    req.Topic,
+		Proto:    req.Proto,
+		MsgID:    s.deliveryID(req.MessageId),
+		Priority: req.Priority,
+		ExpireAt: req.ExpireAt,
+		AppID:    req.AppId,
+	}
+
+	for _, client := range s.clients() {
+		client.enqueue(kindTopic, req.Priority, &args)
+	}
+}
+
+// nodeClientID Connect-Node 客户端在客户端池中的 key（按 gRPC 地址）
+func nodeClientID(address string) string {
+	return fmt.Sprintf("connect-node-%s", address)
+}
+
+// EnqueueUserMsg 根据用户连接索引，将消息按节点分组加入对应 Connect-Node 的队列，返回没有（匹配的）连接的用户。
+// 指定 device_ids / device_types 时只推送到匹配的会话（key 为 userId/deviceId），否则推送到用户的全部会话（key 为 userId）
+func (s *PushManagerServer) EnqueueUserMsg(ctx context.Context, req *broadcast.PushToUserReq) ([]string, error) {
+	byNode := make(map[string][]string)
+	var offline []string
+	filtered := len(req.DeviceIds) > 0 || len(req.DeviceTypes) > 0
+
+	for _, userID := range req.UserIds {
+		conns, err := s.userConns.GetConnections(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		// 同一节点上的多个连接只需推送一次（Connect-Node 按 userId 查找全部会话）
+		seen := make(map[string]bool)
+		for _, conn := range conns {
+			if conn.NodeAddress == "" {
+				continue
+			}
+			key := userID
+			if filtered {
+				if !matchDevice(conn, req.DeviceIds, req.DeviceTypes) {
+					continue
+				}
+				key = userID + "/" + conn.DeviceID
+			}
+			if seen[conn.NodeAddress+"|"+key] {
+				continue
+			}
+			seen[conn.NodeAddress+"|"+key] = true
+			byNode[conn.NodeAddress] = append(byNode[conn.NodeAddress], key)
+		}
+		if len(seen) == 0 {
+			offline = append(offline, userID)
 		}
 	}
+
+	msgID := s.deliveryID(req.MessageId)
+	for address, keys := range byNode {
+		client, ok := s.clientFor(address)
+		if !ok {
+			log.Printf("⚠️  [Push-Manager] 未找到 Connect-Node 客户端: %s, 用户: %v\n", address, keys)
+			continue
+		}
+		args := &push.PushMsgReq{
+			Keys:     keys,
+			ProtoOp:  req.Proto.Op,
+			Proto:    req.Proto,
+			MsgID:    msgID,
+			Priority: req.Priority,
+			ExpireAt: req.ExpireAt,
+		}
+		client.enqueue(kindUser, req.Priority, args)
+	}
+
+	return offline, nil
+}
+
+// matchDevice 连接是否匹配设备过滤条件（两个条件同时指定时需同时满足）
+func matchDevice(conn *redisstore.UserConnection, deviceIDs, deviceTypes []string) bool {
+	if len(deviceIDs) > 0 && !slices.Contains(deviceIDs, conn.DeviceID) {
+		return false
+	}
+	if len(deviceTypes) > 0 && !slices.Contains(deviceTypes, conn.DeviceType) {
+		return false
+	}
+	return true
 }
 
 // Close 关闭客户端
 func (bc *BroadcastClient) Close() {
 	log.Printf("🔌 [Push-Manager] 关闭客户端: %s\n", bc.serverID)
+	// 工作协程和 stream 读取协程随 ctx 退出；不关闭 outbound，避免并发写入已关闭的通道。
+	// Redis 模式下未确认的消息保留在 stream 中，节点恢复后继续投递
 	bc.cancel()
-	close(bc.broadcastChan)
+	bc.cancelRetries()
 
 	if bc.conn != nil {
 		bc.conn.Close()
@@ -255,6 +472,9 @@ func (bc *BroadcastClient) Close() {
 
 // cleanupAllClients 清理所有客户端
 func (s *PushManagerServer) cleanupAllClients() {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+
 	for nodeID, client := range s.broadCastClientMap {
 		log.Printf("🧹 [Push-Manager] 清理客户端: %s\n", nodeID)
 		client.Close()
@@ -267,6 +487,37 @@ func (s *PushManagerServer) cleanupAllClients() {
 // Broadcast 实现 PushServer 的 Broadcast 方法
 func (s *PushManagerServer) Broadcast(ctx context.Context, req *broadcast.BroadCastReq) (*broadcast.BroadCastReply, error) {
 	log.Printf("📡 [Push-Manager] 收到广播请求\n")
+	if req.Proto == nil {
+		return &broadcast.BroadCastReply{Code: "1", Msg: "INVALID_ARGUMENT", Desc: "proto 不能为空"}, nil
+	}
+	if msg, desc, ok := s.tenants.admit(ctx, req.AppId, len(req.Proto.Body)); !ok {
+		return &broadcast.BroadCastReply{Code: "1", Msg: msg, Desc: desc}, nil
+	}
+	scopeBroadcast(req)
+	if desc, expired := s.rejectExpired(ctx, kindBroadcast, req); expired {
+		return &broadcast.BroadCastReply{Code: "1", Msg: "EXPIRED", Desc: desc}, nil
+	}
+
+	return idempotent(ctx, s, kindBroadcast, req.MessageId,
+		func() *broadcast.BroadCastReply { return &broadcast.BroadCastReply{} },
+		func() (*broadcast.BroadCastReply, error) {
+			if msg, desc, ok := s.tenants.countPublish(ctx, req.AppId); !ok {
+				return &broadcast.BroadCastReply{Code: "1", Msg: msg, Desc: desc}, nil
+			}
+			return s.broadcast(ctx, req)
+		})
+}
+
+// broadcast 广播（定时或立即投递）
+func (s *PushManagerServer) broadcast(ctx context.Context, req *broadcast.BroadCastReq) (*broadcast.BroadCastReply, error) {
+
+	scheduleID, err := s.scheduleLater(ctx, kindBroadcast, req, req.DeliverAt, req.DelayMs)
+	if err != nil {
+		return &broadcast.BroadCastReply{Code: "1", Msg: "UNAVAILABLE", Desc: err.Error()}, nil
+	}
+	if scheduleID != "" {
+		return &broadcast.BroadCastReply{Code: "0", Msg: "OK", Desc: "已创建定时推送", ScheduleId: scheduleID}, nil
+	}
 
 	// 将消息加入所有 Connect-Node 的队列
 	s.EnqueueBroadcastMsg(req)
@@ -277,3 +528,169 @@ func (s *PushManagerServer) Broadcast(ctx context.Context, req *broadcast.BroadC
 		Desc: "消息已加入推送队列",
 	}, nil
 }
+
+// BroadcastToRoom 实现 PushServer 的 BroadcastToRoom 方法
+func (s *PushManagerServer) BroadcastToRoom(ctx context.Context, req *broadcast.BroadCastRoomReq) (*broadcast.BroadCastRoomReply, error) {
+	if req.RoomId == "" || req.Proto == nil {
+		return &broadcast.BroadCastRoomReply{Code: "1", Msg: "INVALID_ARGUMENT", Desc: "room_id 和 proto 不能为空"}, nil
+	}
+	if !validateLocalIDs(req.RoomId) {
+		return &broadcast.BroadCastRoomReply{Code: "1", Msg: "INVALID_ARGUMENT", Desc: pkg.ErrTenantIDInvalid.Error()}, nil
+	}
+	if msg, desc, ok := s.tenants.admit(ctx, req.AppId, len(req.Proto.Body)); !ok {
+		return &broadcast.BroadCastRoomReply{Code: "1", Msg: msg, Desc: desc}, nil
+	}
+	scopeRoom(req)
+	log.Printf("🎯 [Push-Manager] 收到房间广播请求: room=%s\n", req.RoomId)
+	if desc, expired := s.rejectExpired(ctx, kindRoom, req); expired {
+		return &broadcast.BroadCastRoomReply{Code: "1", Msg: "EXPIRED", Desc: desc}, nil
+	}
+
+	return idempotent(ctx, s, kindRoom, req.MessageId,
+		func() *broadcast.BroadCastRoomReply { return &broadcast.BroadCastRoomReply{} },
+		func() (*broadcast.BroadCastRoomReply, error) {
+			if msg, desc, ok := s.tenants.countPublish(ctx, req.AppId); !ok {
+				return &broadcast.BroadCastRoomReply{Code: "1", Msg: msg, Desc: desc}, nil
+			}
+			if req.AssignSeq {
+				s.assignPublishSeq(ctx, req)
+			}
+			return s.broadcastToRoom(ctx, req)
+		})
+}
+
+// assignPublishSeq 客户端发言通过校验后按 (房间, 发送者) 分配连续的 Seq，同一用户的多个设备共用一个消息流；
+// Redis 不可用时沿用客户端的 Seq（多个设备同时发言时接收方可能误报缺口）
+func (s *PushManagerServer) assignPublishSeq(ctx context.Context, req *broadcast.BroadCastRoomReq) {
+	if s.publishSeqs == nil || req.Proto.Userid == "" {
+		return
+	}
+	seq, err := s.publishSeqs.Next(ctx, req.RoomId, pkg.ScopeID(req.AppId, req.Proto.Userid))
+	if err != nil {
+		log.Printf("⚠️  [Push-Manager] 分配发言 Seq 失败，沿用客户端 Seq: room=%s, user=%s, err=%v\n", req.RoomId, req.Proto.Userid, err)
+		return
+	}
+	req.Proto.Seq = seq
+}
+
+// broadcastToRoom 房间广播（定时或立即投递）
+func (s *PushManagerServer) broadcastToRoom(ctx context.Context, req *broadcast.BroadCastRoomReq) (*broadcast.BroadCastRoomReply, error) {
+
+	scheduleID, err := s.scheduleLater(ctx, kindRoom, req, req.DeliverAt, req.DelayMs)
+	if err != nil {
+		return &broadcast.BroadCastRoomReply{Code: "1", Msg: "UNAVAILABLE", Desc: err.Error()}, nil
+	}
+	if scheduleID != "" {
+		return &broadcast.BroadCastRoomReply{Code: "0", Msg: "OK", Desc: "已创建定时推送", ScheduleId: scheduleID}, nil
+	}
+
+	s.EnqueueRoomMsg(req)
+
+	return &broadcast.BroadCastRoomReply{
+		Code: "0",
+		Msg:  "OK",
+		Desc: "消息已加入推送队列",
+	}, nil
+}
+
+// PushToUser 实现 PushServer 的 PushToUser 方法（按 Redis 用户连接索引路由，不访问 MySQL）
+func (s *PushManagerServer) PushToUser(ctx context.Context, req *broadcast.PushToUserReq) (*broadcast.PushToUserReply, error) {
+	if len(req.UserIds) == 0 || req.Proto == nil {
+		return &broadcast.PushToUserReply{Code: "1", Msg: "INVALID_ARGUMENT", Desc: "user_ids 和 proto 不能为空"}, nil
+	}
+	if !validateLocalIDs(req.UserIds...) {
+		return &broadcast.PushToUserReply{Code: "1", Msg: "INVALID_ARGUMENT", Desc: pkg.ErrTenantIDInvalid.Error()}, nil
+	}
+	if s.userConns == nil {
+		return &broadcast.PushToUserReply{Code: "1", Msg: "UNAVAILABLE", Desc: "用户连接索引不可用（Redis 未连接）"}, nil
+	}
+	if msg, desc, ok := s.tenants.admit(ctx, req.AppId, len(req.Proto.Body)); !ok {
+		return &broadcast.PushToUserReply{Code: "1", Msg: msg, Desc: desc}, nil
+	}
+	scopeUser(req)
+	log.Printf("👤 [Push-Manager] 收到用户推送请求: users=%d\n", len(req.UserIds))
+	if desc, expired := s.rejectExpired(ctx, kindUser, req); expired {
+		return &broadcast.PushToUserReply{Code: "1", Msg: "EXPIRED", Desc: desc}, nil
+	}
+
+	return idempotent(ctx, s, kindUser, req.MessageId,
+		func() *broadcast.PushToUserReply { return &broadcast.PushToUserReply{} },
+		func() (*broadcast.PushToUserReply, error) {
+			if msg, desc, ok := s.tenants.countPublish(ctx, req.AppId); !ok {
+				return &broadcast.PushToUserReply{Code: "1", Msg: msg, Desc: desc}, nil
+			}
+			return s.pushToUser(ctx, req)
+		})
+}
+
+// pushToUser 用户推送（定时或立即投递）
+func (s *PushManagerServer) pushToUser(ctx context.Context, req *broadcast.PushToUserReq) (*broadcast.PushToUserReply, error) {
+
+	// 定时推送到期时才按当时的连接索引路由
+	scheduleID, err := s.scheduleLater(ctx, kindUser, req, req.DeliverAt, req.DelayMs)
+	if err != nil {
+		return &broadcast.PushToUserReply{Code: "1", Msg: "UNAVAILABLE", Desc: err.Error()}, nil
+	}
+	if scheduleID != "" {
+		return &broadcast.PushToUserReply{Code: "0", Msg: "OK", Desc: "已创建定时推送", ScheduleId: scheduleID}, nil
+	}
+
+	offline, err := s.EnqueueUserMsg(ctx, req)
+	if err != nil {
+		log.Printf("❌ [Push-Manager] 查询用户连接索引失败: %v\n", err)
+		return &broadcast.PushToUserReply{Code: "1", Msg: "INTERNAL", Desc: err.Error()}, nil
+	}
+
+	for i, userID := range offline {
+		offline[i] = pkg.UnscopeID(req.AppId, userID)
+	}
+	return &broadcast.PushToUserReply{
+		Code:           "0",
+		Msg:            "OK",
+		Desc:           "消息已加入推送队列",
+		OfflineUserIds: offline,
+	}, nil
+}
+
+// PublishTopic 实现 PushServer 的 PublishTopic 方法：推送给订阅模式匹配主题的连接（与房间无关）
+func (s *PushManagerServer) PublishTopic(ctx context.Context, req *broadcast.PublishTopicReq) (*broadcast.PublishTopicReply, error) {
+	if req.Proto == nil || pkg.ValidateTopic(req.Topic) != nil {
+		return &broadcast.PublishTopicReply{Code: "1", Msg: "INVALID_ARGUMENT", Desc: "topic 不合法或 proto 为空"}, nil
+	}
+	if msg, desc, ok := s.tenants.admit(ctx, req.AppId, len(req.Proto.Body)); !ok {
+		return &broadcast.PublishTopicReply{Code: "1", Msg: msg, Desc: desc}, nil
+	}
+	scopeTopic(req)
+	log.Printf("🏷️  [Push-Manager] 收到主题发布请求: topic=%s\n", req.Topic)
+	if desc, expired := s.rejectExpired(ctx, kindTopic, req); expired {
+		return &broadcast.PublishTopicReply{Code: "1", Msg: "EXPIRED", Desc: desc}, nil
+	}
+
+	return idempotent(ctx, s, kindTopic, req.MessageId,
+		func() *broadcast.PublishTopicReply { return &broadcast.PublishTopicReply{} },
+		func() (*broadcast.PublishTopicReply, error) {
+			if msg, desc, ok := s.tenants.countPublish(ctx, req.AppId); !ok {
+				return &broadcast.PublishTopicReply{Code: "1", Msg: msg, Desc: desc}, nil
+			}
+			return s.publishTopic(ctx, req)
+		})
+}
+
+// publishTopic 主题发布（定时或立即投递）
+func (s *PushManagerServer) publishTopic(ctx context.Context, req *broadcast.PublishTopicReq) (*broadcast.PublishTopicReply, error) {
+	scheduleID, err := s.scheduleLater(ctx, kindTopic, req, req.DeliverAt, req.DelayMs)
+	if err != nil {
+		return &broadcast.PublishTopicReply{Code: "1", Msg: "UNAVAILABLE", Desc: err.Error()}, nil
+	}
+	if scheduleID != "" {
+		return &broadcast.PublishTopicReply{Code: "0", Msg: "OK", Desc: "已创建定时推送", ScheduleId: scheduleID}, nil
+	}
+
+	s.EnqueueTopicMsg(req)
+
+	return &broadcast.PublishTopicReply{
+		Code: "0",
+		Msg:  "OK",
+		Desc: "消息已加入推送队列",
+	}, nil
+}
@@ -0,0 +1,294 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/livekit/psrpc/examples/pubsub/protocol/push"
+)
+
+const (
+	// streamMaxBatch 每批最多携带的推送命令数
+	streamMaxBatch = 64
+	// streamMaxCredits 本地最多累积的 credit 数
+	streamMaxCredits = 1024
+	// ackCheckInterval 检查批次确认超时的间隔
+	ackCheckInterval = time.Second
+)
+
+// errAckTimeout 批次确认超时（节点卡死或过载）
+var errAckTimeout = errors.New("push batch ack timeout")
+
+// inflightBatch 已发送未确认的批次
+type inflightBatch struct {
+	msgs   []*outboundMsg
+	sentAt time.Time
+}
+
+// pushStream 到 Connect-Node 的一条推送流（Comet.PushStream）
+type pushStream struct {
+	bc     *BroadcastClient
+	stream push.Comet_PushStreamClient
+
+	// credits 可发送的批次数（由 Connect-Node 的确认发放）
+	credits chan struct{}
+
+	mu       sync.Mutex
+	nextID   uint64
+	inflight map[uint64]*inflightBatch
+	acked    bool // 是否收到过确认（用于重置重连退避）
+}
+
+// runPushStream 维护到 Connect-Node 的长连接推送流，断开后按退避重连
+func (bc *BroadcastClient) runPushStream() {
+	attempt := 0
+	for bc.ctx.Err() == nil {
+		acked, err := bc.servePushStream()
+		if bc.ctx.Err() != nil {
+			return
+		}
+		if acked {
+			attempt = 0
+		}
+		attempt++
+		delay := retryBackoff(bc.pushCfg.RetryBaseDelay, bc.pushCfg.RetryMaxDelay, attempt)
+		log.Printf("⚠️  [Push-Manager] 推送流断开，%v 后重连: node=%s, err=%v\n", delay, bc.node, err)
+		sleepCtx(bc.ctx, delay)
+	}
+}
+
+// servePushStream 建立一条推送流并运行到出错；未确认的批次全部按失败重试
+func (bc *BroadcastClient) servePushStream() (bool, error) {
+	ctx, cancel := context.WithCancel(bc.ctx)
+	defer cancel()
+
+	stream, err := bc.client.PushStream(ctx)
+	if err != nil {
+		bc.breaker.Failure()
+		return false, err
+	}
+	log.Printf("🔗 [Push-Manager] 推送流已建立: %s\n", bc.node)
+
+	ps := &pushStream{
+		bc:       bc,
+		stream:   stream,
+		credits:  make(chan struct{}, streamMaxCredits),
+		inflight: make(map[uint64]*inflightBatch),
+	}
+
+	recvErr := make(chan error, 1)
+	go func() {
+		recvErr <- ps.recvLoop()
+		cancel()
+	}()
+
+	err = ps.sendLoop(ctx)
+	cancel()
+	// 接收侧出错时发送侧只会看到 ctx 取消，以接收侧的错误为准
+	if rerr := <-recvErr; errors.Is(err, context.Canceled) {
+		err = rerr
+	}
+
+	if bc.ctx.Err() == nil {
+		bc.breaker.Failure()
+	}
+	ps.failInflight(err)
+	return ps.acked, err
+}
+
+// sendLoop 取得 credit 且熔断器放行后，把队列中已有的消息合并成一批发送；批次确认超时时断开重建
+func (ps *pushStream) sendLoop(ctx context.Context) error {
+	ticker := time.NewTicker(ackCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := ps.waitCredit(ctx, ticker); err != nil {
+			return err
+		}
+
+		// 按优先级出队：高优先级消息总是排在批次前面
+		var msgs []*outboundMsg
+		for msgs == nil {
+			if msg, ok := ps.bc.popLive(); ok {
+				msgs = append(msgs, msg)
+				break
+			}
+			select {
+			case <-ps.bc.outbound.Wait():
+			case <-ticker.C:
+				if ps.ackOverdue() {
+					return errAckTimeout
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		for len(msgs) < streamMaxBatch {
+			msg, ok := ps.bc.popLive()
+			if !ok {
+				break
+			}
+			msgs = append(msgs, msg)
+		}
+
+		batch := &push.PushBatch{Commands: make([]*push.PushCommand, 0, len(msgs))}
+		for _, msg := range msgs {
+			batch.Commands = append(batch.Commands, toPushCommand(msg))
+		}
+
+		ps.mu.Lock()
+		ps.nextID++
+		batch.BatchId = ps.nextID
+		ps.inflight[batch.BatchId] = &inflightBatch{msgs: msgs, sentAt: time.Now()}
+		ps.mu.Unlock()
+
+		if err := ps.stream.Send(batch); err != nil {
+			return err
+		}
+	}
+}
+
+// waitCredit 等待 credit 和熔断器放行（熔断中的节点延迟发送，消息留在队列中）
+func (ps *pushStream) waitCredit(ctx context.Context, ticker *time.Ticker) error {
+	for {
+		select {
+		case <-ps.credits:
+		case <-ticker.C:
+			if ps.ackOverdue() {
+				return errAckTimeout
+			}
+			continue
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		for {
+			allowed, wait := ps.bc.breaker.Allow()
+			if allowed {
+				return nil
+			}
+			if !sleepCtx(ctx, wait) {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// ackOverdue 是否有批次超过确认超时仍未确认
+func (ps *pushStream) ackOverdue() bool {
+	timeout := ps.bc.pushCfg.AckTimeout
+	if timeout <= 0 {
+		return false
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for _, b := range ps.inflight {
+		if time.Since(b.sentAt) > timeout {
+			return true
+		}
+	}
+	return false
+}
+
+// recvLoop 处理批次确认：成功的消息从出站队列确认，失败的消息进入重试
+func (ps *pushStream) recvLoop() error {
+	for {
+		ack, err := ps.stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		if ack.BatchId != 0 {
+			ps.mu.Lock()
+			var msgs []*outboundMsg
+			if b := ps.inflight[ack.BatchId]; b != nil {
+				msgs = b.msgs
+			}
+			delete(ps.inflight, ack.BatchId)
+			ps.acked = true
+			ps.mu.Unlock()
+
+			// 整批失败视为节点异常，部分失败（如个别连接缓冲区满）不影响熔断器
+			if len(msgs) > 0 && len(ack.Failed) == len(msgs) {
+				ps.bc.breaker.Failure()
+			} else {
+				ps.bc.breaker.Success()
+			}
+
+			failed := make(map[int32]bool, len(ack.Failed))
+			for _, i := range ack.Failed {
+				failed[i] = true
+			}
+			for i, msg := range msgs {
+				if failed[int32(i)] {
+					ps.bc.retry(msg, errors.New(ack.Error))
+				} else {
+					ps.bc.ack(msg)
+				}
+			}
+		}
+
+		for i := int32(0); i < ack.Credits; i++ {
+			select {
+			case ps.credits <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// failInflight 推送流断开时，未确认的批次按失败处理
+func (ps *pushStream) failInflight(cause error) {
+	if ps.bc.ctx.Err() != nil {
+		// Push-Manager 关闭或节点移除：Redis 模式下消息仍在 stream 中等待重新认领
+		return
+	}
+	ps.mu.Lock()
+	inflight := ps.inflight
+	ps.inflight = make(map[uint64]*inflightBatch)
+	ps.mu.Unlock()
+
+	for _, b := range inflight {
+		for _, msg := range b.msgs {
+			ps.bc.retry(msg, cause)
+		}
+	}
+}
+
+// onBreakerChange 熔断器状态变化：记录日志和指标
+func (bc *BroadcastClient) onBreakerChange(from, to breakerState) {
+	log.Printf("⚡ [Push-Manager] 节点熔断器状态变化: node=%s, %s -> %s\n", bc.node, from, to)
+	bc.metrics.SetBreakerState(context.Background(), bc.node, from.String(), to.String(), int64(to))
+}
+
+// toPushCommand 转换为推送流中的命令
+func toPushCommand(msg *outboundMsg) *push.PushCommand {
+	switch req := msg.req.(type) {
+	case *push.BroadcastReq:
+		return &push.PushCommand{Command: &push.PushCommand_Broadcast{Broadcast: req}}
+	case *push.BroadcastRoomReq:
+		return &push.PushCommand{Command: &push.PushCommand_BroadcastRoom{BroadcastRoom: req}}
+	case *push.PushMsgReq:
+		return &push.PushCommand{Command: &push.PushCommand_PushMsg{PushMsg: req}}
+	case *push.BroadcastTopicReq:
+		return &push.PushCommand{Command: &push.PushCommand_BroadcastTopic{BroadcastTopic: req}}
+	}
+	return &push.PushCommand{}
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/pkg/metrics"
+	redisstore "github.com/livekit/psrpc/examples/pubsub/pkg/redis"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
+)
+
+// tenantGate 推送请求的租户准入：校验租户状态、消息大小、发布速率和每日推送数。
+// 发布速率按 Push-Manager 实例计（每个实例各自一个令牌桶），每日推送数在 Redis 中全集群计数
+type tenantGate struct {
+	resolver *pkg.TenantResolver
+	usage    *redisstore.UsageStore // Redis 不可用时为 nil（不计量，每日限制不生效）
+	metrics  *metrics.MetricsCollector
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket // appID -> 发布速率令牌桶
+}
+
+func newTenantGate(resolver *pkg.TenantResolver, usage *redisstore.UsageStore, metricsCollector *metrics.MetricsCollector) *tenantGate {
+	return &tenantGate{
+		resolver: resolver,
+		usage:    usage,
+		metrics:  metricsCollector,
+		buckets:  make(map[string]*tokenBucket),
+	}
+}
+
+// admit 校验租户状态、消息大小和发布速率，拒绝时返回响应的 Msg 和 Desc。默认租户（appID 为空）不受限制。
+// 每日推送数在请求通过过期和去重检查后由 countPublish 计入，重复和过期的请求不占用配额
+func (g *tenantGate) admit(ctx context.Context, appID string, size int) (string, string, bool) {
+	if appID == "" {
+		return "", "", true
+	}
+	tenant, err := g.resolver.Tenant(ctx, appID)
+	switch {
+	case errors.Is(err, pkg.ErrTenantNotFound), errors.Is(err, pkg.ErrTenantDisabled):
+		return "PERMISSION_DENIED", err.Error(), false
+	case err != nil:
+		return "UNAVAILABLE", fmt.Sprintf("查询租户失败: %v", err), false
+	}
+
+	if tenant.MaxMessageBytes > 0 && size > int(tenant.MaxMessageBytes) {
+		g.metrics.RecordTenantLimit(ctx, appID, "message_bytes", "hard")
+		return "RESOURCE_EXHAUSTED", fmt.Sprintf("消息大小 %d 超过租户限制 %d 字节", size, tenant.MaxMessageBytes), false
+	}
+	if tenant.PublishRate > 0 && !g.take(appID, float64(tenant.PublishRate)) {
+		log.Printf("🚦 [Push-Manager] 租户发布速率超限: app=%s, rate=%d/s\n", appID, tenant.PublishRate)
+		g.metrics.RecordTenantLimit(ctx, appID, "publish_rate", "hard")
+		return "RESOURCE_EXHAUSTED", fmt.Sprintf("超过租户发布速率 %d 条/秒", tenant.PublishRate), false
+	}
+	return "", "", true
+}
+
+// countPublish 在投递前计入租户当天的推送数：超过硬限制时拒绝，超过软限制时只告警。
+// 计量存储不可用时放行（不因计量故障阻塞推送）
+func (g *tenantGate) countPublish(ctx context.Context, appID string) (string, string, bool) {
+	if appID == "" || g.usage == nil {
+		return "", "", true
+	}
+	tenant, err := g.resolver.Tenant(ctx, appID)
+	switch {
+	case errors.Is(err, pkg.ErrTenantNotFound), errors.Is(err, pkg.ErrTenantDisabled):
+		return "PERMISSION_DENIED", err.Error(), false
+	case err != nil:
+		return "UNAVAILABLE", fmt.Sprintf("查询租户失败: %v", err), false
+	}
+	count, rejected, err := g.usage.IncrPublished(ctx, tenant.AppId, time.Now(), tenant.MaxDailyMessages)
+	if err != nil {
+		log.Printf("⚠️  [Push-Manager] 租户推送计量失败: app=%s, err=%v\n", tenant.AppId, err)
+		return "", "", true
+	}
+	if rejected {
+		log.Printf("🚦 [Push-Manager] 租户每日推送数超过硬限制: app=%s, limit=%d\n", tenant.AppId, tenant.MaxDailyMessages)
+		g.metrics.RecordTenantLimit(ctx, tenant.AppId, "daily_messages", "hard")
+		return "RESOURCE_EXHAUSTED", fmt.Sprintf("超过租户每日推送上限 %d 条", tenant.MaxDailyMessages), false
+	}
+	if tenant.SoftDailyMessages > 0 && count > tenant.SoftDailyMessages {
+		// 每次超出都计入 metrics，日志只在越过软限制时输出一次
+		g.metrics.RecordTenantLimit(ctx, tenant.AppId, "daily_messages", "soft")
+		if count == tenant.SoftDailyMessages+1 {
+			log.Printf("⚠️  [Push-Manager] 租户每日推送数超过软限制: app=%s, soft=%d\n", tenant.AppId, tenant.SoftDailyMessages)
+		}
+	}
+	return "", "", true
+}
+
+// take 从租户的令牌桶取一个令牌（桶容量为 1 秒的配额）
+func (g *tenantGate) take(appID string, rate float64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	b, ok := g.buckets[appID]
+	if !ok {
+		b = &tokenBucket{tokens: rate, last: now}
+		g.buckets[appID] = b
+	}
+	return b.take(rate, now)
+}
+
+// tokenBucket 令牌桶
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) take(rate float64, now time.Time) bool {
+	b.tokens = min(rate, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// validateLocalIDs 租户内 ID 不能包含分隔符（默认租户同样校验，避免寻址到其他租户）
+func validateLocalIDs(ids ...string) bool {
+	for _, id := range ids {
+		if pkg.ValidateLocalID(id) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// 以下 scope* 将请求中的租户内 ID 转为集群内 ID，在去重和定时推送之前调用，
+// 之后的 Enqueue* 和定时推送到期投递都直接使用集群内 ID
+
+func scopeBroadcast(req *broadcast.BroadCastReq) {
+	req.MessageId = pkg.ScopeID(req.AppId, req.MessageId)
+}
+
+func scopeRoom(req *broadcast.BroadCastRoomReq) {
+	req.RoomId = pkg.ScopeID(req.AppId, req.RoomId)
+	req.MessageId = pkg.ScopeID(req.AppId, req.MessageId)
+}
+
+func scopeUser(req *broadcast.PushToUserReq) {
+	req.UserIds = pkg.ScopeIDs(req.AppId, req.UserIds)
+	req.MessageId = pkg.ScopeID(req.AppId, req.MessageId)
+}
+
+func scopeTopic(req *broadcast.PublishTopicReq) {
+	req.MessageId = pkg.ScopeID(req.AppId, req.MessageId)
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := &tokenBucket{tokens: 3, last: now}
+
+	// 桶满时可以连续取 rate 个令牌
+	for i := 0; i < 3; i++ {
+		if !b.take(3, now) {
+			t.Fatalf("take() #%d on a full bucket = false", i+1)
+		}
+	}
+	if b.take(3, now) {
+		t.Fatal("take() on an empty bucket = true")
+	}
+
+	// 按速率补充：1/3 秒补充 1 个
+	if !b.take(3, now.Add(time.Second/3+time.Millisecond)) {
+		t.Error("take() after refilling one token = false")
+	}
+	if b.take(3, now.Add(time.Second/3+2*time.Millisecond)) {
+		t.Error("take() before the next refill = true")
+	}
+
+	// 容量不超过 1 秒的配额
+	later := now.Add(time.Hour)
+	taken := 0
+	for b.take(3, later) {
+		taken++
+	}
+	if taken != 3 {
+		t.Errorf("took %d tokens after a long idle period, want 3", taken)
+	}
+}
+
+func TestTenantGateTakePerApp(t *testing.T) {
+	g := newTenantGate(nil, nil, nil)
+	if !g.take("app1", 1) || g.take("app1", 1) {
+		t.Fatal("app1 should get exactly one token at rate 1/s")
+	}
+	// 租户之间互不影响
+	if !g.take("app2", 1) {
+		t.Error("app2 limited by app1's bucket")
+	}
+}
@@ -0,0 +1,792 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+	proto "github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+const (
+	// apiPrefix 当前 API 版本的路径前缀
+	apiPrefix = "/api/v1"
+	// maxRequestBytes 请求体大小上限
+	maxRequestBytes = 1 << 20
+	// maxPushUsers 单次按用户推送的最大用户数
+	maxPushUsers = 1000
+	// apiTimeout 调用后端 gRPC 的超时（向客户端发起请求时另加等待时间）
+	apiTimeout = 5 * time.Second
+	// defaultTokenTTL 客户端连接令牌默认有效期
+	defaultTokenTTL = time.Hour
+)
+
+// ========== 请求与响应 ==========
+
+// messageBody 推送消息（房间、用户、全员、主题推送共用）
+type messageBody struct {
+	Op         int32  `json:"op,omitempty" desc:"消息 op，默认 2（OpSendMsg），客户端需订阅该 op"`
+	Body       string `json:"body,omitempty" desc:"文本消息体，与 body_base64 二选一"`
+	BodyBase64 string `json:"body_base64,omitempty" desc:"二进制消息体（base64），与 body 二选一"`
+	Priority   string `json:"priority,omitempty" enum:"high,normal,low" desc:"推送优先级，默认 normal"`
+	MessageID  string `json:"message_id,omitempty" desc:"幂等 key，去重窗口内相同 message_id 的重复请求返回首次的结果"`
+	DeliverAt  int64  `json:"deliver_at,omitempty" desc:"定时投递时间（Unix 毫秒）"`
+	DelayMs    int64  `json:"delay_ms,omitempty" desc:"延迟投递（毫秒）"`
+	ExpireAt   int64  `json:"expire_at,omitempty" desc:"过期时间（Unix 毫秒），过期的消息在任何环节都直接丢弃"`
+}
+
+// userMessageRequest 按用户推送
+type userMessageRequest struct {
+	messageBody
+	UserIDs     []string `json:"user_ids" desc:"目标用户（1-1000 个）"`
+	DeviceIDs   []string `json:"device_ids,omitempty" desc:"只推送到这些设备"`
+	DeviceTypes []string `json:"device_types,omitempty" desc:"只推送到这些类型的设备"`
+}
+
+// clientRequestBody 向用户的客户端发起请求
+type clientRequestBody struct {
+	DeviceID   string `json:"device_id,omitempty" desc:"指定设备；为空时发给用户的全部会话，最先响应的生效"`
+	Body       string `json:"body,omitempty" desc:"文本请求体，与 body_base64 二选一"`
+	BodyBase64 string `json:"body_base64,omitempty" desc:"二进制请求体（base64）"`
+	TimeoutMs  int64  `json:"timeout_ms,omitempty" desc:"等待客户端响应的超时，默认 10 秒，最长 60 秒"`
+}
+
+// pushResponse 推送结果（消息已加入推送队列或已创建定时推送）
+type pushResponse struct {
+	Code           string   `json:"code"`
+	Msg            string   `json:"msg"`
+	Desc           string   `json:"desc,omitempty"`
+	ScheduleID     string   `json:"schedule_id,omitempty" desc:"定时/延迟投递的计划 ID"`
+	OfflineUserIDs []string `json:"offline_user_ids,omitempty" desc:"没有在线连接的用户（按用户推送）"`
+}
+
+// clientResponse 客户端响应
+type clientResponse struct {
+	RequestID  string `json:"request_id"`
+	ConnID     string `json:"conn_id" desc:"响应的连接"`
+	Body       string `json:"body" desc:"响应体（按 UTF-8 文本）"`
+	BodyBase64 string `json:"body_base64" desc:"响应体（base64）"`
+}
+
+// tokenRequest 签发客户端连接令牌
+type tokenRequest struct {
+	UserID     string `json:"user_id" desc:"连接的用户 ID"`
+	RoomID     string `json:"room_id,omitempty" desc:"只允许加入该房间，为空时不限制"`
+	TTLSeconds int64  `json:"ttl_seconds,omitempty" desc:"有效期（秒），默认 3600，最长 86400"`
+}
+
+// tokenResponse 客户端连接令牌（客户端加入房间时在请求体的 token 字段携带）
+type tokenResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expires_at" desc:"Unix 秒"`
+}
+
+// roomPolicy 房间策略
+type roomPolicy struct {
+	MaxUsers        int32  `json:"max_users"`
+	JoinMode        string `json:"join_mode" enum:"open,invite_only,password"`
+	PublishPolicy   string `json:"publish_policy" enum:"anyone,owner,none"`
+	MaxMessageBytes int32  `json:"max_message_bytes"`
+	OwnerID         string `json:"owner_id,omitempty"`
+}
+
+// roomResponse 房间信息
+type roomResponse struct {
+	RoomID      string      `json:"room_id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	MaxUsers    int32       `json:"max_users"`
+	UserCount   int         `json:"user_count" desc:"在线成员数"`
+	Policy      *roomPolicy `json:"policy,omitempty"`
+	CreatedAt   int64       `json:"created_at" desc:"Unix 秒"`
+	UpdatedAt   int64       `json:"updated_at" desc:"Unix 秒"`
+}
+
+// roomMember 房间成员
+type roomMember struct {
+	UserID   string `json:"user_id"`
+	UserName string `json:"user_name"`
+	NodeID   string `json:"node_id"`
+	Role     string `json:"role" enum:"owner,moderator,member,viewer"`
+	JoinedAt int64  `json:"joined_at" desc:"Unix 秒"`
+}
+
+// membersResponse 房间在线成员
+type membersResponse struct {
+	RoomID  string       `json:"room_id"`
+	Members []roomMember `json:"members"`
+}
+
+// userConnection 用户连接
+type userConnection struct {
+	ConnID      string   `json:"conn_id"`
+	NodeID      string   `json:"node_id"`
+	NodeAddress string   `json:"node_address"`
+	DeviceID    string   `json:"device_id,omitempty"`
+	DeviceType  string   `json:"device_type,omitempty"`
+	Rooms       []string `json:"rooms"`
+	ConnectedAt int64    `json:"connected_at" desc:"Unix 秒"`
+}
+
+// userLocationResponse 用户所在节点
+type userLocationResponse struct {
+	UserID      string           `json:"user_id"`
+	Online      bool             `json:"online"`
+	NodeID      string           `json:"node_id,omitempty"`
+	NodeAddress string           `json:"node_address,omitempty"`
+	RoomID      string           `json:"room_id,omitempty"`
+	Connections []userConnection `json:"connections"`
+}
+
+// roomStats 房间统计
+type roomStats struct {
+	RoomID    string `json:"room_id"`
+	UserCount int32  `json:"user_count"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// statsResponse 系统统计
+type statsResponse struct {
+	TotalRooms int32       `json:"total_rooms"`
+	TotalUsers int32       `json:"total_users"`
+	Rooms      []roomStats `json:"rooms"`
+}
+
+// apiError 错误响应（HTTP 状态码 + 稳定的错误码）
+type apiError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code" desc:"INVALID_ARGUMENT / UNAUTHENTICATED / PERMISSION_DENIED / NOT_FOUND / EXPIRED / RESOURCE_EXHAUSTED / OFFLINE / TIMEOUT / UNAVAILABLE / INTERNAL"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// errorResponse 错误响应体
+type errorResponse struct {
+	Error *apiError `json:"error"`
+}
+
+func invalidArgument(format string, args ...interface{}) *apiError {
+	return &apiError{Status: http.StatusBadRequest, Code: "INVALID_ARGUMENT", Message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) *apiError {
+	return &apiError{Status: http.StatusNotFound, Code: "NOT_FOUND", Message: fmt.Sprintf(format, args...)}
+}
+
+func unauthenticated(message string) *apiError {
+	return &apiError{Status: http.StatusUnauthorized, Code: "UNAUTHENTICATED", Message: message}
+}
+
+func unavailable(service string) *apiError {
+	return &apiError{Status: http.StatusServiceUnavailable, Code: "UNAVAILABLE", Message: service + " 未连接"}
+}
+
+// ========== 路由 ==========
+
+// apiParam 路径或查询参数
+type apiParam struct {
+	Name        string
+	In          string // path / query
+	Description string
+}
+
+// apiRoute 一个 API 端点（同时用于注册路由和生成 OpenAPI 文档）
+type apiRoute struct {
+	Method   string
+	Path     string
+	Summary  string
+	Tag      string
+	Params   []apiParam
+	Request  interface{} // 请求体类型的零值，nil 表示没有请求体
+	Response interface{} // 成功响应体类型的零值
+	Status   int         // 成功时的 HTTP 状态码
+	handle   func(r *http.Request, appID string) (interface{}, error)
+}
+
+// apiServer 版本化 REST API（/api/v1），转发到 Push-Manager 和 Controller 的 gRPC。
+// 每个请求按 API key 确定租户，只能推送和查询该租户的房间和用户
+type apiServer struct {
+	push       broadcast.PushServerClient         // 未连接时为 nil
+	controller controller.ControllerServiceClient // 未连接时为 nil
+	tenants    *pkg.TenantResolver                // Controller 未连接时为 nil
+	anonymous  bool                               // 允许不带 API key 访问默认租户
+	secret     string                             // 客户端连接令牌签名密钥，为空时不签发
+	routes     []apiRoute
+}
+
+// newAPIServer 创建 REST API
+func newAPIServer(pushClient broadcast.PushServerClient, controllerClient controller.ControllerServiceClient, allowAnonymous bool, tokenSecret string) *apiServer {
+	a := &apiServer{push: pushClient, controller: controllerClient, anonymous: allowAnonymous, secret: tokenSecret}
+	if controllerClient != nil {
+		a.tenants = pkg.NewTenantResolver(controllerClient, pkg.TenantCacheSize, pkg.TenantCacheTTL)
+	}
+	roomParam := apiParam{Name: "room_id", In: "path", Description: "房间 ID"}
+	userParam := apiParam{Name: "user_id", In: "path", Description: "用户 ID"}
+
+	a.routes = []apiRoute{
+		{Method: http.MethodPost, Path: "/rooms/{room_id}/messages", Summary: "推送消息到房间", Tag: "push",
+			Params: []apiParam{roomParam}, Request: messageBody{}, Response: pushResponse{}, Status: http.StatusAccepted, handle: a.pushRoom},
+		{Method: http.MethodPost, Path: "/users/messages", Summary: "推送消息给指定用户", Tag: "push",
+			Request: userMessageRequest{}, Response: pushResponse{}, Status: http.StatusAccepted, handle: a.pushUsers},
+		{Method: http.MethodPost, Path: "/broadcast", Summary: "推送消息给全部连接", Tag: "push",
+			Request: messageBody{}, Response: pushResponse{}, Status: http.StatusAccepted, handle: a.pushAll},
+		{Method: http.MethodPost, Path: "/topics/{topic}/messages", Summary: "发布消息到主题（推送给订阅了匹配模式的连接）", Tag: "push",
+			Params:  []apiParam{{Name: "topic", In: "path", Description: "按 \".\" 分段的主题，如 stock.AAPL.trade"}},
+			Request: messageBody{}, Response: pushResponse{}, Status: http.StatusAccepted, handle: a.pushTopic},
+		{Method: http.MethodPost, Path: "/users/{user_id}/requests", Summary: "向用户的客户端发起请求并等待响应", Tag: "push",
+			Params: []apiParam{userParam}, Request: clientRequestBody{}, Response: clientResponse{}, Status: http.StatusOK, handle: a.requestClient},
+		{Method: http.MethodGet, Path: "/rooms/{room_id}", Summary: "查询房间信息", Tag: "query",
+			Params: []apiParam{roomParam}, Response: roomResponse{}, Status: http.StatusOK, handle: a.getRoom},
+		{Method: http.MethodGet, Path: "/rooms/{room_id}/members", Summary: "查询房间在线成员", Tag: "query",
+			Params: []apiParam{roomParam}, Response: membersResponse{}, Status: http.StatusOK, handle: a.getRoomMembers},
+		{Method: http.MethodGet, Path: "/users/{user_id}/location", Summary: "查询用户所在节点和连接", Tag: "query",
+			Params: []apiParam{userParam}, Response: userLocationResponse{}, Status: http.StatusOK, handle: a.getUserLocation},
+		{Method: http.MethodGet, Path: "/stats", Summary: "查询房间统计", Tag: "query",
+			Response: statsResponse{}, Status: http.StatusOK, handle: a.getStats},
+		{Method: http.MethodPost, Path: "/tokens", Summary: "签发客户端连接令牌（客户端连接 Connect-Node 时使用，无需持有 API key）", Tag: "auth",
+			Request: tokenRequest{}, Response: tokenResponse{}, Status: http.StatusOK, handle: a.issueToken},
+	}
+	return a
+}
+
+// Register 注册全部 API 路由和 OpenAPI 文档
+func (a *apiServer) Register(mux *http.ServeMux) {
+	for _, route := range a.routes {
+		mux.HandleFunc(route.Method+" "+apiPrefix+route.Path, a.wrap(route))
+	}
+	mux.HandleFunc("GET "+apiPrefix+"/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, a.OpenAPI())
+	})
+}
+
+// wrap 统一处理鉴权和响应：成功时按路由的状态码返回 JSON，失败时返回 errorResponse
+func (a *apiServer) wrap(route apiRoute) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		appID, err := a.authenticate(r)
+		var resp interface{}
+		if err == nil {
+			resp, err = route.handle(r, appID)
+		}
+		if err != nil {
+			var apiErr *apiError
+			if !errors.As(err, &apiErr) {
+				apiErr = fromGRPCError(err)
+			}
+			log.Printf("⚠️  %s %s 失败: %v", r.Method, r.URL.Path, apiErr)
+			writeJSON(w, apiErr.Status, errorResponse{Error: apiErr})
+			return
+		}
+		writeJSON(w, route.Status, resp)
+	}
+}
+
+// authenticate 按 API key（Authorization: Bearer <key> 或 X-Api-Key）确定租户；
+// 未携带 key 且允许匿名访问时为默认租户（返回空字符串）
+func (a *apiServer) authenticate(r *http.Request) (string, error) {
+	key := r.Header.Get("X-Api-Key")
+	if auth := r.Header.Get("Authorization"); key == "" && auth != "" {
+		scheme, token, _ := strings.Cut(auth, " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			return "", unauthenticated("Authorization 只支持 Bearer")
+		}
+		key = strings.TrimSpace(token)
+	}
+	if key == "" {
+		if a.anonymous {
+			return "", nil
+		}
+		return "", unauthenticated("缺少 API key")
+	}
+	if a.tenants == nil {
+		return "", unavailable("Controller")
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), apiTimeout)
+	defer cancel()
+	tenant, err := a.tenants.ResolveAPIKey(ctx, key)
+	switch {
+	case errors.Is(err, pkg.ErrAPIKeyInvalid):
+		return "", unauthenticated("API key 无效或已吊销")
+	case errors.Is(err, pkg.ErrTenantDisabled):
+		return "", &apiError{Status: http.StatusForbidden, Code: "PERMISSION_DENIED", Message: "租户已停用"}
+	case err != nil:
+		return "", err
+	}
+	return tenant.AppId, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// decodeJSON 解析请求体（拒绝未知字段和多余内容）
+func decodeJSON(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return invalidArgument("请求体不是合法的 JSON: %v", err)
+	}
+	if dec.More() {
+		return invalidArgument("请求体只能包含一个 JSON 对象")
+	}
+	return nil
+}
+
+// fromGRPCError 将后端 gRPC 错误转换为 HTTP 错误
+func fromGRPCError(err error) *apiError {
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return &apiError{Status: http.StatusBadRequest, Code: "INVALID_ARGUMENT", Message: st.Message()}
+	case codes.NotFound:
+		return &apiError{Status: http.StatusNotFound, Code: "NOT_FOUND", Message: st.Message()}
+	case codes.DeadlineExceeded:
+		return &apiError{Status: http.StatusGatewayTimeout, Code: "TIMEOUT", Message: st.Message()}
+	case codes.Unavailable:
+		return &apiError{Status: http.StatusServiceUnavailable, Code: "UNAVAILABLE", Message: st.Message()}
+	}
+	return &apiError{Status: http.StatusBadGateway, Code: "INTERNAL", Message: st.Message()}
+}
+
+// fromReplyCode 将 Push-Manager 响应中的失败码（msg）转换为 HTTP 错误
+func fromReplyCode(msg, desc string) *apiError {
+	statusCode := http.StatusInternalServerError
+	switch msg {
+	case "INVALID_ARGUMENT":
+		statusCode = http.StatusBadRequest
+	case "PERMISSION_DENIED":
+		statusCode = http.StatusForbidden
+	case "RESOURCE_EXHAUSTED":
+		statusCode = http.StatusTooManyRequests
+	case "EXPIRED":
+		statusCode = http.StatusUnprocessableEntity
+	case "OFFLINE", "NOT_FOUND":
+		statusCode = http.StatusNotFound
+	case "TIMEOUT":
+		statusCode = http.StatusGatewayTimeout
+	case "UNAVAILABLE":
+		statusCode = http.StatusServiceUnavailable
+	}
+	if msg == "" {
+		msg = "INTERNAL"
+	}
+	return &apiError{Status: statusCode, Code: msg, Message: desc}
+}
+
+// pushReply 转换 Push-Manager 的推送响应
+func pushReply(code, msg, desc, scheduleID string, offline []string) (interface{}, error) {
+	if code != "0" {
+		return nil, fromReplyCode(msg, desc)
+	}
+	return pushResponse{Code: code, Msg: msg, Desc: desc, ScheduleID: scheduleID, OfflineUserIDs: offline}, nil
+}
+
+// ========== 请求校验 ==========
+
+// decodeBody 解析文本或 base64 消息体（二选一，不能都为空）
+func decodeBody(text, b64 string) ([]byte, error) {
+	switch {
+	case text != "" && b64 != "":
+		return nil, invalidArgument("body 和 body_base64 只能指定一个")
+	case b64 != "":
+		body, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			return nil, invalidArgument("body_base64 不是合法的 base64: %v", err)
+		}
+		return body, nil
+	case text != "":
+		return []byte(text), nil
+	}
+	return nil, invalidArgument("body 或 body_base64 不能为空")
+}
+
+// validate 校验推送消息并构造 Proto
+func (m *messageBody) validate(roomID string) (*proto.Proto, proto.Priority, error) {
+	body, err := decodeBody(m.Body, m.BodyBase64)
+	if err != nil {
+		return nil, 0, err
+	}
+	priority, ok := pkg.ParsePriority(m.Priority)
+	if !ok {
+		return nil, 0, invalidArgument("priority 只能是 high / normal / low")
+	}
+	if m.Op < 0 {
+		return nil, 0, invalidArgument("op 不能为负数")
+	}
+	if m.DeliverAt < 0 || m.DelayMs < 0 || m.ExpireAt < 0 {
+		return nil, 0, invalidArgument("deliver_at / delay_ms / expire_at 不能为负数")
+	}
+	op := m.Op
+	if op == 0 {
+		op = proto.OpSendMsg
+	}
+	return &proto.Proto{Ver: 1, Op: op, Roomid: roomID, Body: body}, priority, nil
+}
+
+// ========== 推送 ==========
+
+func (a *apiServer) pushRoom(r *http.Request, appID string) (interface{}, error) {
+	if a.push == nil {
+		return nil, unavailable("Push-Manager")
+	}
+	roomID := r.PathValue("room_id")
+	if err := pkg.ValidateLocalID(roomID); err != nil {
+		return nil, invalidArgument("room_id 不能包含 %q", pkg.TenantSeparator)
+	}
+	var req messageBody
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+	p, priority, err := req.validate(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), apiTimeout)
+	defer cancel()
+	reply, err := a.push.BroadcastToRoom(ctx, &broadcast.BroadCastRoomReq{
+		AppId:     appID,
+		RoomId:    roomID,
+		Proto:     p,
+		DeliverAt: req.DeliverAt,
+		DelayMs:   req.DelayMs,
+		MessageId: req.MessageID,
+		Priority:  priority,
+		ExpireAt:  req.ExpireAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("📡 房间推送: app=%s, room=%s, op=%d, code=%s", appID, roomID, p.Op, reply.Code)
+	return pushReply(reply.Code, reply.Msg, reply.Desc, reply.ScheduleId, nil)
+}
+
+func (a *apiServer) pushUsers(r *http.Request, appID string) (interface{}, error) {
+	if a.push == nil {
+		return nil, unavailable("Push-Manager")
+	}
+	var req userMessageRequest
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+	if len(req.UserIDs) == 0 || len(req.UserIDs) > maxPushUsers {
+		return nil, invalidArgument("user_ids 需要 1-%d 个用户", maxPushUsers)
+	}
+	p, priority, err := req.validate("")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), apiTimeout)
+	defer cancel()
+	reply, err := a.push.PushToUser(ctx, &broadcast.PushToUserReq{
+		AppId:       appID,
+		UserIds:     req.UserIDs,
+		Proto:       p,
+		DeviceIds:   req.DeviceIDs,
+		DeviceTypes: req.DeviceTypes,
+		DeliverAt:   req.DeliverAt,
+		DelayMs:     req.DelayMs,
+		MessageId:   req.MessageID,
+		Priority:    priority,
+		ExpireAt:    req.ExpireAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("👤 用户推送: app=%s, users=%d, op=%d, code=%s", appID, len(req.UserIDs), p.Op, reply.Code)
+	return pushReply(reply.Code, reply.Msg, reply.Desc, reply.ScheduleId, reply.OfflineUserIds)
+}
+
+func (a *apiServer) pushAll(r *http.Request, appID string) (interface{}, error) {
+	if a.push == nil {
+		return nil, unavailable("Push-Manager")
+	}
+	var req messageBody
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+	p, priority, err := req.validate("")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), apiTimeout)
+	defer cancel()
+	reply, err := a.push.Broadcast(ctx, &broadcast.BroadCastReq{
+		AppId:     appID,
+		Proto:     p,
+		DeliverAt: req.DeliverAt,
+		DelayMs:   req.DelayMs,
+		MessageId: req.MessageID,
+		Priority:  priority,
+		ExpireAt:  req.ExpireAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("📢 全员推送: app=%s, op=%d, code=%s", appID, p.Op, reply.Code)
+	return pushReply(reply.Code, reply.Msg, reply.Desc, reply.ScheduleId, nil)
+}
+
+func (a *apiServer) pushTopic(r *http.Request, appID string) (interface{}, error) {
+	if a.push == nil {
+		return nil, unavailable("Push-Manager")
+	}
+	topic := r.PathValue("topic")
+	if err := pkg.ValidateTopic(topic); err != nil {
+		return nil, invalidArgument("topic 不合法: %q", topic)
+	}
+	var req messageBody
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+	p, priority, err := req.validate("")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), apiTimeout)
+	defer cancel()
+	reply, err := a.push.PublishTopic(ctx, &broadcast.PublishTopicReq{
+		AppId:     appID,
+		Topic:     topic,
+		Proto:     p,
+		DeliverAt: req.DeliverAt,
+		DelayMs:   req.DelayMs,
+		MessageId: req.MessageID,
+		Priority:  priority,
+		ExpireAt:  req.ExpireAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🏷️  主题发布: app=%s, topic=%s, op=%d, code=%s", appID, topic, p.Op, reply.Code)
+	return pushReply(reply.Code, reply.Msg, reply.Desc, reply.ScheduleId, nil)
+}
+
+func (a *apiServer) requestClient(r *http.Request, appID string) (interface{}, error) {
+	if a.push == nil {
+		return nil, unavailable("Push-Manager")
+	}
+	userID := r.PathValue("user_id")
+	var req clientRequestBody
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+	body, err := decodeBody(req.Body, req.BodyBase64)
+	if err != nil {
+		return nil, err
+	}
+	if req.TimeoutMs < 0 {
+		return nil, invalidArgument("timeout_ms 不能为负数")
+	}
+
+	// 等待客户端响应的时间由 Push-Manager 控制（最长 60 秒），这里只加上转发的余量
+	ctx, cancel := context.WithTimeout(r.Context(), time.Minute+apiTimeout)
+	defer cancel()
+	reply, err := a.push.RequestClient(ctx, &broadcast.RequestClientReq{
+		AppId:     appID,
+		UserId:    userID,
+		DeviceId:  req.DeviceID,
+		Body:      body,
+		TimeoutMs: req.TimeoutMs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reply.Code != "0" {
+		return nil, fromReplyCode(reply.Msg, reply.Desc)
+	}
+	return clientResponse{
+		RequestID:  reply.RequestId,
+		ConnID:     reply.ConnId,
+		Body:       string(reply.Body),
+		BodyBase64: base64.StdEncoding.EncodeToString(reply.Body),
+	}, nil
+}
+
+// ========== 查询 ==========
+
+// roomInfo 查询租户的房间，不存在时返回 NOT_FOUND
+func (a *apiServer) roomInfo(r *http.Request, appID string) (*controller.RoomInfo, error) {
+	if a.controller == nil {
+		return nil, unavailable("Controller")
+	}
+	roomID := r.PathValue("room_id")
+	if pkg.ValidateLocalID(roomID) != nil {
+		return nil, notFound("房间不存在: %s", roomID)
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), apiTimeout)
+	defer cancel()
+	resp, err := a.controller.GetRoomInfo(ctx, &controller.GetRoomInfoRequest{RoomId: pkg.ScopeID(appID, roomID)})
+	if err != nil {
+		return nil, err
+	}
+	if resp.RoomInfo == nil {
+		return nil, notFound("房间不存在: %s", roomID)
+	}
+	return resp.RoomInfo, nil
+}
+
+func (a *apiServer) getRoom(r *http.Request, appID string) (interface{}, error) {
+	info, err := a.roomInfo(r, appID)
+	if err != nil {
+		return nil, err
+	}
+	resp := roomResponse{
+		RoomID:    pkg.UnscopeID(appID, info.RoomId),
+		UserCount: len(info.Users),
+		CreatedAt: info.CreatedAt,
+		UpdatedAt: info.UpdatedAt,
+	}
+	if md := info.Metadata; md != nil {
+		resp.Name = md.Name
+		resp.Description = md.Description
+		resp.MaxUsers = md.MaxUsers
+	}
+	if p := info.Policy; p != nil {
+		resp.Policy = &roomPolicy{
+			MaxUsers:        p.MaxUsers,
+			JoinMode:        p.JoinMode,
+			PublishPolicy:   p.PublishPolicy,
+			MaxMessageBytes: p.MaxMessageBytes,
+			OwnerID:         pkg.UnscopeID(appID, p.OwnerId),
+		}
+	}
+	return resp, nil
+}
+
+func (a *apiServer) getRoomMembers(r *http.Request, appID string) (interface{}, error) {
+	info, err := a.roomInfo(r, appID)
+	if err != nil {
+		return nil, err
+	}
+	resp := membersResponse{RoomID: pkg.UnscopeID(appID, info.RoomId), Members: make([]roomMember, 0, len(info.Users))}
+	for _, u := range info.Users {
+		resp.Members = append(resp.Members, roomMember{
+			UserID:   pkg.UnscopeID(appID, u.UserId),
+			UserName: u.UserName,
+			NodeID:   u.NodeId,
+			Role:     u.Role,
+			JoinedAt: u.JoinedAt,
+		})
+	}
+	return resp, nil
+}
+
+func (a *apiServer) getUserLocation(r *http.Request, appID string) (interface{}, error) {
+	if a.controller == nil {
+		return nil, unavailable("Controller")
+	}
+	userID := r.PathValue("user_id")
+	if pkg.ValidateLocalID(userID) != nil {
+		return nil, invalidArgument("user_id 不能包含 %q", pkg.TenantSeparator)
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), apiTimeout)
+	defer cancel()
+	node, err := a.controller.GetUserNode(ctx, &controller.GetUserNodeRequest{UserId: pkg.ScopeID(appID, userID)})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := userLocationResponse{UserID: userID, Online: node.Found, Connections: make([]userConnection, 0, len(node.Connections))}
+	if node.Found {
+		resp.NodeID = node.NodeId
+		resp.NodeAddress = node.NodeAddress
+		resp.RoomID = pkg.UnscopeID(appID, node.RoomId)
+	}
+	for _, c := range node.Connections {
+		resp.Connections = append(resp.Connections, userConnection{
+			ConnID:      c.ConnId,
+			NodeID:      c.NodeId,
+			NodeAddress: c.NodeAddress,
+			DeviceID:    c.DeviceId,
+			DeviceType:  c.DeviceType,
+			Rooms:       unscopeIDs(appID, c.Rooms),
+			ConnectedAt: c.ConnectedAt,
+		})
+	}
+	return resp, nil
+}
+
+func (a *apiServer) getStats(r *http.Request, appID string) (interface{}, error) {
+	if a.controller == nil {
+		return nil, unavailable("Controller")
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), apiTimeout)
+	defer cancel()
+	stats, err := a.controller.GetRoomStats(ctx, &controller.GetRoomStatsRequest{AppId: appID})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := statsResponse{TotalRooms: stats.TotalRooms, TotalUsers: stats.TotalUsers, Rooms: make([]roomStats, 0, len(stats.Rooms))}
+	for _, rs := range stats.Rooms {
+		resp.Rooms = append(resp.Rooms, roomStats{RoomID: pkg.UnscopeID(appID, rs.RoomId), UserCount: rs.UserCount, CreatedAt: rs.CreatedAt})
+	}
+	return resp, nil
+}
+
+// issueToken 为 API key 所属租户的用户签发连接令牌：租户后端调用后下发给客户端，客户端不持有 API key
+func (a *apiServer) issueToken(r *http.Request, appID string) (interface{}, error) {
+	if a.secret == "" {
+		return nil, &apiError{Status: http.StatusServiceUnavailable, Code: "UNAVAILABLE", Message: "未配置 CLIENT_TOKEN_SECRET"}
+	}
+	var req tokenRequest
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+	if req.UserID == "" {
+		return nil, invalidArgument("user_id 不能为空")
+	}
+	if pkg.ValidateLocalID(req.UserID) != nil || pkg.ValidateLocalID(req.RoomID) != nil {
+		return nil, invalidArgument("user_id / room_id 不能包含 %q", pkg.TenantSeparator)
+	}
+	ttl := defaultTokenTTL
+	if req.TTLSeconds != 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if ttl <= 0 || ttl > pkg.MaxClientTokenTTL {
+		return nil, invalidArgument("ttl_seconds 需在 1-%d 之间", int64(pkg.MaxClientTokenTTL/time.Second))
+	}
+
+	expiresAt := time.Now().Add(ttl).Unix()
+	token, err := pkg.SignClientToken(a.secret, pkg.ClientTokenClaims{
+		AppID:     appID,
+		UserID:    req.UserID,
+		RoomID:    req.RoomID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokenResponse{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// unscopeIDs 集群内 ID 批量转回租户内 ID
+func unscopeIDs(appID string, ids []string) []string {
+	local := make([]string, len(ids))
+	for i, id := range ids {
+		local[i] = pkg.UnscopeID(appID, id)
+	}
+	return local
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
+	proto "github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
+)
+
+const (
+	testKey         = pkg.APIKeyPrefix + "valid"
+	testDisabledKey = pkg.APIKeyPrefix + "disabled"
+	testSecret      = "token-secret"
+)
+
+// fakePush 记录收到的房间推送请求，按 reply / err 返回
+type fakePush struct {
+	broadcast.PushServerClient
+
+	roomReq *broadcast.BroadCastRoomReq
+	reply   *broadcast.BroadCastRoomReply
+	err     error
+}
+
+func (f *fakePush) BroadcastToRoom(ctx context.Context, in *broadcast.BroadCastRoomReq, opts ...grpc.CallOption) (*broadcast.BroadCastRoomReply, error) {
+	f.roomReq = in
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.reply != nil {
+		return f.reply, nil
+	}
+	return &broadcast.BroadCastRoomReply{Code: "0", Msg: "OK"}, nil
+}
+
+// fakeController 按 key 哈希返回租户，按集群内房间 ID 返回房间
+type fakeController struct {
+	controller.ControllerServiceClient
+
+	tenants map[string]*controller.Tenant
+	rooms   map[string]*controller.RoomInfo
+}
+
+func (f *fakeController) ResolveAPIKey(ctx context.Context, in *controller.ResolveAPIKeyRequest, opts ...grpc.CallOption) (*controller.ResolveAPIKeyResponse, error) {
+	tenant, ok := f.tenants[in.KeyHash]
+	return &controller.ResolveAPIKeyResponse{Found: ok, Tenant: tenant}, nil
+}
+
+func (f *fakeController) GetRoomInfo(ctx context.Context, in *controller.GetRoomInfoRequest, opts ...grpc.CallOption) (*controller.GetRoomInfoResponse, error) {
+	return &controller.GetRoomInfoResponse{RoomInfo: f.rooms[in.RoomId]}, nil
+}
+
+func newTestAPI(t *testing.T, push broadcast.PushServerClient, anonymous bool) *httptest.Server {
+	t.Helper()
+	ctrl := &fakeController{
+		tenants: map[string]*controller.Tenant{
+			pkg.HashAPIKey(testKey):         {AppId: "app1", Status: pkg.TenantActive},
+			pkg.HashAPIKey(testDisabledKey): {AppId: "app2", Status: pkg.TenantDisabled},
+		},
+		rooms: map[string]*controller.RoomInfo{
+			"app1:room-1": {
+				RoomId:   "app1:room-1",
+				Metadata: &controller.RoomMetadata{Name: "lobby", MaxUsers: 10},
+				Policy:   &controller.RoomPolicy{JoinMode: "open", OwnerId: "app1:alice"},
+				Users:    []*controller.UserInfo{{UserId: "app1:alice", Role: "owner"}},
+			},
+		},
+	}
+	mux := http.NewServeMux()
+	newAPIServer(push, ctrl, anonymous, testSecret).Register(mux)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// do 发送请求，返回状态码和解析后的 JSON 响应体
+func do(t *testing.T, srv *httptest.Server, method, path, body string, header map[string]string) (int, map[string]interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var out map[string]interface{}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+	}
+	return resp.StatusCode, out
+}
+
+// errorCode 错误响应中的 error.code
+func errorCode(body map[string]interface{}) string {
+	e, _ := body["error"].(map[string]interface{})
+	code, _ := e["code"].(string)
+	return code
+}
+
+func TestAPIAuth(t *testing.T) {
+	const path = apiPrefix + "/rooms/room-1/messages"
+	const body = `{"body":"hi"}`
+
+	tests := []struct {
+		name      string
+		anonymous bool
+		header    map[string]string
+		status    int
+		code      string
+		appID     string
+	}{
+		{"missing key", false, nil, http.StatusUnauthorized, "UNAUTHENTICATED", ""},
+		{"anonymous default tenant", true, nil, http.StatusAccepted, "", ""},
+		{"bearer key", false, map[string]string{"Authorization": "Bearer " + testKey}, http.StatusAccepted, "", "app1"},
+		{"x-api-key", false, map[string]string{"X-Api-Key": testKey}, http.StatusAccepted, "", "app1"},
+		{"basic scheme", false, map[string]string{"Authorization": "Basic abc"}, http.StatusUnauthorized, "UNAUTHENTICATED", ""},
+		{"unknown key", false, map[string]string{"X-Api-Key": pkg.APIKeyPrefix + "nope"}, http.StatusUnauthorized, "UNAUTHENTICATED", ""},
+		{"malformed key", false, map[string]string{"X-Api-Key": "nope"}, http.StatusUnauthorized, "UNAUTHENTICATED", ""},
+		{"disabled tenant", false, map[string]string{"X-Api-Key": testDisabledKey}, http.StatusForbidden, "PERMISSION_DENIED", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			push := &fakePush{}
+			srv := newTestAPI(t, push, tt.anonymous)
+			status, resp := do(t, srv, http.MethodPost, path, body, tt.header)
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d (%v)", status, tt.status, resp)
+			}
+			if tt.code != "" {
+				if got := errorCode(resp); got != tt.code {
+					t.Fatalf("error code = %q, want %q", got, tt.code)
+				}
+				if push.roomReq != nil {
+					t.Fatal("rejected request reached Push-Manager")
+				}
+				return
+			}
+			if push.roomReq == nil || push.roomReq.AppId != tt.appID || push.roomReq.RoomId != "room-1" {
+				t.Fatalf("push request = %v, want app %q room room-1", push.roomReq, tt.appID)
+			}
+		})
+	}
+}
+
+func TestAPIRouting(t *testing.T) {
+	srv := newTestAPI(t, &fakePush{}, true)
+
+	if status, _ := do(t, srv, http.MethodGet, apiPrefix+"/rooms/room-1/messages", "", nil); status != http.StatusMethodNotAllowed {
+		t.Errorf("GET on POST route: status = %d, want 405", status)
+	}
+	if status, _ := do(t, srv, http.MethodGet, apiPrefix+"/nope", "", nil); status != http.StatusNotFound {
+		t.Errorf("unknown path: status = %d, want 404", status)
+	}
+
+	status, doc := do(t, srv, http.MethodGet, apiPrefix+"/openapi.json", "", nil)
+	if status != http.StatusOK {
+		t.Fatalf("openapi.json: status = %d", status)
+	}
+	paths, _ := doc["paths"].(map[string]interface{})
+	api := newAPIServer(nil, nil, true, "")
+	for _, route := range api.routes {
+		item, ok := paths[apiPrefix+route.Path].(map[string]interface{})
+		if !ok {
+			t.Errorf("openapi.json missing path %s", route.Path)
+			continue
+		}
+		if _, ok := item[strings.ToLower(route.Method)]; !ok {
+			t.Errorf("openapi.json missing %s %s", route.Method, route.Path)
+		}
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		push   *fakePush
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"invalid json", &fakePush{}, http.MethodPost, "/rooms/room-1/messages", `{`, http.StatusBadRequest, "INVALID_ARGUMENT"},
+		{"unknown field", &fakePush{}, http.MethodPost, "/rooms/room-1/messages", `{"body":"hi","nope":1}`, http.StatusBadRequest, "INVALID_ARGUMENT"},
+		{"trailing data", &fakePush{}, http.MethodPost, "/rooms/room-1/messages", `{"body":"hi"}{}`, http.StatusBadRequest, "INVALID_ARGUMENT"},
+		{"empty body", &fakePush{}, http.MethodPost, "/rooms/room-1/messages", `{}`, http.StatusBadRequest, "INVALID_ARGUMENT"},
+		{"body and base64", &fakePush{}, http.MethodPost, "/rooms/room-1/messages", `{"body":"hi","body_base64":"aGk="}`, http.StatusBadRequest, "INVALID_ARGUMENT"},
+		{"bad priority", &fakePush{}, http.MethodPost, "/rooms/room-1/messages", `{"body":"hi","priority":"urgent"}`, http.StatusBadRequest, "INVALID_ARGUMENT"},
+		{"scoped room id", &fakePush{}, http.MethodPost, "/rooms/app2:room-1/messages", `{"body":"hi"}`, http.StatusBadRequest, "INVALID_ARGUMENT"},
+		{"too many users", &fakePush{}, http.MethodPost, "/users/messages", `{"body":"hi","user_ids":[]}`, http.StatusBadRequest, "INVALID_ARGUMENT"},
+		{"reply code", &fakePush{reply: &broadcast.BroadCastRoomReply{Code: "1", Msg: "RESOURCE_EXHAUSTED"}}, http.MethodPost, "/rooms/room-1/messages", `{"body":"hi"}`, http.StatusTooManyRequests, "RESOURCE_EXHAUSTED"},
+		{"grpc not found", &fakePush{err: status.Error(codes.NotFound, "room")}, http.MethodPost, "/rooms/room-1/messages", `{"body":"hi"}`, http.StatusNotFound, "NOT_FOUND"},
+		{"grpc unavailable", &fakePush{err: status.Error(codes.Unavailable, "down")}, http.MethodPost, "/rooms/room-1/messages", `{"body":"hi"}`, http.StatusServiceUnavailable, "UNAVAILABLE"},
+		{"grpc internal", &fakePush{err: status.Error(codes.Internal, "boom")}, http.MethodPost, "/rooms/room-1/messages", `{"body":"hi"}`, http.StatusBadGateway, "INTERNAL"},
+		{"room not found", &fakePush{}, http.MethodGet, "/rooms/room-2", "", http.StatusNotFound, "NOT_FOUND"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestAPI(t, tt.push, false)
+			status, resp := do(t, srv, tt.method, apiPrefix+tt.path, tt.body, map[string]string{"X-Api-Key": testKey})
+			if status != tt.status || errorCode(resp) != tt.code {
+				t.Fatalf("got %d %q, want %d %q (%v)", status, errorCode(resp), tt.status, tt.code, resp)
+			}
+		})
+	}
+}
+
+func TestAPIPushUnavailable(t *testing.T) {
+	mux := http.NewServeMux()
+	newAPIServer(nil, nil, true, "").Register(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	status, resp := do(t, srv, http.MethodPost, apiPrefix+"/broadcast", `{"body":"hi"}`, nil)
+	if status != http.StatusServiceUnavailable || errorCode(resp) != "UNAVAILABLE" {
+		t.Fatalf("got %d %q, want 503 UNAVAILABLE", status, errorCode(resp))
+	}
+}
+
+func TestAPIPushDefaults(t *testing.T) {
+	push := &fakePush{}
+	srv := newTestAPI(t, push, true)
+
+	status, resp := do(t, srv, http.MethodPost, apiPrefix+"/rooms/room-1/messages", `{"body_base64":"aGk=","priority":"high"}`, nil)
+	if status != http.StatusAccepted || resp["code"] != "0" {
+		t.Fatalf("got %d %v", status, resp)
+	}
+	req := push.roomReq
+	if req.Proto.Op != proto.OpSendMsg || string(req.Proto.Body) != "hi" || req.Priority != proto.Priority_PRIORITY_HIGH {
+		t.Fatalf("push request = %v", req)
+	}
+}
+
+func TestAPIGetRoomUnscopesIDs(t *testing.T) {
+	srv := newTestAPI(t, &fakePush{}, false)
+
+	status, resp := do(t, srv, http.MethodGet, apiPrefix+"/rooms/room-1", "", map[string]string{"X-Api-Key": testKey})
+	if status != http.StatusOK {
+		t.Fatalf("status = %d (%v)", status, resp)
+	}
+	policy, _ := resp["policy"].(map[string]interface{})
+	if resp["room_id"] != "room-1" || resp["name"] != "lobby" || resp["user_count"] != float64(1) || policy["owner_id"] != "alice" {
+		t.Fatalf("room = %v", resp)
+	}
+
+	status, resp = do(t, srv, http.MethodGet, apiPrefix+"/rooms/room-1/members", "", map[string]string{"X-Api-Key": testKey})
+	members, _ := resp["members"].([]interface{})
+	if status != http.StatusOK || len(members) != 1 || members[0].(map[string]interface{})["user_id"] != "alice" {
+		t.Fatalf("members = %d %v", status, resp)
+	}
+}
+
+func TestAPIIssueToken(t *testing.T) {
+	srv := newTestAPI(t, &fakePush{}, false)
+	header := map[string]string{"X-Api-Key": testKey}
+
+	status, resp := do(t, srv, http.MethodPost, apiPrefix+"/tokens", `{"user_id":"alice","room_id":"room-1"}`, header)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d (%v)", status, resp)
+	}
+	token, _ := resp["token"].(string)
+	claims, err := pkg.VerifyClientToken(testSecret, token)
+	if err != nil {
+		t.Fatalf("VerifyClientToken() = %v", err)
+	}
+	if claims.AppID != "app1" || claims.UserID != "alice" || claims.RoomID != "room-1" || resp["expires_at"] != float64(claims.ExpiresAt) {
+		t.Fatalf("claims = %+v, resp = %v", claims, resp)
+	}
+
+	for _, body := range []string{`{}`, `{"user_id":"app2:alice"}`, `{"user_id":"alice","ttl_seconds":-1}`, `{"user_id":"alice","ttl_seconds":86401}`} {
+		if status, resp := do(t, srv, http.MethodPost, apiPrefix+"/tokens", body, header); status != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400 (%v)", body, status, resp)
+		}
+	}
+	if status, _ := do(t, srv, http.MethodPost, apiPrefix+"/tokens", `{"user_id":"alice"}`, nil); status != http.StatusUnauthorized {
+		t.Errorf("without key: status = %d, want 401", status)
+	}
+}
@@ -11,6 +11,11 @@ const CONFIG = {
     API_URL: (window.location.hostname === 'localhost' || window.location.hostname === '127.0.0.1')
         ? 'http://localhost:8086'  // 本地开发或 Docker 通过 localhost 访问
         : `http://${window.location.hostname}:8086`,  // 通过 IP 或其他域名访问
+
+    // 租户 ID 和 API key（为空时使用默认租户，Web 服务器需设置 WEB_ALLOW_ANONYMOUS=true）；
+    // 设置 API key 时先经 /api/v1/tokens 申请连接令牌再连接 Connect-Node
+    APP_ID: '',
+    API_KEY: '',
 };
 
 console.log('📝 当前配置:', CONFIG);
@@ -3,12 +3,19 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/livekit/psrpc/examples/pubsub/pkg"
 	"github.com/livekit/psrpc/examples/pubsub/protocol/broadcast"
+	"github.com/livekit/psrpc/examples/pubsub/protocol/controller"
 	proto "github.com/livekit/psrpc/examples/pubsub/protocol/protocol"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -26,12 +33,30 @@ type BroadcastResponse struct {
 }
 
 func main() {
+	printOpenAPI := flag.Bool("openapi", false, "输出 OpenAPI 文档后退出")
+	flag.Parse()
+	if *printOpenAPI {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(newAPIServer(nil, nil, false, "").OpenAPI())
+		return
+	}
+
 	port := getEnv("WEB_PORT", "8086")
 	pushManagerAddr := getEnv("PUSH_MANAGER_ADDR", "localhost:50053")
+	controllerAddr := getEnv("CONTROLLER_ADDR", "localhost:50051")
+	// 允许不带 API key 访问默认租户（仅用于本地开发）
+	allowAnonymous := getEnv("WEB_ALLOW_ANONYMOUS", "false") == "true"
+	// 客户端连接令牌签名密钥（与 Connect-Node 的 auth.client_token_secret 一致）
+	tokenSecret := getEnv("CLIENT_TOKEN_SECRET", "")
+	// 允许跨域访问的来源（逗号分隔，"*" 表示任意来源），默认不允许跨域
+	allowedOrigins := pkg.SplitList(getEnv("WEB_ALLOWED_ORIGINS", ""))
 
 	log.Printf("🌐 Web 服务器启动中...")
 	log.Printf("   端口: %s", port)
 	log.Printf("   Push-Manager: %s", pushManagerAddr)
+	log.Printf("   Controller: %s", controllerAddr)
+	log.Printf("   匿名访问: %v, 跨域来源: %v, 签发连接令牌: %v", allowAnonymous, allowedOrigins, tokenSecret != "")
 	log.Printf("")
 
 	// 连接 Push-Manager gRPC
@@ -55,11 +80,32 @@ func main() {
 		pushClient = broadcast.NewPushServerClient(conn)
 	}
 
+	// 连接 Controller gRPC（查询接口使用）
+	log.Printf("🔗 连接 Controller...")
+	var controllerClient controller.ControllerServiceClient
+	ctrlConn, err := grpc.Dial(controllerAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+		grpc.WithTimeout(10*time.Second),
+	)
+	if err != nil {
+		log.Printf("⚠️  连接 Controller 失败: %v", err)
+		log.Printf("⚠️  查询 API 将不可用")
+	} else {
+		defer ctrlConn.Close()
+		controllerClient = controller.NewControllerServiceClient(ctrlConn)
+		log.Printf("✅ Controller 客户端已连接")
+	}
+
 	log.Printf("")
 
 	// HTTP 路由
 	mux := http.NewServeMux()
 
+	// API: /api/v1 REST 接口和 OpenAPI 文档
+	api := newAPIServer(pushClient, controllerClient, allowAnonymous, tokenSecret)
+	api.Register(mux)
+
 	// API: 广播消息
 	mux.HandleFunc("/broadcast", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
@@ -79,6 +125,25 @@ func main() {
 			return
 		}
 
+		// 与 REST API 相同的 API key 鉴权，只能推送到 key 所属租户
+		appID, err := api.authenticate(r)
+		if err != nil {
+			log.Printf("❌ 鉴权失败: %v", err)
+			status := http.StatusUnauthorized
+			var apiErr *apiError
+			if errors.As(err, &apiErr) {
+				status = apiErr.Status
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(BroadcastResponse{
+				Code: strconv.Itoa(status),
+				Msg:  http.StatusText(status),
+				Desc: err.Error(),
+			})
+			return
+		}
+
 		var req BroadcastRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			log.Printf("❌ 解析请求失败: %v", err)
@@ -92,7 +157,7 @@ func main() {
 			return
 		}
 
-		log.Printf("📡 收到广播请求: room=%s, message=%s", req.RoomID, req.Message)
+		log.Printf("📡 收到广播请求: app=%s, room=%s, message=%s", appID, req.RoomID, req.Message)
 
 		// 调用 Push-Manager 广播
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -106,7 +171,10 @@ func main() {
 			Body:   []byte(req.Message),
 		}
 
-		_, err := pushClient.Broadcast(ctx, &broadcast.BroadCastReq{Proto: protoMsg})
+		reply, err := pushClient.Broadcast(ctx, &broadcast.BroadCastReq{AppId: appID, Proto: protoMsg})
+		if err == nil && reply.Code != "0" {
+			err = fmt.Errorf("%s: %s", reply.Msg, reply.Desc)
+		}
 		if err != nil {
 			log.Printf("❌ 广播失败: %v", err)
 			w.Header().Set("Content-Type", "application/json")
@@ -141,12 +209,15 @@ func main() {
 	fs := http.FileServer(http.Dir("./"))
 	mux.Handle("/", fs)
 
-	// 设置 CORS 头
+	// 设置 CORS 头（只对允许的来源）
 	corsHandler := func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			if origin := r.Header.Get("Origin"); origin != "" && originAllowed(allowedOrigins, origin) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Vary", "Origin")
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Api-Key")
+			}
 
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
@@ -162,6 +233,7 @@ func main() {
 	log.Printf("📝 功能:")
 	log.Printf("   - 聊天页面: http://localhost:%s/chat.html", port)
 	log.Printf("   - 广播 API: POST http://localhost:%s/broadcast", port)
+	log.Printf("   - REST API: http://localhost:%s%s（文档: GET %s/openapi.json）", port, apiPrefix, apiPrefix)
 	log.Printf("   - 健康检查: GET http://localhost:%s/health", port)
 	log.Printf("")
 	log.Printf("💡 使用说明:")
@@ -176,6 +248,16 @@ func main() {
 	}
 }
 
+// originAllowed 来源是否在允许列表中
+func originAllowed(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
 // getEnv 获取环境变量（带默认值）
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// OpenAPI 由路由表生成 OpenAPI 3 文档：请求/响应结构体按 json tag 反射为 schema，
+// desc tag 为字段说明，enum tag（逗号分隔）为可选值
+func (a *apiServer) OpenAPI() map[string]interface{} {
+	schemas := map[string]interface{}{}
+	paths := map[string]interface{}{}
+
+	errorSchema := schemaOf(reflect.TypeOf(errorResponse{}), schemas)
+	for _, route := range a.routes {
+		op := map[string]interface{}{
+			"summary":     route.Summary,
+			"tags":        []string{route.Tag},
+			"operationId": strings.ToLower(route.Method) + operationName(route.Path),
+		}
+
+		params := make([]interface{}, 0, len(route.Params))
+		for _, p := range route.Params {
+			params = append(params, map[string]interface{}{
+				"name":        p.Name,
+				"in":          p.In,
+				"required":    p.In == "path",
+				"description": p.Description,
+				"schema":      map[string]interface{}{"type": "string"},
+			})
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		if route.Request != nil {
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  jsonContent(schemaOf(reflect.TypeOf(route.Request), schemas)),
+			}
+		}
+		op["responses"] = map[string]interface{}{
+			strconv.Itoa(route.Status): map[string]interface{}{
+				"description": "成功",
+				"content":     jsonContent(schemaOf(reflect.TypeOf(route.Response), schemas)),
+			},
+			"default": map[string]interface{}{
+				"description": "错误",
+				"content":     jsonContent(errorSchema),
+			},
+		}
+
+		path := apiPrefix + route.Path
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Pubsub Web API",
+			"version":     "v1",
+			"description": "推送和查询接口，转发到 Push-Manager 和 Controller",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth":   map[string]interface{}{"type": "http", "scheme": "bearer", "description": "租户的 API key"},
+				"apiKeyHeader": map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-Api-Key"},
+			},
+		},
+		// 两种方式任选其一；请求只能访问 API key 所属租户的房间和用户
+		"security": []interface{}{
+			map[string]interface{}{"bearerAuth": []string{}},
+			map[string]interface{}{"apiKeyHeader": []string{}},
+		},
+	}
+}
+
+// schemaOf 生成类型的 schema，命名结构体登记到 schemas 并返回 $ref
+func schemaOf(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaOf(t.Elem(), schemas)
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": schemaOf(t.Elem(), schemas)}
+	case reflect.Struct:
+		name := schemaName(t)
+		if _, ok := schemas[name]; !ok {
+			// 先占位，避免自引用的结构体无限递归
+			schemas[name] = nil
+			properties := map[string]interface{}{}
+			var required []string
+			structFields(t, schemas, properties, &required)
+			schema := map[string]interface{}{"type": "object", "properties": properties}
+			if len(required) > 0 {
+				schema["required"] = required
+			}
+			schemas[name] = schema
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+// structFields 收集结构体字段（匿名嵌入的结构体与 encoding/json 一样展开），没有 omitempty 的字段为必填
+func structFields(t reflect.Type, schemas, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			structFields(f.Type, schemas, properties, required)
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" || !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+
+		schema := schemaOf(f.Type, schemas)
+		if _, isRef := schema["$ref"]; isRef {
+			// $ref 不能带兄弟字段，说明放到 allOf 外层
+			if desc := f.Tag.Get("desc"); desc != "" {
+				schema = map[string]interface{}{"allOf": []interface{}{schema}, "description": desc}
+			}
+		} else {
+			if desc := f.Tag.Get("desc"); desc != "" {
+				schema["description"] = desc
+			}
+			if enum := f.Tag.Get("enum"); enum != "" {
+				schema["enum"] = strings.Split(enum, ",")
+			}
+		}
+		properties[name] = schema
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaName 结构体类型名转为 schema 名（messageBody → MessageBody）
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return "Object"
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// operationName 路径转为 operationId 后缀：/rooms/{room_id}/messages → RoomsRoomIdMessages
+func operationName(path string) string {
+	var b strings.Builder
+	for _, seg := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '{' || r == '}' || r == '_' }) {
+		b.WriteString(strings.ToUpper(seg[:1]) + seg[1:])
+	}
+	return b.String()
+}
+
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+}